@@ -14,7 +14,10 @@ func main() {
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
 
 	// Load configuration
-	cfg := config.DefaultConfig()
+	cfg, err := config.Load("")
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
 
 	// Create agent
 	agentInstance, err := agent.New(cfg)
@@ -28,4 +31,11 @@ func main() {
 		log.Fatalf("Agent error: %v", err)
 		os.Exit(1)
 	}
+
+	// Restart requested by backend
+	if agentInstance.RestartRequested() {
+		if err := agent.Restart(); err != nil {
+			log.Fatalf("Failed to restart agent: %v", err)
+		}
+	}
 }
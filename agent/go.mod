@@ -7,6 +7,8 @@ replace smart-monitor => ../
 require (
 	github.com/shirou/gopsutil/v3 v3.24.5
 	google.golang.org/grpc v1.78.0
+	google.golang.org/protobuf v1.36.11
+	gopkg.in/yaml.v3 v3.0.1
 	smart-monitor v0.0.0-00010101000000-000000000000
 )
 
@@ -24,5 +26,4 @@ require (
 	golang.org/x/text v0.32.0 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20251222181119-0a764e51fe1b // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20251222181119-0a764e51fe1b // indirect
-	google.golang.org/protobuf v1.36.11 // indirect
 )
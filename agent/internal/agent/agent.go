@@ -7,6 +7,9 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -14,16 +17,27 @@ import (
 	"smart-agent/internal/collector"
 	"smart-agent/internal/config"
 	"smart-agent/internal/identity"
+	"smart-agent/internal/spool"
 )
 
 // Agent represents the monitoring agent
 type Agent struct {
-	config    *config.Config
+	config    atomic.Pointer[config.Config] // running configuration, replaced on reload
 	identity  *identity.Manager
 	collector *collector.Collector
 	client    *client.Client
+	spool     *spool.Spool
 	ctx       context.Context
 	cancel    context.CancelFunc
+
+	streaming atomic.Bool   // metrics stream is up
+	wake      chan struct{} // cuts the reconnect delay short
+	restart   atomic.Bool   // set when the backend asked for a restart
+	reloaded  chan struct{} // tells the collect loop to pick up a new interval
+
+	configMu sync.Mutex      // serializes configuration changes
+	local    *config.Config  // configuration from file and environment
+	profile  *config.Profile // central config profile applied on top, nil if none
 }
 
 // New creates a new agent instance
@@ -37,32 +51,61 @@ func New(cfg *config.Config) (*Agent, error) {
 	}
 
 	// Setup metrics collector
-	metricsCollector := collector.NewCollector("/")
+	metricsCollector, err := collector.NewCollector(collectorSettings(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure collectors: %w", err)
+	}
+	log.Printf("Collector plugins: %v", metricsCollector.Plugins())
+
+	// Setup on-disk spool for samples taken while the backend is unreachable
+	sampleSpool, err := spool.Open(filepath.Join(cfg.CacheDir, "spool"), cfg.SpoolMaxSamples, cfg.BatchSize*10)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open spool: %w", err)
+	}
+	if pending := sampleSpool.Len(); pending > 0 {
+		log.Printf("Spool holds %d samples from a previous run", pending)
+	}
 
 	// Setup backend client
-	backendClient := client.NewClient(cfg, identityMgr, metricsCollector)
+	backendClient := client.NewClient(cfg, identityMgr, sampleSpool)
 
 	// Create agent
 	ctx, cancel := context.WithCancel(context.Background())
 
-	return &Agent{
-		config:    cfg,
+	a := &Agent{
 		identity:  identityMgr,
 		collector: metricsCollector,
 		client:    backendClient,
+		spool:     sampleSpool,
 		ctx:       ctx,
 		cancel:    cancel,
-	}, nil
+		wake:      make(chan struct{}, 1),
+		reloaded:  make(chan struct{}, 1),
+	}
+	a.local = cfg
+	a.config.Store(cfg)
+	return a, nil
+}
+
+// collectorSettings converts the collector configuration to plugin settings
+func collectorSettings(cfg *config.Config) map[string]collector.Settings {
+	settings := make(map[string]collector.Settings, len(cfg.Collectors))
+	for name, c := range cfg.Collectors {
+		settings[name] = collector.Settings{Enabled: c.Enabled, Interval: c.Interval}
+	}
+	return settings
 }
 
 // Start starts the agent
 func (a *Agent) Start() error {
+	cfg := a.config.Load()
 	log.Println("=== Smart Monitor Agent ===")
-	log.Printf("Version: %s", a.config.AgentVersion)
-	log.Printf("Hostname: %s", a.config.Hostname)
-	log.Printf("IP Address: %s", a.config.IPAddress)
-	log.Printf("Backend: %s", a.config.BackendAddr)
-	log.Printf("Metrics Interval: %v", a.config.MetricsInterval)
+	log.Printf("Version: %s", cfg.AgentVersion)
+	log.Printf("Hostname: %s", cfg.Hostname)
+	log.Printf("IP Address: %s", cfg.IPAddress)
+	log.Printf("Backend: %s (TLS: %v)", cfg.BackendAddr, cfg.BackendTLS)
+	log.Printf("Metrics Interval: %v", cfg.MetricsInterval)
+	log.Printf("Config File: %s", cfg.ConfigFile)
 
 	// Setup graceful shutdown
 	a.setupSignalHandler()
@@ -72,11 +115,24 @@ func (a *Agent) Start() error {
 		return fmt.Errorf("failed to connect to backend: %w", err)
 	}
 	defer a.client.Close()
+	defer a.spool.Close()
 
 	// Register or load credentials
 	if err := a.client.LoadOrRegister(a.ctx); err != nil {
 		return fmt.Errorf("failed to register: %w", err)
 	}
+	a.fetchProfile()
+
+	// Renew the access token before it expires
+	go a.identity.KeepTokenFresh(a.ctx, a.client, func() float64 {
+		return a.config.Load().TokenRenewFraction
+	})
+
+	// Sample metrics independently of the backend connection
+	go a.collectLoop()
+
+	// Receive commands from backend
+	go a.runCommands()
 
 	// Start monitoring loop with auto-reconnect
 	return a.runWithReconnect()
@@ -90,24 +146,35 @@ func (a *Agent) Stop() {
 	log.Println("Agent stopped")
 }
 
-// setupSignalHandler sets up graceful shutdown on signals
+// setupSignalHandler reloads the configuration on SIGHUP and shuts down
+// gracefully on the other signals
 func (a *Agent) setupSignalHandler() {
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT, syscall.SIGHUP)
 
 	go func() {
-		sig := <-sigChan
-		log.Printf("\n⚠ Received signal: %v", sig)
-		a.Stop()
+		for sig := range sigChan {
+			if sig == syscall.SIGHUP {
+				if err := a.Reload(); err != nil {
+					log.Printf("⚠ Config reload failed, keeping current configuration: %v", err)
+				}
+				continue
+			}
+
+			log.Printf("\n⚠ Received signal: %v", sig)
+			a.Stop()
+			return
+		}
 	}()
 }
 
 // connectWithRetry connects to backend with retry logic
 func (a *Agent) connectWithRetry() error {
-	for i := 0; i < a.config.MaxRetries; i++ {
+	cfg := a.config.Load()
+	for i := 0; i < cfg.MaxRetries; i++ {
 		if i > 0 {
-			log.Printf("Retry attempt %d/%d...", i+1, a.config.MaxRetries)
-			time.Sleep(a.config.RetryInterval)
+			log.Printf("Retry attempt %d/%d...", i+1, cfg.MaxRetries)
+			time.Sleep(cfg.RetryInterval)
 		}
 
 		ctx, cancel := context.WithTimeout(a.ctx, 10*time.Second)
@@ -121,11 +188,45 @@ func (a *Agent) connectWithRetry() error {
 		log.Printf("Connection failed: %v", err)
 	}
 
-	return fmt.Errorf("failed to connect after %d retries", a.config.MaxRetries)
+	return fmt.Errorf("failed to connect after %d retries", cfg.MaxRetries)
+}
+
+// fetchProfile applies the central config profile, keeping the current
+// configuration if the backend cannot provide it
+func (a *Agent) fetchProfile() {
+	if _, err := a.syncProfile(); err != nil {
+		log.Printf("⚠ Failed to apply central config, keeping current configuration: %v", err)
+	}
+}
+
+// collectLoop samples metrics every interval and hands them to the client,
+// which streams or spools them
+func (a *Agent) collectLoop() {
+	interval := a.config.Load().MetricsInterval
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-a.reloaded:
+			if next := a.config.Load().MetricsInterval; next != interval {
+				interval = next
+				ticker.Reset(interval)
+			}
+		case <-ticker.C:
+			a.client.Record(a.collector.Collect(a.ctx))
+		}
+	}
 }
 
-// runWithReconnect runs the main loop with auto-reconnect
+// runWithReconnect runs the main loop with auto-reconnect. When the backend
+// no longer accepts the agent's credentials the agent registers again; while
+// it refuses the agent, the agent waits longer between attempts.
 func (a *Agent) runWithReconnect() error {
+	denials := 0 // refusals in a row
+	reregister := false
 	for {
 		// Check if context is cancelled
 		select {
@@ -135,7 +236,9 @@ func (a *Agent) runWithReconnect() error {
 		}
 
 		// Stream metrics
+		a.streaming.Store(true)
 		err := a.client.StreamMetrics(a.ctx)
+		a.streaming.Store(false)
 
 		// Check if it's a graceful shutdown
 		if err == context.Canceled {
@@ -144,24 +247,61 @@ func (a *Agent) runWithReconnect() error {
 
 		if err != nil {
 			log.Printf("Streaming error: %v", err)
-			log.Printf("Reconnecting in %v...", a.config.ReconnectDelay)
+			switch {
+			case client.IsUnauthenticated(err):
+				log.Println("⚠ Backend no longer accepts the agent's credentials, registering again")
+				reregister = true
+				denials = 0
+			case client.IsDenied(err):
+				denials++
+			default:
+				denials = 0
+			}
+
+			delay := a.retryDelay(denials)
+			log.Printf("Reconnecting in %v...", delay)
 
 			select {
 			case <-a.ctx.Done():
 				return nil
-			case <-time.After(a.config.ReconnectDelay):
-				// Try to reconnect
-				if err := a.connectWithRetry(); err != nil {
-					log.Printf("Failed to reconnect: %v", err)
-					continue
-				}
+			case <-a.wake:
+				log.Println("Reconnecting now (start command)")
+			case <-time.After(delay):
+			}
+
+			// Try to reconnect
+			if err := a.connectWithRetry(); err != nil {
+				log.Printf("Failed to reconnect: %v", err)
+				continue
+			}
 
-				// Re-register if needed
-				if err := a.client.LoadOrRegister(a.ctx); err != nil {
-					log.Printf("Failed to register after reconnect: %v", err)
-					continue
+			// Register again if the credentials were rejected, or if needed
+			register := a.client.LoadOrRegister
+			if reregister {
+				register = a.client.Register
+			}
+			if err := register(a.ctx); err != nil {
+				log.Printf("Failed to register after reconnect: %v", err)
+				if client.IsDenied(err) {
+					denials++
 				}
+				continue
 			}
+			reregister = false
+			a.fetchProfile()
 		}
 	}
 }
+
+// maxDeniedDelay caps the delay between attempts while the backend refuses the agent
+const maxDeniedDelay = 5 * time.Minute
+
+// retryDelay returns how long to wait before connecting again. The reconnect
+// delay doubles with each refusal in a row, up to maxDeniedDelay.
+func (a *Agent) retryDelay(denials int) time.Duration {
+	delay := a.config.Load().ReconnectDelay
+	for i := 0; i < denials && delay < maxDeniedDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDeniedDelay)
+}
@@ -0,0 +1,110 @@
+package agent
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"syscall"
+	"time"
+
+	"smart-agent/internal/client"
+	pb "smart-monitor/pbtypes/monitor"
+)
+
+// Execute runs a command pushed by the backend
+func (a *Agent) Execute(cmd *pb.AgentCommand) (string, error) {
+	switch cmd.Action {
+	case "start":
+		if a.streaming.Load() {
+			return "monitoring already running", nil
+		}
+		// Skip the reconnect delay of the metrics loop
+		select {
+		case a.wake <- struct{}{}:
+		default:
+		}
+		return "monitoring resumed", nil
+	case "reconfigure":
+		revision, err := a.syncProfile()
+		if err != nil {
+			return "", err
+		}
+		if revision == "" {
+			return "no config profile applies, using local configuration", nil
+		}
+		return fmt.Sprintf("applied config profile %s", revision), nil
+	case "shutdown":
+		return "agent shutting down", nil
+	case "restart":
+		if _, err := os.Executable(); err != nil {
+			return "", fmt.Errorf("cannot locate agent binary: %w", err)
+		}
+		return "agent restarting", nil
+	default:
+		return "", fmt.Errorf("unknown action: %s", cmd.Action)
+	}
+}
+
+// Completed stops or restarts the agent once the backend knows the result
+func (a *Agent) Completed(cmd *pb.AgentCommand, err error) {
+	if err != nil {
+		return
+	}
+
+	switch cmd.Action {
+	case "shutdown":
+		log.Printf("⚠ Shutdown requested by backend (command %s)", cmd.CommandId)
+		go a.Stop()
+	case "restart":
+		log.Printf("⚠ Restart requested by backend (command %s)", cmd.CommandId)
+		a.restart.Store(true)
+		go a.Stop()
+	}
+}
+
+// RestartRequested reports whether the agent stopped to be restarted
+func (a *Agent) RestartRequested() bool {
+	return a.restart.Load()
+}
+
+// Restart replaces the current process with a fresh instance of the agent binary
+func Restart() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("cannot locate agent binary: %w", err)
+	}
+
+	log.Printf("Restarting %s...", executable)
+	return syscall.Exec(executable, os.Args, os.Environ())
+}
+
+// runCommands keeps the command channel open until the agent stops. The
+// metrics loop registers again when the credentials are rejected; while the
+// backend refuses the agent, the channel is retried less and less often.
+func (a *Agent) runCommands() {
+	denials := 0 // refusals in a row
+	for {
+		err := a.client.RunCommands(a.ctx, a)
+
+		select {
+		case <-a.ctx.Done():
+			return
+		default:
+		}
+
+		if err != nil {
+			log.Printf("Command channel error: %v", err)
+		}
+		if client.IsDenied(err) {
+			denials++
+		} else {
+			denials = 0
+		}
+
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-time.After(a.retryDelay(denials)):
+		}
+	}
+}
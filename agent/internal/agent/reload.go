@@ -0,0 +1,119 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"time"
+
+	"smart-agent/internal/config"
+)
+
+// Reload re-reads the config file and environment and applies the result
+// without restarting the agent or dropping the metrics stream. An invalid
+// configuration is rejected as a whole. The central config profile, if any,
+// still takes precedence over the reloaded settings.
+//
+// The interval, metadata, batch size and collectors apply to the next sample.
+// The backend address and TLS settings apply the next time the agent connects,
+// the token renewal fraction within the hour.
+// Identity, storage and spool size only apply at startup.
+func (a *Agent) Reload() error {
+	a.configMu.Lock()
+	defer a.configMu.Unlock()
+
+	current := a.local
+
+	local, err := config.Load(current.ConfigFile)
+	if err != nil {
+		return err
+	}
+
+	// Settings that only apply at startup
+	local.AgentVersion = current.AgentVersion
+	local.Hostname = current.Hostname
+	local.IPAddress = current.IPAddress
+	local.TokenFile = current.TokenFile
+	local.LogFile = current.LogFile
+	local.CacheDir = current.CacheDir
+	if local.SpoolMaxSamples != current.SpoolMaxSamples {
+		log.Printf("⚠ Spool size change to %d samples applies after a restart", local.SpoolMaxSamples)
+		local.SpoolMaxSamples = current.SpoolMaxSamples
+	}
+
+	return a.applyConfig(local, a.profile, "Configuration reloaded from "+local.ConfigFile)
+}
+
+// syncProfile fetches the central config profile from the backend and applies
+// it on top of the local configuration. It returns the applied revision, empty
+// when no profile applies.
+func (a *Agent) syncProfile() (string, error) {
+	ctx, cancel := context.WithTimeout(a.ctx, 10*time.Second)
+	defer cancel()
+
+	profile, err := a.client.FetchConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	a.configMu.Lock()
+	defer a.configMu.Unlock()
+
+	if profile == nil {
+		if a.profile != nil {
+			if err := a.applyConfig(a.local, nil, "No config profile applies any more, using local configuration"); err != nil {
+				return "", err
+			}
+		}
+		return "", nil
+	}
+
+	if a.profile != nil && a.profile.Revision == profile.Revision {
+		return profile.Revision, nil
+	}
+	if err := a.applyConfig(a.local, profile, "Applied config profile "+profile.Revision); err != nil {
+		return "", fmt.Errorf("config profile %s rejected: %w", profile.Revision, err)
+	}
+	return profile.Revision, nil
+}
+
+// applyConfig makes local overlaid with profile the running configuration and
+// logs what changed under summary. Nothing changes when the result is invalid.
+// configMu must be held.
+func (a *Agent) applyConfig(local *config.Config, profile *config.Profile, summary string) error {
+	current := a.config.Load()
+	next := local.WithProfile(profile)
+	if err := next.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if err := a.collector.Configure(collectorSettings(next)); err != nil {
+		return fmt.Errorf("failed to configure collectors: %w", err)
+	}
+
+	a.local = local
+	a.profile = profile
+	a.config.Store(next)
+	a.client.SetConfig(next)
+	select {
+	case a.reloaded <- struct{}{}:
+	default:
+	}
+
+	log.Printf("✓ %s", summary)
+	if next.MetricsInterval != current.MetricsInterval {
+		log.Printf("  Metrics Interval: %v", next.MetricsInterval)
+	}
+	if !maps.Equal(next.Metadata, current.Metadata) {
+		log.Printf("  Metadata: %v", next.Metadata)
+	}
+	log.Printf("  Collector plugins: %v", a.collector.Plugins())
+	if next.BackendAddr != current.BackendAddr || next.BackendTLS != current.BackendTLS ||
+		next.TLSCAFile != current.TLSCAFile || next.TLSCertFile != current.TLSCertFile ||
+		next.TLSKeyFile != current.TLSKeyFile || next.TLSServerName != current.TLSServerName {
+		log.Printf("  Backend: %s (TLS: %v), applies on the next reconnect", next.BackendAddr, next.BackendTLS)
+	}
+
+	return nil
+}
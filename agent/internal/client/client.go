@@ -3,72 +3,202 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 
 	"smart-agent/internal/collector"
 	"smart-agent/internal/config"
 	"smart-agent/internal/identity"
+	"smart-agent/internal/spool"
+	diskpb "smart-monitor/pbtypes/disk"
 	pb "smart-monitor/pbtypes/monitor"
+	netpb "smart-monitor/pbtypes/network"
 )
 
+// deniedError is a registration the backend refused
+type deniedError struct {
+	message string
+}
+
+func (e *deniedError) Error() string {
+	return e.message
+}
+
+// IsDenied reports whether the backend refuses the agent, for instance because
+// it is blocked or revoked or lacks an enrollment token. Retrying soon does not help.
+func IsDenied(err error) bool {
+	var denied *deniedError
+	return errors.As(err, &denied) || status.Code(err) == codes.PermissionDenied
+}
+
+// IsUnauthenticated reports whether the backend no longer accepts the agent's
+// credentials, for instance because they expired or the agent was deleted.
+// The agent should register again.
+func IsUnauthenticated(err error) bool {
+	return status.Code(err) == codes.Unauthenticated
+}
+
 // Client handles communication with backend
 type Client struct {
-	config      *config.Config
+	config      atomic.Pointer[config.Config] // replaced when the configuration is reloaded
 	identity    *identity.Manager
-	collector   *collector.Collector
-	conn        *grpc.ClientConn
-	grpcClient  pb.MonitorServiceClient
-	credentials *identity.Credentials
+	spool       *spool.Spool
+	credentials atomic.Pointer[identity.Credentials] // replaced when the token is renewed
+
+	mu         sync.RWMutex // guards conn and grpcClient, which are replaced on reconnect
+	conn       *grpc.ClientConn
+	grpcClient pb.MonitorServiceClient
+
+	streamMu  sync.Mutex // guards the live metrics stream
+	stream    pb.MonitorService_StreamStatsClient
+	streamErr chan error
 }
 
-// NewClient creates a new backend client
-func NewClient(cfg *config.Config, identityMgr *identity.Manager, metricsCollector *collector.Collector) *Client {
-	return &Client{
-		config:    cfg,
-		identity:  identityMgr,
-		collector: metricsCollector,
+// CommandHandler executes commands pushed by the backend
+type CommandHandler interface {
+	// Execute runs the command and returns a result message
+	Execute(cmd *pb.AgentCommand) (string, error)
+
+	// Completed is called once the result has been reported to the backend
+	Completed(cmd *pb.AgentCommand, err error)
+}
+
+// NewClient creates a new backend client.
+// Samples that cannot be sent are kept in sampleSpool.
+func NewClient(cfg *config.Config, identityMgr *identity.Manager, sampleSpool *spool.Spool) *Client {
+	c := &Client{
+		identity: identityMgr,
+		spool:    sampleSpool,
 	}
+	c.config.Store(cfg)
+	return c
+}
+
+// SetConfig replaces the configuration. Metadata and batch size apply to the
+// next sample; the backend address and TLS settings apply on the next Connect.
+func (c *Client) SetConfig(cfg *config.Config) {
+	c.config.Store(cfg)
 }
 
 // Connect establishes connection to backend
 func (c *Client) Connect(ctx context.Context) error {
-	log.Printf("Connecting to backend at %s...", c.config.BackendAddr)
+	cfg := c.config.Load()
+	log.Printf("Connecting to backend at %s...", cfg.BackendAddr)
+
+	creds, err := c.transportCredentials()
+	if err != nil {
+		return err
+	}
 
-	// TODO: Add TLS support when BackendTLS is true
 	conn, err := grpc.DialContext(
 		ctx,
-		c.config.BackendAddr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		cfg.BackendAddr,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithPerRPCCredentials(agentCredentials{client: c}),
 		grpc.WithBlock(),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to connect: %w", err)
 	}
 
+	c.mu.Lock()
+	if c.conn != nil {
+		c.conn.Close()
+	}
 	c.conn = conn
 	c.grpcClient = pb.NewMonitorServiceClient(conn)
+	c.mu.Unlock()
 	log.Printf("✓ Connected to backend")
 
 	return nil
 }
 
+// transportCredentials returns TLS credentials when BackendTLS is set
+func (c *Client) transportCredentials() (credentials.TransportCredentials, error) {
+	cfg := c.config.Load()
+	if !cfg.BackendTLS {
+		return insecure.NewCredentials(), nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.TLSServerName,
+	}
+
+	if cfg.TLSCAFile != "" {
+		data, err := os.ReadFile(cfg.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.TLSCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
 // Register registers the agent with backend
 func (c *Client) Register(ctx context.Context) error {
 	log.Println("Registering agent with backend...")
+	cfg := c.config.Load()
+
+	fingerprint, err := c.identity.Fingerprint()
+	if err != nil {
+		log.Printf("Warning: No machine fingerprint, the backend will not recognise this agent if it registers again: %v", err)
+	}
+
+	// The last token issued to this machine, even expired, proves to the
+	// backend that it is the machine registered before
+	var previousToken string
+	if creds := c.credentials.Load(); creds != nil {
+		previousToken = creds.AccessToken
+	} else if creds, err := c.identity.LoadCredentials(); err == nil {
+		previousToken = creds.AccessToken
+	}
 
 	req := &pb.RegisterRequest{
-		Hostname:     c.config.Hostname,
-		IpAddress:    c.config.IPAddress,
-		AgentVersion: c.config.AgentVersion,
-		Metadata:     c.config.Metadata,
+		Hostname:     cfg.Hostname,
+		IpAddress:    cfg.IPAddress,
+		AgentVersion: cfg.AgentVersion,
+		Metadata:     cfg.Metadata,
+		Fingerprint:  fingerprint,
+
+		EnrollmentToken:     cfg.EnrollmentToken,
+		PreviousAccessToken: previousToken,
 	}
 
-	resp, err := c.grpcClient.RegisterAgent(ctx, req)
+	resp, err := c.service().RegisterAgent(ctx, req)
+	if status.Code(err) == codes.PermissionDenied {
+		if cfg.EnrollmentToken == "" {
+			return &deniedError{message: status.Convert(err).Message() + " (set ENROLLMENT_TOKEN)"}
+		}
+		return &deniedError{message: status.Convert(err).Message()}
+	}
 	if err != nil {
 		return fmt.Errorf("registration RPC failed: %w", err)
 	}
@@ -78,22 +208,24 @@ func (c *Client) Register(ctx context.Context) error {
 	}
 
 	// Store credentials
-	c.credentials = &identity.Credentials{
+	creds := &identity.Credentials{
 		AgentID:     resp.AgentId,
 		AccessToken: resp.AccessToken,
 		ExpiresAt:   resp.ExpiresAt,
-		Hostname:    c.config.Hostname,
-		IPAddress:   c.config.IPAddress,
+		IssuedAt:    time.Now().Unix(),
+		Hostname:    cfg.Hostname,
+		IPAddress:   cfg.IPAddress,
 	}
+	c.credentials.Store(creds)
 
 	// Save to disk
-	if err := c.identity.SaveCredentials(c.credentials); err != nil {
+	if err := c.identity.SaveCredentials(creds); err != nil {
 		log.Printf("Warning: Failed to save credentials: %v", err)
 	}
 
 	log.Printf("✓ Agent registered successfully")
-	log.Printf("  Agent ID: %s", c.credentials.AgentID)
-	log.Printf("  Token expires: %s", time.Unix(c.credentials.ExpiresAt, 0).Format(time.RFC3339))
+	log.Printf("  Agent ID: %s", creds.AgentID)
+	log.Printf("  Token expires: %s", time.Unix(creds.ExpiresAt, 0).Format(time.RFC3339))
 
 	return nil
 }
@@ -104,7 +236,7 @@ func (c *Client) LoadOrRegister(ctx context.Context) error {
 	if c.identity.HasValidCredentials() {
 		creds, err := c.identity.LoadCredentials()
 		if err == nil {
-			c.credentials = creds
+			c.credentials.Store(creds)
 			log.Printf("✓ Loaded existing credentials for agent %s", creds.AgentID)
 			return nil
 		}
@@ -114,79 +246,437 @@ func (c *Client) LoadOrRegister(ctx context.Context) error {
 	return c.Register(ctx)
 }
 
-// StreamMetrics streams metrics to backend
+// StreamMetrics replays spooled samples, then keeps a live stream open for the
+// samples passed to Record until the context ends or the stream fails
 func (c *Client) StreamMetrics(ctx context.Context) error {
-	if c.credentials == nil {
+	if c.credentials.Load() == nil {
 		return fmt.Errorf("not registered, credentials missing")
 	}
 
-	log.Println("Starting metrics streaming...")
+	var stream pb.MonitorService_StreamStatsClient
+	streamErr := make(chan error, 1)
+	for stream == nil {
+		if err := c.replaySpool(ctx); err != nil {
+			return err
+		}
 
-	stream, err := c.grpcClient.StreamStats(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to create stream: %w", err)
+		c.streamMu.Lock()
+		// Samples spooled during the replay must be sent before live ones
+		if c.spool.Len() > 0 {
+			c.streamMu.Unlock()
+			continue
+		}
+
+		s, err := c.openStatsStream(ctx)
+		if err != nil {
+			c.streamMu.Unlock()
+			return fmt.Errorf("failed to create stream: %w", err)
+		}
+		stream = s
+		c.stream = s
+		c.streamErr = streamErr
+		c.streamMu.Unlock()
+	}
+
+	log.Printf("📊 Streaming system metrics (interval: %v)...", c.config.Load().MetricsInterval)
+
+	select {
+	case <-ctx.Done():
+		// Graceful shutdown
+		c.streamMu.Lock()
+		c.stream = nil
+		resp, err := stream.CloseAndRecv()
+		c.streamMu.Unlock()
+		if err != nil {
+			log.Printf("Error closing stream: %v", err)
+		} else {
+			log.Printf("Final response: %s", resp.Message)
+		}
+		return ctx.Err()
+
+	case err := <-streamErr:
+		return fmt.Errorf("failed to send: %w", err)
+	}
+}
+
+// Record sends a sample over the live stream, or spools it on disk while the
+// backend is unreachable
+func (c *Client) Record(metrics *collector.Metrics) {
+	cfg := c.config.Load()
+
+	// Build request
+	req := &pb.StatsRequest{
+		Hostname:     cfg.Hostname,
+		IpAddress:    cfg.IPAddress,
+		AgentVersion: cfg.AgentVersion,
+		Cpu:          metrics.CPUPercent,
+		Ram:          metrics.RAMPercent,
+		Disk:         metrics.DiskPercent,
+		Metadata:     cfg.Metadata,
+		CollectedAt:  metrics.Timestamp.UnixMilli(),
+		Extended:     extendedMetrics(metrics),
+
+		ReportIntervalMs: cfg.MetricsInterval.Milliseconds(),
 	}
+	for _, e := range metrics.Errors {
+		req.CollectorErrors = append(req.CollectorErrors, &pb.CollectorError{
+			Collector: e.Plugin,
+			Message:   e.Message,
+		})
+	}
+
+	c.streamMu.Lock()
+	defer c.streamMu.Unlock()
 
-	ticker := time.NewTicker(c.config.MetricsInterval)
-	defer ticker.Stop()
+	if c.stream != nil {
+		err := c.stream.Send(c.identify(req))
+		if err == io.EOF {
+			// The backend ended the stream, its status tells why
+			_, err = c.stream.CloseAndRecv()
+		}
+		if err == nil {
+			log.Printf("✓ Sent [%s]: CPU=%.2f%%, RAM=%.2f%%, Disk=%.2f%%, Load=%.2f",
+				req.AgentId, metrics.CPUPercent, metrics.RAMPercent, metrics.DiskPercent, req.Extended.Load_1)
+			return
+		}
 
-	log.Printf("📊 Monitoring system metrics (interval: %v)...", c.config.MetricsInterval)
+		log.Printf("Error sending metrics: %v", err)
+		c.stream = nil
+		c.streamErr <- err
+	}
 
+	// The agent ID is added again when the sample is replayed
+	req.AgentId = ""
+	req.AccessToken = ""
+	if err := c.spool.Append(req); err != nil {
+		log.Printf("Failed to spool sample, it is lost: %v", err)
+		return
+	}
+	log.Printf("⏸ Backend unreachable, spooled sample (%d waiting)", c.spool.Len())
+}
+
+// replaySpool sends spooled samples in order, in batches of BatchSize. Each batch
+// goes out as a single batched message on its own stream, so it is only removed
+// once the backend has confirmed it.
+func (c *Client) replaySpool(ctx context.Context) error {
 	for {
-		select {
-		case <-ctx.Done():
-			// Graceful shutdown
-			resp, err := stream.CloseAndRecv()
+		batch, err := c.spool.Next(c.config.Load().BatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to read spool: %w", err)
+		}
+		if batch == nil {
+			return nil
+		}
+
+		if len(batch.Requests) > 0 {
+			stream, err := c.openStatsStream(ctx)
 			if err != nil {
-				log.Printf("Error closing stream: %v", err)
-			} else {
-				log.Printf("Final response: %s", resp.Message)
+				return fmt.Errorf("failed to create replay stream: %w", err)
 			}
-			return ctx.Err()
-
-		case <-ticker.C:
-			if err := c.sendMetrics(stream); err != nil {
-				log.Printf("Error sending metrics: %v", err)
-				return err
+			if err := stream.Send(c.identify(batchRequest(batch.Requests))); err != nil && err != io.EOF {
+				return fmt.Errorf("failed to replay spooled samples: %w", err)
 			}
+			if _, err := stream.CloseAndRecv(); err != nil {
+				return fmt.Errorf("failed to replay spooled samples: %w", err)
+			}
+		}
+
+		if err := c.spool.Ack(batch); err != nil {
+			return err
+		}
+		log.Printf("↻ Replayed %d spooled samples (%d remaining)", len(batch.Requests), c.spool.Len())
+	}
+}
+
+// extendedMetrics converts the metrics sent next to the cpu, ram and disk percentages
+func extendedMetrics(metrics *collector.Metrics) *pb.ExtendedMetrics {
+	extended := &pb.ExtendedMetrics{
+		CpuCores:     int32(metrics.CPUCores),
+		RamTotal:     metrics.RAMTotal,
+		RamUsed:      metrics.RAMUsed,
+		DiskTotal:    metrics.DiskTotal,
+		DiskUsed:     metrics.DiskUsed,
+		Uptime:       metrics.Uptime,
+		ProcessCount: metrics.ProcessCount,
+		NetworkSent:  metrics.NetworkSent,
+		NetworkRecv:  metrics.NetworkRecv,
+		Custom:       metrics.Custom,
+
+		NetworkSentRate:   metrics.NetworkSentRate,
+		NetworkRecvRate:   metrics.NetworkRecvRate,
+		NetworkErrorsRate: metrics.NetworkErrorsRate,
+		NetworkDropsRate:  metrics.NetworkDropsRate,
+	}
+	if len(metrics.LoadAverage) == 3 {
+		extended.Load_1 = metrics.LoadAverage[0]
+		extended.Load_5 = metrics.LoadAverage[1]
+		extended.Load_15 = metrics.LoadAverage[2]
+	}
+
+	for _, core := range metrics.Cores {
+		extended.Cores = append(extended.Cores, &pb.CPUCoreUsage{
+			Core:    core.Core,
+			Percent: core.Percent,
+		})
+	}
+	for _, p := range metrics.Partitions {
+		extended.Partitions = append(extended.Partitions, &diskpb.DiskPartition{
+			MountPoint:        p.MountPoint,
+			Device:            p.Device,
+			Fstype:            p.FSType,
+			Total:             float64(p.Total),
+			Used:              float64(p.Used),
+			Free:              float64(p.Free),
+			UsedPercent:       p.UsedPercent,
+			InodesTotal:       p.InodesTotal,
+			InodesUsed:        p.InodesUsed,
+			InodesFree:        p.InodesFree,
+			InodesUsedPercent: p.InodesUsedPercent,
+		})
+	}
+	for _, nic := range metrics.Interfaces {
+		extended.Interfaces = append(extended.Interfaces, &netpb.NetworkInterface{
+			Name:      nic.Name,
+			Ip:        nic.IP,
+			TxBytes:   float64(nic.BytesSent),
+			RxBytes:   float64(nic.BytesRecv),
+			TxPackets: nic.PacketsSent,
+			RxPackets: nic.PacketsRecv,
+			RxErrors:  nic.ErrorsIn,
+			TxErrors:  nic.ErrorsOut,
+			RxDropped: nic.DropsIn,
+			TxDropped: nic.DropsOut,
+
+			TxBytesPerSec:   nic.Rates.BytesSent,
+			RxBytesPerSec:   nic.Rates.BytesRecv,
+			TxPacketsPerSec: nic.Rates.PacketsSent,
+			RxPacketsPerSec: nic.Rates.PacketsRecv,
+			RxErrorsPerSec:  nic.Rates.ErrorsIn,
+			TxErrorsPerSec:  nic.Rates.ErrorsOut,
+			RxDroppedPerSec: nic.Rates.DropsIn,
+			TxDroppedPerSec: nic.Rates.DropsOut,
+		})
+	}
+
+	return extended
+}
+
+// batchRequest folds spooled samples into one batched request that carries the
+// identity of the most recent sample
+func batchRequest(reqs []*pb.StatsRequest) *pb.StatsRequest {
+	last := reqs[len(reqs)-1]
+	batch := &pb.StatsRequest{
+		Hostname:     last.Hostname,
+		IpAddress:    last.IpAddress,
+		AgentVersion: last.AgentVersion,
+		Metadata:     last.Metadata,
+		Samples:      make([]*pb.StatsSample, 0, len(reqs)),
+
+		ReportIntervalMs: last.ReportIntervalMs,
+	}
+	for _, req := range reqs {
+		batch.Samples = append(batch.Samples, &pb.StatsSample{
+			Cpu:             req.Cpu,
+			Ram:             req.Ram,
+			Disk:            req.Disk,
+			CollectedAt:     req.CollectedAt,
+			Extended:        req.Extended,
+			CollectorErrors: req.CollectorErrors,
+		})
+	}
+	return batch
+}
+
+// identify sets the current agent ID on a sample
+func (c *Client) identify(req *pb.StatsRequest) *pb.StatsRequest {
+	if creds := c.credentials.Load(); creds != nil {
+		req.AgentId = creds.AgentID
+	}
+	return req
+}
+
+// openStatsStream opens a stats stream and waits until the backend accepted
+// the agent's credentials, so a rejection is reported here rather than by
+// the first send
+func (c *Client) openStatsStream(ctx context.Context) (pb.MonitorService_StreamStatsClient, error) {
+	stream, err := c.service().StreamStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if header, _ := stream.Header(); header == nil {
+		// The backend ended the stream before accepting it
+		if _, err := stream.CloseAndRecv(); err != nil {
+			return nil, err
 		}
+		return nil, errors.New("stream closed by backend")
 	}
+	return stream, nil
 }
 
-// sendMetrics collects and sends metrics
-func (c *Client) sendMetrics(stream pb.MonitorService_StreamStatsClient) error {
-	// Collect metrics
-	metrics, err := c.collector.Collect()
+// RenewToken exchanges the current access token for a new one and uses it for
+// all further requests. Saving it is left to the caller.
+func (c *Client) RenewToken(ctx context.Context) (*identity.Credentials, error) {
+	creds := c.credentials.Load()
+	if creds == nil {
+		return nil, fmt.Errorf("not registered, credentials missing")
+	}
+
+	resp, err := c.service().RenewToken(ctx, &pb.RenewTokenRequest{
+		AgentId:     creds.AgentID,
+		AccessToken: creds.AccessToken,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to collect metrics: %w", err)
+		return nil, fmt.Errorf("failed to renew token: %w", err)
 	}
 
-	// Build request
-	req := &pb.StatsRequest{
-		Hostname:     c.config.Hostname,
-		AgentId:      c.credentials.AgentID,
-		IpAddress:    c.config.IPAddress,
-		AgentVersion: c.config.AgentVersion,
-		AccessToken:  c.credentials.AccessToken,
-		Cpu:          metrics.CPUPercent,
-		Ram:          metrics.RAMPercent,
-		Disk:         metrics.DiskPercent,
-		Metadata:     c.config.Metadata,
+	renewed := *creds
+	renewed.AccessToken = resp.AccessToken
+	renewed.ExpiresAt = resp.ExpiresAt
+	renewed.IssuedAt = time.Now().Unix()
+	c.credentials.Store(&renewed)
+
+	return &renewed, nil
+}
+
+// FetchConfig retrieves the central config profile that applies to the agent.
+// It returns nil when no profile applies and the local configuration stands.
+func (c *Client) FetchConfig(ctx context.Context) (*config.Profile, error) {
+	creds := c.credentials.Load()
+	if creds == nil {
+		return nil, fmt.Errorf("not registered, credentials missing")
 	}
 
-	// Send to backend
-	if err := stream.Send(req); err != nil {
-		return fmt.Errorf("failed to send: %w", err)
+	resp, err := c.service().GetAgentConfig(ctx, &pb.AgentConfigRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch config: %w", err)
+	}
+	if !resp.Managed {
+		return nil, nil
+	}
+
+	settings := resp.GetSettings()
+	profile := &config.Profile{
+		Revision:        resp.Revision,
+		MetricsInterval: time.Duration(settings.GetMetricsIntervalSeconds()) * time.Second,
+		Metadata:        settings.GetMetadata(),
+		Collectors:      make(map[string]config.CollectorConfig, len(settings.GetCollectors())),
 	}
+	for name, collector := range settings.GetCollectors() {
+		profile.Collectors[name] = config.CollectorConfig{
+			Enabled:  collector.GetEnabled(),
+			Interval: time.Duration(collector.GetIntervalSeconds()) * time.Second,
+		}
+	}
+
+	return profile, nil
+}
+
+// RunCommands opens the command channel and executes commands pushed by the
+// backend until the stream ends. Each command is acknowledged as delivered on
+// receipt and as executed or failed once the handler returns.
+func (c *Client) RunCommands(ctx context.Context, handler CommandHandler) error {
+	creds := c.credentials.Load()
+	if creds == nil {
+		return fmt.Errorf("not registered, credentials missing")
+	}
+
+	stream, err := c.service().CommandStream(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open command channel: %w", err)
+	}
+
+	// Wait until the backend accepted the agent's credentials
+	if header, _ := stream.Header(); header == nil {
+		if _, err := stream.Recv(); err != nil && err != io.EOF {
+			return fmt.Errorf("failed to open command channel: %w", err)
+		}
+		return errors.New("command channel closed by backend")
+	}
+
+	log.Println("✓ Command channel opened")
+
+	for {
+		cmd, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("command channel closed: %w", err)
+		}
+
+		log.Printf("⚡ Received command %s: %s (reason: %s)", cmd.CommandId, cmd.Action, cmd.Reason)
+		if err := c.ack(stream, creds.AgentID, cmd, pb.CommandStatus_COMMAND_STATUS_DELIVERED, ""); err != nil {
+			return err
+		}
+
+		message, execErr := handler.Execute(cmd)
+		ackStatus := pb.CommandStatus_COMMAND_STATUS_EXECUTED
+		if execErr != nil {
+			ackStatus = pb.CommandStatus_COMMAND_STATUS_FAILED
+			message = execErr.Error()
+			log.Printf("Command %s failed: %v", cmd.CommandId, execErr)
+		}
+		if err := c.ack(stream, creds.AgentID, cmd, ackStatus, message); err != nil {
+			return err
+		}
 
-	log.Printf("✓ Sent [%s]: CPU=%.2f%%, RAM=%.2f%%, Disk=%.2f%%",
-		c.credentials.AgentID, metrics.CPUPercent, metrics.RAMPercent, metrics.DiskPercent)
+		handler.Completed(cmd, execErr)
+	}
+}
 
+// ack reports the status of a command to the backend
+func (c *Client) ack(stream pb.MonitorService_CommandStreamClient, agentID string, cmd *pb.AgentCommand, status pb.CommandStatus, message string) error {
+	err := stream.Send(&pb.CommandStreamRequest{
+		AgentId: agentID,
+		Ack: &pb.CommandAck{
+			CommandId: cmd.CommandId,
+			Status:    status,
+			Message:   message,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to acknowledge command %s: %w", cmd.CommandId, err)
+	}
 	return nil
 }
 
+// agentCredentials adds the current agent credentials to the metadata of
+// every call, once the agent is registered
+type agentCredentials struct {
+	client *Client
+}
+
+// GetRequestMetadata returns the authorization and agent ID metadata
+func (a agentCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	creds := a.client.credentials.Load()
+	if creds == nil {
+		return nil, nil
+	}
+	return map[string]string{
+		"authorization": "Bearer " + creds.AccessToken,
+		"x-agent-id":    creds.AgentID,
+	}, nil
+}
+
+// RequireTransportSecurity reports false, TLS to the backend is optional
+func (a agentCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// service returns the client of the current connection
+func (c *Client) service() pb.MonitorServiceClient {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.grpcClient
+}
+
 // Close closes the connection
 func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	if c.conn != nil {
 		return c.conn.Close()
 	}
@@ -195,5 +685,5 @@ func (c *Client) Close() error {
 
 // GetCredentials returns current credentials
 func (c *Client) GetCredentials() *identity.Credentials {
-	return c.credentials
+	return c.credentials.Load()
 }
@@ -0,0 +1,163 @@
+package collector
+
+import (
+	"context"
+
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+// Built-in plugins, run in this order
+func init() {
+	Register("cpu", func() Plugin { return &cpuPlugin{} })
+	Register("memory", func() Plugin { return memoryPlugin{} })
+	Register("disk", func() Plugin { return diskPlugin{path: "/"} })
+	Register("partitions", func() Plugin { return partitionsPlugin{} })
+	Register("load", func() Plugin { return loadPlugin{} })
+	Register("host", func() Plugin { return hostPlugin{} })
+	Register("network", func() Plugin { return &networkPlugin{} })
+}
+
+// memoryPlugin collects RAM usage
+type memoryPlugin struct{}
+
+func (memoryPlugin) Name() string { return "memory" }
+
+func (memoryPlugin) Collect(ctx context.Context, metrics *Metrics) error {
+	memInfo, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	metrics.RAMPercent = memInfo.UsedPercent
+	metrics.RAMTotal = memInfo.Total
+	metrics.RAMUsed = memInfo.Used
+
+	return nil
+}
+
+func (memoryPlugin) Apply(sample, result *Metrics) {
+	sample.RAMPercent = result.RAMPercent
+	sample.RAMTotal = result.RAMTotal
+	sample.RAMUsed = result.RAMUsed
+}
+
+// diskPlugin collects usage of the filesystem holding path
+type diskPlugin struct {
+	path string
+}
+
+func (diskPlugin) Name() string { return "disk" }
+
+func (p diskPlugin) Collect(ctx context.Context, metrics *Metrics) error {
+	diskInfo, err := disk.UsageWithContext(ctx, p.path)
+	if err != nil {
+		return err
+	}
+
+	metrics.DiskPercent = diskInfo.UsedPercent
+	metrics.DiskTotal = diskInfo.Total
+	metrics.DiskUsed = diskInfo.Used
+
+	return nil
+}
+
+func (diskPlugin) Apply(sample, result *Metrics) {
+	sample.DiskPercent = result.DiskPercent
+	sample.DiskTotal = result.DiskTotal
+	sample.DiskUsed = result.DiskUsed
+}
+
+// partitionsPlugin collects usage and inodes of every mounted filesystem
+type partitionsPlugin struct{}
+
+func (partitionsPlugin) Name() string { return "partitions" }
+
+func (partitionsPlugin) Collect(ctx context.Context, metrics *Metrics) error {
+	partitions, err := disk.PartitionsWithContext(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	for _, p := range partitions {
+		if seen[p.Mountpoint] {
+			continue
+		}
+		seen[p.Mountpoint] = true
+
+		usage, err := disk.UsageWithContext(ctx, p.Mountpoint)
+		if err != nil || usage.Total == 0 {
+			// Unreadable or pseudo filesystem
+			continue
+		}
+
+		metrics.Partitions = append(metrics.Partitions, PartitionUsage{
+			MountPoint:        p.Mountpoint,
+			Device:            p.Device,
+			FSType:            p.Fstype,
+			Total:             usage.Total,
+			Used:              usage.Used,
+			Free:              usage.Free,
+			UsedPercent:       usage.UsedPercent,
+			InodesTotal:       usage.InodesTotal,
+			InodesUsed:        usage.InodesUsed,
+			InodesFree:        usage.InodesFree,
+			InodesUsedPercent: usage.InodesUsedPercent,
+		})
+	}
+
+	return nil
+}
+
+func (partitionsPlugin) Apply(sample, result *Metrics) {
+	sample.Partitions = result.Partitions
+}
+
+// loadPlugin collects the system load average
+type loadPlugin struct{}
+
+func (loadPlugin) Name() string { return "load" }
+
+func (loadPlugin) Collect(ctx context.Context, metrics *Metrics) error {
+	loadInfo, err := load.AvgWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	metrics.LoadAverage = []float64{
+		loadInfo.Load1,
+		loadInfo.Load5,
+		loadInfo.Load15,
+	}
+
+	return nil
+}
+
+func (loadPlugin) Apply(sample, result *Metrics) {
+	sample.LoadAverage = result.LoadAverage
+}
+
+// hostPlugin collects uptime and the process count
+type hostPlugin struct{}
+
+func (hostPlugin) Name() string { return "host" }
+
+func (hostPlugin) Collect(ctx context.Context, metrics *Metrics) error {
+	hostInfo, err := host.InfoWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	metrics.Uptime = hostInfo.Uptime
+	metrics.ProcessCount = hostInfo.Procs
+
+	return nil
+}
+
+func (hostPlugin) Apply(sample, result *Metrics) {
+	sample.Uptime = result.Uptime
+	sample.ProcessCount = result.ProcessCount
+}
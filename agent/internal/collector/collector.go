@@ -1,16 +1,17 @@
-// Package collector handles system metrics collection
+// Package collector handles system metrics collection.
+//
+// Metrics are gathered by plugins. The built-in plugins cover CPU, memory,
+// disk, partitions, load, host and network; other packages can add their own
+// with Register, typically from an init function.
 package collector
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"sort"
+	"sync"
 	"time"
-
-	"github.com/shirou/gopsutil/v3/cpu"
-	"github.com/shirou/gopsutil/v3/disk"
-	"github.com/shirou/gopsutil/v3/host"
-	"github.com/shirou/gopsutil/v3/load"
-	"github.com/shirou/gopsutil/v3/mem"
-	"github.com/shirou/gopsutil/v3/net"
 )
 
 // Metrics holds collected system metrics
@@ -30,158 +31,288 @@ type Metrics struct {
 	Uptime       uint64
 	ProcessCount uint64
 
-	// Network metrics
-	NetworkSent uint64
-	NetworkRecv uint64
+	// Network metrics; the rates cover all interfaces except loopback
+	NetworkSent       uint64
+	NetworkRecv       uint64
+	NetworkSentRate   float64 // bytes/sec
+	NetworkRecvRate   float64 // bytes/sec
+	NetworkErrorsRate float64 // errors/sec, in and out
+	NetworkDropsRate  float64 // dropped packets/sec, in and out
+
+	// Labelled series
+	Cores      []CoreUsage
+	Partitions []PartitionUsage
+	Interfaces []InterfaceCounters
+
+	// Custom holds values reported by additional plugins, keyed by metric name
+	Custom map[string]float64
+
+	// Errors lists the plugins that failed while taking this sample
+	Errors []PluginError
 
 	Timestamp time.Time
 }
 
-// Collector collects system metrics
-type Collector struct {
-	// Configuration
-	diskPath string
+// CoreUsage is the utilisation of one logical core
+type CoreUsage struct {
+	Core    string
+	Percent float64
 }
 
-// NewCollector creates a new metrics collector
-func NewCollector(diskPath string) *Collector {
-	if diskPath == "" {
-		diskPath = "/"
-	}
-	return &Collector{
-		diskPath: diskPath,
-	}
+// PartitionUsage is the usage of one mounted filesystem
+type PartitionUsage struct {
+	MountPoint        string
+	Device            string
+	FSType            string
+	Total             uint64
+	Used              uint64
+	Free              uint64
+	UsedPercent       float64
+	InodesTotal       uint64
+	InodesUsed        uint64
+	InodesFree        uint64
+	InodesUsedPercent float64
 }
 
-// Collect gathers current system metrics
-func (c *Collector) Collect() (*Metrics, error) {
-	metrics := &Metrics{
-		Timestamp: time.Now(),
-	}
+// InterfaceCounters holds the counters of one network interface since boot,
+// and their rates per second since the previous sample
+type InterfaceCounters struct {
+	Name        string
+	IP          string
+	Loopback    bool
+	BytesSent   uint64
+	BytesRecv   uint64
+	PacketsSent uint64
+	PacketsRecv uint64
+	ErrorsIn    uint64
+	ErrorsOut   uint64
+	DropsIn     uint64
+	DropsOut    uint64
+
+	Rates InterfaceRates
+}
 
-	// CPU metrics
-	if err := c.collectCPU(metrics); err != nil {
-		return nil, fmt.Errorf("failed to collect CPU metrics: %w", err)
-	}
+// InterfaceRates are per-second rates of the interface counters. They are
+// zero on the first sample of an interface.
+type InterfaceRates struct {
+	BytesSent   float64
+	BytesRecv   float64
+	PacketsSent float64
+	PacketsRecv float64
+	ErrorsIn    float64
+	ErrorsOut   float64
+	DropsIn     float64
+	DropsOut    float64
+}
 
-	// Memory metrics
-	if err := c.collectMemory(metrics); err != nil {
-		return nil, fmt.Errorf("failed to collect memory metrics: %w", err)
+// SetCustom sets a value reported by an additional plugin
+func (m *Metrics) SetCustom(name string, value float64) {
+	if m.Custom == nil {
+		m.Custom = make(map[string]float64)
 	}
+	m.Custom[name] = value
+}
 
-	// Disk metrics
-	if err := c.collectDisk(metrics); err != nil {
-		return nil, fmt.Errorf("failed to collect disk metrics: %w", err)
-	}
+// PluginError reports a plugin that failed to collect
+type PluginError struct {
+	Plugin  string
+	Message string
+}
 
-	// Load average
-	if err := c.collectLoadAverage(metrics); err != nil {
-		// Non-critical, log but continue
-		metrics.LoadAverage = []float64{0, 0, 0}
-	}
+// Plugin collects one group of metrics
+type Plugin interface {
+	// Name identifies the plugin in configuration and error reports
+	Name() string
 
-	// Host info
-	if err := c.collectHostInfo(metrics); err != nil {
-		// Non-critical
-	}
+	// Collect fills the fields of metrics the plugin is responsible for
+	Collect(ctx context.Context, metrics *Metrics) error
 
-	// Network metrics
-	if err := c.collectNetwork(metrics); err != nil {
-		// Non-critical
-	}
+	// Apply copies the fields the plugin is responsible for from result, the
+	// metrics of its last successful run, into sample
+	Apply(sample, result *Metrics)
+}
 
-	return metrics, nil
+// Factory creates a plugin instance
+type Factory func() Plugin
+
+// Settings configures a registered plugin
+type Settings struct {
+	Enabled  bool
+	Interval time.Duration // how often the plugin runs, 0 means every sample
 }
 
-// collectCPU collects CPU metrics
-func (c *Collector) collectCPU(metrics *Metrics) error {
-	// CPU usage percentage
-	cpuPercent, err := cpu.Percent(time.Second, false)
-	if err != nil {
-		return err
+var (
+	registryMu sync.Mutex
+	registry   = make(map[string]Factory)
+	registered []string // registration order
+)
+
+// Register makes a plugin available to collectors. It panics if the name is
+// already taken, like database/sql drivers.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if factory == nil {
+		panic("collector: Register factory is nil")
 	}
-	if len(cpuPercent) > 0 {
-		metrics.CPUPercent = cpuPercent[0]
+	if _, exists := registry[name]; exists {
+		panic("collector: Register called twice for plugin " + name)
 	}
+	registry[name] = factory
+	registered = append(registered, name)
+}
 
-	// CPU cores
-	cores, err := cpu.Counts(true)
-	if err != nil {
-		return err
-	}
-	metrics.CPUCores = cores
+// Registered returns the names of all registered plugins in registration order
+func Registered() []string {
+	registryMu.Lock()
+	defer registryMu.Unlock()
 
-	return nil
+	return append([]string(nil), registered...)
 }
 
-// collectMemory collects memory metrics
-func (c *Collector) collectMemory(metrics *Metrics) error {
-	memInfo, err := mem.VirtualMemory()
-	if err != nil {
-		return err
-	}
-
-	metrics.RAMPercent = memInfo.UsedPercent
-	metrics.RAMTotal = memInfo.Total
-	metrics.RAMUsed = memInfo.Used
+// Collector runs the enabled plugins and assembles their results into samples
+type Collector struct {
+	mu      sync.Mutex // guards plugins, which Configure replaces
+	plugins []*pluginRunner
+}
 
-	return nil
+// pluginRunner schedules one plugin and keeps its latest result
+type pluginRunner struct {
+	name     string // registered name
+	plugin   Plugin
+	interval time.Duration
+	lastRun  time.Time
+	last     *Metrics // result of the last run, nil if it failed
+	err      error    // error of the last run
 }
 
-// collectDisk collects disk metrics
-func (c *Collector) collectDisk(metrics *Metrics) error {
-	diskInfo, err := disk.Usage(c.diskPath)
-	if err != nil {
-		return err
+// NewCollector creates a collector running every registered plugin.
+// settings enables, disables or slows down plugins by name; plugins without
+// settings run for every sample.
+func NewCollector(settings map[string]Settings) (*Collector, error) {
+	c := &Collector{}
+	if err := c.Configure(settings); err != nil {
+		return nil, err
 	}
+	return c, nil
+}
 
-	metrics.DiskPercent = diskInfo.UsedPercent
-	metrics.DiskTotal = diskInfo.Total
-	metrics.DiskUsed = diskInfo.Used
+// Configure applies new plugin settings. Plugins that stay enabled keep their
+// state and last result; on error the current settings are left untouched.
+func (c *Collector) Configure(settings map[string]Settings) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	var unknown []string
+	for name := range settings {
+		if _, exists := registry[name]; !exists {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown collector plugins: %v (registered: %v)", unknown, registered)
+	}
 
-	return nil
-}
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-// collectLoadAverage collects system load average
-func (c *Collector) collectLoadAverage(metrics *Metrics) error {
-	loadInfo, err := load.Avg()
-	if err != nil {
-		return err
+	current := make(map[string]*pluginRunner, len(c.plugins))
+	for _, r := range c.plugins {
+		current[r.name] = r
 	}
 
-	metrics.LoadAverage = []float64{
-		loadInfo.Load1,
-		loadInfo.Load5,
-		loadInfo.Load15,
+	var plugins []*pluginRunner
+	for _, name := range registered {
+		s, configured := settings[name]
+		if configured && !s.Enabled {
+			continue
+		}
+		if s.Interval < 0 {
+			return fmt.Errorf("collector plugin %s: interval must not be negative", name)
+		}
+
+		r, exists := current[name]
+		if !exists {
+			r = &pluginRunner{name: name, plugin: registry[name]()}
+		}
+		r.interval = s.Interval
+		plugins = append(plugins, r)
 	}
+	c.plugins = plugins
 
 	return nil
 }
 
-// collectHostInfo collects host information
-func (c *Collector) collectHostInfo(metrics *Metrics) error {
-	hostInfo, err := host.Info()
-	if err != nil {
-		return err
+// Plugins returns the names of the enabled plugins
+func (c *Collector) Plugins() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names := make([]string, 0, len(c.plugins))
+	for _, r := range c.plugins {
+		names = append(names, r.plugin.Name())
 	}
+	return names
+}
 
-	metrics.Uptime = hostInfo.Uptime
-	metrics.ProcessCount = hostInfo.Procs
+// Collect takes a sample. Each plugin that is due runs; the others contribute
+// the result of their last run. A plugin whose last run failed contributes no
+// fields and is listed in the sample's Errors until it succeeds again.
+func (c *Collector) Collect(ctx context.Context) *Metrics {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	metrics := &Metrics{Timestamp: now}
+
+	for _, r := range c.plugins {
+		if r.lastRun.IsZero() || now.Sub(r.lastRun) >= r.interval {
+			r.run(ctx, now)
+		}
+
+		if r.last != nil {
+			r.plugin.Apply(metrics, r.last)
+		}
+		if r.err != nil {
+			metrics.Errors = append(metrics.Errors, PluginError{
+				Plugin:  r.plugin.Name(),
+				Message: r.err.Error(),
+			})
+		}
+	}
 
-	return nil
+	return metrics
 }
 
-// collectNetwork collects network metrics
-func (c *Collector) collectNetwork(metrics *Metrics) error {
-	netIO, err := net.IOCounters(false)
+// run executes the plugin, turning a panic into an error so that one broken
+// plugin cannot take the agent down
+func (r *pluginRunner) run(ctx context.Context, now time.Time) {
+	r.lastRun = now
+
+	result := &Metrics{}
+	err := func() (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = fmt.Errorf("panic: %v", p)
+			}
+		}()
+		return r.plugin.Collect(ctx, result)
+	}()
+
 	if err != nil {
-		return err
+		if r.err == nil || r.err.Error() != err.Error() {
+			log.Printf("⚠ Collector plugin %s failed: %v", r.plugin.Name(), err)
+		}
+		r.err = err
+		r.last = nil
+		return
 	}
 
-	if len(netIO) > 0 {
-		metrics.NetworkSent = netIO[0].BytesSent
-		metrics.NetworkRecv = netIO[0].BytesRecv
+	if r.err != nil {
+		log.Printf("✓ Collector plugin %s recovered", r.plugin.Name())
 	}
-
-	return nil
+	r.err = nil
+	r.last = result
 }
@@ -0,0 +1,87 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakePlugin reports ram and the custom value "fake" unless err is set. It
+// also sets CPUPercent, which it does not own.
+type fakePlugin struct {
+	ram float64
+	err error
+}
+
+func (*fakePlugin) Name() string { return "fake" }
+
+func (p *fakePlugin) Collect(ctx context.Context, metrics *Metrics) error {
+	if p.err != nil {
+		return p.err
+	}
+	metrics.RAMPercent = p.ram
+	metrics.CPUPercent = 99
+	metrics.SetCustom("fake", p.ram)
+	return nil
+}
+
+func (*fakePlugin) Apply(sample, result *Metrics) {
+	sample.RAMPercent = result.RAMPercent
+	sample.SetCustom("fake", result.Custom["fake"])
+}
+
+func TestCollectAppliesOwnFields(t *testing.T) {
+	plugin := &fakePlugin{ram: 40}
+	c := &Collector{plugins: []*pluginRunner{{name: "fake", plugin: plugin}}}
+
+	metrics := c.Collect(context.Background())
+	if metrics.RAMPercent != 40 || metrics.Custom["fake"] != 40 {
+		t.Errorf("sample = %+v, want the plugin's ram and custom value", metrics)
+	}
+	if metrics.CPUPercent != 0 {
+		t.Errorf("CPUPercent = %v, want 0 as the plugin does not own it", metrics.CPUPercent)
+	}
+}
+
+func TestCollectDropsFieldsOfFailedPlugin(t *testing.T) {
+	plugin := &fakePlugin{ram: 40}
+	runner := &pluginRunner{name: "fake", plugin: plugin, interval: time.Hour}
+	c := &Collector{plugins: []*pluginRunner{runner}}
+	ctx := context.Background()
+
+	c.Collect(ctx)
+
+	// Failed: no fields, reported until the plugin succeeds again
+	plugin.err = errors.New("unavailable")
+	for range 2 {
+		runner.lastRun = time.Time{}
+		metrics := c.Collect(ctx)
+		if metrics.RAMPercent != 0 || metrics.Custom != nil {
+			t.Errorf("sample = %+v, want no fields of the failed plugin", metrics)
+		}
+		want := []PluginError{{Plugin: "fake", Message: "unavailable"}}
+		if !slices.Equal(metrics.Errors, want) {
+			t.Errorf("Errors = %v, want %v", metrics.Errors, want)
+		}
+	}
+
+	// Skipped by its interval: the failure is still reported
+	if metrics := c.Collect(ctx); metrics.RAMPercent != 0 || len(metrics.Errors) != 1 {
+		t.Errorf("sample = %+v, want the failure reported and no fields", metrics)
+	}
+
+	plugin.err = nil
+	plugin.ram = 60
+	runner.lastRun = time.Time{}
+	if metrics := c.Collect(ctx); metrics.RAMPercent != 60 || len(metrics.Errors) != 0 {
+		t.Errorf("sample = %+v, want the recovered plugin's fields and no errors", metrics)
+	}
+
+	// Skipped by its interval: the last result is reused
+	plugin.ram = 80
+	if metrics := c.Collect(ctx); metrics.RAMPercent != 60 {
+		t.Errorf("RAMPercent = %v, want 60 from the last run", metrics.RAMPercent)
+	}
+}
@@ -0,0 +1,87 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+)
+
+// cpuPlugin collects total and per-core CPU usage without blocking. It keeps
+// the CPU time counters of the previous run and reports utilisation over the
+// time between two runs, so the result covers the whole sampling interval.
+type cpuPlugin struct {
+	prev []cpu.TimesStat // per-core counters of the previous run
+}
+
+func (*cpuPlugin) Name() string { return "cpu" }
+
+func (p *cpuPlugin) Collect(ctx context.Context, metrics *Metrics) error {
+	times, err := cpu.TimesWithContext(ctx, true)
+	if err != nil {
+		return err
+	}
+	if len(times) == 0 {
+		return fmt.Errorf("no CPU time counters available")
+	}
+
+	// The first run, and a run after cores were added or removed, has no
+	// matching baseline and reports the average since boot
+	prev := p.prev
+	if len(prev) != len(times) {
+		prev = make([]cpu.TimesStat, len(times))
+	}
+	p.prev = times
+
+	var totalBusy, totalAll float64
+	for i, t := range times {
+		all, busy := cpuBusy(t)
+		prevAll, prevBusy := cpuBusy(prev[i])
+		if all < prevAll || busy < prevBusy {
+			// Counters went backwards, fall back to the values since boot
+			prevAll, prevBusy = 0, 0
+		}
+
+		deltaAll, deltaBusy := all-prevAll, busy-prevBusy
+		metrics.Cores = append(metrics.Cores, CoreUsage{
+			Core:    fmt.Sprintf("cpu%d", i),
+			Percent: cpuPercent(deltaBusy, deltaAll),
+		})
+		totalBusy += deltaBusy
+		totalAll += deltaAll
+	}
+
+	metrics.CPUPercent = cpuPercent(totalBusy, totalAll)
+	metrics.CPUCores = len(times)
+
+	return nil
+}
+
+func (*cpuPlugin) Apply(sample, result *Metrics) {
+	sample.CPUPercent = result.CPUPercent
+	sample.CPUCores = result.CPUCores
+	sample.Cores = result.Cores
+}
+
+// cpuBusy returns the total and busy time of a core
+func cpuBusy(t cpu.TimesStat) (all, busy float64) {
+	all = t.Total()
+	if runtime.GOOS == "linux" {
+		// Guest time is already included in user time
+		all -= t.Guest + t.GuestNice
+	}
+	return all, all - t.Idle - t.Iowait
+}
+
+// cpuPercent returns busy time as a percentage of total time
+func cpuPercent(busy, all float64) float64 {
+	if all <= 0 || busy <= 0 {
+		return 0
+	}
+	percent := busy / all * 100
+	if percent > 100 {
+		return 100
+	}
+	return percent
+}
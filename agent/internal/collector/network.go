@@ -0,0 +1,138 @@
+package collector
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+// networkPlugin collects per-interface counters and turns them into rates.
+// It keeps the counters of the previous run to compute bytes, packets, errors
+// and drops per second.
+type networkPlugin struct {
+	prev     map[string]net.IOCountersStat
+	prevTime time.Time
+}
+
+func (*networkPlugin) Name() string { return "network" }
+
+func (p *networkPlugin) Collect(ctx context.Context, metrics *Metrics) error {
+	netIO, err := net.IOCountersWithContext(ctx, true)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	elapsed := now.Sub(p.prevTime).Seconds()
+	current := make(map[string]net.IOCountersStat, len(netIO))
+	addrs, loopbacks := interfaceInfo(ctx)
+
+	for _, nic := range netIO {
+		current[nic.Name] = nic
+		metrics.NetworkSent += nic.BytesSent
+		metrics.NetworkRecv += nic.BytesRecv
+
+		counters := InterfaceCounters{
+			Name:        nic.Name,
+			IP:          addrs[nic.Name],
+			Loopback:    loopbacks[nic.Name],
+			BytesSent:   nic.BytesSent,
+			BytesRecv:   nic.BytesRecv,
+			PacketsSent: nic.PacketsSent,
+			PacketsRecv: nic.PacketsRecv,
+			ErrorsIn:    nic.Errin,
+			ErrorsOut:   nic.Errout,
+			DropsIn:     nic.Dropin,
+			DropsOut:    nic.Dropout,
+		}
+
+		if prev, ok := p.prev[nic.Name]; ok && elapsed > 0 {
+			rate := func(prev, cur uint64) float64 {
+				return float64(counterDelta(prev, cur)) / elapsed
+			}
+			counters.Rates = InterfaceRates{
+				BytesSent:   rate(prev.BytesSent, nic.BytesSent),
+				BytesRecv:   rate(prev.BytesRecv, nic.BytesRecv),
+				PacketsSent: rate(prev.PacketsSent, nic.PacketsSent),
+				PacketsRecv: rate(prev.PacketsRecv, nic.PacketsRecv),
+				ErrorsIn:    rate(prev.Errin, nic.Errin),
+				ErrorsOut:   rate(prev.Errout, nic.Errout),
+				DropsIn:     rate(prev.Dropin, nic.Dropin),
+				DropsOut:    rate(prev.Dropout, nic.Dropout),
+			}
+		}
+
+		if !counters.Loopback {
+			metrics.NetworkSentRate += counters.Rates.BytesSent
+			metrics.NetworkRecvRate += counters.Rates.BytesRecv
+			metrics.NetworkErrorsRate += counters.Rates.ErrorsIn + counters.Rates.ErrorsOut
+			metrics.NetworkDropsRate += counters.Rates.DropsIn + counters.Rates.DropsOut
+		}
+
+		metrics.Interfaces = append(metrics.Interfaces, counters)
+	}
+
+	p.prev = current
+	p.prevTime = now
+	return nil
+}
+
+func (*networkPlugin) Apply(sample, result *Metrics) {
+	sample.NetworkSent = result.NetworkSent
+	sample.NetworkRecv = result.NetworkRecv
+	sample.NetworkSentRate = result.NetworkSentRate
+	sample.NetworkRecvRate = result.NetworkRecvRate
+	sample.NetworkErrorsRate = result.NetworkErrorsRate
+	sample.NetworkDropsRate = result.NetworkDropsRate
+	sample.Interfaces = result.Interfaces
+}
+
+// counterDelta returns how much a counter grew between two readings. A counter
+// that went down either wrapped around 32 bits, as some drivers expose, or was
+// reset when the interface was re-created or the host rebooted; after a reset
+// the counter has grown from zero.
+func counterDelta(prev, cur uint64) uint64 {
+	if cur >= prev {
+		return cur - prev
+	}
+	if prev <= math.MaxUint32 {
+		wrapped := math.MaxUint32 - prev + cur + 1
+		if wrapped < 1<<31 {
+			return wrapped
+		}
+	}
+	return cur
+}
+
+// interfaceInfo returns the first address of each network interface and
+// which interfaces are loopbacks
+func interfaceInfo(ctx context.Context) (map[string]string, map[string]bool) {
+	addrs := make(map[string]string)
+	loopbacks := make(map[string]bool)
+
+	interfaces, err := net.InterfacesWithContext(ctx)
+	if err != nil {
+		return addrs, loopbacks
+	}
+
+	for _, iface := range interfaces {
+		for _, flag := range iface.Flags {
+			if flag == "loopback" {
+				loopbacks[iface.Name] = true
+			}
+		}
+		if len(iface.Addrs) == 0 {
+			continue
+		}
+		addr := iface.Addrs[0].Addr
+		if i := strings.IndexByte(addr, '/'); i >= 0 {
+			addr = addr[:i]
+		}
+		addrs[iface.Name] = addr
+	}
+
+	return addrs, loopbacks
+}
@@ -2,9 +2,17 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"net"
 	"os"
+	"strings"
 	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 // Config holds agent configuration
@@ -13,14 +21,26 @@ type Config struct {
 	BackendAddr string
 	BackendTLS  bool
 
+	// TLS settings, used when BackendTLS is true
+	TLSCAFile     string // CA that signed the backend certificate, system roots when empty
+	TLSCertFile   string // client certificate for mutual TLS
+	TLSKeyFile    string
+	TLSServerName string // overrides the host name checked against the backend certificate
+
 	// Agent identity
-	AgentVersion string
-	Hostname     string
-	IPAddress    string
+	AgentVersion       string
+	Hostname           string
+	IPAddress          string
+	TokenRenewFraction float64 // share of the token lifetime after which it is renewed
+	EnrollmentToken    string  // presented when the machine registers for the first time
 
 	// Monitoring settings
 	MetricsInterval time.Duration
-	BatchSize       int
+	BatchSize       int // spooled samples replayed per batch
+	SpoolMaxSamples int // samples kept on disk while the backend is unreachable
+
+	// Collector plugins by name; plugins not listed run for every sample
+	Collectors map[string]CollectorConfig
 
 	// Storage
 	TokenFile  string
@@ -37,7 +57,14 @@ type Config struct {
 	Metadata map[string]string
 }
 
-// DefaultConfig returns default configuration
+// CollectorConfig configures one collector plugin
+type CollectorConfig struct {
+	Enabled  bool
+	Interval time.Duration // 0 runs the plugin for every sample
+}
+
+// DefaultConfig returns the built-in configuration, before the config file
+// and environment are applied
 func DefaultConfig() *Config {
 	hostname, _ := os.Hostname()
 	if hostname == "" {
@@ -45,27 +72,230 @@ func DefaultConfig() *Config {
 	}
 
 	return &Config{
-		BackendAddr:     getEnv("BACKEND_ADDR", "localhost:50051"),
-		BackendTLS:      getEnvBool("BACKEND_TLS", false),
+		BackendAddr:     "localhost:50051",
 		AgentVersion:    "2.0.0",
 		Hostname:        hostname,
-		MetricsInterval: time.Duration(getEnvInt("METRICS_INTERVAL", 5)) * time.Second,
-		BatchSize:       getEnvInt("BATCH_SIZE", 10),
-		TokenFile:       getEnv("TOKEN_FILE", ".agent_token"),
-		ConfigFile:      getEnv("CONFIG_FILE", "agent.yaml"),
-		LogFile:         getEnv("LOG_FILE", "agent.log"),
-		CacheDir:        getEnv("CACHE_DIR", ".cache"),
-		MaxRetries:      getEnvInt("MAX_RETRIES", 3),
-		RetryInterval:   time.Duration(getEnvInt("RETRY_INTERVAL", 5)) * time.Second,
-		ReconnectDelay:  time.Duration(getEnvInt("RECONNECT_DELAY", 10)) * time.Second,
+		MetricsInterval: 5 * time.Second,
+		BatchSize:       10,
+		SpoolMaxSamples: 17280, // 24h at the default interval
+		Collectors:      make(map[string]CollectorConfig),
+		TokenFile:       ".agent_token",
+		ConfigFile:      "agent.yaml",
+		LogFile:         "agent.log",
+		CacheDir:        ".cache",
+		MaxRetries:      3,
+		RetryInterval:   5 * time.Second,
+		ReconnectDelay:  10 * time.Second,
+
+		TokenRenewFraction: 0.5, // renew halfway through the token lifetime
+
 		Metadata: map[string]string{
-			"environment": getEnv("ENVIRONMENT", "production"),
-			"location":    getEnv("LOCATION", "default"),
-			"datacenter":  getEnv("DATACENTER", "dc-01"),
+			"environment": "production",
+			"location":    "default",
+			"datacenter":  "dc-01",
 		},
 	}
 }
 
+// Load builds the configuration from the built-in defaults, the YAML file at
+// path and the environment, in increasing order of precedence, and validates
+// it. An empty path reads CONFIG_FILE, falling back to agent.yaml. A missing
+// file is not an error; the agent then runs on defaults and environment.
+func Load(path string) (*Config, error) {
+	cfg := DefaultConfig()
+	if path == "" {
+		path = getEnv("CONFIG_FILE", cfg.ConfigFile)
+	}
+	cfg.ConfigFile = path
+
+	if err := cfg.loadFile(path); err != nil {
+		return nil, err
+	}
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// Validate checks that the configuration is usable
+func (c *Config) Validate() error {
+	var errs []error
+
+	if _, _, err := net.SplitHostPort(c.BackendAddr); err != nil {
+		errs = append(errs, fmt.Errorf("backend address %q: %w", c.BackendAddr, err))
+	}
+	if c.BackendTLS {
+		if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+			errs = append(errs, errors.New("tls: cert_file and key_file must be set together"))
+		}
+		for _, file := range []string{c.TLSCAFile, c.TLSCertFile, c.TLSKeyFile} {
+			if file == "" {
+				continue
+			}
+			if _, err := os.Stat(file); err != nil {
+				errs = append(errs, fmt.Errorf("tls: %w", err))
+			}
+		}
+	}
+	if c.TokenRenewFraction <= 0 || c.TokenRenewFraction >= 1 {
+		errs = append(errs, fmt.Errorf("token renew fraction %v must be between 0 and 1", c.TokenRenewFraction))
+	}
+	if c.MetricsInterval < time.Second {
+		errs = append(errs, fmt.Errorf("metrics interval %v must be at least 1s", c.MetricsInterval))
+	}
+	if c.BatchSize <= 0 {
+		errs = append(errs, fmt.Errorf("batch size %d must be positive", c.BatchSize))
+	}
+	if c.SpoolMaxSamples <= 0 {
+		errs = append(errs, fmt.Errorf("spool max samples %d must be positive", c.SpoolMaxSamples))
+	}
+	for name, collector := range c.Collectors {
+		if collector.Interval < 0 {
+			errs = append(errs, fmt.Errorf("collector %s: interval must not be negative", name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// fileConfig is the layout of the YAML config file. Settings left out keep
+// their default.
+type fileConfig struct {
+	Backend struct {
+		Addr string `yaml:"addr"`
+		TLS  struct {
+			Enabled    *bool  `yaml:"enabled"`
+			CAFile     string `yaml:"ca_file"`
+			CertFile   string `yaml:"cert_file"`
+			KeyFile    string `yaml:"key_file"`
+			ServerName string `yaml:"server_name"`
+		} `yaml:"tls"`
+		TokenRenewFraction float64 `yaml:"token_renew_fraction"`
+		EnrollmentToken    string  `yaml:"enrollment_token"`
+	} `yaml:"backend"`
+
+	Metrics struct {
+		Interval        time.Duration `yaml:"interval"`
+		BatchSize       int           `yaml:"batch_size"`
+		SpoolMaxSamples int           `yaml:"spool_max_samples"`
+	} `yaml:"metrics"`
+
+	Metadata map[string]string `yaml:"metadata"`
+
+	Collectors map[string]struct {
+		Enabled  *bool         `yaml:"enabled"`
+		Interval time.Duration `yaml:"interval"`
+	} `yaml:"collectors"`
+}
+
+// loadFile applies the settings of the YAML file at path
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var file fileConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	if file.Backend.Addr != "" {
+		c.BackendAddr = file.Backend.Addr
+	}
+	if file.Backend.TLS.Enabled != nil {
+		c.BackendTLS = *file.Backend.TLS.Enabled
+	}
+	if file.Backend.TLS.CAFile != "" {
+		c.TLSCAFile = file.Backend.TLS.CAFile
+	}
+	if file.Backend.TLS.CertFile != "" {
+		c.TLSCertFile = file.Backend.TLS.CertFile
+	}
+	if file.Backend.TLS.KeyFile != "" {
+		c.TLSKeyFile = file.Backend.TLS.KeyFile
+	}
+	if file.Backend.TLS.ServerName != "" {
+		c.TLSServerName = file.Backend.TLS.ServerName
+	}
+	if file.Backend.TokenRenewFraction != 0 {
+		c.TokenRenewFraction = file.Backend.TokenRenewFraction
+	}
+	if file.Backend.EnrollmentToken != "" {
+		c.EnrollmentToken = file.Backend.EnrollmentToken
+	}
+
+	if file.Metrics.Interval != 0 {
+		c.MetricsInterval = file.Metrics.Interval
+	}
+	if file.Metrics.BatchSize != 0 {
+		c.BatchSize = file.Metrics.BatchSize
+	}
+	if file.Metrics.SpoolMaxSamples != 0 {
+		c.SpoolMaxSamples = file.Metrics.SpoolMaxSamples
+	}
+
+	for key, value := range file.Metadata {
+		c.Metadata[key] = value
+	}
+
+	for name, collector := range file.Collectors {
+		enabled := collector.Enabled == nil || *collector.Enabled
+		c.Collectors[name] = CollectorConfig{Enabled: enabled, Interval: collector.Interval}
+	}
+
+	return nil
+}
+
+// applyEnv overrides settings with the environment variables that are set
+func (c *Config) applyEnv() error {
+	c.BackendAddr = getEnv("BACKEND_ADDR", c.BackendAddr)
+	c.BackendTLS = getEnvBool("BACKEND_TLS", c.BackendTLS)
+	c.TLSCAFile = getEnv("BACKEND_TLS_CA_FILE", c.TLSCAFile)
+	c.TLSCertFile = getEnv("BACKEND_TLS_CERT_FILE", c.TLSCertFile)
+	c.TLSKeyFile = getEnv("BACKEND_TLS_KEY_FILE", c.TLSKeyFile)
+	c.TLSServerName = getEnv("BACKEND_TLS_SERVER_NAME", c.TLSServerName)
+	c.TokenRenewFraction = getEnvFloat("TOKEN_RENEW_FRACTION", c.TokenRenewFraction)
+	c.EnrollmentToken = getEnv("ENROLLMENT_TOKEN", c.EnrollmentToken)
+
+	if os.Getenv("METRICS_INTERVAL") != "" {
+		c.MetricsInterval = time.Duration(getEnvInt("METRICS_INTERVAL", 0)) * time.Second
+	}
+	c.BatchSize = getEnvInt("BATCH_SIZE", c.BatchSize)
+	c.SpoolMaxSamples = getEnvInt("SPOOL_MAX_SAMPLES", c.SpoolMaxSamples)
+
+	collectors, err := collectorsFromEnv()
+	if err != nil {
+		return err
+	}
+	for name, collector := range collectors {
+		c.Collectors[name] = collector
+	}
+
+	c.TokenFile = getEnv("TOKEN_FILE", c.TokenFile)
+	c.LogFile = getEnv("LOG_FILE", c.LogFile)
+	c.CacheDir = getEnv("CACHE_DIR", c.CacheDir)
+
+	c.MaxRetries = getEnvInt("MAX_RETRIES", c.MaxRetries)
+	c.RetryInterval = time.Duration(getEnvInt("RETRY_INTERVAL", int(c.RetryInterval/time.Second))) * time.Second
+	c.ReconnectDelay = time.Duration(getEnvInt("RECONNECT_DELAY", int(c.ReconnectDelay/time.Second))) * time.Second
+
+	c.Metadata["environment"] = getEnv("ENVIRONMENT", c.Metadata["environment"])
+	c.Metadata["location"] = getEnv("LOCATION", c.Metadata["location"])
+	c.Metadata["datacenter"] = getEnv("DATACENTER", c.Metadata["datacenter"])
+
+	return nil
+}
+
 // getEnv gets environment variable with default value
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -85,6 +315,17 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvFloat gets environment variable as float with default value
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		var floatValue float64
+		if _, err := fmt.Sscanf(value, "%g", &floatValue); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 // getEnvBool gets environment variable as bool with default value
 func getEnvBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
@@ -92,3 +333,66 @@ func getEnvBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+// collectorsFromEnv reads plugin settings from COLLECTORS_DISABLED, a comma
+// separated list of plugin names, and COLLECTOR_INTERVALS, a comma separated
+// list of name=duration pairs such as "partitions=1m,network=10s"
+func collectorsFromEnv() (map[string]CollectorConfig, error) {
+	collectors := make(map[string]CollectorConfig)
+
+	for _, pair := range strings.Split(os.Getenv("COLLECTOR_INTERVALS"), ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("COLLECTOR_INTERVALS: expected name=duration, got %q", pair)
+		}
+		interval, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("COLLECTOR_INTERVALS: %w", err)
+		}
+		collectors[strings.TrimSpace(name)] = CollectorConfig{Enabled: true, Interval: interval}
+	}
+
+	for _, name := range strings.Split(os.Getenv("COLLECTORS_DISABLED"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			collectors[name] = CollectorConfig{Enabled: false}
+		}
+	}
+
+	return collectors, nil
+}
+
+// ConfigVersionKey is the metadata label that reports the applied config
+// profile revision to the backend
+const ConfigVersionKey = "config_version"
+
+// Profile holds settings pushed by the backend from a central config profile.
+// They take precedence over the config file and environment.
+type Profile struct {
+	Revision        string        // profile ID and version, e.g. "cfg-1a2b@3"
+	MetricsInterval time.Duration // 0 keeps the local interval
+	Metadata        map[string]string
+	Collectors      map[string]CollectorConfig
+}
+
+// WithProfile returns a copy of the configuration with the profile applied.
+// A nil profile returns the configuration unchanged.
+func (c *Config) WithProfile(p *Profile) *Config {
+	cfg := *c
+	cfg.Metadata = maps.Clone(c.Metadata)
+	cfg.Collectors = maps.Clone(c.Collectors)
+	if p == nil {
+		return &cfg
+	}
+
+	if p.MetricsInterval > 0 {
+		cfg.MetricsInterval = p.MetricsInterval
+	}
+	maps.Copy(cfg.Metadata, p.Metadata)
+	maps.Copy(cfg.Collectors, p.Collectors)
+	cfg.Metadata[ConfigVersionKey] = p.Revision
+
+	return &cfg
+}
@@ -2,23 +2,55 @@
 package identity
 
 import (
+	"context"
+	"crypto/rand"
 	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"net"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
+const (
+	// renewRetryInterval is the delay before retrying a failed renewal
+	renewRetryInterval = time.Minute
+
+	// maxRenewWait bounds how long the renewal loop sleeps, so credentials
+	// replaced by a new registration are picked up
+	maxRenewWait = time.Hour
+
+	// instanceIDFile holds the generated instance ID, next to the token file
+	instanceIDFile = ".agent_instance_id"
+)
+
+// machineIDFiles are read in order for the machine ID set up by the OS
+var machineIDFiles = []string{"/etc/machine-id", "/var/lib/dbus/machine-id"}
+
 // Credentials holds agent authentication information
 type Credentials struct {
 	AgentID     string `json:"agent_id"`
 	AccessToken string `json:"access_token"`
 	ExpiresAt   int64  `json:"expires_at"`
+	IssuedAt    int64  `json:"issued_at,omitempty"`
 	Hostname    string `json:"hostname"`
 	IPAddress   string `json:"ip_address"`
 }
 
+// TokenRenewer exchanges the current access token for a new one
+type TokenRenewer interface {
+	// GetCredentials returns the credentials in use
+	GetCredentials() *Credentials
+
+	// RenewToken obtains a new token from the backend and starts using it
+	RenewToken(ctx context.Context) (*Credentials, error)
+}
+
 // Manager handles agent identity
 type Manager struct {
 	tokenFile string
@@ -38,6 +70,67 @@ func (m *Manager) GenerateAgentID(hostname, ipAddress string) string {
 	return fmt.Sprintf("agent-%x", hash[:8])
 }
 
+// Fingerprint returns a stable identifier of this machine and agent install,
+// which lets the backend recognise the agent when it registers again. It
+// combines the OS machine ID with an instance ID generated on first use and
+// kept next to the token file, and hashes them so the machine ID is not sent.
+func (m *Manager) Fingerprint() (string, error) {
+	instanceID, err := m.instanceID()
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256([]byte("smart-agent:" + machineID() + ":" + instanceID))
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// instanceID loads the instance ID, generating and saving it on first use
+func (m *Manager) instanceID() (string, error) {
+	path := filepath.Join(filepath.Dir(m.tokenFile), instanceIDFile)
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if id := strings.TrimSpace(string(data)); id != "" {
+			return id, nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("failed to read instance ID: %w", err)
+	}
+
+	id, err := newUUID()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate instance ID: %w", err)
+	}
+	if err := writeFileAtomic(path, []byte(id+"\n")); err != nil {
+		return "", fmt.Errorf("failed to save instance ID: %w", err)
+	}
+
+	return id, nil
+}
+
+// machineID returns the machine ID set up by the OS, empty if there is none
+func machineID() string {
+	for _, path := range machineIDFiles {
+		if data, err := os.ReadFile(path); err == nil {
+			if id := strings.TrimSpace(string(data)); id != "" {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+// newUUID generates a random (version 4) UUID
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
 // GetLocalIP returns the local IP address
 func (m *Manager) GetLocalIP() string {
 	addrs, err := net.InterfaceAddrs()
@@ -55,20 +148,44 @@ func (m *Manager) GetLocalIP() string {
 	return "unknown"
 }
 
-// SaveCredentials saves agent credentials to file
+// SaveCredentials saves agent credentials to file. The file is replaced
+// atomically, so a crash never leaves a partially written token behind.
 func (m *Manager) SaveCredentials(creds *Credentials) error {
 	data, err := json.MarshalIndent(creds, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal credentials: %w", err)
 	}
 
-	if err := os.WriteFile(m.tokenFile, data, 0600); err != nil {
+	if err := writeFileAtomic(m.tokenFile, data); err != nil {
 		return fmt.Errorf("failed to write credentials: %w", err)
 	}
 
 	return nil
 }
 
+// writeFileAtomic replaces path with data through a temporary file in the same
+// directory, readable by the owner only
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
 // LoadCredentials loads agent credentials from file
 func (m *Manager) LoadCredentials() (*Credentials, error) {
 	data, err := os.ReadFile(m.tokenFile)
@@ -97,3 +214,48 @@ func (m *Manager) HasValidCredentials() bool {
 	}
 	return !m.IsTokenExpired(creds)
 }
+
+// RenewAt returns when credentials are due for renewal: once fraction of the
+// token lifetime has passed. Credentials without an issue time are due now.
+func (m *Manager) RenewAt(creds *Credentials, fraction float64) time.Time {
+	if creds.IssuedAt == 0 || creds.IssuedAt >= creds.ExpiresAt {
+		return time.Now()
+	}
+
+	lifetime := time.Duration(creds.ExpiresAt-creds.IssuedAt) * time.Second
+	return time.Unix(creds.IssuedAt, 0).Add(time.Duration(float64(lifetime) * fraction))
+}
+
+// KeepTokenFresh renews the access token whenever fraction of its lifetime
+// has passed and saves each new token, until ctx ends. fraction is read again
+// for every renewal. Failed renewals are retried every minute.
+func (m *Manager) KeepTokenFresh(ctx context.Context, renewer TokenRenewer, fraction func() float64) {
+	for {
+		wait := maxRenewWait
+		if creds := renewer.GetCredentials(); creds != nil {
+			wait = time.Until(m.RenewAt(creds, fraction()))
+		}
+
+		if wait <= 0 {
+			creds, err := renewer.RenewToken(ctx)
+			if err == nil {
+				if err := m.SaveCredentials(creds); err != nil {
+					log.Printf("⚠ Failed to save renewed token: %v", err)
+				}
+				log.Printf("✓ Access token renewed, expires %s", time.Unix(creds.ExpiresAt, 0).Format(time.RFC3339))
+				continue
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("⚠ Token renewal failed, retrying in %v: %v", renewRetryInterval, err)
+			wait = renewRetryInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(min(wait, maxRenewWait)):
+		}
+	}
+}
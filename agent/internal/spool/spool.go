@@ -0,0 +1,447 @@
+// Package spool implements a bounded on-disk queue for samples that could not be sent
+package spool
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "smart-monitor/pbtypes/monitor"
+)
+
+const segmentExt = ".spool"
+
+// progressFile records how far the head segment has been acknowledged
+const progressFile = "progress"
+
+// maxRecordSize guards against reading garbage as a record length
+const maxRecordSize = 1 << 20
+
+// Spool is a FIFO queue of stats samples stored in segment files under a directory.
+// Each record is a length-prefixed protobuf StatsRequest. When the queue holds more
+// than maxRecords samples, the oldest segment is dropped. Acknowledged progress
+// through the head segment is saved, so a restart does not replay it.
+type Spool struct {
+	dir         string
+	maxRecords  int
+	segmentSize int
+
+	mu       sync.Mutex
+	segments []*segment // oldest first, the last one is written to
+	tail     *os.File
+}
+
+// segment is one file of the queue
+type segment struct {
+	seq      uint64
+	count    int   // records in the file
+	consumed int   // records already replayed
+	offset   int64 // byte offset of the first unreplayed record
+}
+
+// Batch is a group of samples read from the head of the queue
+type Batch struct {
+	Requests []*pb.StatsRequest
+
+	seq    uint64
+	count  int
+	offset int64
+}
+
+// Open opens or creates a spool in dir
+func Open(dir string, maxRecords, segmentSize int) (*Spool, error) {
+	if segmentSize <= 0 {
+		segmentSize = 100
+	}
+	if maxRecords < segmentSize {
+		maxRecords = segmentSize
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory: %w", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spool directory: %w", err)
+	}
+
+	s := &Spool{
+		dir:         dir,
+		maxRecords:  maxRecords,
+		segmentSize: segmentSize,
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		count, err := s.recover(seq)
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			os.Remove(s.path(seq))
+			continue
+		}
+		s.segments = append(s.segments, &segment{seq: seq, count: count})
+	}
+
+	sort.Slice(s.segments, func(i, j int) bool {
+		return s.segments[i].seq < s.segments[j].seq
+	})
+
+	if err := s.restoreProgress(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Append adds a sample to the end of the queue
+func (s *Spool) Append(req *pb.StatsRequest) error {
+	data, err := proto.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to encode sample: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ensureTail(); err != nil {
+		return err
+	}
+
+	record := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(record, uint32(len(data)))
+	copy(record[4:], data)
+	if _, err := s.tail.Write(record); err != nil {
+		return fmt.Errorf("failed to write sample: %w", err)
+	}
+	s.segments[len(s.segments)-1].count++
+
+	s.enforceLimit()
+	return nil
+}
+
+// Next reads up to n samples from the head of the queue without removing them.
+// A batch never spans segments. It returns nil when the queue is empty.
+func (s *Spool) Next(n int) (*Batch, error) {
+	if n <= 0 {
+		n = 1
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.segments) == 0 {
+		return nil, nil
+	}
+
+	head := s.segments[0]
+	if head.consumed == head.count {
+		return nil, nil
+	}
+
+	f, err := os.Open(s.path(head.seq))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open spool segment: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(head.offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to read spool segment: %w", err)
+	}
+
+	batch := &Batch{seq: head.seq, offset: head.offset}
+	reader := bufio.NewReader(f)
+	for len(batch.Requests) < n && head.consumed+batch.count < head.count {
+		data, size, err := readRecord(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read spool segment: %w", err)
+		}
+		batch.count++
+		batch.offset += size
+
+		req := &pb.StatsRequest{}
+		if err := proto.Unmarshal(data, req); err != nil {
+			log.Printf("⚠ Skipping undecodable spooled sample: %v", err)
+			continue
+		}
+		batch.Requests = append(batch.Requests, req)
+	}
+
+	return batch, nil
+}
+
+// Ack removes a batch returned by Next once it has been delivered
+func (s *Spool) Ack(batch *Batch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.segments) == 0 || s.segments[0].seq != batch.seq {
+		// The segment was dropped while the batch was in flight
+		return nil
+	}
+
+	head := s.segments[0]
+	head.consumed += batch.count
+	head.offset = batch.offset
+
+	if head.consumed < head.count {
+		return s.saveProgress(head)
+	}
+
+	if len(s.segments) == 1 && s.tail != nil {
+		s.tail.Close()
+		s.tail = nil
+	}
+	s.segments = s.segments[1:]
+	return s.removeHead(head.seq)
+}
+
+// Len returns the number of samples waiting to be replayed
+func (s *Spool) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	total := 0
+	for _, seg := range s.segments {
+		total += seg.count - seg.consumed
+	}
+	return total
+}
+
+// Close flushes and closes the segment being written
+func (s *Spool) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.closeTail()
+}
+
+// closeTail flushes the segment being written to disk and closes it
+func (s *Spool) closeTail() error {
+	if s.tail == nil {
+		return nil
+	}
+	err := s.tail.Sync()
+	if closeErr := s.tail.Close(); err == nil {
+		err = closeErr
+	}
+	s.tail = nil
+	if err != nil {
+		return fmt.Errorf("failed to close spool segment: %w", err)
+	}
+	return nil
+}
+
+// ensureTail opens a segment with room for another record
+func (s *Spool) ensureTail() error {
+	if s.tail != nil && s.segments[len(s.segments)-1].count < s.segmentSize {
+		return nil
+	}
+
+	if err := s.closeTail(); err != nil {
+		return err
+	}
+
+	var seq uint64 = 1
+	if len(s.segments) > 0 {
+		last := s.segments[len(s.segments)-1]
+		if last.count < s.segmentSize {
+			// Continue a segment left over from a previous run
+			f, err := os.OpenFile(s.path(last.seq), os.O_WRONLY|os.O_APPEND, 0600)
+			if err != nil {
+				return fmt.Errorf("failed to open spool segment: %w", err)
+			}
+			s.tail = f
+			return nil
+		}
+		seq = last.seq + 1
+	}
+
+	f, err := os.OpenFile(s.path(seq), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create spool segment: %w", err)
+	}
+	s.tail = f
+	s.segments = append(s.segments, &segment{seq: seq})
+	return nil
+}
+
+// enforceLimit drops the oldest segments while the queue is over its limit
+func (s *Spool) enforceLimit() {
+	total := 0
+	for _, seg := range s.segments {
+		total += seg.count - seg.consumed
+	}
+
+	for total > s.maxRecords && len(s.segments) > 1 {
+		oldest := s.segments[0]
+		dropped := oldest.count - oldest.consumed
+		s.segments = s.segments[1:]
+		total -= dropped
+		if err := s.removeHead(oldest.seq); err != nil {
+			log.Printf("⚠ %v", err)
+		}
+		log.Printf("⚠ Spool full, dropped %d oldest samples", dropped)
+	}
+}
+
+// recover counts the records of a segment and truncates a partially written last record
+func (s *Spool) recover(seq uint64) (int, error) {
+	f, err := os.OpenFile(s.path(seq), os.O_RDWR, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open spool segment: %w", err)
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	count := 0
+	var valid int64
+	for {
+		_, size, err := readRecord(reader)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Printf("⚠ Spool segment %d is damaged after %d samples, truncating", seq, count)
+			if err := f.Truncate(valid); err != nil {
+				return 0, fmt.Errorf("failed to truncate spool segment: %w", err)
+			}
+			break
+		}
+		count++
+		valid += size
+	}
+
+	return count, nil
+}
+
+// saveProgress records how many records of the head segment were acknowledged.
+// The file is replaced atomically, so a crash leaves the old or new progress.
+func (s *Spool) saveProgress(head *segment) error {
+	var data [16]byte
+	binary.BigEndian.PutUint64(data[:8], head.seq)
+	binary.BigEndian.PutUint64(data[8:], uint64(head.consumed))
+
+	path := filepath.Join(s.dir, progressFile)
+	f, err := os.OpenFile(path+".tmp", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to save spool progress: %w", err)
+	}
+	_, err = f.Write(data[:])
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(path+".tmp", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save spool progress: %w", err)
+	}
+	return nil
+}
+
+// restoreProgress skips the records of the head segment acknowledged before
+// the spool was last closed. Progress saved for another segment is stale.
+func (s *Spool) restoreProgress() error {
+	data, err := os.ReadFile(filepath.Join(s.dir, progressFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read spool progress: %w", err)
+	}
+	if len(data) != 16 || len(s.segments) == 0 || s.segments[0].seq != binary.BigEndian.Uint64(data[:8]) {
+		return nil
+	}
+
+	head := s.segments[0]
+	consumed := int(binary.BigEndian.Uint64(data[8:]))
+	if consumed >= head.count {
+		// Crashed while removing a fully acknowledged segment
+		s.segments = s.segments[1:]
+		return s.removeHead(head.seq)
+	}
+
+	f, err := os.Open(s.path(head.seq))
+	if err != nil {
+		return fmt.Errorf("failed to open spool segment: %w", err)
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	for head.consumed < consumed {
+		_, size, err := readRecord(reader)
+		if err != nil {
+			return fmt.Errorf("failed to read spool segment: %w", err)
+		}
+		head.consumed++
+		head.offset += size
+	}
+
+	return nil
+}
+
+// removeHead deletes the file of the head segment after it was removed from
+// the queue. Its progress goes first, so it can never apply to a later
+// segment of the same number.
+func (s *Spool) removeHead(seq uint64) error {
+	if err := os.Remove(filepath.Join(s.dir, progressFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove spool progress: %w", err)
+	}
+	if err := os.Remove(s.path(seq)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove spool segment: %w", err)
+	}
+	return nil
+}
+
+// path returns the file of a segment
+func (s *Spool) path(seq uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%012d%s", seq, segmentExt))
+}
+
+// readRecord reads one length-prefixed record and returns it with its size on disk
+func readRecord(r *bufio.Reader) ([]byte, int64, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, 0, errors.New("truncated record header")
+		}
+		return nil, 0, err
+	}
+
+	length := binary.BigEndian.Uint32(header[:])
+	if length > maxRecordSize {
+		return nil, 0, fmt.Errorf("invalid record length %d", length)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, 0, errors.New("truncated record")
+	}
+
+	return data, int64(4 + length), nil
+}
@@ -0,0 +1,337 @@
+package spool
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	pb "smart-monitor/pbtypes/monitor"
+)
+
+// openTestSpool opens a spool in dir, closed when the test ends
+func openTestSpool(t *testing.T, dir string, maxRecords, segmentSize int) *Spool {
+	t.Helper()
+
+	s, err := Open(dir, maxRecords, segmentSize)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// appendSamples appends samples numbered from through to-1 by CollectedAt
+func appendSamples(t *testing.T, s *Spool, from, to int) {
+	t.Helper()
+
+	for i := from; i < to; i++ {
+		if err := s.Append(&pb.StatsRequest{Hostname: "web-01", CollectedAt: int64(i)}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+}
+
+// drain reads and acknowledges every queued sample and returns their numbers
+func drain(t *testing.T, s *Spool) []int64 {
+	t.Helper()
+
+	got := []int64{}
+	for {
+		batch, err := s.Next(4)
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		if batch == nil {
+			return got
+		}
+		for _, req := range batch.Requests {
+			got = append(got, req.CollectedAt)
+		}
+		if err := s.Ack(batch); err != nil {
+			t.Fatalf("Ack() error = %v", err)
+		}
+	}
+}
+
+// numbers returns from through to-1
+func numbers(from, to int) []int64 {
+	out := []int64{}
+	for i := from; i < to; i++ {
+		out = append(out, int64(i))
+	}
+	return out
+}
+
+// segmentFiles returns the names of the segment files in dir
+func segmentFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, match := range matches {
+		matches[i] = filepath.Base(match)
+	}
+	return matches
+}
+
+func TestSpoolRecoversSegments(t *testing.T) {
+	tests := []struct {
+		name     string
+		appended int
+		// samples read and acknowledged before the restart
+		acked int
+		want  []int64
+	}{
+		{name: "empty", appended: 0, want: []int64{}},
+		{name: "partial segment", appended: 3, want: numbers(0, 3)},
+		{name: "several segments", appended: 12, want: numbers(0, 12)},
+		{name: "after a whole segment was acknowledged", appended: 12, acked: 4, want: numbers(4, 12)},
+		{name: "after part of a segment was acknowledged", appended: 12, acked: 6, want: numbers(6, 12)},
+		{name: "after part of the only segment was acknowledged", appended: 3, acked: 2, want: numbers(2, 3)},
+		{name: "after everything was acknowledged", appended: 6, acked: 6, want: []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			s := openTestSpool(t, dir, 100, 4)
+			appendSamples(t, s, 0, tt.appended)
+			for acked := 0; acked < tt.acked; {
+				batch, err := s.Next(tt.acked - acked)
+				if err != nil || batch == nil {
+					t.Fatalf("Next() = %v, %v", batch, err)
+				}
+				if err := s.Ack(batch); err != nil {
+					t.Fatalf("Ack() error = %v", err)
+				}
+				acked += len(batch.Requests)
+			}
+			if err := s.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			s = openTestSpool(t, dir, 100, 4)
+			if s.Len() != len(tt.want) {
+				t.Errorf("Len() = %d, want %d", s.Len(), len(tt.want))
+			}
+
+			// Samples appended after the restart follow the recovered ones
+			appendSamples(t, s, 100, 102)
+			want := append(slices.Clone(tt.want), 100, 101)
+			if got := drain(t, s); !slices.Equal(got, want) {
+				t.Errorf("replayed %v, want %v", got, want)
+			}
+			if files := segmentFiles(t, dir); len(files) != 0 {
+				t.Errorf("segments %v left after everything was acknowledged", files)
+			}
+			if _, err := os.Stat(filepath.Join(dir, progressFile)); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("progress left after everything was acknowledged: %v", err)
+			}
+		})
+	}
+}
+
+func TestSpoolOpenSkipsOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"notes.txt":                           "not a segment",
+		"abc" + segmentExt:                    "not numbered",
+		fmt.Sprintf("%012d%s", 7, segmentExt): "",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := openTestSpool(t, dir, 100, 4)
+	if s.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", s.Len())
+	}
+	if files := segmentFiles(t, dir); !slices.Equal(files, []string{"abc" + segmentExt}) {
+		t.Errorf("segments = %v, want the empty segment removed and others kept", files)
+	}
+}
+
+func TestSpoolTruncatesDamagedSegment(t *testing.T) {
+	tests := []struct {
+		name   string
+		damage func(t *testing.T, path string)
+	}{
+		{
+			name: "partial header",
+			damage: func(t *testing.T, path string) {
+				appendBytes(t, path, []byte{0, 0})
+			},
+		},
+		{
+			name: "partial record",
+			damage: func(t *testing.T, path string) {
+				appendBytes(t, path, []byte{0, 0, 0, 10, 1, 2, 3})
+			},
+		},
+		{
+			name: "invalid length",
+			damage: func(t *testing.T, path string) {
+				appendBytes(t, path, []byte{0xff, 0xff, 0xff, 0xff, 1})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			s := openTestSpool(t, dir, 100, 10)
+			appendSamples(t, s, 0, 3)
+			if err := s.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			path := s.path(1)
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			intact := info.Size()
+			tt.damage(t, path)
+
+			s = openTestSpool(t, dir, 100, 10)
+			if s.Len() != 3 {
+				t.Errorf("Len() = %d, want 3", s.Len())
+			}
+			info, err = os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Size() != intact {
+				t.Errorf("segment is %d bytes after recovery, want %d", info.Size(), intact)
+			}
+
+			appendSamples(t, s, 3, 5)
+			if got := drain(t, s); !slices.Equal(got, numbers(0, 5)) {
+				t.Errorf("replayed %v, want %v", got, numbers(0, 5))
+			}
+		})
+	}
+}
+
+func TestSpoolEnforcesLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxRecords  int
+		segmentSize int
+		appended    int
+		// samples read and acknowledged before the last appends
+		acked int
+		want  []int64
+	}{
+		{name: "under the limit", maxRecords: 8, segmentSize: 4, appended: 8, want: numbers(0, 8)},
+		{name: "oldest segment dropped", maxRecords: 8, segmentSize: 4, appended: 9, want: numbers(4, 9)},
+		{name: "several segments dropped", maxRecords: 8, segmentSize: 4, appended: 17, want: numbers(12, 17)},
+		{name: "limit below segment size", maxRecords: 2, segmentSize: 4, appended: 6, want: numbers(4, 6)},
+		{name: "acknowledged samples not counted", maxRecords: 8, segmentSize: 4, appended: 10, acked: 2, want: numbers(2, 10)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			s := openTestSpool(t, dir, tt.maxRecords, tt.segmentSize)
+
+			appendSamples(t, s, 0, tt.appended-2)
+			if tt.acked > 0 {
+				batch, err := s.Next(tt.acked)
+				if err != nil || batch == nil {
+					t.Fatalf("Next() = %v, %v", batch, err)
+				}
+				if err := s.Ack(batch); err != nil {
+					t.Fatalf("Ack() error = %v", err)
+				}
+			}
+			appendSamples(t, s, tt.appended-2, tt.appended)
+
+			if s.Len() != len(tt.want) {
+				t.Errorf("Len() = %d, want %d", s.Len(), len(tt.want))
+			}
+			if got := drain(t, s); !slices.Equal(got, tt.want) {
+				t.Errorf("replayed %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpoolProgressOfDroppedSegment(t *testing.T) {
+	dir := t.TempDir()
+	s := openTestSpool(t, dir, 8, 4)
+	appendSamples(t, s, 0, 6)
+	batch, err := s.Next(2)
+	if err != nil || batch == nil {
+		t.Fatalf("Next() = %v, %v", batch, err)
+	}
+	if err := s.Ack(batch); err != nil {
+		t.Fatalf("Ack() error = %v", err)
+	}
+	// Drops the partly acknowledged segment
+	appendSamples(t, s, 6, 11)
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	s = openTestSpool(t, dir, 8, 4)
+	if got := drain(t, s); !slices.Equal(got, numbers(4, 11)) {
+		t.Errorf("replayed %v, want %v", got, numbers(4, 11))
+	}
+}
+
+func TestSpoolIgnoresDamagedProgress(t *testing.T) {
+	dir := t.TempDir()
+	s := openTestSpool(t, dir, 100, 4)
+	appendSamples(t, s, 0, 3)
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, progressFile), []byte{1, 2, 3}, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	s = openTestSpool(t, dir, 100, 4)
+	if got := drain(t, s); !slices.Equal(got, numbers(0, 3)) {
+		t.Errorf("replayed %v, want %v", got, numbers(0, 3))
+	}
+}
+
+func TestSpoolAckOfDroppedSegment(t *testing.T) {
+	s := openTestSpool(t, t.TempDir(), 4, 4)
+	appendSamples(t, s, 0, 4)
+
+	batch, err := s.Next(2)
+	if err != nil || batch == nil {
+		t.Fatalf("Next() = %v, %v", batch, err)
+	}
+	// The segment of the batch in flight is dropped
+	appendSamples(t, s, 4, 5)
+	if err := s.Ack(batch); err != nil {
+		t.Fatalf("Ack() error = %v", err)
+	}
+
+	if got := drain(t, s); !slices.Equal(got, []int64{4}) {
+		t.Errorf("replayed %v, want [4]", got)
+	}
+}
+
+// appendBytes appends data to a file
+func appendBytes(t *testing.T, path string, data []byte) {
+	t.Helper()
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		t.Fatal(err)
+	}
+}
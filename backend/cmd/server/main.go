@@ -3,6 +3,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -14,16 +15,20 @@ import (
 	"time"
 
 	"smart-monitor/backend/internal/application/usecase"
+	"smart-monitor/backend/internal/domain/repository"
 	"smart-monitor/backend/internal/domain/service"
 	grpchandler "smart-monitor/backend/internal/infrastructure/grpc"
 	httphandler "smart-monitor/backend/internal/infrastructure/http"
 	"smart-monitor/backend/internal/infrastructure/opensearch"
 	"smart-monitor/backend/internal/infrastructure/persistence"
+	"smart-monitor/backend/internal/infrastructure/sqlstore"
+	"smart-monitor/backend/internal/infrastructure/tsdb"
 	"smart-monitor/backend/pkg/config"
 	pb "smart-monitor/pbtypes/monitor"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
@@ -38,13 +43,66 @@ func main() {
 	log.Printf("Configuration loaded: gRPC=:%s, HTTP=:%s", cfg.Server.GRPCPort, cfg.Server.HTTPPort)
 
 	// Initialize in-memory repositories as fallback
-	statsRepo := persistence.NewInMemoryStatsRepository()
+	statsCfg := config.LoadStatsConfig()
+	statsRepo := persistence.NewInMemoryStatsRepository(statsCfg.MemoryHistorySize)
 	hostRepo := persistence.NewInMemoryHostRepository()
 	agentRepo := persistence.NewInMemoryAgentRegistryRepository()
-	policyRepo := persistence.NewInMemoryPolicyRepository()
+	var policyRepo repository.PolicyRepository = persistence.NewInMemoryPolicyRepository()
 	userRepo := persistence.NewInMemoryUserRepository()
+	alertRepo := persistence.NewInMemoryAlertRepository()
+	eventRepo := persistence.NewInMemoryEventRepository()
+	commandRepo := persistence.NewInMemoryAgentCommandRepository()
+	agentConfigRepo := persistence.NewInMemoryAgentConfigRepository()
+	enrollmentRepo := persistence.NewInMemoryEnrollmentTokenRepository()
 	log.Println("✓ In-memory repositories initialized (fallback)")
 
+	// Agents, hosts, policies, users, enrollment tokens, agent config
+	// profiles and command history survive restarts with a database
+	dbCfg := config.LoadDatabaseConfig()
+	if dbCfg.Driver != "memory" {
+		db, err := sqlstore.Open(dbCfg.Driver, dbCfg.DSN)
+		if err != nil {
+			log.Fatalf("Failed to open %s database: %v", dbCfg.Driver, err)
+		}
+		defer db.Close()
+
+		hostRepo = sqlstore.NewSQLHostRepository(db)
+		agentRepo = sqlstore.NewSQLAgentRegistryRepository(db)
+		policyRepo = sqlstore.NewSQLPolicyRepository(db)
+		userRepo = sqlstore.NewSQLUserRepository(db)
+		enrollmentRepo = sqlstore.NewSQLEnrollmentTokenRepository(db)
+		agentConfigRepo = sqlstore.NewSQLAgentConfigRepository(db)
+		commandRepo = sqlstore.NewSQLAgentCommandRepository(db)
+		log.Printf("✓ Using %s for agents, hosts, policies, users, enrollment tokens, agent configs and commands", dbCfg.Driver)
+	}
+
+	var tsdbStatsRepo *tsdb.TSDBStatsRepository
+	switch statsCfg.Storage {
+	case config.StatsStorageTSDB:
+		repo, err := tsdb.NewTSDBStatsRepository(statsCfg.TSDBDir, tsdb.Options{
+			BlockDuration: statsCfg.TSDBBlockDuration,
+			Retention:     statsCfg.TSDBRetention,
+			MaxBytes:      statsCfg.TSDBMaxBytes,
+		})
+		if err != nil {
+			log.Fatalf("Failed to open TSDB stats storage: %v", err)
+		}
+		defer func() {
+			if err := repo.Close(); err != nil {
+				log.Printf("⚠ Failed to close TSDB stats storage: %v", err)
+			}
+		}()
+		tsdbStatsRepo = repo
+		statsRepo = repo
+		log.Printf("✓ Using TSDB for stats storage (%s)", statsCfg.TSDBDir)
+	case config.StatsStorageMemory:
+		log.Println("✓ Using in-memory stats storage")
+	case config.StatsStorageOpenSearch:
+	default:
+		log.Printf("⚠ Unknown STATS_STORAGE %q, using %s", statsCfg.Storage, config.StatsStorageOpenSearch)
+		statsCfg.Storage = config.StatsStorageOpenSearch
+	}
+
 	// Initialize OpenSearch
 	var osClient *opensearch.Client
 	var osStatsRepo *opensearch.OpenSearchStatsRepository
@@ -65,48 +123,95 @@ func main() {
 		}
 
 		// Create OpenSearch repositories
-		osStatsRepoTemp, err := opensearch.NewOpenSearchStatsRepository(osClient)
-		if err == nil {
-			osStatsRepo = osStatsRepoTemp
-			statsRepo = osStatsRepo
-			log.Println("✓ Using OpenSearch for stats storage")
+		if statsCfg.Storage == config.StatsStorageOpenSearch {
+			osStatsRepoTemp, err := opensearch.NewOpenSearchStatsRepository(osClient)
+			if err == nil {
+				osStatsRepo = osStatsRepoTemp
+				statsRepo = osStatsRepo
+				log.Println("✓ Using OpenSearch for stats storage")
+			}
 		}
 
 		osAlertsRepoTemp, err := opensearch.NewAlertsRepository(osClient)
 		if err == nil {
 			osAlertsRepo = osAlertsRepoTemp
+			alertRepo = opensearch.NewDomainAlertsRepository(osAlertsRepo)
 			log.Println("✓ Alerts repository initialized")
 		}
 
 		osEventsRepoTemp, err := opensearch.NewEventsRepository(osClient)
 		if err == nil {
 			osEventsRepo = osEventsRepoTemp
+			eventRepo = opensearch.NewDomainEventsRepository(osEventsRepo)
 			log.Println("✓ Events repository initialized")
 		}
 	}
+	// The SQL database does not store alerts and events
+	if osAlertsRepo == nil || osEventsRepo == nil {
+		log.Println("⚠ Alerts and events are kept in memory and lost on restart, as OpenSearch is unavailable")
+	}
 
 	// Initialize domain services
+	authCfg := config.LoadAuthConfig()
 	statsService := service.NewStatsService(statsRepo, hostRepo)
-	authService := service.NewAuthService(agentRepo)
-	controlService := service.NewAgentControlService(agentRepo)
-	policyService := service.NewPolicyService(policyRepo, agentRepo)
-	log.Println("✓ Domain services initialized")
+	enrollmentService := service.NewEnrollmentService(enrollmentRepo, policyRepo, authCfg.AgentEnrollmentRequired)
+	authService := service.NewAuthService(agentRepo, enrollmentService, authCfg.AgentTokenTTL)
+	controlService := service.NewAgentControlService(agentRepo, commandRepo)
+	var statsHistory repository.StatsHistoryRepository
+	if osStatsRepo != nil {
+		statsHistory = osStatsRepo
+	} else if tsdbStatsRepo != nil {
+		statsHistory = tsdbStatsRepo
+	}
+	policyEvaluator := service.NewPolicyEvaluator(policyRepo, alertRepo, statsHistory)
+	policyService := service.NewPolicyService(policyRepo, agentRepo, policyEvaluator)
+	agentConfigService := service.NewAgentConfigService(agentConfigRepo, agentRepo, controlService)
+	agentMergeService := service.NewAgentMergeService(agentRepo, policyRepo, agentConfigRepo, hostRepo, statsRepo, controlService)
+	agentFleetService := service.NewAgentFleetService(agentRepo, policyRepo, agentConfigRepo, hostRepo, authService, controlService)
+	if migrated, err := policyService.MigrateLegacyPolicies(); err != nil {
+		log.Printf("⚠ Failed to migrate legacy policy thresholds: %v", err)
+	} else if migrated > 0 {
+		log.Printf("✓ Migrated %d legacy policies to typed rules", migrated)
+	}
+	if restored, err := policyEvaluator.Restore(context.Background()); err != nil {
+		log.Printf("⚠ Failed to restore firing policy alerts: %v", err)
+	} else if restored > 0 {
+		log.Printf("✓ Restored %d firing policy alerts", restored)
+	}
+	livenessCfg := config.LoadLivenessConfig()
+	livenessMonitor := service.NewHostLivenessMonitor(hostRepo, statsService, eventRepo, alertRepo, service.LivenessThresholds{
+		DefaultReportInterval: livenessCfg.DefaultReportInterval,
+		StaleAfterIntervals:   livenessCfg.StaleAfterIntervals,
+		OfflineAfterIntervals: livenessCfg.OfflineAfterIntervals,
+	})
+	log.Printf("✓ Domain services initialized (agent token TTL: %v)", authCfg.AgentTokenTTL)
+	if !authCfg.AgentEnrollmentRequired {
+		log.Println("⚠ Agent enrollment tokens are not required: anyone reaching the gRPC port can register an agent")
+	}
+
+	// Start host liveness monitoring
+	livenessCtx, stopLiveness := context.WithCancel(context.Background())
+	defer stopLiveness()
+	go livenessMonitor.Run(livenessCtx, livenessCfg.CheckInterval)
+	log.Printf("✓ Host liveness monitor started (stale after %.1f, offline after %.1f reporting intervals)", livenessCfg.StaleAfterIntervals, livenessCfg.OfflineAfterIntervals)
 
 	// Initialize use cases
-	monitorUseCase := usecase.NewMonitorUseCase(statsService)
+	statsBroker := service.NewStatsBroker()
+	monitorUseCase := usecase.NewMonitorUseCase(statsService, policyEvaluator, statsBroker)
 	log.Println("✓ Use cases initialized")
 
 	// Initialize user auth service
-	authCfg := config.LoadAuthConfig()
 	userAuthService := service.NewUserAuthService(userRepo, authCfg.JWTSecret)
 	log.Println("✓ User auth service initialized")
 
 	// Initialize gRPC handlers
-	monitorGRPCHandler := grpchandler.NewMonitorServiceServer(monitorUseCase, authService, controlService, policyService)
+	monitorGRPCHandler := grpchandler.NewMonitorServiceServer(monitorUseCase, authService, controlService, policyService, agentConfigService, agentMergeService, enrollmentService, agentFleetService)
 	log.Println("✓ gRPC handlers initialized")
 
 	// Start gRPC server
-	grpcServer, lis := startGRPCServer(cfg, monitorGRPCHandler)
+	agentAuth := grpchandler.NewAgentAuthInterceptor(authService)
+	adminAuth := grpchandler.NewAdminAuthInterceptor(userAuthService)
+	grpcServer, lis := startGRPCServer(cfg, monitorGRPCHandler, agentAuth, adminAuth)
 	log.Printf("✓ gRPC Server starting on port :%s", cfg.Server.GRPCPort)
 
 	// Start HTTP server
@@ -128,14 +233,35 @@ func main() {
 }
 
 // startGRPCServer starts the gRPC server
-func startGRPCServer(cfg *config.Config, monitorHandler *grpchandler.MonitorServiceServer) (*grpc.Server, net.Listener) {
+func startGRPCServer(cfg *config.Config, monitorHandler *grpchandler.MonitorServiceServer, agentAuth *grpchandler.AgentAuthInterceptor, adminAuth *grpchandler.AdminAuthInterceptor) (*grpc.Server, net.Listener) {
 	lis, err := net.Listen("tcp", ":"+cfg.Server.GRPCPort)
 	if err != nil {
 		log.Fatalf("Failed to listen on port %s: %v", cfg.Server.GRPCPort, err)
 	}
 
-	// Create gRPC server with health check
-	grpcServer := grpc.NewServer()
+	// Create gRPC server with health check; agents authenticate once per call
+	// or stream, admin RPCs require a user token with the admin role
+	serverOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(agentAuth.Unary(), adminAuth.Unary()),
+		grpc.ChainStreamInterceptor(agentAuth.Stream()),
+	}
+	if cfg.Server.TLS.Enabled {
+		tlsConfig, err := cfg.Server.TLS.ServerTLSConfig()
+		if err != nil {
+			log.Fatalf("Failed to configure gRPC TLS: %v", err)
+		}
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		if tlsConfig.ClientAuth == tls.RequireAndVerifyClientCert {
+			log.Println("✓ gRPC TLS enabled (client certificates required)")
+		} else if tlsConfig.ClientCAs != nil {
+			log.Println("✓ gRPC TLS enabled (client certificates verified when presented)")
+		} else {
+			log.Println("✓ gRPC TLS enabled")
+		}
+	} else {
+		log.Println("⚠ gRPC TLS disabled, agent traffic is not encrypted")
+	}
+	grpcServer := grpc.NewServer(serverOpts...)
 
 	// Register services
 	pb.RegisterMonitorServiceServer(grpcServer, monitorHandler)
@@ -165,6 +291,13 @@ func startHTTPServer(cfg *config.Config, monitorUseCase *usecase.MonitorUseCase,
 	// Create gRPC gateway mux
 	gwMux := runtime.NewServeMux()
 	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if cfg.Server.TLS.Enabled {
+		tlsConfig, err := cfg.Server.TLS.GatewayTLSConfig()
+		if err != nil {
+			log.Fatalf("Failed to configure gateway TLS: %v", err)
+		}
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}
+	}
 	endpoint := fmt.Sprintf("localhost:%s", cfg.Server.GRPCPort)
 
 	err := pb.RegisterMonitorServiceHandlerFromEndpoint(ctx, gwMux, endpoint, opts)
@@ -175,6 +308,30 @@ func startHTTPServer(cfg *config.Config, monitorUseCase *usecase.MonitorUseCase,
 	// Mount API gateway
 	httpMux.Handle("/v1/", gwMux)
 
+	// Admin-only gateway routes
+	adminOnly := httphandler.RequireRoles(userAuthService, []string{"admin"}, gwMux.ServeHTTP)
+	httpMux.HandleFunc("/v1/enrollment-tokens", adminOnly)
+	httpMux.HandleFunc("/v1/enrollment-tokens/", adminOnly)
+	httpMux.HandleFunc("POST /v1/agent/{agent_id}/control", adminOnly)
+	httpMux.HandleFunc("GET /v1/agent/{agent_id}/commands", adminOnly)
+	httpMux.HandleFunc("POST /v1/agent/{agent_id}/block", adminOnly)
+	httpMux.HandleFunc("POST /v1/agent/{agent_id}/revoke", adminOnly)
+	httpMux.HandleFunc("DELETE /v1/agent/{agent_id}", adminOnly)
+	httpMux.HandleFunc("POST /v1/agent/{agent_id}/merge", adminOnly)
+	httpMux.HandleFunc("POST /v1/config-profiles", adminOnly)
+	httpMux.HandleFunc("PUT /v1/config-profiles/{profile_id}", adminOnly)
+	httpMux.HandleFunc("DELETE /v1/config-profiles/{profile_id}", adminOnly)
+	httpMux.HandleFunc("POST /v1/agent/{agent_id}/config-profile/{profile_id}/attach", adminOnly)
+	httpMux.HandleFunc("POST /v1/agent/{agent_id}/config-profile/{profile_id}/detach", adminOnly)
+
+	// Gateway routes for admins and operators
+	adminOrOperator := httphandler.RequireRoles(userAuthService, []string{"admin", "operator"}, gwMux.ServeHTTP)
+	httpMux.HandleFunc("GET /v1/agents", adminOrOperator)
+	httpMux.HandleFunc("GET /v1/agent/{agent_id}", adminOrOperator)
+
+	// Live stats for dashboards, as server-sent events
+	httpMux.Handle("/v1/stats/watch", httphandler.NewStatsWatchHandler(monitorUseCase))
+
 	// Health check endpoints
 	httpMux.Handle("/health", httphandler.NewHealthHandler(monitorUseCase))
 	httpMux.Handle("/ready", httphandler.NewReadyHandler(monitorUseCase))
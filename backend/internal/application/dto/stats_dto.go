@@ -1,7 +1,11 @@
 // Package dto defines data transfer objects
 package dto
 
-import "time"
+import (
+	"time"
+
+	"smart-monitor/backend/internal/domain/entity"
+)
 
 // StatsRequest represents incoming stats request
 type StatsRequest struct {
@@ -13,6 +17,11 @@ type StatsRequest struct {
 	RAM          float64
 	Disk         float64
 	Metadata     map[string]string
+	CollectedAt  time.Time // when the agent took the sample, zero if unknown
+	Extended     entity.ExtendedMetrics
+	Errors       []entity.CollectorError // agent plugins that failed on the sample
+
+	ReportInterval time.Duration // how often the agent samples, zero if not reported
 }
 
 // StatsResponse represents stats response
@@ -26,6 +35,25 @@ type StatsResponse struct {
 	Timestamp    time.Time
 	LastReceived time.Time
 	Metadata     map[string]string
+	Extended     entity.ExtendedMetrics
+	Errors       []entity.CollectorError
+}
+
+// NewStatsResponse converts a stats entity to a response
+func NewStatsResponse(stats *entity.Stats) *StatsResponse {
+	return &StatsResponse{
+		Hostname:     stats.Hostname,
+		AgentID:      stats.AgentID,
+		IPAddress:    stats.IPAddress,
+		CPU:          stats.CPU,
+		RAM:          stats.RAM,
+		Disk:         stats.Disk,
+		Timestamp:    stats.Timestamp,
+		LastReceived: stats.LastReceived,
+		Metadata:     stats.Metadata,
+		Extended:     stats.ExtendedMetrics,
+		Errors:       stats.CollectorErrors,
+	}
 }
 
 // HealthResponse represents health check response
@@ -3,6 +3,7 @@ package usecase
 
 import (
 	"context"
+	"log"
 	"smart-monitor/backend/internal/application/dto"
 	"smart-monitor/backend/internal/domain/entity"
 	"smart-monitor/backend/internal/domain/service"
@@ -10,26 +11,88 @@ import (
 
 // MonitorUseCase handles monitoring use cases
 type MonitorUseCase struct {
-	statsService *service.StatsService
+	statsService    *service.StatsService
+	policyEvaluator *service.PolicyEvaluator
+	broker          *service.StatsBroker
 }
 
-// NewMonitorUseCase creates a new MonitorUseCase
-func NewMonitorUseCase(statsService *service.StatsService) *MonitorUseCase {
+// NewMonitorUseCase creates a new MonitorUseCase. Recorded samples are
+// published to broker for live subscribers.
+func NewMonitorUseCase(statsService *service.StatsService, policyEvaluator *service.PolicyEvaluator, broker *service.StatsBroker) *MonitorUseCase {
 	return &MonitorUseCase{
-		statsService: statsService,
+		statsService:    statsService,
+		policyEvaluator: policyEvaluator,
+		broker:          broker,
 	}
 }
 
 // RecordStats records incoming stats from agent
 func (uc *MonitorUseCase) RecordStats(ctx context.Context, req *dto.StatsRequest) error {
-	// Convert DTO to entity
+	stats := newStatsFromRequest(req)
+
+	// Process through domain service
+	if err := uc.statsService.ProcessStats(ctx, stats, req.AgentVersion); err != nil {
+		return err
+	}
+
+	uc.evaluatePolicies(ctx, stats)
+	uc.broker.Publish(stats)
+	return nil
+}
+
+// RecordStatsBatch records a batch of samples from one agent. Samples are
+// stored and evaluated in the order they were collected; invalid samples are
+// skipped and reported in the error. It returns the number of samples stored.
+func (uc *MonitorUseCase) RecordStatsBatch(ctx context.Context, reqs []*dto.StatsRequest) (int, error) {
+	if len(reqs) == 0 {
+		return 0, nil
+	}
+
+	batch := make([]*entity.Stats, 0, len(reqs))
+	for _, req := range reqs {
+		batch = append(batch, newStatsFromRequest(req))
+	}
+
+	stored, err := uc.statsService.ProcessBatch(ctx, batch, reqs[len(reqs)-1].AgentVersion)
+	for _, stats := range stored {
+		uc.evaluatePolicies(ctx, stats)
+	}
+
+	// Live subscribers only see the current state, not the backlog
+	if len(stored) > 0 {
+		uc.broker.Publish(stored[len(stored)-1])
+	}
+
+	return len(stored), err
+}
+
+// WatchStats subscribes to the samples recorded from now on that match filter.
+// The caller must close the subscription.
+func (uc *MonitorUseCase) WatchStats(filter service.StatsFilter) *service.StatsSubscription {
+	return uc.broker.Subscribe(filter)
+}
+
+// evaluatePolicies evaluates policies applied to the agent; alerting failures must not drop the sample
+func (uc *MonitorUseCase) evaluatePolicies(ctx context.Context, stats *entity.Stats) {
+	if uc.policyEvaluator == nil {
+		return
+	}
+	if err := uc.policyEvaluator.Evaluate(ctx, stats); err != nil {
+		log.Printf("Policy evaluation failed for agent %s: %v", stats.AgentID, err)
+	}
+}
+
+// newStatsFromRequest converts a stats DTO to an entity stamped with its collection time
+func newStatsFromRequest(req *dto.StatsRequest) *entity.Stats {
 	stats := entity.NewStats(req.Hostname, req.AgentID, req.IPAddress, req.CPU, req.RAM, req.Disk)
 	if req.Metadata != nil {
 		stats.Metadata = req.Metadata
 	}
-
-	// Process through domain service
-	return uc.statsService.ProcessStats(ctx, stats, req.AgentVersion)
+	stats.ExtendedMetrics = req.Extended
+	stats.CollectorErrors = req.Errors
+	stats.ReportInterval = req.ReportInterval
+	stats.SetCollectedAt(req.CollectedAt)
+	return stats
 }
 
 // GetStats retrieves stats for a hostname
@@ -40,17 +103,22 @@ func (uc *MonitorUseCase) GetStats(ctx context.Context, hostname string) (*dto.S
 	}
 
 	// Convert entity to DTO
-	return &dto.StatsResponse{
-		Hostname:     stats.Hostname,
-		AgentID:      stats.AgentID,
-		IPAddress:    stats.IPAddress,
-		CPU:          stats.CPU,
-		RAM:          stats.RAM,
-		Disk:         stats.Disk,
-		Timestamp:    stats.Timestamp,
-		LastReceived: stats.LastReceived,
-		Metadata:     stats.Metadata,
-	}, nil
+	return dto.NewStatsResponse(stats), nil
+}
+
+// GetStatsByAgent retrieves the latest stats reported by an agent
+func (uc *MonitorUseCase) GetStatsByAgent(ctx context.Context, agentID string) (*dto.StatsResponse, error) {
+	stats, err := uc.statsService.GetStatsByAgent(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewStatsResponse(stats), nil
+}
+
+// QueryStatsRange retrieves the downsampled metrics of a host or agent over a time range
+func (uc *MonitorUseCase) QueryStatsRange(ctx context.Context, query *entity.StatsRangeQuery) ([]entity.StatsSeries, error) {
+	return uc.statsService.QueryRange(ctx, query)
 }
 
 // GetAllStats retrieves all stats
@@ -63,17 +131,7 @@ func (uc *MonitorUseCase) GetAllStats(ctx context.Context) ([]*dto.StatsResponse
 	// Convert entities to DTOs
 	responses := make([]*dto.StatsResponse, len(statsList))
 	for i, stats := range statsList {
-		responses[i] = &dto.StatsResponse{
-			Hostname:     stats.Hostname,
-			AgentID:      stats.AgentID,
-			IPAddress:    stats.IPAddress,
-			CPU:          stats.CPU,
-			RAM:          stats.RAM,
-			Disk:         stats.Disk,
-			Timestamp:    stats.Timestamp,
-			LastReceived: stats.LastReceived,
-			Metadata:     stats.Metadata,
-		}
+		responses[i] = dto.NewStatsResponse(stats)
 	}
 
 	return responses, nil
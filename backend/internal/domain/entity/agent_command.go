@@ -0,0 +1,81 @@
+// Package entity defines core business entities
+package entity
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// CommandStatus represents the delivery status of an agent command
+type CommandStatus string
+
+const (
+	CommandStatusPending   CommandStatus = "pending"   // queued, not yet received by the agent
+	CommandStatusDelivered CommandStatus = "delivered" // received by the agent
+	CommandStatusExecuted  CommandStatus = "executed"
+	CommandStatusFailed    CommandStatus = "failed"
+)
+
+// AgentCommand is a control command sent to an agent over its command channel
+type AgentCommand struct {
+	CommandID string
+	AgentID   string
+	Action    AgentControlAction
+	Reason    string
+	Status    CommandStatus
+	Message   string // result reported by the agent
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewAgentCommand creates a pending command
+func NewAgentCommand(agentID string, action AgentControlAction, reason string) *AgentCommand {
+	now := time.Now()
+	return &AgentCommand{
+		CommandID: generateCommandID(),
+		AgentID:   agentID,
+		Action:    action,
+		Reason:    reason,
+		Status:    CommandStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Acknowledge records a status reported by the agent.
+// A command moves pending → delivered → executed/failed and never goes back.
+func (c *AgentCommand) Acknowledge(status CommandStatus, message string) error {
+	switch status {
+	case CommandStatusDelivered:
+		if c.Status != CommandStatusPending {
+			return fmt.Errorf("command %s is already %s", c.CommandID, c.Status)
+		}
+	case CommandStatusExecuted, CommandStatusFailed:
+		if c.IsFinal() {
+			return fmt.Errorf("command %s is already %s", c.CommandID, c.Status)
+		}
+	default:
+		return fmt.Errorf("invalid acknowledgement status: %s", status)
+	}
+
+	c.Status = status
+	c.Message = message
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsFinal checks if the agent has reported the command result
+func (c *AgentCommand) IsFinal() bool {
+	return c.Status == CommandStatusExecuted || c.Status == CommandStatusFailed
+}
+
+// generateCommandID generates a random command identifier
+func generateCommandID() string {
+	bytes := make([]byte, 8)
+	if _, err := rand.Read(bytes); err != nil {
+		return fmt.Sprintf("cmd-%d", time.Now().UnixNano())
+	}
+	return "cmd-" + hex.EncodeToString(bytes)
+}
@@ -0,0 +1,168 @@
+// Package entity defines core business entities
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+)
+
+// AgentConfigProfile is a versioned set of agent settings managed by the
+// backend. It reaches an agent when attached to it directly or when its
+// selector matches the agent's metadata labels.
+type AgentConfigProfile struct {
+	ProfileID   string
+	Name        string
+	Description string
+	Version     int64 // incremented on every change
+	Settings    AgentSettings
+	AgentIDs    []string          // agents the profile is attached to
+	Selector    map[string]string // labels an agent must carry, empty matches none
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// AgentSettings are the agent settings a profile controls. Settings left at
+// their zero value keep the agent's local configuration.
+type AgentSettings struct {
+	MetricsInterval time.Duration
+	Metadata        map[string]string // merged into the agent's labels
+	Collectors      map[string]CollectorSettings
+}
+
+// CollectorSettings configures one agent collector plugin
+type CollectorSettings struct {
+	Enabled  bool
+	Interval time.Duration // 0 runs the plugin for every sample
+}
+
+// NewAgentConfigProfile creates the first version of a profile
+func NewAgentConfigProfile(profileID, name, description string, settings AgentSettings, selector map[string]string) *AgentConfigProfile {
+	now := time.Now()
+
+	if selector == nil {
+		selector = make(map[string]string)
+	}
+
+	return &AgentConfigProfile{
+		ProfileID:   profileID,
+		Name:        name,
+		Description: description,
+		Version:     1,
+		Settings:    settings,
+		AgentIDs:    []string{},
+		Selector:    selector,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// Update changes the profile and bumps its version. Settings and selector
+// are replaced only when provided.
+func (p *AgentConfigProfile) Update(name, description string, settings *AgentSettings, selector map[string]string) {
+	if name != "" {
+		p.Name = name
+	}
+	if description != "" {
+		p.Description = description
+	}
+	if settings != nil {
+		p.Settings = *settings
+	}
+	if selector != nil {
+		p.Selector = selector
+	}
+	p.Version++
+	p.UpdatedAt = time.Now()
+}
+
+// Attach attaches the profile to an agent
+func (p *AgentConfigProfile) Attach(agentID string) bool {
+	if p.IsAttachedTo(agentID) {
+		return false
+	}
+	p.AgentIDs = append(p.AgentIDs, agentID)
+	p.UpdatedAt = time.Now()
+	return true
+}
+
+// Detach removes the profile from an agent
+func (p *AgentConfigProfile) Detach(agentID string) bool {
+	for i, id := range p.AgentIDs {
+		if id == agentID {
+			p.AgentIDs = append(p.AgentIDs[:i], p.AgentIDs[i+1:]...)
+			p.UpdatedAt = time.Now()
+			return true
+		}
+	}
+	return false
+}
+
+// IsAttachedTo checks if the profile is attached to an agent
+func (p *AgentConfigProfile) IsAttachedTo(agentID string) bool {
+	for _, id := range p.AgentIDs {
+		if id == agentID {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches checks if an agent with the given labels is selected by the profile
+func (p *AgentConfigProfile) Matches(labels map[string]string) bool {
+	if len(p.Selector) == 0 {
+		return false
+	}
+	for key, value := range p.Selector {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// AppliesTo checks if the profile reaches an agent, directly or by selector
+func (p *AgentConfigProfile) AppliesTo(agent *AgentRegistry) bool {
+	return p.IsAttachedTo(agent.AgentID) || p.Matches(agent.Metadata)
+}
+
+// Revision identifies this version of the profile, e.g. "cfg-1a2b@3"
+func (p *AgentConfigProfile) Revision() string {
+	return fmt.Sprintf("%s@%d", p.ProfileID, p.Version)
+}
+
+// Clone returns a copy that does not share maps or slices with the profile
+func (p *AgentConfigProfile) Clone() *AgentConfigProfile {
+	c := *p
+	c.Settings.Metadata = maps.Clone(p.Settings.Metadata)
+	c.Settings.Collectors = maps.Clone(p.Settings.Collectors)
+	c.AgentIDs = append([]string(nil), p.AgentIDs...)
+	c.Selector = maps.Clone(p.Selector)
+	return &c
+}
+
+// Validate checks that the settings can be applied by an agent. Collector
+// names are checked by the agent, which knows its plugins.
+func (s *AgentSettings) Validate() error {
+	var errs []error
+
+	if s.MetricsInterval != 0 && s.MetricsInterval < time.Second {
+		errs = append(errs, fmt.Errorf("metrics interval %v must be at least 1s", s.MetricsInterval))
+	}
+	for key := range s.Metadata {
+		if key == "" {
+			errs = append(errs, errors.New("metadata keys must not be empty"))
+		}
+	}
+	for name, collector := range s.Collectors {
+		if name == "" {
+			errs = append(errs, errors.New("collector names must not be empty"))
+		}
+		if collector.Interval < 0 {
+			errs = append(errs, fmt.Errorf("collector %s: interval must not be negative", name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -3,7 +3,9 @@ package entity
 
 import (
 	"crypto/rand"
+	"crypto/subtle"
 	"encoding/hex"
+	"maps"
 	"time"
 )
 
@@ -13,6 +15,7 @@ type AgentRegistry struct {
 	Hostname     string
 	IPAddress    string
 	AgentVersion string
+	Fingerprint  string // stable machine fingerprint reported by the agent
 	AccessToken  string
 	TokenExpiry  time.Time
 	Status       AgentStatus
@@ -21,6 +24,16 @@ type AgentRegistry struct {
 	Metadata     map[string]string
 	RegisteredAt time.Time
 	LastAuthAt   time.Time
+
+	// Token replaced by the last renewal, still accepted until PreviousExpiry
+	// so an agent can finish in-flight requests and persist the new token
+	PreviousToken  string
+	PreviousExpiry time.Time
+
+	// Labels assigned by the backend on enrollment, kept in Metadata over
+	// whatever the agent reports
+	EnrollmentTokenID string
+	Labels            map[string]string
 }
 
 // AgentStatus represents agent registration status
@@ -40,10 +53,20 @@ const (
 	AgentActionStart    AgentControlAction = "start"
 	AgentActionShutdown AgentControlAction = "shutdown"
 	AgentActionRestart  AgentControlAction = "restart"
+
+	// AgentActionReconfigure tells the agent to fetch its config profile again.
+	// It is issued by the backend when a profile reaching the agent changes.
+	AgentActionReconfigure AgentControlAction = "reconfigure"
 )
 
-// NewAgentRegistry creates a new agent registry entry
-func NewAgentRegistry(agentID, hostname, ipAddress, agentVersion string, metadata map[string]string) *AgentRegistry {
+// DefaultTokenTTL is the lifetime of agent access tokens unless configured otherwise
+const DefaultTokenTTL = 365 * 24 * time.Hour
+
+// TokenRenewalGrace is how long a token stays valid after it was renewed
+const TokenRenewalGrace = 10 * time.Minute
+
+// NewAgentRegistry creates a new agent registry entry with a token valid for tokenTTL
+func NewAgentRegistry(agentID, hostname, ipAddress, agentVersion string, metadata map[string]string, tokenTTL time.Duration) *AgentRegistry {
 	now := time.Now()
 	token := generateAccessToken()
 
@@ -55,7 +78,7 @@ func NewAgentRegistry(agentID, hostname, ipAddress, agentVersion string, metadat
 		AccessToken:  token,
 		Blocked:      false,
 		BlockReason:  "",
-		TokenExpiry:  now.Add(365 * 24 * time.Hour), // 1 year validity
+		TokenExpiry:  now.Add(tokenTTL),
 		Status:       AgentStatusActive,
 		Metadata:     metadata,
 		RegisteredAt: now,
@@ -63,21 +86,89 @@ func NewAgentRegistry(agentID, hostname, ipAddress, agentVersion string, metadat
 	}
 }
 
+// UpdateDetails records the host details reported by a returning agent
+func (a *AgentRegistry) UpdateDetails(hostname, ipAddress, agentVersion string, metadata map[string]string) {
+	a.Hostname = hostname
+	a.IPAddress = ipAddress
+	a.AgentVersion = agentVersion
+	if metadata != nil {
+		a.Metadata = metadata
+	}
+	a.AssignLabels(nil)
+}
+
+// AssignLabels adds backend-assigned labels and merges all of them into the
+// agent's metadata
+func (a *AgentRegistry) AssignLabels(labels map[string]string) {
+	if len(labels) > 0 {
+		if a.Labels == nil {
+			a.Labels = make(map[string]string, len(labels))
+		}
+		maps.Copy(a.Labels, labels)
+	}
+	if len(a.Labels) == 0 {
+		return
+	}
+
+	metadata := make(map[string]string, len(a.Metadata)+len(a.Labels))
+	maps.Copy(metadata, a.Metadata)
+	maps.Copy(metadata, a.Labels)
+	a.Metadata = metadata
+}
+
 // IsValid checks if agent registration is valid
 func (a *AgentRegistry) IsValid() bool {
 	return a.Status == AgentStatusActive && time.Now().Before(a.TokenExpiry)
 }
 
-// IsTokenValid checks if the provided token matches and is not expired
+// IsTokenValid checks if the provided token matches and is not expired.
+// The token replaced by the last renewal is accepted during its grace period.
 func (a *AgentRegistry) IsTokenValid(token string) bool {
-	return a.AccessToken == token && a.IsValid()
+	return a.IsValid() && a.MatchesToken(token)
 }
 
-// RenewToken generates a new access token
-func (a *AgentRegistry) RenewToken() {
+// MatchesToken checks if token was issued to the agent, regardless of its
+// status and expiry. The token replaced by the last renewal matches during
+// its grace period.
+func (a *AgentRegistry) MatchesToken(token string) bool {
+	if token == "" {
+		return false
+	}
+	if tokenEqual(a.AccessToken, token) {
+		return true
+	}
+	return tokenEqual(a.PreviousToken, token) && time.Now().Before(a.PreviousExpiry)
+}
+
+// HasIssued checks if token is the current or the previous access token of
+// the agent, even if it expired. A machine registering again presents it to
+// prove it is the one the agent was registered from.
+func (a *AgentRegistry) HasIssued(token string) bool {
+	if token == "" {
+		return false
+	}
+	return tokenEqual(a.AccessToken, token) || tokenEqual(a.PreviousToken, token)
+}
+
+// tokenEqual compares a stored token with a presented one in constant time
+func tokenEqual(stored, token string) bool {
+	return stored != "" && subtle.ConstantTimeCompare([]byte(stored), []byte(token)) == 1
+}
+
+// RenewToken generates a new access token valid for tokenTTL. The current
+// token keeps working for TokenRenewalGrace, or until it expires if sooner.
+func (a *AgentRegistry) RenewToken(tokenTTL time.Duration) {
+	now := time.Now()
+
+	a.PreviousToken = a.AccessToken
+	a.PreviousExpiry = now.Add(TokenRenewalGrace)
+	if a.TokenExpiry.Before(a.PreviousExpiry) {
+		a.PreviousExpiry = a.TokenExpiry
+	}
+
 	a.AccessToken = generateAccessToken()
-	a.TokenExpiry = time.Now().Add(365 * 24 * time.Hour)
-	a.LastAuthAt = time.Now()
+	a.TokenExpiry = now.Add(tokenTTL)
+	a.LastAuthAt = now
 }
 
 // Suspend suspends the agent
@@ -95,6 +186,7 @@ func (a *AgentRegistry) Activate() {
 func (a *AgentRegistry) Revoke() {
 	a.Status = AgentStatusRevoked
 	a.AccessToken = ""
+	a.PreviousToken = ""
 }
 
 // UpdateLastAuth updates the last authentication timestamp
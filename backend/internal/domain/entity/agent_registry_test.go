@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAgentRegistryMatchesToken(t *testing.T) {
+	agent := NewAgentRegistry("agent-1", "web-01", "10.0.0.1", "1.0.0", nil, time.Hour)
+	previous := agent.AccessToken
+	agent.RenewToken(time.Hour)
+
+	expired := NewAgentRegistry("agent-2", "web-02", "10.0.0.2", "1.0.0", nil, time.Hour)
+	expiredPrevious := expired.AccessToken
+	expired.RenewToken(time.Hour)
+	expired.PreviousExpiry = time.Now().Add(-time.Second)
+
+	tests := []struct {
+		name  string
+		agent *AgentRegistry
+		token string
+		want  bool
+	}{
+		{name: "current token", agent: agent, token: agent.AccessToken, want: true},
+		{name: "previous token in grace period", agent: agent, token: previous, want: true},
+		{name: "previous token after grace period", agent: expired, token: expiredPrevious},
+		{name: "other token", agent: agent, token: "not-a-token"},
+		{name: "prefix of the current token", agent: agent, token: agent.AccessToken[:10]},
+		{name: "empty token", agent: &AgentRegistry{}, token: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.agent.MatchesToken(tt.token); got != tt.want {
+				t.Errorf("MatchesToken(%q) = %v, want %v", tt.token, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAgentRegistryHasIssued(t *testing.T) {
+	agent := NewAgentRegistry("agent-1", "web-01", "10.0.0.1", "1.0.0", nil, time.Hour)
+	previous := agent.AccessToken
+	agent.RenewToken(time.Hour)
+	// Long after the renewal, and the current token expired
+	agent.PreviousExpiry = time.Now().Add(-time.Hour)
+	agent.TokenExpiry = time.Now().Add(-time.Minute)
+
+	revoked := NewAgentRegistry("agent-2", "web-02", "10.0.0.2", "1.0.0", nil, time.Hour)
+	revoked.Revoke()
+
+	tests := []struct {
+		name  string
+		agent *AgentRegistry
+		token string
+		want  bool
+	}{
+		{name: "expired current token", agent: agent, token: agent.AccessToken, want: true},
+		{name: "previous token after grace period", agent: agent, token: previous, want: true},
+		{name: "other token", agent: agent, token: "not-a-token"},
+		{name: "empty token of revoked agent", agent: revoked, token: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.agent.HasIssued(tt.token); got != tt.want {
+				t.Errorf("HasIssued(%q) = %v, want %v", tt.token, got, tt.want)
+			}
+		})
+	}
+}
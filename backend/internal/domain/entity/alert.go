@@ -0,0 +1,83 @@
+// Package entity defines core business entities
+package entity
+
+import "time"
+
+// Alert represents a threshold breach raised against a host
+type Alert struct {
+	ID         string
+	Hostname   string
+	AgentID    string
+	PolicyID   string
+	AlertType  string
+	Severity   AlertSeverity
+	Title      string
+	Message    string
+	Metric     string
+	Value      float64
+	Threshold  float64
+	Status     AlertStatus
+	Metadata   map[string]string
+	CreatedAt  time.Time
+	ResolvedAt *time.Time
+}
+
+// AlertSeverity represents how urgent an alert is
+type AlertSeverity string
+
+const (
+	AlertSeverityCritical AlertSeverity = "critical"
+	AlertSeverityHigh     AlertSeverity = "high"
+	AlertSeverityMedium   AlertSeverity = "medium"
+	AlertSeverityLow      AlertSeverity = "low"
+)
+
+// AlertStatus represents the lifecycle state of an alert
+type AlertStatus string
+
+const (
+	AlertStatusActive   AlertStatus = "active"
+	AlertStatusResolved AlertStatus = "resolved"
+)
+
+// AlertTypePolicy is the alert type used for policy threshold breaches
+const AlertTypePolicy = "policy_threshold"
+
+// AlertTypeAvailability is the alert type used for hosts that stopped reporting
+const AlertTypeAvailability = "host_availability"
+
+// ParseAlertSeverity converts a string to a known severity
+func ParseAlertSeverity(value string) (AlertSeverity, bool) {
+	switch AlertSeverity(value) {
+	case AlertSeverityCritical, AlertSeverityHigh, AlertSeverityMedium, AlertSeverityLow:
+		return AlertSeverity(value), true
+	}
+	return "", false
+}
+
+// NewAlert creates a new active alert
+func NewAlert(hostname, agentID, alertType string, severity AlertSeverity, title, message string) *Alert {
+	return &Alert{
+		Hostname:  hostname,
+		AgentID:   agentID,
+		AlertType: alertType,
+		Severity:  severity,
+		Title:     title,
+		Message:   message,
+		Status:    AlertStatusActive,
+		Metadata:  make(map[string]string),
+		CreatedAt: time.Now(),
+	}
+}
+
+// Resolve marks the alert as resolved
+func (a *Alert) Resolve() {
+	now := time.Now()
+	a.Status = AlertStatusResolved
+	a.ResolvedAt = &now
+}
+
+// IsActive checks if the alert is still open
+func (a *Alert) IsActive() bool {
+	return a.Status == AlertStatusActive
+}
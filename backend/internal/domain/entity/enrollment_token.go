@@ -0,0 +1,128 @@
+// Package entity defines core business entities
+package entity
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"strings"
+	"time"
+)
+
+// EnrollmentToken allows agents to register with the backend. It is created
+// by an admin, may expire, limit how many agents it enrolls and which
+// hostnames may use it, and pre-assigns labels and policies to those agents.
+// Only a hash of the secret is kept.
+type EnrollmentToken struct {
+	TokenID         string
+	Description     string
+	SecretHash      string            // hex SHA-256 of the secret
+	HostnamePattern string            // glob the agent's hostname must match, empty matches any
+	Labels          map[string]string // merged into the metadata of enrolled agents
+	PolicyIDs       []string          // policies applied to enrolled agents
+	MaxUses         int               // 0 means unlimited
+	Uses            int
+	ExpiresAt       time.Time // zero means it never expires
+	Revoked         bool
+	CreatedAt       time.Time
+	LastUsedAt      time.Time
+}
+
+// NewEnrollmentToken creates an enrollment token and returns it with the
+// secret agents present, in the form "<token ID>.<random hex>"
+func NewEnrollmentToken(tokenID, description, hostnamePattern string, labels map[string]string, policyIDs []string, maxUses int, expiresAt time.Time) (*EnrollmentToken, string) {
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	if policyIDs == nil {
+		policyIDs = []string{}
+	}
+
+	secret := tokenID + "." + generateAccessToken()
+
+	return &EnrollmentToken{
+		TokenID:         tokenID,
+		Description:     description,
+		SecretHash:      hashEnrollmentSecret(secret),
+		HostnamePattern: hostnamePattern,
+		Labels:          labels,
+		PolicyIDs:       policyIDs,
+		MaxUses:         maxUses,
+		ExpiresAt:       expiresAt,
+		CreatedAt:       time.Now(),
+	}, secret
+}
+
+// Validate checks the token's settings
+func (t *EnrollmentToken) Validate() error {
+	if t.MaxUses < 0 {
+		return errors.New("max uses must not be negative")
+	}
+	if t.HostnamePattern != "" {
+		if _, err := path.Match(t.HostnamePattern, ""); err != nil {
+			return fmt.Errorf("invalid hostname pattern %q: %w", t.HostnamePattern, err)
+		}
+	}
+	return nil
+}
+
+// MatchesSecret checks in constant time if secret belongs to the token
+func (t *EnrollmentToken) MatchesSecret(secret string) bool {
+	hash := hashEnrollmentSecret(secret)
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(t.SecretHash)) == 1
+}
+
+// CheckUsable reports why the token cannot enroll an agent with hostname, nil if it can
+func (t *EnrollmentToken) CheckUsable(hostname string) error {
+	switch {
+	case t.Revoked:
+		return errors.New("enrollment token has been revoked")
+	case !t.ExpiresAt.IsZero() && !time.Now().Before(t.ExpiresAt):
+		return errors.New("enrollment token has expired")
+	case t.MaxUses > 0 && t.Uses >= t.MaxUses:
+		return errors.New("enrollment token has been used up")
+	}
+	if t.HostnamePattern != "" {
+		if ok, _ := path.Match(t.HostnamePattern, hostname); !ok {
+			return fmt.Errorf("hostname %q is not allowed by enrollment token", hostname)
+		}
+	}
+	return nil
+}
+
+// Use records an enrollment
+func (t *EnrollmentToken) Use() {
+	t.Uses++
+	t.LastUsedAt = time.Now()
+}
+
+// Revoke stops the token from enrolling further agents
+func (t *EnrollmentToken) Revoke() {
+	t.Revoked = true
+}
+
+// Clone returns a copy that does not share maps or slices with the token
+func (t *EnrollmentToken) Clone() *EnrollmentToken {
+	c := *t
+	c.Labels = maps.Clone(t.Labels)
+	c.PolicyIDs = append([]string(nil), t.PolicyIDs...)
+	return &c
+}
+
+// EnrollmentTokenID extracts the token ID from a secret, empty if malformed
+func EnrollmentTokenID(secret string) string {
+	if i := strings.LastIndex(secret, "."); i > 0 {
+		return secret[:i]
+	}
+	return ""
+}
+
+// hashEnrollmentSecret returns the hex SHA-256 of an enrollment secret
+func hashEnrollmentSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,46 @@
+// Package entity defines core business entities
+package entity
+
+import "time"
+
+// Event records something that happened to a host, such as it going offline
+type Event struct {
+	ID        string
+	Hostname  string
+	AgentID   string
+	EventType string // security, performance, availability, etc
+	EventName string
+	Level     EventLevel
+	Message   string
+	Source    string
+	Details   map[string]string
+	Timestamp time.Time
+}
+
+// EventLevel represents how important an event is
+type EventLevel string
+
+const (
+	EventLevelInfo     EventLevel = "info"
+	EventLevelWarning  EventLevel = "warning"
+	EventLevelError    EventLevel = "error"
+	EventLevelCritical EventLevel = "critical"
+)
+
+// EventTypeAvailability is the event type of host liveness transitions
+const EventTypeAvailability = "availability"
+
+// NewEvent creates an event stamped with the current time
+func NewEvent(hostname, agentID, eventType, eventName string, level EventLevel, message string) *Event {
+	return &Event{
+		Hostname:  hostname,
+		AgentID:   agentID,
+		EventType: eventType,
+		EventName: eventName,
+		Level:     level,
+		Message:   message,
+		Source:    "backend",
+		Details:   make(map[string]string),
+		Timestamp: time.Now(),
+	}
+}
@@ -1,7 +1,10 @@
 // Package entity defines core business entities
 package entity
 
-import "time"
+import (
+	"maps"
+	"time"
+)
 
 // Host represents a monitored host/agent
 type Host struct {
@@ -15,6 +18,9 @@ type Host struct {
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 	LastSeenAt   time.Time
+
+	ReportInterval  time.Duration // how often the agent samples, zero if not reported
+	StatusChangedAt time.Time
 }
 
 // HostStatus represents the status of a host
@@ -22,6 +28,7 @@ type HostStatus string
 
 const (
 	HostStatusOnline  HostStatus = "online"
+	HostStatusStale   HostStatus = "stale" // samples are late, not yet offline
 	HostStatusOffline HostStatus = "offline"
 	HostStatusUnknown HostStatus = "unknown"
 )
@@ -38,25 +45,51 @@ func NewHost(hostname, ipAddress, agentID string) *Host {
 		CreatedAt:  now,
 		UpdatedAt:  now,
 		LastSeenAt: now,
+
+		StatusChangedAt: now,
 	}
 }
 
 // UpdateStatus updates host status
 func (h *Host) UpdateStatus(status HostStatus) {
+	now := time.Now()
+	if status != h.Status {
+		h.StatusChangedAt = now
+	}
 	h.Status = status
-	h.UpdatedAt = time.Now()
+	h.UpdatedAt = now
 }
 
 // MarkSeen updates last seen timestamp
 func (h *Host) MarkSeen() {
 	h.LastSeenAt = time.Now()
 	h.UpdatedAt = time.Now()
-	h.Status = HostStatusOnline
+	h.UpdateStatus(HostStatusOnline)
 }
 
-// IsActive checks if host has been seen recently (within 30 seconds)
+// IsActive checks if the host still reports, even if late
 func (h *Host) IsActive() bool {
-	return time.Since(h.LastSeenAt) < 30*time.Second
+	return h.Status == HostStatusOnline || h.Status == HostStatusStale
+}
+
+// StatusAt returns the status the host has at now when it becomes stale once
+// staleAfter and offline once offlineAfter passed without a sample
+func (h *Host) StatusAt(now time.Time, staleAfter, offlineAfter time.Duration) HostStatus {
+	silent := now.Sub(h.LastSeenAt)
+	switch {
+	case silent >= offlineAfter:
+		return HostStatusOffline
+	case silent >= staleAfter:
+		return HostStatusStale
+	}
+	return HostStatusOnline
+}
+
+// Clone returns a copy that does not share metadata with the host
+func (h *Host) Clone() *Host {
+	c := *h
+	c.Metadata = maps.Clone(h.Metadata)
+	return &c
 }
 
 // UpdateMetadata updates or adds metadata
@@ -2,6 +2,8 @@
 package entity
 
 import (
+	"fmt"
+	"sort"
 	"time"
 )
 
@@ -10,7 +12,8 @@ type Policy struct {
 	PolicyID       string
 	Name           string
 	Description    string
-	Thresholds     map[string]string // e.g., {"cpu": "80", "ram": "90"}
+	Thresholds     map[string]string // Deprecated: legacy thresholds, migrated into Rules
+	Rules          []PolicyRule      // e.g., cpu > 80 for 5m
 	Actions        []string          // e.g., ["alert", "restart", "email"]
 	Metadata       map[string]string
 	Enabled        bool
@@ -30,11 +33,11 @@ const (
 )
 
 // NewPolicy creates a new policy
-func NewPolicy(policyID, name, description string, thresholds map[string]string, actions []string, metadata map[string]string) *Policy {
+func NewPolicy(policyID, name, description string, rules []PolicyRule, actions []string, metadata map[string]string) *Policy {
 	now := time.Now()
 
-	if thresholds == nil {
-		thresholds = make(map[string]string)
+	if rules == nil {
+		rules = []PolicyRule{}
 	}
 	if actions == nil {
 		actions = []string{}
@@ -47,7 +50,8 @@ func NewPolicy(policyID, name, description string, thresholds map[string]string,
 		PolicyID:       policyID,
 		Name:           name,
 		Description:    description,
-		Thresholds:     thresholds,
+		Thresholds:     make(map[string]string),
+		Rules:          rules,
 		Actions:        actions,
 		Metadata:       metadata,
 		Enabled:        true,
@@ -59,15 +63,15 @@ func NewPolicy(policyID, name, description string, thresholds map[string]string,
 }
 
 // Update updates policy fields
-func (p *Policy) Update(name, description string, thresholds map[string]string, actions []string, metadata map[string]string) {
+func (p *Policy) Update(name, description string, rules []PolicyRule, actions []string, metadata map[string]string) {
 	if name != "" {
 		p.Name = name
 	}
 	if description != "" {
 		p.Description = description
 	}
-	if thresholds != nil {
-		p.Thresholds = thresholds
+	if rules != nil {
+		p.Rules = rules
 	}
 	if actions != nil {
 		p.Actions = actions
@@ -78,6 +82,59 @@ func (p *Policy) Update(name, description string, thresholds map[string]string,
 	p.UpdatedAt = time.Now()
 }
 
+// HasLegacyThresholds checks if the policy still carries map-based thresholds
+func (p *Policy) HasLegacyThresholds() bool {
+	return len(p.Thresholds) > 0
+}
+
+// MigrateThresholds converts legacy map-based thresholds into typed rules
+func (p *Policy) MigrateThresholds() error {
+	if !p.HasLegacyThresholds() {
+		return nil
+	}
+
+	rules, err := RulesFromThresholds(p.Thresholds)
+	if err != nil {
+		return err
+	}
+
+	p.Rules = append(p.Rules, rules...)
+	p.Thresholds = make(map[string]string)
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// LegacyThresholds returns the rules as map-based threshold expressions.
+// Only the first rule of each metric is represented.
+func (p *Policy) LegacyThresholds() map[string]string {
+	thresholds := make(map[string]string, len(p.Rules))
+	for i := range p.Rules {
+		if _, exists := thresholds[p.Rules[i].Metric]; !exists {
+			thresholds[p.Rules[i].Metric] = p.Rules[i].Expression()
+		}
+	}
+	return thresholds
+}
+
+// RulesFromThresholds parses map-based thresholds into typed rules
+func RulesFromThresholds(thresholds map[string]string) ([]PolicyRule, error) {
+	metrics := make([]string, 0, len(thresholds))
+	for metric := range thresholds {
+		metrics = append(metrics, metric)
+	}
+	sort.Strings(metrics)
+
+	rules := make([]PolicyRule, 0, len(metrics))
+	for _, metric := range metrics {
+		rule, err := ParsePolicyRule(metric, thresholds[metric])
+		if err != nil {
+			return nil, fmt.Errorf("invalid threshold: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
 // Enable enables the policy
 func (p *Policy) Enable() {
 	p.Enabled = true
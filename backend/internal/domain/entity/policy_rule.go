@@ -0,0 +1,213 @@
+// Package entity defines policy management
+package entity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Comparator is the comparison operator of a policy rule
+type Comparator string
+
+const (
+	ComparatorGreaterThan    Comparator = ">"
+	ComparatorGreaterOrEqual Comparator = ">="
+	ComparatorLessThan       Comparator = "<"
+	ComparatorLessOrEqual    Comparator = "<="
+)
+
+// PolicyRule is a typed policy threshold, e.g. cpu > 80 for 5m.
+//
+// A rule fires once Compare(metric, Value) has held for at least For, and
+// recovers only when the metric crosses back over Clear. Clear defaults to
+// Value; setting it apart from Value stops alerts flapping around the trigger.
+type PolicyRule struct {
+	Metric     string
+	Comparator Comparator
+	Value      float64
+	Clear      float64
+	For        time.Duration
+	Severity   AlertSeverity // empty means the policy default
+}
+
+// NewPolicyRule creates a rule that clears at its trigger value
+func NewPolicyRule(metric string, comparator Comparator, value float64, forDuration time.Duration, severity AlertSeverity) PolicyRule {
+	return PolicyRule{
+		Metric:     metric,
+		Comparator: comparator,
+		Value:      value,
+		Clear:      value,
+		For:        forDuration,
+		Severity:   severity,
+	}
+}
+
+// ParsePolicyRule parses a legacy threshold expression for a metric.
+//
+// Expressions have the form
+//
+//	[<comparator>] <value> [for <duration>] [clear <level>]
+//
+// e.g. {"cpu": "80 for 5m clear 70"}. The comparator defaults to ">".
+func ParsePolicyRule(metric, expr string) (PolicyRule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) == 0 {
+		return PolicyRule{}, fmt.Errorf("%s: empty threshold", metric)
+	}
+
+	comparator := ComparatorGreaterThan
+	for _, c := range []Comparator{ComparatorGreaterOrEqual, ComparatorLessOrEqual, ComparatorGreaterThan, ComparatorLessThan} {
+		if strings.HasPrefix(fields[0], string(c)) {
+			comparator = c
+			fields[0] = strings.TrimPrefix(fields[0], string(c))
+			if fields[0] == "" {
+				fields = fields[1:]
+			}
+			break
+		}
+	}
+	if len(fields) == 0 {
+		return PolicyRule{}, fmt.Errorf("%s: missing threshold value", metric)
+	}
+
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return PolicyRule{}, fmt.Errorf("%s: invalid threshold %q", metric, fields[0])
+	}
+
+	rule := NewPolicyRule(metric, comparator, value, 0, "")
+
+	rest := fields[1:]
+	for len(rest) > 0 {
+		if len(rest) < 2 {
+			return PolicyRule{}, fmt.Errorf("%s: missing value after %q", metric, rest[0])
+		}
+		switch strings.ToLower(rest[0]) {
+		case "for":
+			d, err := time.ParseDuration(rest[1])
+			if err != nil {
+				return PolicyRule{}, fmt.Errorf("%s: invalid duration %q", metric, rest[1])
+			}
+			rule.For = d
+		case "clear":
+			level, err := strconv.ParseFloat(rest[1], 64)
+			if err != nil {
+				return PolicyRule{}, fmt.Errorf("%s: invalid clear level %q", metric, rest[1])
+			}
+			rule.Clear = level
+		default:
+			return PolicyRule{}, fmt.Errorf("%s: unexpected %q", metric, rest[0])
+		}
+		rest = rest[2:]
+	}
+
+	return rule, nil
+}
+
+// Normalize resolves metric aliases and fills defaults
+func (r *PolicyRule) Normalize() {
+	if name, ok := CanonicalMetric(r.Metric); ok {
+		r.Metric = name
+	}
+	if r.Comparator == "" {
+		r.Comparator = ComparatorGreaterThan
+	}
+}
+
+// Validate checks that the rule is well formed
+func (r *PolicyRule) Validate() error {
+	if _, ok := CanonicalMetric(r.Metric); !ok {
+		return fmt.Errorf("unknown metric %q", r.Metric)
+	}
+
+	switch r.Comparator {
+	case ComparatorGreaterThan, ComparatorGreaterOrEqual:
+		if r.Clear > r.Value {
+			return fmt.Errorf("%s: clear level %g must not be above trigger %g", r.Metric, r.Clear, r.Value)
+		}
+	case ComparatorLessThan, ComparatorLessOrEqual:
+		if r.Clear < r.Value {
+			return fmt.Errorf("%s: clear level %g must not be below trigger %g", r.Metric, r.Clear, r.Value)
+		}
+	default:
+		return fmt.Errorf("%s: unknown comparator %q", r.Metric, r.Comparator)
+	}
+
+	if r.Value < 0 {
+		return fmt.Errorf("%s: value %g must not be negative", r.Metric, r.Value)
+	}
+	if r.Clear < 0 {
+		return fmt.Errorf("%s: clear level %g must not be negative", r.Metric, r.Clear)
+	}
+	if IsPercentMetric(r.Metric) {
+		if r.Value > 100 {
+			return fmt.Errorf("%s: value %g must be between 0 and 100", r.Metric, r.Value)
+		}
+		if r.Clear > 100 {
+			return fmt.Errorf("%s: clear level %g must be between 0 and 100", r.Metric, r.Clear)
+		}
+	}
+	if r.For < 0 {
+		return fmt.Errorf("%s: duration must not be negative", r.Metric)
+	}
+	if r.Severity != "" {
+		if _, ok := ParseAlertSeverity(string(r.Severity)); !ok {
+			return fmt.Errorf("%s: unknown severity %q", r.Metric, r.Severity)
+		}
+	}
+
+	return nil
+}
+
+// IsBreached checks if a value meets the trigger condition
+func (r *PolicyRule) IsBreached(value float64) bool {
+	switch r.Comparator {
+	case ComparatorGreaterOrEqual:
+		return value >= r.Value
+	case ComparatorLessThan:
+		return value < r.Value
+	case ComparatorLessOrEqual:
+		return value <= r.Value
+	default:
+		return value > r.Value
+	}
+}
+
+// IsCleared checks if a value has recovered to the clear level
+func (r *PolicyRule) IsCleared(value float64) bool {
+	switch r.Comparator {
+	case ComparatorLessThan, ComparatorLessOrEqual:
+		return value >= r.Clear
+	default:
+		return value <= r.Clear
+	}
+}
+
+// Expression formats the rule as a legacy threshold expression
+func (r *PolicyRule) Expression() string {
+	expr := strconv.FormatFloat(r.Value, 'f', -1, 64)
+	if r.Comparator != ComparatorGreaterThan {
+		expr = string(r.Comparator) + " " + expr
+	}
+	if r.For > 0 {
+		expr += " for " + r.For.String()
+	}
+	if r.Clear != r.Value {
+		expr += " clear " + strconv.FormatFloat(r.Clear, 'f', -1, 64)
+	}
+	return expr
+}
+
+// String formats the rule, e.g. "cpu > 80 for 5m0s"
+func (r *PolicyRule) String() string {
+	expr := fmt.Sprintf("%s %s %g", r.Metric, r.Comparator, r.Value)
+	if r.For > 0 {
+		expr += " for " + r.For.String()
+	}
+	if r.Clear != r.Value {
+		expr += fmt.Sprintf(" clear %g", r.Clear)
+	}
+	return expr
+}
@@ -0,0 +1,158 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePolicyRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		metric  string
+		expr    string
+		want    PolicyRule
+		wantErr bool
+	}{
+		{
+			name:   "value only",
+			metric: "cpu",
+			expr:   "80",
+			want:   PolicyRule{Metric: "cpu", Comparator: ComparatorGreaterThan, Value: 80, Clear: 80},
+		},
+		{
+			name:   "all parts",
+			metric: "cpu",
+			expr:   "> 80 for 5m clear 70",
+			want:   PolicyRule{Metric: "cpu", Comparator: ComparatorGreaterThan, Value: 80, Clear: 70, For: 5 * time.Minute},
+		},
+		{
+			name:   "comparator attached to value",
+			metric: "ram",
+			expr:   ">=90.5",
+			want:   PolicyRule{Metric: "ram", Comparator: ComparatorGreaterOrEqual, Value: 90.5, Clear: 90.5},
+		},
+		{
+			name:   "less than",
+			metric: "disk",
+			expr:   "< 10",
+			want:   PolicyRule{Metric: "disk", Comparator: ComparatorLessThan, Value: 10, Clear: 10},
+		},
+		{
+			name:   "less or equal with clear before for",
+			metric: "disk",
+			expr:   "<= 10 clear 15 for 1h",
+			want:   PolicyRule{Metric: "disk", Comparator: ComparatorLessOrEqual, Value: 10, Clear: 15, For: time.Hour},
+		},
+		{
+			name:   "keywords in any case and extra spaces",
+			metric: "cpu",
+			expr:   "  80   FOR 30s  Clear 60 ",
+			want:   PolicyRule{Metric: "cpu", Comparator: ComparatorGreaterThan, Value: 80, Clear: 60, For: 30 * time.Second},
+		},
+		{name: "empty", metric: "cpu", expr: "  ", wantErr: true},
+		{name: "comparator only", metric: "cpu", expr: ">=", wantErr: true},
+		{name: "invalid value", metric: "cpu", expr: "high", wantErr: true},
+		{name: "missing duration", metric: "cpu", expr: "80 for", wantErr: true},
+		{name: "invalid duration", metric: "cpu", expr: "80 for 5", wantErr: true},
+		{name: "invalid clear level", metric: "cpu", expr: "80 clear low", wantErr: true},
+		{name: "unknown keyword", metric: "cpu", expr: "80 during 5m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePolicyRule(tt.metric, tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePolicyRule(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePolicyRule(%q) = %+v, want %+v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePolicyRuleExpressionRoundTrip(t *testing.T) {
+	rules := []PolicyRule{
+		NewPolicyRule("cpu", ComparatorGreaterThan, 80, 0, ""),
+		{Metric: "ram", Comparator: ComparatorGreaterOrEqual, Value: 90.5, Clear: 85, For: 90 * time.Second},
+		{Metric: "disk", Comparator: ComparatorLessThan, Value: 5, Clear: 10},
+	}
+
+	for _, rule := range rules {
+		got, err := ParsePolicyRule(rule.Metric, rule.Expression())
+		if err != nil {
+			t.Fatalf("ParsePolicyRule(%q) error = %v", rule.Expression(), err)
+		}
+		if got != rule {
+			t.Errorf("ParsePolicyRule(%q) = %+v, want %+v", rule.Expression(), got, rule)
+		}
+	}
+}
+
+func TestPolicyRuleValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    PolicyRule
+		wantErr bool
+	}{
+		{name: "valid", rule: PolicyRule{Metric: "cpu", Comparator: ComparatorGreaterThan, Value: 80, Clear: 70, For: time.Minute, Severity: AlertSeverityHigh}},
+		{name: "metric alias", rule: PolicyRule{Metric: "memory_usage", Comparator: ComparatorGreaterOrEqual, Value: 90, Clear: 90}},
+		{name: "below trigger clears above", rule: PolicyRule{Metric: "disk", Comparator: ComparatorLessThan, Value: 5, Clear: 10}},
+		{name: "rate above 100", rule: PolicyRule{Metric: "network_tx", Comparator: ComparatorGreaterThan, Value: 1e6, Clear: 5e5}},
+		{name: "percent at 100", rule: PolicyRule{Metric: "ram", Comparator: ComparatorGreaterOrEqual, Value: 100, Clear: 100}},
+		{name: "unknown metric", rule: PolicyRule{Metric: "temperature", Comparator: ComparatorGreaterThan, Value: 80, Clear: 80}, wantErr: true},
+		{name: "unknown comparator", rule: PolicyRule{Metric: "cpu", Comparator: "==", Value: 80, Clear: 80}, wantErr: true},
+		{name: "empty comparator", rule: PolicyRule{Metric: "cpu", Value: 80, Clear: 80}, wantErr: true},
+		{name: "clear above trigger", rule: PolicyRule{Metric: "cpu", Comparator: ComparatorGreaterThan, Value: 80, Clear: 90}, wantErr: true},
+		{name: "clear below trigger", rule: PolicyRule{Metric: "disk", Comparator: ComparatorLessOrEqual, Value: 10, Clear: 5}, wantErr: true},
+		{name: "negative value", rule: PolicyRule{Metric: "disk", Comparator: ComparatorLessThan, Value: -1, Clear: 5}, wantErr: true},
+		{name: "negative clear level", rule: PolicyRule{Metric: "cpu", Comparator: ComparatorGreaterThan, Value: 10, Clear: -1}, wantErr: true},
+		{name: "percent above 100", rule: PolicyRule{Metric: "cpu", Comparator: ComparatorGreaterThan, Value: 120, Clear: 90}, wantErr: true},
+		{name: "clear level above 100", rule: PolicyRule{Metric: "disk", Comparator: ComparatorLessThan, Value: 90, Clear: 110}, wantErr: true},
+		{name: "negative duration", rule: PolicyRule{Metric: "cpu", Comparator: ComparatorGreaterThan, Value: 80, Clear: 80, For: -time.Second}, wantErr: true},
+		{name: "unknown severity", rule: PolicyRule{Metric: "cpu", Comparator: ComparatorGreaterThan, Value: 80, Clear: 80, Severity: "urgent"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPolicyRuleIsBreachedIsCleared(t *testing.T) {
+	tests := []struct {
+		name         string
+		rule         PolicyRule
+		value        float64
+		wantBreached bool
+		wantCleared  bool
+	}{
+		{name: "> above", rule: PolicyRule{Comparator: ComparatorGreaterThan, Value: 80, Clear: 70}, value: 85, wantBreached: true},
+		{name: "> at trigger", rule: PolicyRule{Comparator: ComparatorGreaterThan, Value: 80, Clear: 70}, value: 80},
+		{name: "> between clear and trigger", rule: PolicyRule{Comparator: ComparatorGreaterThan, Value: 80, Clear: 70}, value: 75},
+		{name: "> at clear level", rule: PolicyRule{Comparator: ComparatorGreaterThan, Value: 80, Clear: 70}, value: 70, wantCleared: true},
+		{name: ">= at trigger", rule: PolicyRule{Comparator: ComparatorGreaterOrEqual, Value: 80, Clear: 80}, value: 80, wantBreached: true, wantCleared: true},
+		{name: ">= below", rule: PolicyRule{Comparator: ComparatorGreaterOrEqual, Value: 80, Clear: 80}, value: 79.9, wantCleared: true},
+		{name: "< below", rule: PolicyRule{Comparator: ComparatorLessThan, Value: 10, Clear: 20}, value: 5, wantBreached: true},
+		{name: "< at trigger", rule: PolicyRule{Comparator: ComparatorLessThan, Value: 10, Clear: 20}, value: 10},
+		{name: "< at clear level", rule: PolicyRule{Comparator: ComparatorLessThan, Value: 10, Clear: 20}, value: 20, wantCleared: true},
+		{name: "<= at trigger", rule: PolicyRule{Comparator: ComparatorLessOrEqual, Value: 10, Clear: 10}, value: 10, wantBreached: true, wantCleared: true},
+		{name: "<= above", rule: PolicyRule{Comparator: ComparatorLessOrEqual, Value: 10, Clear: 10}, value: 11, wantCleared: true},
+		{name: "empty comparator is >", rule: PolicyRule{Value: 80, Clear: 80}, value: 81, wantBreached: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.IsBreached(tt.value); got != tt.wantBreached {
+				t.Errorf("IsBreached(%g) = %v, want %v", tt.value, got, tt.wantBreached)
+			}
+			if got := tt.rule.IsCleared(tt.value); got != tt.wantCleared {
+				t.Errorf("IsCleared(%g) = %v, want %v", tt.value, got, tt.wantCleared)
+			}
+		})
+	}
+}
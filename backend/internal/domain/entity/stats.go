@@ -1,7 +1,25 @@
 // Package entity defines core business entities
 package entity
 
-import "time"
+import (
+	"strings"
+	"time"
+)
+
+// Metric names that policies can evaluate
+const (
+	MetricCPU  = "cpu"
+	MetricRAM  = "ram"
+	MetricDisk = "disk"
+
+	MetricNetworkTx     = "network_tx"     // bytes/sec sent
+	MetricNetworkRx     = "network_rx"     // bytes/sec received
+	MetricNetworkErrors = "network_errors" // errors/sec
+	MetricNetworkDrops  = "network_drops"  // dropped packets/sec
+)
+
+// MaxClockSkew is how far ahead of the backend clock a sample may be stamped
+const MaxClockSkew = 5 * time.Minute
 
 // Stats represents system metrics for a host
 type Stats struct {
@@ -14,6 +32,89 @@ type Stats struct {
 	Timestamp    time.Time
 	LastReceived time.Time
 	Metadata     map[string]string // Additional metadata
+
+	ExtendedMetrics
+	CollectorErrors []CollectorError // agent plugins that failed on this sample
+	ReportInterval  time.Duration    // how often the agent samples, zero if not reported
+}
+
+// CollectorError reports a collector plugin that failed on the agent
+type CollectorError struct {
+	Collector string
+	Message   string
+}
+
+// ExtendedMetrics holds the load, capacity and counter metrics of a sample.
+// Fields are zero when the agent does not report them.
+type ExtendedMetrics struct {
+	Load1        float64
+	Load5        float64
+	Load15       float64
+	CPUCores     int
+	RAMTotal     uint64 // bytes
+	RAMUsed      uint64 // bytes
+	DiskTotal    uint64 // bytes
+	DiskUsed     uint64 // bytes
+	Uptime       uint64 // seconds
+	ProcessCount uint64
+	NetworkSent  uint64 // bytes sent since boot
+	NetworkRecv  uint64 // bytes received since boot
+
+	// Network rates over all interfaces except loopback
+	NetworkSentRate   float64 // bytes/sec
+	NetworkRecvRate   float64 // bytes/sec
+	NetworkErrorsRate float64 // errors/sec
+	NetworkDropsRate  float64 // dropped packets/sec
+
+	Cores      []CPUCoreUsage
+	Partitions []DiskPartition
+	Interfaces []NetworkInterface
+	Custom     map[string]float64 // values reported by additional agent plugins
+}
+
+// CPUCoreUsage is the utilisation of one logical core
+type CPUCoreUsage struct {
+	Core    string // e.g. cpu0
+	Percent float64
+}
+
+// DiskPartition is the usage of one mounted filesystem
+type DiskPartition struct {
+	MountPoint        string
+	Device            string
+	FSType            string
+	Total             uint64 // bytes
+	Used              uint64 // bytes
+	Free              uint64 // bytes
+	UsedPercent       float64
+	InodesTotal       uint64
+	InodesUsed        uint64
+	InodesFree        uint64
+	InodesUsedPercent float64
+}
+
+// NetworkInterface holds the counters of one network interface since boot,
+// and their per-second rates since the previous sample
+type NetworkInterface struct {
+	Name        string
+	IP          string
+	BytesSent   uint64
+	BytesRecv   uint64
+	PacketsSent uint64
+	PacketsRecv uint64
+	ErrorsIn    uint64
+	ErrorsOut   uint64
+	DropsIn     uint64
+	DropsOut    uint64
+
+	BytesSentRate   float64
+	BytesRecvRate   float64
+	PacketsSentRate float64
+	PacketsRecvRate float64
+	ErrorsInRate    float64
+	ErrorsOutRate   float64
+	DropsInRate     float64
+	DropsOutRate    float64
 }
 
 // NewStats creates a new Stats instance
@@ -32,6 +133,16 @@ func NewStats(hostname, agentID, ipAddress string, cpu, ram, disk float64) *Stat
 	}
 }
 
+// SetCollectedAt sets when the agent took the sample. A zero time keeps the
+// receive time, and a time more than MaxClockSkew ahead of it is clamped to it.
+func (s *Stats) SetCollectedAt(t time.Time) {
+	if t.IsZero() || t.After(s.LastReceived.Add(MaxClockSkew)) {
+		s.Timestamp = s.LastReceived
+		return
+	}
+	s.Timestamp = t
+}
+
 // IsValid checks if stats are valid
 func (s *Stats) IsValid() bool {
 	if s.Hostname == "" {
@@ -60,3 +171,87 @@ func (s *Stats) Update(cpu, ram, disk float64) {
 	s.Timestamp = time.Now()
 	s.LastReceived = time.Now()
 }
+
+// CanonicalMetric resolves a metric name or alias to its canonical name
+func CanonicalMetric(metric string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(metric)) {
+	case MetricCPU, "cpu_usage":
+		return MetricCPU, true
+	case MetricRAM, "memory", "memory_usage":
+		return MetricRAM, true
+	case MetricDisk, "disk_usage":
+		return MetricDisk, true
+	case MetricNetworkTx, "network_sent_rate", "tx_bytes_per_sec":
+		return MetricNetworkTx, true
+	case MetricNetworkRx, "network_recv_rate", "rx_bytes_per_sec":
+		return MetricNetworkRx, true
+	case MetricNetworkErrors, "network_errors_rate":
+		return MetricNetworkErrors, true
+	case MetricNetworkDrops, "network_drops_rate":
+		return MetricNetworkDrops, true
+	}
+	return "", false
+}
+
+// IsPercentMetric reports whether a metric is a percentage between 0 and 100
+// rather than an unbounded rate
+func IsPercentMetric(metric string) bool {
+	name, _ := CanonicalMetric(metric)
+	switch name {
+	case MetricCPU, MetricRAM, MetricDisk:
+		return true
+	}
+	return false
+}
+
+// MetricValue returns the value of a named metric
+func (s *Stats) MetricValue(metric string) (float64, bool) {
+	name, ok := CanonicalMetric(metric)
+	if !ok {
+		return 0, false
+	}
+	switch name {
+	case MetricCPU:
+		return s.CPU, true
+	case MetricRAM:
+		return s.RAM, true
+	case MetricDisk:
+		return s.Disk, true
+	case MetricNetworkTx:
+		return s.NetworkSentRate, true
+	case MetricNetworkRx:
+		return s.NetworkRecvRate, true
+	case MetricNetworkErrors:
+		return s.NetworkErrorsRate, true
+	case MetricNetworkDrops:
+		return s.NetworkDropsRate, true
+	}
+	return 0, false
+}
+
+// SetMetricValue sets the value of a named metric
+func (s *Stats) SetMetricValue(metric string, value float64) bool {
+	name, ok := CanonicalMetric(metric)
+	if !ok {
+		return false
+	}
+	switch name {
+	case MetricCPU:
+		s.CPU = value
+	case MetricRAM:
+		s.RAM = value
+	case MetricDisk:
+		s.Disk = value
+	case MetricNetworkTx:
+		s.NetworkSentRate = value
+	case MetricNetworkRx:
+		s.NetworkRecvRate = value
+	case MetricNetworkErrors:
+		s.NetworkErrorsRate = value
+	case MetricNetworkDrops:
+		s.NetworkDropsRate = value
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,191 @@
+// Package entity defines core business entities
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"time"
+)
+
+// Aggregation reduces the samples within one step of a range query to a value
+type Aggregation string
+
+// Aggregation values
+const (
+	AggregationAvg Aggregation = "avg"
+	AggregationMax Aggregation = "max"
+	AggregationMin Aggregation = "min"
+	AggregationP95 Aggregation = "p95"
+)
+
+// MaxRangePoints limits how many steps a range query may span
+const MaxRangePoints = 10000
+
+// RangeMetrics returns the metrics range queries can return, by canonical name
+func RangeMetrics() []string {
+	return []string{
+		MetricCPU, MetricRAM, MetricDisk,
+		MetricNetworkTx, MetricNetworkRx, MetricNetworkErrors, MetricNetworkDrops,
+	}
+}
+
+// StatsRangeQuery asks for the metrics of a host or agent between From and To,
+// downsampled to one point per Step. Steps are aligned to multiples of Step
+// since the Unix epoch.
+type StatsRangeQuery struct {
+	Hostname    string
+	AgentID     string // with Hostname, only the host's samples from this agent
+	Metrics     []string
+	From        time.Time
+	To          time.Time
+	Step        time.Duration
+	Aggregation Aggregation
+}
+
+// Validate checks the query and resolves metric aliases to canonical names
+func (q *StatsRangeQuery) Validate() error {
+	var errs []error
+
+	if q.Hostname == "" && q.AgentID == "" {
+		errs = append(errs, errors.New("hostname or agent ID is required"))
+	}
+	if len(q.Metrics) == 0 {
+		errs = append(errs, errors.New("at least one metric is required"))
+	}
+	metrics := make([]string, 0, len(q.Metrics))
+	for _, metric := range q.Metrics {
+		name, ok := CanonicalMetric(metric)
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown metric %q", metric))
+			continue
+		}
+		if !slices.Contains(metrics, name) {
+			metrics = append(metrics, name)
+		}
+	}
+	q.Metrics = metrics
+
+	switch q.Aggregation {
+	case AggregationAvg, AggregationMax, AggregationMin, AggregationP95:
+	default:
+		errs = append(errs, fmt.Errorf("unknown aggregation %q, use avg, max, min or p95", q.Aggregation))
+	}
+
+	if !q.From.Before(q.To) {
+		errs = append(errs, errors.New("from must be before to"))
+	}
+	if q.Step < time.Second {
+		errs = append(errs, fmt.Errorf("step %v must be at least 1s", q.Step))
+	} else if q.To.Sub(q.From)/q.Step > MaxRangePoints {
+		errs = append(errs, fmt.Errorf("range spans more than %d steps of %v", MaxRangePoints, q.Step))
+	}
+
+	return errors.Join(errs...)
+}
+
+// StepStart returns the start of the step a sample taken at t falls into
+func (q *StatsRangeQuery) StepStart(t time.Time) time.Time {
+	step := q.Step.Milliseconds()
+	ms := t.UnixMilli()
+	return time.UnixMilli(ms - ((ms%step)+step)%step)
+}
+
+// StatsPoint is the aggregated value of a metric over the step starting at Timestamp
+type StatsPoint struct {
+	Timestamp time.Time
+	Value     float64
+}
+
+// StatsSeries holds the points of one metric, oldest first. Steps without
+// samples have no point.
+type StatsSeries struct {
+	Metric string
+	Points []StatsPoint
+}
+
+// StatsRangeBuilder collects samples into the steps of a range query
+type StatsRangeBuilder struct {
+	query *StatsRangeQuery
+	steps map[time.Time][][]float64 // values of each queried metric per step
+}
+
+// NewStatsRangeBuilder creates a builder for a validated query
+func NewStatsRangeBuilder(query *StatsRangeQuery) *StatsRangeBuilder {
+	return &StatsRangeBuilder{
+		query: query,
+		steps: make(map[time.Time][][]float64),
+	}
+}
+
+// Add adds a sample taken at t; value returns its value of a metric. Samples
+// outside the query range are ignored.
+func (b *StatsRangeBuilder) Add(t time.Time, value func(metric string) (float64, bool)) {
+	if t.Before(b.query.From) || t.After(b.query.To) {
+		return
+	}
+
+	start := b.query.StepStart(t)
+	values, exists := b.steps[start]
+	if !exists {
+		values = make([][]float64, len(b.query.Metrics))
+		b.steps[start] = values
+	}
+	for i, metric := range b.query.Metrics {
+		if v, ok := value(metric); ok {
+			values[i] = append(values[i], v)
+		}
+	}
+}
+
+// Series aggregates the collected samples into one series per queried metric
+func (b *StatsRangeBuilder) Series() []StatsSeries {
+	starts := slices.SortedFunc(maps.Keys(b.steps), time.Time.Compare)
+
+	series := make([]StatsSeries, len(b.query.Metrics))
+	for i, metric := range b.query.Metrics {
+		series[i] = StatsSeries{Metric: metric, Points: make([]StatsPoint, 0, len(starts))}
+		for _, start := range starts {
+			values := b.steps[start][i]
+			if len(values) == 0 {
+				continue
+			}
+			series[i].Points = append(series[i].Points, StatsPoint{
+				Timestamp: start,
+				Value:     b.query.Aggregation.Aggregate(values),
+			})
+		}
+	}
+
+	return series
+}
+
+// Aggregate reduces values with the aggregation. Percentiles interpolate
+// linearly between the closest ranks.
+func (a Aggregation) Aggregate(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	switch a {
+	case AggregationMax:
+		return slices.Max(values)
+	case AggregationMin:
+		return slices.Min(values)
+	case AggregationP95:
+		sorted := slices.Clone(values)
+		slices.Sort(sorted)
+		rank := 0.95 * float64(len(sorted)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+	}
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
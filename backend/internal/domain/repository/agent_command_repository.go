@@ -0,0 +1,25 @@
+// Package repository defines repository interfaces
+package repository
+
+import (
+	"context"
+	"smart-monitor/backend/internal/domain/entity"
+)
+
+// AgentCommandRepository defines the interface for agent command persistence
+type AgentCommandRepository interface {
+	// Create stores a new command
+	Create(ctx context.Context, cmd *entity.AgentCommand) error
+
+	// GetByID retrieves a command by ID
+	GetByID(ctx context.Context, commandID string) (*entity.AgentCommand, error)
+
+	// Update updates a command
+	Update(ctx context.Context, cmd *entity.AgentCommand) error
+
+	// ListByAgent retrieves the commands of an agent, newest first
+	ListByAgent(ctx context.Context, agentID string) ([]*entity.AgentCommand, error)
+
+	// ListPending retrieves the commands not yet received by an agent, oldest first
+	ListPending(ctx context.Context, agentID string) ([]*entity.AgentCommand, error)
+}
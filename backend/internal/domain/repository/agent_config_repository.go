@@ -0,0 +1,25 @@
+// Package repository defines repository interfaces
+package repository
+
+import (
+	"context"
+	"smart-monitor/backend/internal/domain/entity"
+)
+
+// AgentConfigRepository defines the interface for agent config profile persistence
+type AgentConfigRepository interface {
+	// Create stores a new profile
+	Create(ctx context.Context, profile *entity.AgentConfigProfile) error
+
+	// GetByID retrieves a profile by ID
+	GetByID(ctx context.Context, profileID string) (*entity.AgentConfigProfile, error)
+
+	// Update replaces a stored profile
+	Update(ctx context.Context, profile *entity.AgentConfigProfile) error
+
+	// Delete removes a profile
+	Delete(ctx context.Context, profileID string) error
+
+	// GetAll retrieves all profiles ordered by ID
+	GetAll(ctx context.Context) ([]*entity.AgentConfigProfile, error)
+}
@@ -0,0 +1,26 @@
+// Package repository defines repository interfaces
+package repository
+
+import (
+	"context"
+	"smart-monitor/backend/internal/domain/entity"
+)
+
+// AlertFilter selects alerts; empty fields match every alert
+type AlertFilter struct {
+	AgentID   string
+	PolicyID  string
+	AlertType string
+}
+
+// AlertRepository defines the interface for alert persistence
+type AlertRepository interface {
+	// Create stores a new alert and returns its ID
+	Create(ctx context.Context, alert *entity.Alert) (string, error)
+
+	// Resolve marks an alert as resolved
+	Resolve(ctx context.Context, alertID string) error
+
+	// ListActive retrieves the unresolved alerts matching a filter
+	ListActive(ctx context.Context, filter AlertFilter) ([]*entity.Alert, error)
+}
@@ -0,0 +1,25 @@
+// Package repository defines repository interfaces
+package repository
+
+import (
+	"context"
+	"smart-monitor/backend/internal/domain/entity"
+)
+
+// EnrollmentTokenRepository defines the interface for enrollment token persistence
+type EnrollmentTokenRepository interface {
+	// Create stores a new token
+	Create(ctx context.Context, token *entity.EnrollmentToken) error
+
+	// GetByID retrieves a token by ID
+	GetByID(ctx context.Context, tokenID string) (*entity.EnrollmentToken, error)
+
+	// Update replaces a stored token
+	Update(ctx context.Context, token *entity.EnrollmentToken) error
+
+	// Delete removes a token
+	Delete(ctx context.Context, tokenID string) error
+
+	// GetAll retrieves all tokens ordered by ID
+	GetAll(ctx context.Context) ([]*entity.EnrollmentToken, error)
+}
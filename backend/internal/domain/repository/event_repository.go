@@ -0,0 +1,13 @@
+// Package repository defines repository interfaces
+package repository
+
+import (
+	"context"
+	"smart-monitor/backend/internal/domain/entity"
+)
+
+// EventRepository defines the interface for event persistence
+type EventRepository interface {
+	// Log stores an event and returns its ID
+	Log(ctx context.Context, event *entity.Event) (string, error)
+}
@@ -3,9 +3,15 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"smart-monitor/backend/internal/domain/entity"
+	"time"
 )
 
+// ErrStatsNotFound is returned by StatsRepository.Get and GetByAgent when a
+// host or agent has no samples
+var ErrStatsNotFound = errors.New("stats not found")
+
 // StatsRepository defines the interface for stats persistence
 type StatsRepository interface {
 	// Save stores stats
@@ -14,6 +20,9 @@ type StatsRepository interface {
 	// Get retrieves stats by hostname
 	Get(ctx context.Context, hostname string) (*entity.Stats, error)
 
+	// GetByAgent retrieves the latest stats reported by an agent
+	GetByAgent(ctx context.Context, agentID string) (*entity.Stats, error)
+
 	// GetAll retrieves all stats
 	GetAll(ctx context.Context) ([]*entity.Stats, error)
 
@@ -22,6 +31,28 @@ type StatsRepository interface {
 
 	// GetActiveHosts returns list of active hostnames
 	GetActiveHosts(ctx context.Context) ([]string, error)
+
+	// QueryRange returns one series per metric of a validated query
+	QueryRange(ctx context.Context, query *entity.StatsRangeQuery) ([]entity.StatsSeries, error)
+}
+
+// StatsBatchRepository is implemented by stats stores that can save many samples at once
+type StatsBatchRepository interface {
+	// SaveBatch stores samples in a single write
+	SaveBatch(ctx context.Context, stats []*entity.Stats) error
+}
+
+// StatsHistoryRepository is implemented by stats stores that keep past samples
+type StatsHistoryRepository interface {
+	// GetHistory retrieves samples for an agent between from and to, oldest first
+	GetHistory(ctx context.Context, agentID string, from, to time.Time) ([]*entity.Stats, error)
+}
+
+// StatsAgentReassigner is implemented by stats stores that can move the samples
+// of one agent to another, used when duplicate agents are merged
+type StatsAgentReassigner interface {
+	// ReassignAgent moves the samples of fromAgentID to toAgentID and returns how many moved
+	ReassignAgent(ctx context.Context, fromAgentID, toAgentID string) (int64, error)
 }
 
 // HostRepository defines the interface for host persistence
@@ -53,6 +84,9 @@ type AgentRegistryRepository interface {
 	// GetByToken retrieves an agent by access token
 	GetByToken(ctx context.Context, token string) (*entity.AgentRegistry, error)
 
+	// GetByFingerprint retrieves an agent by machine fingerprint
+	GetByFingerprint(ctx context.Context, fingerprint string) (*entity.AgentRegistry, error)
+
 	// Update updates an agent registry
 	Update(ctx context.Context, agent *entity.AgentRegistry) error
 
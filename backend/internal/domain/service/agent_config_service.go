@@ -0,0 +1,267 @@
+// Package service implements business logic
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/repository"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInvalidConfigProfile is returned when a profile fails validation
+	ErrInvalidConfigProfile = errors.New("invalid config profile")
+
+	// ErrConfigProfileNotFound is returned for an unknown profile ID
+	ErrConfigProfileNotFound = errors.New("config profile not found")
+
+	// ErrConfigVersionConflict is returned when a profile changed since the
+	// version the caller based its update on
+	ErrConfigVersionConflict = errors.New("config profile version conflict")
+)
+
+// AgentConfigService manages central agent config profiles and tells the
+// agents they reach to fetch them again when they change
+type AgentConfigService struct {
+	configRepo     repository.AgentConfigRepository
+	agentRepo      repository.AgentRegistryRepository
+	controlService *AgentControlService
+
+	mu sync.Mutex // serializes profile changes
+}
+
+// NewAgentConfigService creates a new agent config service
+func NewAgentConfigService(configRepo repository.AgentConfigRepository, agentRepo repository.AgentRegistryRepository, controlService *AgentControlService) *AgentConfigService {
+	return &AgentConfigService{
+		configRepo:     configRepo,
+		agentRepo:      agentRepo,
+		controlService: controlService,
+	}
+}
+
+// CreateProfile stores the first version of a profile
+func (s *AgentConfigService) CreateProfile(ctx context.Context, name, description string, settings entity.AgentSettings, selector map[string]string) (*entity.AgentConfigProfile, error) {
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidConfigProfile)
+	}
+	if err := settings.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfigProfile, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	profile := entity.NewAgentConfigProfile(generateProfileID(name), name, description, settings, selector)
+	if err := s.configRepo.Create(ctx, profile); err != nil {
+		return nil, fmt.Errorf("failed to store config profile: %w", err)
+	}
+
+	s.notifyAgents(ctx, nil, profile)
+	return profile, nil
+}
+
+// UpdateProfile stores a new version of a profile. Settings and selector are
+// replaced only when provided. A non-zero expectedVersion must match the
+// stored version.
+func (s *AgentConfigService) UpdateProfile(ctx context.Context, profileID, name, description string, settings *entity.AgentSettings, selector map[string]string, expectedVersion int64) (*entity.AgentConfigProfile, error) {
+	if settings != nil {
+		if err := settings.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidConfigProfile, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, err := s.getProfile(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+	if expectedVersion != 0 && expectedVersion != previous.Version {
+		return nil, fmt.Errorf("%w: profile %s is at version %d, not %d", ErrConfigVersionConflict, profileID, previous.Version, expectedVersion)
+	}
+
+	profile := previous.Clone()
+	profile.Update(name, description, settings, selector)
+	if err := s.configRepo.Update(ctx, profile); err != nil {
+		return nil, fmt.Errorf("failed to update config profile: %w", err)
+	}
+
+	s.notifyAgents(ctx, previous, profile)
+	return profile, nil
+}
+
+// RemoveProfile deletes a profile. The agents it reached fall back to another
+// matching profile or to their local configuration.
+func (s *AgentConfigService) RemoveProfile(ctx context.Context, profileID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	profile, err := s.getProfile(ctx, profileID)
+	if err != nil {
+		return err
+	}
+	if err := s.configRepo.Delete(ctx, profileID); err != nil {
+		return fmt.Errorf("failed to delete config profile: %w", err)
+	}
+
+	s.notifyAgents(ctx, profile, nil)
+	return nil
+}
+
+// ListProfiles retrieves all profiles
+func (s *AgentConfigService) ListProfiles(ctx context.Context) ([]*entity.AgentConfigProfile, error) {
+	return s.configRepo.GetAll(ctx)
+}
+
+// AttachProfile attaches a profile to an agent. An agent has at most one
+// attached profile, so it is detached from any other.
+func (s *AgentConfigService) AttachProfile(ctx context.Context, profileID, agentID string) error {
+	agent, err := s.agentRepo.GetByAgentID(ctx, agentID)
+	if err != nil {
+		return errors.New("agent not found")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	profile, err := s.getProfile(ctx, profileID)
+	if err != nil {
+		return err
+	}
+	if !profile.Attach(agentID) {
+		return fmt.Errorf("config profile %s is already attached to agent %s", profileID, agentID)
+	}
+
+	profiles, err := s.configRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list config profiles: %w", err)
+	}
+	for _, other := range profiles {
+		if other.ProfileID != profileID && other.Detach(agentID) {
+			if err := s.configRepo.Update(ctx, other); err != nil {
+				return fmt.Errorf("failed to detach config profile %s: %w", other.ProfileID, err)
+			}
+		}
+	}
+	if err := s.configRepo.Update(ctx, profile); err != nil {
+		return fmt.Errorf("failed to attach config profile: %w", err)
+	}
+
+	s.requestReconfigure(ctx, agent, fmt.Sprintf("config profile %s attached", profile.Revision()))
+	return nil
+}
+
+// DetachProfile removes a profile from an agent
+func (s *AgentConfigService) DetachProfile(ctx context.Context, profileID, agentID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	profile, err := s.getProfile(ctx, profileID)
+	if err != nil {
+		return err
+	}
+	if !profile.Detach(agentID) {
+		return fmt.Errorf("config profile %s is not attached to agent %s", profileID, agentID)
+	}
+	if err := s.configRepo.Update(ctx, profile); err != nil {
+		return fmt.Errorf("failed to detach config profile: %w", err)
+	}
+
+	if agent, err := s.agentRepo.GetByAgentID(ctx, agentID); err == nil {
+		s.requestReconfigure(ctx, agent, fmt.Sprintf("config profile %s detached", profile.ProfileID))
+	}
+	return nil
+}
+
+// ResolveProfile returns the profile that applies to an agent, or nil when the
+// agent runs on its local configuration. An attached profile wins over
+// selectors; among matching selectors the most specific one wins, then the
+// lowest profile ID.
+func (s *AgentConfigService) ResolveProfile(ctx context.Context, agentID string) (*entity.AgentConfigProfile, error) {
+	agent, err := s.agentRepo.GetByAgentID(ctx, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("agent not found: %w", err)
+	}
+
+	profiles, err := s.configRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list config profiles: %w", err)
+	}
+
+	return resolveProfile(profiles, agent), nil
+}
+
+// resolveProfile picks the profile for an agent from profiles ordered by ID
+func resolveProfile(profiles []*entity.AgentConfigProfile, agent *entity.AgentRegistry) *entity.AgentConfigProfile {
+	var best *entity.AgentConfigProfile
+	for _, profile := range profiles {
+		if profile.IsAttachedTo(agent.AgentID) {
+			return profile
+		}
+		if profile.Matches(agent.Metadata) && (best == nil || len(profile.Selector) > len(best.Selector)) {
+			best = profile
+		}
+	}
+	return best
+}
+
+// notifyAgents asks every active agent reached by a profile before or after a
+// change to fetch its configuration again. Either profile may be nil.
+func (s *AgentConfigService) notifyAgents(ctx context.Context, before, after *entity.AgentConfigProfile) {
+	agents, err := s.agentRepo.GetActive(ctx)
+	if err != nil {
+		log.Printf("⚠ Failed to list agents to reconfigure: %v", err)
+		return
+	}
+	sort.Slice(agents, func(i, j int) bool {
+		return agents[i].AgentID < agents[j].AgentID
+	})
+
+	reason := "config profile changed"
+	switch {
+	case after != nil:
+		reason = fmt.Sprintf("config profile %s", after.Revision())
+	case before != nil:
+		reason = fmt.Sprintf("config profile %s removed", before.ProfileID)
+	}
+
+	for _, agent := range agents {
+		if (before != nil && before.AppliesTo(agent)) || (after != nil && after.AppliesTo(agent)) {
+			s.requestReconfigure(ctx, agent, reason)
+		}
+	}
+}
+
+// requestReconfigure queues a reconfigure command for an active agent
+func (s *AgentConfigService) requestReconfigure(ctx context.Context, agent *entity.AgentRegistry, reason string) {
+	if agent.Status != entity.AgentStatusActive {
+		return
+	}
+	if _, err := s.controlService.RequestReconfigure(ctx, agent.AgentID, reason); err != nil {
+		log.Printf("⚠ Failed to ask agent %s to reconfigure: %v", agent.AgentID, err)
+	}
+}
+
+// getProfile retrieves a profile, mapping a miss to ErrConfigProfileNotFound
+func (s *AgentConfigService) getProfile(ctx context.Context, profileID string) (*entity.AgentConfigProfile, error) {
+	profile, err := s.configRepo.GetByID(ctx, profileID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrConfigProfileNotFound, profileID)
+	}
+	return profile, nil
+}
+
+// generateProfileID generates a unique profile ID
+func generateProfileID(name string) string {
+	data := fmt.Sprintf("%s-%d", name, time.Now().UnixNano())
+	hash := sha256.Sum256([]byte(data))
+	return "cfg-" + hex.EncodeToString(hash[:])[:8]
+}
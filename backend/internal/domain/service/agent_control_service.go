@@ -8,56 +8,184 @@ import (
 	"log"
 	"smart-monitor/backend/internal/domain/entity"
 	"smart-monitor/backend/internal/domain/repository"
+	"sync"
 )
 
+// commandQueueSize bounds the commands buffered for a connected agent.
+// Commands that do not fit stay pending and are delivered on the next connect.
+const commandQueueSize = 32
+
 // AgentControlService handles agent control operations
 type AgentControlService struct {
-	agentRepo repository.AgentRegistryRepository
+	agentRepo   repository.AgentRegistryRepository
+	commandRepo repository.AgentCommandRepository
+
+	mu       sync.Mutex
+	sessions map[string]chan *entity.AgentCommand // key: agentID, connected command channels
 }
 
 // NewAgentControlService creates a new agent control service
-func NewAgentControlService(agentRepo repository.AgentRegistryRepository) *AgentControlService {
+func NewAgentControlService(agentRepo repository.AgentRegistryRepository, commandRepo repository.AgentCommandRepository) *AgentControlService {
 	return &AgentControlService{
-		agentRepo: agentRepo,
+		agentRepo:   agentRepo,
+		commandRepo: commandRepo,
+		sessions:    make(map[string]chan *entity.AgentCommand),
 	}
 }
 
-// ControlAgent sends control command to agent
-func (s *AgentControlService) ControlAgent(agentID string, action entity.AgentControlAction, reason string) error {
-	agent, err := s.agentRepo.GetByAgentID(context.Background(), agentID)
+// ControlAgent queues a control command for the agent and pushes it over the
+// agent's command channel when it is connected
+func (s *AgentControlService) ControlAgent(ctx context.Context, agentID string, action entity.AgentControlAction, reason string) (*entity.AgentCommand, error) {
+	agent, err := s.agentRepo.GetByAgentID(ctx, agentID)
 	if err != nil {
-		return errors.New("agent not found")
+		return nil, errors.New("agent not found")
 	}
 
 	if agent.Status != entity.AgentStatusActive {
-		return fmt.Errorf("agent is not active (status: %s)", agent.Status)
+		return nil, fmt.Errorf("agent is not active (status: %s)", agent.Status)
 	}
 
 	if agent.IsBlocked() {
-		return errors.New("agent is blocked")
+		return nil, errors.New("agent is blocked")
 	}
 
-	// Log the control action
-	log.Printf("Control action for agent %s: %s (reason: %s)", agentID, action, reason)
-
-	// In a real implementation, this would send a message to the agent
-	// For now, we just log it and return success
 	switch action {
-	case entity.AgentActionStart:
-		log.Printf("Agent %s: Starting...", agentID)
-	case entity.AgentActionShutdown:
-		log.Printf("Agent %s: Shutting down...", agentID)
-		agent.Suspend()
-		s.agentRepo.Update(context.Background(), agent)
-	case entity.AgentActionRestart:
-		log.Printf("Agent %s: Restarting...", agentID)
+	case entity.AgentActionStart, entity.AgentActionShutdown, entity.AgentActionRestart:
 	default:
-		return fmt.Errorf("unknown action: %s", action)
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+
+	return s.queueCommand(ctx, entity.NewAgentCommand(agentID, action, reason))
+}
+
+// RequestReconfigure asks the agent to fetch its config profile again. Agents
+// that are not connected pick the command up on their next connect.
+func (s *AgentControlService) RequestReconfigure(ctx context.Context, agentID, reason string) (*entity.AgentCommand, error) {
+	return s.queueCommand(ctx, entity.NewAgentCommand(agentID, entity.AgentActionReconfigure, reason))
+}
+
+// queueCommand stores a command and pushes it to the agent when connected
+func (s *AgentControlService) queueCommand(ctx context.Context, cmd *entity.AgentCommand) (*entity.AgentCommand, error) {
+	agentID := cmd.AgentID
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.commandRepo.Create(ctx, cmd); err != nil {
+		return nil, fmt.Errorf("failed to store command: %w", err)
+	}
+
+	// Log the control action
+	log.Printf("Control action for agent %s: %s (reason: %s, command: %s)", agentID, cmd.Action, cmd.Reason, cmd.CommandID)
+
+	if session, connected := s.sessions[agentID]; connected {
+		select {
+		case session <- cmd:
+		default:
+			log.Printf("⚠ Command queue of agent %s is full, %s stays pending", agentID, cmd.CommandID)
+		}
+	} else {
+		log.Printf("Agent %s is not connected, %s stays pending", agentID, cmd.CommandID)
+	}
+
+	return cmd, nil
+}
+
+// ConnectAgent opens a command session for an agent. Pending commands are queued
+// first. The returned channel is closed when the session ends, either through
+// the returned disconnect function or because the agent connected again.
+func (s *AgentControlService) ConnectAgent(ctx context.Context, agentID string) (<-chan *entity.AgentCommand, func(), error) {
+	pending, err := s.commandRepo.ListPending(ctx, agentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load pending commands: %w", err)
+	}
+
+	session := make(chan *entity.AgentCommand, commandQueueSize)
+
+	s.mu.Lock()
+	if previous, exists := s.sessions[agentID]; exists {
+		close(previous)
+	}
+	s.sessions[agentID] = session
+	for _, cmd := range pending {
+		if cmd.Status != entity.CommandStatusPending {
+			continue
+		}
+		select {
+		case session <- cmd:
+		default:
+		}
+	}
+	s.mu.Unlock()
+
+	disconnect := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.sessions[agentID] == session {
+			delete(s.sessions, agentID)
+			close(session)
+		}
 	}
 
+	return session, disconnect, nil
+}
+
+// DisconnectAgent ends the agent's command session, if it has one
+func (s *AgentControlService) DisconnectAgent(agentID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if session, exists := s.sessions[agentID]; exists {
+		delete(s.sessions, agentID)
+		close(session)
+	}
+}
+
+// AcknowledgeCommand records a status reported by the agent for one of its commands
+func (s *AgentControlService) AcknowledgeCommand(ctx context.Context, agentID, commandID string, status entity.CommandStatus, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cmd, err := s.commandRepo.GetByID(ctx, commandID)
+	if err != nil {
+		return err
+	}
+
+	if cmd.AgentID != agentID {
+		return fmt.Errorf("command %s does not belong to agent %s", commandID, agentID)
+	}
+
+	if err := cmd.Acknowledge(status, message); err != nil {
+		return err
+	}
+
+	if err := s.commandRepo.Update(ctx, cmd); err != nil {
+		return fmt.Errorf("failed to update command: %w", err)
+	}
+
+	log.Printf("Command %s (%s) on agent %s: %s %s", commandID, cmd.Action, agentID, status, message)
 	return nil
 }
 
+// ListCommands retrieves the commands sent to an agent, newest first
+func (s *AgentControlService) ListCommands(ctx context.Context, agentID string) ([]*entity.AgentCommand, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	commands, err := s.commandRepo.ListByAgent(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy so callers can read them while acknowledgements update the originals
+	result := make([]*entity.AgentCommand, len(commands))
+	for i, cmd := range commands {
+		c := *cmd
+		result[i] = &c
+	}
+	return result, nil
+}
+
 // BlockAgent blocks an agent
 func (s *AgentControlService) BlockAgent(agentID string, reason string) error {
 	agent, err := s.agentRepo.GetByAgentID(context.Background(), agentID)
@@ -70,7 +198,13 @@ func (s *AgentControlService) BlockAgent(agentID string, reason string) error {
 	}
 
 	agent.Block(reason)
-	return s.agentRepo.Update(context.Background(), agent)
+	if err := s.agentRepo.Update(context.Background(), agent); err != nil {
+		return err
+	}
+
+	// The agent learns it is blocked when it tries to open the channel again
+	s.DisconnectAgent(agentID)
+	return nil
 }
 
 // UnblockAgent unblocks an agent
@@ -0,0 +1,219 @@
+// Package service implements business logic
+package service
+
+import (
+	"context"
+	"fmt"
+	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/repository"
+	"sort"
+	"sync"
+)
+
+// AgentFilter selects agents in a listing. Zero fields match every agent.
+type AgentFilter struct {
+	Status       entity.AgentStatus
+	Blocked      *bool
+	AgentVersion string
+	Labels       map[string]string // metadata labels an agent must carry
+}
+
+// Matches checks if an agent passes the filter
+func (f AgentFilter) Matches(agent *entity.AgentRegistry) bool {
+	if f.Status != "" && agent.Status != f.Status {
+		return false
+	}
+	if f.Blocked != nil && agent.IsBlocked() != *f.Blocked {
+		return false
+	}
+	if f.AgentVersion != "" && agent.AgentVersion != f.AgentVersion {
+		return false
+	}
+	for key, value := range f.Labels {
+		if labelValue, ok := agent.Metadata[key]; !ok || labelValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// AgentDeleteResult describes what was cleaned up with a deleted agent
+type AgentDeleteResult struct {
+	PoliciesUnapplied      int
+	ConfigProfilesDetached int
+	HostsRemoved           int
+}
+
+// AgentFleetService lists and manages the registered agents
+type AgentFleetService struct {
+	agentRepo      repository.AgentRegistryRepository
+	policyRepo     repository.PolicyRepository
+	configRepo     repository.AgentConfigRepository
+	hostRepo       repository.HostRepository
+	authService    *AuthService
+	controlService *AgentControlService
+
+	mu sync.Mutex // serializes deletions
+}
+
+// NewAgentFleetService creates a new agent fleet service
+func NewAgentFleetService(
+	agentRepo repository.AgentRegistryRepository,
+	policyRepo repository.PolicyRepository,
+	configRepo repository.AgentConfigRepository,
+	hostRepo repository.HostRepository,
+	authService *AuthService,
+	controlService *AgentControlService,
+) *AgentFleetService {
+	return &AgentFleetService{
+		agentRepo:      agentRepo,
+		policyRepo:     policyRepo,
+		configRepo:     configRepo,
+		hostRepo:       hostRepo,
+		authService:    authService,
+		controlService: controlService,
+	}
+}
+
+// ListAgents retrieves one page of the agents matching filter, ordered by
+// hostname, and the number of matching agents
+func (s *AgentFleetService) ListAgents(ctx context.Context, filter AgentFilter, page, pageSize int) ([]*entity.AgentRegistry, int, error) {
+	agents, err := s.agentRepo.GetAll(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list agents: %w", err)
+	}
+
+	matching := make([]*entity.AgentRegistry, 0, len(agents))
+	for _, agent := range agents {
+		if filter.Matches(agent) {
+			matching = append(matching, agent)
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		if matching[i].Hostname != matching[j].Hostname {
+			return matching[i].Hostname < matching[j].Hostname
+		}
+		return matching[i].AgentID < matching[j].AgentID
+	})
+
+	total := len(matching)
+	start := (page - 1) * pageSize
+	if start >= total {
+		return []*entity.AgentRegistry{}, total, nil
+	}
+	end := min(start+pageSize, total)
+
+	return matching[start:end], total, nil
+}
+
+// GetAgent retrieves an agent by ID
+func (s *AgentFleetService) GetAgent(ctx context.Context, agentID string) (*entity.AgentRegistry, error) {
+	agent, err := s.agentRepo.GetByAgentID(ctx, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrAgentNotFound, agentID)
+	}
+	return agent, nil
+}
+
+// RevokeAgent permanently revokes an agent's access and ends its command
+// session. The record is kept, so the machine cannot register again.
+func (s *AgentFleetService) RevokeAgent(ctx context.Context, agentID string) error {
+	if err := s.authService.RevokeAgent(ctx, agentID); err != nil {
+		return err
+	}
+	s.controlService.DisconnectAgent(agentID)
+	return nil
+}
+
+// DeleteAgent removes an agent with its policy assignments, config profile
+// attachments and host entries. Its stats history is kept. The machine may
+// register again as a new agent if it presents an enrollment token.
+// Each cleanup step can run again and the registry entry is deleted last, so
+// a deletion that fails part way completes when retried.
+func (s *AgentFleetService) DeleteAgent(ctx context.Context, agentID string) (*AgentDeleteResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.agentRepo.GetByAgentID(ctx, agentID); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrAgentNotFound, agentID)
+	}
+
+	result := &AgentDeleteResult{}
+	var err error
+
+	if result.PoliciesUnapplied, err = s.unapplyPolicies(agentID); err != nil {
+		return nil, err
+	}
+	if result.ConfigProfilesDetached, err = s.detachConfigProfiles(ctx, agentID); err != nil {
+		return nil, err
+	}
+	if result.HostsRemoved, err = s.removeHosts(ctx, agentID); err != nil {
+		return nil, err
+	}
+
+	if err := s.agentRepo.Delete(ctx, agentID); err != nil {
+		return nil, fmt.Errorf("failed to delete agent: %w", err)
+	}
+	// Its token no longer works, so the agent cannot open a new session
+	s.controlService.DisconnectAgent(agentID)
+
+	return result, nil
+}
+
+// unapplyPolicies removes the agent from the policies applied to it
+func (s *AgentFleetService) unapplyPolicies(agentID string) (int, error) {
+	policies, err := s.policyRepo.GetByAgent(agentID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list policies: %w", err)
+	}
+
+	for i, policy := range policies {
+		if err := s.policyRepo.UnapplyFromAgent(policy.PolicyID, agentID); err != nil {
+			return i, fmt.Errorf("failed to unapply policy %s: %w", policy.PolicyID, err)
+		}
+	}
+
+	return len(policies), nil
+}
+
+// detachConfigProfiles detaches the agent from its config profile
+func (s *AgentFleetService) detachConfigProfiles(ctx context.Context, agentID string) (int, error) {
+	profiles, err := s.configRepo.GetAll(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list config profiles: %w", err)
+	}
+
+	detached := 0
+	for _, profile := range profiles {
+		if !profile.Detach(agentID) {
+			continue
+		}
+		if err := s.configRepo.Update(ctx, profile); err != nil {
+			return detached, fmt.Errorf("failed to detach config profile %s: %w", profile.ProfileID, err)
+		}
+		detached++
+	}
+
+	return detached, nil
+}
+
+// removeHosts deletes the hosts reported by the agent
+func (s *AgentFleetService) removeHosts(ctx context.Context, agentID string) (int, error) {
+	hosts, err := s.hostRepo.GetAll(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list hosts: %w", err)
+	}
+
+	removed := 0
+	for _, host := range hosts {
+		if host.AgentID != agentID {
+			continue
+		}
+		if err := s.hostRepo.Delete(ctx, host.Hostname); err != nil {
+			return removed, fmt.Errorf("failed to remove host %s: %w", host.Hostname, err)
+		}
+		removed++
+	}
+
+	return removed, nil
+}
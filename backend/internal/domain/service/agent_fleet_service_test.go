@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDeleteAgentCompletesWhenRetried(t *testing.T) {
+	for _, failOn := range []string{"policy.unapply", "config.update", "host.delete", "agent.delete"} {
+		t.Run(failOn, func(t *testing.T) {
+			ctx := context.Background()
+			f := newFleetFixture(t)
+			auth := NewAuthService(f.agentRepo, nil, time.Hour)
+			fleet := NewAgentFleetService(f.agentRepo, f.policyRepo, f.configRepo, f.hostRepo, auth, f.control)
+
+			f.faults.failOn = failOn
+			if _, err := fleet.DeleteAgent(ctx, "agent-old"); err == nil {
+				t.Fatalf("DeleteAgent() succeeded although %s failed", failOn)
+			}
+			if _, err := f.agentRepo.GetByAgentID(ctx, "agent-old"); err != nil {
+				t.Fatalf("agent removed by a failed deletion, so it cannot be retried: %v", err)
+			}
+			if _, err := fleet.DeleteAgent(ctx, "agent-old"); err != nil {
+				t.Fatalf("retried DeleteAgent() error = %v", err)
+			}
+
+			if agents := f.policyAgents(t); len(agents) != 0 {
+				t.Errorf("policy still applied to %v", agents)
+			}
+			if agents := f.profileAgents(t); len(agents) != 0 {
+				t.Errorf("config profile still attached to %v", agents)
+			}
+			if _, err := f.hostRepo.Get(ctx, "web-01"); err == nil {
+				t.Error("host of the deleted agent still listed")
+			}
+			if _, err := f.agentRepo.GetByAgentID(ctx, "agent-old"); err == nil {
+				t.Error("deleted agent still registered")
+			}
+			if _, err := f.agentRepo.GetByAgentID(ctx, "agent-new"); err != nil {
+				t.Errorf("other agent removed: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+// Package service implements business logic
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/repository"
+	"sync"
+)
+
+var (
+	// ErrInvalidMerge is returned when two agents cannot be merged
+	ErrInvalidMerge = errors.New("invalid agent merge")
+
+	// ErrAgentNotFound is returned for an unknown agent ID
+	ErrAgentNotFound = errors.New("agent not found")
+)
+
+// AgentMergeResult describes what a merge moved to the surviving agent
+type AgentMergeResult struct {
+	AgentID         string
+	PoliciesMoved   int
+	ConfigProfileID string // attached profile taken over, empty if none
+	HostsMoved      int
+	StatsMoved      int64
+}
+
+// AgentMergeService folds duplicate registrations of the same machine into one agent
+type AgentMergeService struct {
+	agentRepo      repository.AgentRegistryRepository
+	policyRepo     repository.PolicyRepository
+	configRepo     repository.AgentConfigRepository
+	hostRepo       repository.HostRepository
+	statsRepo      repository.StatsRepository
+	controlService *AgentControlService
+
+	mu sync.Mutex // serializes merges
+}
+
+// NewAgentMergeService creates a new agent merge service
+func NewAgentMergeService(
+	agentRepo repository.AgentRegistryRepository,
+	policyRepo repository.PolicyRepository,
+	configRepo repository.AgentConfigRepository,
+	hostRepo repository.HostRepository,
+	statsRepo repository.StatsRepository,
+	controlService *AgentControlService,
+) *AgentMergeService {
+	return &AgentMergeService{
+		agentRepo:      agentRepo,
+		policyRepo:     policyRepo,
+		configRepo:     configRepo,
+		hostRepo:       hostRepo,
+		statsRepo:      statsRepo,
+		controlService: controlService,
+	}
+}
+
+// MergeAgents merges the source agent into the target: its applied policies,
+// attached config profile, hosts and stats history move to the target, which
+// takes over its fingerprint when it has none, and the source is removed.
+// The source's token stops working; a machine still running with it
+// registers again and is recognised as the target by its fingerprint.
+// The steps span several repositories, so each one can run again and the
+// source is removed last: a merge that fails part way completes when retried.
+func (s *AgentMergeService) MergeAgents(ctx context.Context, sourceID, targetID string) (*AgentMergeResult, error) {
+	if sourceID == "" || targetID == "" {
+		return nil, fmt.Errorf("%w: source and target agent IDs are required", ErrInvalidMerge)
+	}
+	if sourceID == targetID {
+		return nil, fmt.Errorf("%w: cannot merge agent %s into itself", ErrInvalidMerge, sourceID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	source, err := s.agentRepo.GetByAgentID(ctx, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrAgentNotFound, sourceID)
+	}
+	target, err := s.agentRepo.GetByAgentID(ctx, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrAgentNotFound, targetID)
+	}
+	if source.Fingerprint != "" && target.Fingerprint != "" && source.Fingerprint != target.Fingerprint {
+		return nil, fmt.Errorf("%w: agents %s and %s run on different machines", ErrInvalidMerge, sourceID, targetID)
+	}
+
+	result := &AgentMergeResult{AgentID: targetID}
+
+	if result.PoliciesMoved, err = s.movePolicies(sourceID, targetID); err != nil {
+		return nil, err
+	}
+	if result.ConfigProfileID, err = s.moveConfigProfile(ctx, sourceID, targetID); err != nil {
+		return nil, err
+	}
+	if result.HostsMoved, err = s.moveHosts(ctx, sourceID, targetID); err != nil {
+		return nil, err
+	}
+	if reassigner, ok := s.statsRepo.(repository.StatsAgentReassigner); ok {
+		if result.StatsMoved, err = reassigner.ReassignAgent(ctx, sourceID, targetID); err != nil {
+			return nil, fmt.Errorf("failed to move stats history: %w", err)
+		}
+	}
+
+	// The most recently seen registration has the current host details
+	if source.LastAuthAt.After(target.LastAuthAt) {
+		target.UpdateDetails(source.Hostname, source.IPAddress, source.AgentVersion, source.Metadata)
+	}
+	if target.Fingerprint == "" {
+		target.Fingerprint = source.Fingerprint
+	}
+	if target.EnrollmentTokenID == "" {
+		target.EnrollmentTokenID = source.EnrollmentTokenID
+	}
+
+	// Labels assigned to either registration are kept, the target's win
+	labels := make(map[string]string, len(source.Labels)+len(target.Labels))
+	maps.Copy(labels, source.Labels)
+	maps.Copy(labels, target.Labels)
+	target.AssignLabels(labels)
+	if source.RegisteredAt.Before(target.RegisteredAt) {
+		target.RegisteredAt = source.RegisteredAt
+	}
+
+	if err := s.agentRepo.Update(ctx, target); err != nil {
+		return nil, fmt.Errorf("failed to update agent: %w", err)
+	}
+	if err := s.agentRepo.Delete(ctx, sourceID); err != nil {
+		return nil, fmt.Errorf("failed to remove merged agent: %w", err)
+	}
+
+	if result.ConfigProfileID != "" && target.Status == entity.AgentStatusActive {
+		reason := fmt.Sprintf("config profile %s taken over from agent %s", result.ConfigProfileID, sourceID)
+		if _, err := s.controlService.RequestReconfigure(ctx, targetID, reason); err != nil {
+			log.Printf("⚠ Failed to ask agent %s to reconfigure: %v", targetID, err)
+		}
+	}
+
+	return result, nil
+}
+
+// movePolicies applies the source's policies to the target. Each policy is
+// applied to the target before it is unapplied from the source, so a merge
+// that fails in between still finds it on the source when retried.
+func (s *AgentMergeService) movePolicies(sourceID, targetID string) (int, error) {
+	policies, err := s.policyRepo.GetByAgent(sourceID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list policies: %w", err)
+	}
+
+	moved := 0
+	for _, policy := range policies {
+		if !policy.IsAppliedTo(targetID) {
+			if err := s.policyRepo.ApplyToAgent(policy.PolicyID, targetID); err != nil {
+				return moved, fmt.Errorf("failed to apply policy %s: %w", policy.PolicyID, err)
+			}
+			moved++
+		}
+		if err := s.policyRepo.UnapplyFromAgent(policy.PolicyID, sourceID); err != nil {
+			return moved, fmt.Errorf("failed to unapply policy %s: %w", policy.PolicyID, err)
+		}
+	}
+
+	return moved, nil
+}
+
+// moveConfigProfile detaches the source from its config profile and attaches
+// the target instead, unless the target has a profile of its own
+func (s *AgentMergeService) moveConfigProfile(ctx context.Context, sourceID, targetID string) (string, error) {
+	profiles, err := s.configRepo.GetAll(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list config profiles: %w", err)
+	}
+
+	var sourceProfile *entity.AgentConfigProfile
+	targetAttached := false
+	for _, profile := range profiles {
+		if profile.IsAttachedTo(targetID) {
+			targetAttached = true
+		}
+		if profile.IsAttachedTo(sourceID) {
+			sourceProfile = profile
+		}
+	}
+	if sourceProfile == nil {
+		return "", nil
+	}
+
+	sourceProfile.Detach(sourceID)
+	moved := ""
+	if !targetAttached {
+		sourceProfile.Attach(targetID)
+		moved = sourceProfile.ProfileID
+	}
+	if err := s.configRepo.Update(ctx, sourceProfile); err != nil {
+		return "", fmt.Errorf("failed to move config profile %s: %w", sourceProfile.ProfileID, err)
+	}
+
+	return moved, nil
+}
+
+// moveHosts points the hosts reported by the source at the target
+func (s *AgentMergeService) moveHosts(ctx context.Context, sourceID, targetID string) (int, error) {
+	hosts, err := s.hostRepo.GetAll(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list hosts: %w", err)
+	}
+
+	moved := 0
+	for _, host := range hosts {
+		if host.AgentID != sourceID {
+			continue
+		}
+		host.AgentID = targetID
+		if err := s.hostRepo.Update(ctx, host); err != nil {
+			return moved, fmt.Errorf("failed to update host %s: %w", host.Hostname, err)
+		}
+		moved++
+	}
+
+	return moved, nil
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/repository"
+	"smart-monitor/backend/internal/infrastructure/persistence"
+)
+
+// faults fails the operation named failOn once
+type faults struct {
+	failOn string
+}
+
+func (f *faults) check(op string) error {
+	if f.failOn != op {
+		return nil
+	}
+	f.failOn = ""
+	return errors.New(op + " failed")
+}
+
+type faultyPolicyRepo struct {
+	repository.PolicyRepository
+	faults *faults
+}
+
+func (r faultyPolicyRepo) ApplyToAgent(policyID, agentID string) error {
+	if err := r.faults.check("policy.apply"); err != nil {
+		return err
+	}
+	return r.PolicyRepository.ApplyToAgent(policyID, agentID)
+}
+
+func (r faultyPolicyRepo) UnapplyFromAgent(policyID, agentID string) error {
+	if err := r.faults.check("policy.unapply"); err != nil {
+		return err
+	}
+	return r.PolicyRepository.UnapplyFromAgent(policyID, agentID)
+}
+
+type faultyConfigRepo struct {
+	repository.AgentConfigRepository
+	faults *faults
+}
+
+func (r faultyConfigRepo) Update(ctx context.Context, profile *entity.AgentConfigProfile) error {
+	if err := r.faults.check("config.update"); err != nil {
+		return err
+	}
+	return r.AgentConfigRepository.Update(ctx, profile)
+}
+
+type faultyHostRepo struct {
+	repository.HostRepository
+	faults *faults
+}
+
+func (r faultyHostRepo) Update(ctx context.Context, host *entity.Host) error {
+	if err := r.faults.check("host.update"); err != nil {
+		return err
+	}
+	return r.HostRepository.Update(ctx, host)
+}
+
+func (r faultyHostRepo) Delete(ctx context.Context, hostname string) error {
+	if err := r.faults.check("host.delete"); err != nil {
+		return err
+	}
+	return r.HostRepository.Delete(ctx, hostname)
+}
+
+type faultyAgentRepo struct {
+	repository.AgentRegistryRepository
+	faults *faults
+}
+
+func (r faultyAgentRepo) Update(ctx context.Context, agent *entity.AgentRegistry) error {
+	if err := r.faults.check("agent.update"); err != nil {
+		return err
+	}
+	return r.AgentRegistryRepository.Update(ctx, agent)
+}
+
+func (r faultyAgentRepo) Delete(ctx context.Context, agentID string) error {
+	if err := r.faults.check("agent.delete"); err != nil {
+		return err
+	}
+	return r.AgentRegistryRepository.Delete(ctx, agentID)
+}
+
+// fleetFixture holds repositories with agent-old running web-01 with policy
+// cpu-high and config profile base, and an empty agent-new
+type fleetFixture struct {
+	faults     *faults
+	agentRepo  repository.AgentRegistryRepository
+	policyRepo repository.PolicyRepository
+	configRepo repository.AgentConfigRepository
+	hostRepo   repository.HostRepository
+	control    *AgentControlService
+}
+
+func newFleetFixture(t *testing.T) *fleetFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	f := &fleetFixture{faults: &faults{}}
+	agentRepo := persistence.NewInMemoryAgentRegistryRepository()
+	policyRepo := persistence.NewInMemoryPolicyRepository()
+	configRepo := persistence.NewInMemoryAgentConfigRepository()
+	hostRepo := persistence.NewInMemoryHostRepository()
+
+	for _, agentID := range []string{"agent-old", "agent-new"} {
+		if err := agentRepo.Register(ctx, entity.NewAgentRegistry(agentID, "web-01", "10.0.0.1", "1.0.0", nil, time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rule := entity.NewPolicyRule("cpu", entity.ComparatorGreaterThan, 80, 0, "")
+	if err := policyRepo.Create(entity.NewPolicy("cpu-high", "CPU high", "", []entity.PolicyRule{rule}, nil, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := policyRepo.ApplyToAgent("cpu-high", "agent-old"); err != nil {
+		t.Fatal(err)
+	}
+	profile := entity.NewAgentConfigProfile("base", "Base", "", entity.AgentSettings{}, nil)
+	profile.Attach("agent-old")
+	if err := configRepo.Create(ctx, profile); err != nil {
+		t.Fatal(err)
+	}
+	if err := hostRepo.Create(ctx, entity.NewHost("web-01", "10.0.0.1", "agent-old")); err != nil {
+		t.Fatal(err)
+	}
+
+	f.agentRepo = faultyAgentRepo{agentRepo, f.faults}
+	f.policyRepo = faultyPolicyRepo{policyRepo, f.faults}
+	f.configRepo = faultyConfigRepo{configRepo, f.faults}
+	f.hostRepo = faultyHostRepo{hostRepo, f.faults}
+	f.control = NewAgentControlService(f.agentRepo, persistence.NewInMemoryAgentCommandRepository())
+	return f
+}
+
+// policyAgents returns the agents policy cpu-high is applied to
+func (f *fleetFixture) policyAgents(t *testing.T) []string {
+	t.Helper()
+	policy, err := f.policyRepo.GetByID("cpu-high")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return policy.AppliedAgents
+}
+
+// profileAgents returns the agents config profile base is attached to
+func (f *fleetFixture) profileAgents(t *testing.T) []string {
+	t.Helper()
+	profile, err := f.configRepo.GetByID(context.Background(), "base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return profile.AgentIDs
+}
+
+func TestMergeAgentsCompletesWhenRetried(t *testing.T) {
+	for _, failOn := range []string{"policy.apply", "policy.unapply", "config.update", "host.update", "agent.update", "agent.delete"} {
+		t.Run(failOn, func(t *testing.T) {
+			ctx := context.Background()
+			f := newFleetFixture(t)
+			merger := NewAgentMergeService(f.agentRepo, f.policyRepo, f.configRepo, f.hostRepo, persistence.NewInMemoryStatsRepository(10), f.control)
+
+			f.faults.failOn = failOn
+			if _, err := merger.MergeAgents(ctx, "agent-old", "agent-new"); err == nil {
+				t.Fatalf("MergeAgents() succeeded although %s failed", failOn)
+			}
+			if _, err := merger.MergeAgents(ctx, "agent-old", "agent-new"); err != nil {
+				t.Fatalf("retried MergeAgents() error = %v", err)
+			}
+
+			if agents := f.policyAgents(t); !slices.Equal(agents, []string{"agent-new"}) {
+				t.Errorf("policy applied to %v, want [agent-new]", agents)
+			}
+			if agents := f.profileAgents(t); !slices.Equal(agents, []string{"agent-new"}) {
+				t.Errorf("config profile attached to %v, want [agent-new]", agents)
+			}
+			host, err := f.hostRepo.Get(ctx, "web-01")
+			if err != nil || host.AgentID != "agent-new" {
+				t.Errorf("host = %+v, %v, want it reported by agent-new", host, err)
+			}
+			if _, err := f.agentRepo.GetByAgentID(ctx, "agent-old"); err == nil {
+				t.Error("merged agent still registered")
+			}
+		})
+	}
+}
@@ -3,7 +3,9 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,73 +13,213 @@ import (
 	"smart-monitor/backend/internal/domain/repository"
 )
 
+var (
+	// ErrInvalidAgentCredentials is returned for an unknown agent or a wrong
+	// or expired token; the agent should register again
+	ErrInvalidAgentCredentials = errors.New("invalid agent credentials")
+
+	// ErrAgentAccessDenied is returned for an agent that is revoked, blocked
+	// or suspended
+	ErrAgentAccessDenied = errors.New("agent access denied")
+)
+
 // AuthService handles agent authentication and registration
 type AuthService struct {
-	agentRepo repository.AgentRegistryRepository
+	agentRepo  repository.AgentRegistryRepository
+	enrollment *EnrollmentService
+	tokenTTL   time.Duration // lifetime of issued access tokens
 }
 
-// NewAuthService creates a new AuthService
-func NewAuthService(agentRepo repository.AgentRegistryRepository) *AuthService {
+// NewAuthService creates a new AuthService issuing tokens valid for tokenTTL.
+// New agents must present a token accepted by enrollment.
+func NewAuthService(agentRepo repository.AgentRegistryRepository, enrollment *EnrollmentService, tokenTTL time.Duration) *AuthService {
+	if tokenTTL <= 0 {
+		tokenTTL = entity.DefaultTokenTTL
+	}
 	return &AuthService{
-		agentRepo: agentRepo,
+		agentRepo:  agentRepo,
+		enrollment: enrollment,
+		tokenTTL:   tokenTTL,
 	}
 }
 
-// RegisterAgent registers a new agent and returns credentials
-func (s *AuthService) RegisterAgent(ctx context.Context, hostname, ipAddress, agentVersion string, metadata map[string]string) (*entity.AgentRegistry, error) {
-	// Generate unique agent ID
-	agentID := generateAgentID(hostname, ipAddress)
-
-	// Check if agent already exists
-	existingAgent, err := s.agentRepo.GetByAgentID(ctx, agentID)
-	if err == nil && existingAgent != nil {
-		// Agent already registered, return existing credentials
-		if existingAgent.IsValid() {
-			existingAgent.UpdateLastAuth()
-			if err := s.agentRepo.Update(ctx, existingAgent); err != nil {
-				return nil, fmt.Errorf("failed to update existing agent: %w", err)
-			}
-			return existingAgent, nil
+// RegisterAgent registers a new agent and returns credentials. An agent that
+// reports the fingerprint of a machine registered before keeps its agent ID,
+// and with it its history, applied policies and status, and gets a new token.
+// It must prove to be that machine with previousToken, an access token issued
+// to it before even if expired, or else present an enrollment token.
+// Any other agent must present a valid enrollment token, whose labels and
+// policies it receives.
+func (s *AuthService) RegisterAgent(ctx context.Context, hostname, ipAddress, agentVersion, fingerprint, enrollmentSecret, previousToken string, metadata map[string]string) (*entity.AgentRegistry, error) {
+	agent := s.findReturningAgent(ctx, hostname, fingerprint)
+	if agent != nil {
+		switch {
+		case agent.Status == entity.AgentStatusRevoked:
+			return nil, fmt.Errorf("agent %s has been revoked", agent.AgentID)
+		case agent.IsBlocked():
+			return nil, fmt.Errorf("agent %s is blocked", agent.AgentID)
 		}
-		// Agent expired or suspended, renew token
-		existingAgent.RenewToken()
-		existingAgent.Activate()
-		if err := s.agentRepo.Update(ctx, existingAgent); err != nil {
-			return nil, fmt.Errorf("failed to renew agent token: %w", err)
+	}
+
+	// Only machines recognised by their fingerprint and holding a token
+	// issued to them skip enrollment
+	var enrollment *entity.EnrollmentToken
+	if agent == nil || agent.Fingerprint == "" || !agent.HasIssued(previousToken) {
+		var err error
+		if enrollment, err = s.enrollment.Redeem(ctx, enrollmentSecret, hostname); err != nil {
+			return nil, err
 		}
-		return existingAgent, nil
 	}
 
-	// Create new agent registry
-	agent := entity.NewAgentRegistry(agentID, hostname, ipAddress, agentVersion, metadata)
+	if agent != nil {
+		agent.UpdateDetails(hostname, ipAddress, agentVersion, metadata)
+		agent.Fingerprint = fingerprint
+		agent.RenewToken(s.tokenTTL)
+		applyEnrollment(agent, enrollment)
+		if err := s.agentRepo.Update(ctx, agent); err != nil {
+			return nil, fmt.Errorf("failed to update returning agent: %w", err)
+		}
+	} else {
+		// Generate unique agent ID
+		agentID := generateAgentID(hostname, ipAddress)
 
-	if err := s.agentRepo.Register(ctx, agent); err != nil {
-		return nil, fmt.Errorf("failed to register agent: %w", err)
+		// Create new agent registry
+		agent = entity.NewAgentRegistry(agentID, hostname, ipAddress, agentVersion, metadata, s.tokenTTL)
+		agent.Fingerprint = fingerprint
+		applyEnrollment(agent, enrollment)
+
+		if err := s.agentRepo.Register(ctx, agent); err != nil {
+			return nil, fmt.Errorf("failed to register agent: %w", err)
+		}
+	}
+
+	if enrollment != nil {
+		s.enrollment.ApplyPolicies(enrollment, agent.AgentID)
 	}
 
 	return agent, nil
 }
 
-// ValidateToken validates an agent's access token
-func (s *AuthService) ValidateToken(ctx context.Context, agentID, token string) error {
-	agent, err := s.agentRepo.GetByAgentID(ctx, agentID)
+// applyEnrollment records the token an agent enrolled with and assigns its labels
+func applyEnrollment(agent *entity.AgentRegistry, enrollment *entity.EnrollmentToken) {
+	if enrollment == nil {
+		return
+	}
+	agent.EnrollmentTokenID = enrollment.TokenID
+	agent.AssignLabels(enrollment.Labels)
+}
+
+// findReturningAgent looks up the agent registered before by the same machine.
+// Agents registered before fingerprints were reported are matched by hostname
+// when exactly one of them has it, so they adopt the fingerprint once.
+func (s *AuthService) findReturningAgent(ctx context.Context, hostname, fingerprint string) *entity.AgentRegistry {
+	if fingerprint == "" {
+		return nil
+	}
+
+	if agent, err := s.agentRepo.GetByFingerprint(ctx, fingerprint); err == nil {
+		return agent
+	}
+
+	agents, err := s.agentRepo.GetAll(ctx)
 	if err != nil {
-		return fmt.Errorf("agent not found: %w", err)
+		return nil
+	}
+
+	var legacy *entity.AgentRegistry
+	for _, agent := range agents {
+		if agent.Fingerprint != "" || agent.Hostname != hostname {
+			continue
+		}
+		if legacy != nil {
+			return nil // ambiguous, left for an admin to merge
+		}
+		legacy = agent
+	}
+
+	return legacy
+}
+
+// AuthenticateAgent checks the credentials an agent presents when it opens a
+// call or stream and records the authentication. The agent is looked up by
+// token when agentID is empty. It fails with ErrInvalidAgentCredentials when
+// the agent should register again and with ErrAgentAccessDenied when it may
+// not connect at all.
+func (s *AuthService) AuthenticateAgent(ctx context.Context, agentID, token string) (*entity.AgentRegistry, error) {
+	if token == "" {
+		return nil, fmt.Errorf("%w: missing access token", ErrInvalidAgentCredentials)
 	}
 
-	if !agent.IsTokenValid(token) {
-		return fmt.Errorf("invalid or expired token")
+	var agent *entity.AgentRegistry
+	var err error
+	if agentID != "" {
+		agent, err = s.agentRepo.GetByAgentID(ctx, agentID)
+	} else {
+		agent, err = s.agentRepo.GetByToken(ctx, token)
+	}
+	if err != nil || !agent.MatchesToken(token) {
+		return nil, fmt.Errorf("%w: unknown agent or token", ErrInvalidAgentCredentials)
+	}
+	if err := checkAgentStanding(agent); err != nil {
+		return nil, err
 	}
 
-	// Update last authentication time
 	agent.UpdateLastAuth()
 	if err := s.agentRepo.Update(ctx, agent); err != nil {
-		return fmt.Errorf("failed to update auth timestamp: %w", err)
+		return nil, fmt.Errorf("failed to update auth timestamp: %w", err)
 	}
 
+	return agent, nil
+}
+
+// CheckAgent reports whether an agent authenticated earlier may keep its
+// session open, failing like AuthenticateAgent. It does not record anything.
+func (s *AuthService) CheckAgent(ctx context.Context, agentID string) error {
+	agent, err := s.agentRepo.GetByAgentID(ctx, agentID)
+	if err != nil {
+		return fmt.Errorf("%w: agent %s no longer exists", ErrInvalidAgentCredentials, agentID)
+	}
+	return checkAgentStanding(agent)
+}
+
+// checkAgentStanding reports why an agent holding a matching token may not
+// connect, nil if it may
+func checkAgentStanding(agent *entity.AgentRegistry) error {
+	switch {
+	case agent.Status == entity.AgentStatusRevoked:
+		return fmt.Errorf("%w: agent %s has been revoked", ErrAgentAccessDenied, agent.AgentID)
+	case agent.IsBlocked():
+		if agent.BlockReason != "" {
+			return fmt.Errorf("%w: agent %s is blocked: %s", ErrAgentAccessDenied, agent.AgentID, agent.BlockReason)
+		}
+		return fmt.Errorf("%w: agent %s is blocked", ErrAgentAccessDenied, agent.AgentID)
+	case agent.Status != entity.AgentStatusActive:
+		return fmt.Errorf("%w: agent %s is %s", ErrAgentAccessDenied, agent.AgentID, agent.Status)
+	case !time.Now().Before(agent.TokenExpiry):
+		return fmt.Errorf("%w: access token expired", ErrInvalidAgentCredentials)
+	}
 	return nil
 }
 
+// RenewToken issues a new access token to an agent that still holds a valid one.
+// The agent keeps its ID; the old token remains valid for a short grace period.
+func (s *AuthService) RenewToken(ctx context.Context, agentID, token string) (*entity.AgentRegistry, error) {
+	agent, err := s.agentRepo.GetByAgentID(ctx, agentID)
+	if err != nil || !agent.MatchesToken(token) {
+		return nil, fmt.Errorf("%w: unknown agent or token", ErrInvalidAgentCredentials)
+	}
+	if err := checkAgentStanding(agent); err != nil {
+		return nil, err
+	}
+
+	agent.RenewToken(s.tokenTTL)
+	if err := s.agentRepo.Update(ctx, agent); err != nil {
+		return nil, fmt.Errorf("failed to renew agent token: %w", err)
+	}
+
+	return agent, nil
+}
+
 // GetAgentByToken retrieves agent by token
 func (s *AuthService) GetAgentByToken(ctx context.Context, token string) (*entity.AgentRegistry, error) {
 	agent, err := s.agentRepo.GetByToken(ctx, token)
@@ -96,7 +238,7 @@ func (s *AuthService) GetAgentByToken(ctx context.Context, token string) (*entit
 func (s *AuthService) RevokeAgent(ctx context.Context, agentID string) error {
 	agent, err := s.agentRepo.GetByAgentID(ctx, agentID)
 	if err != nil {
-		return fmt.Errorf("agent not found: %w", err)
+		return fmt.Errorf("%w: %s", ErrAgentNotFound, agentID)
 	}
 
 	agent.Revoke()
@@ -117,9 +259,15 @@ func (s *AuthService) GetActiveAgents(ctx context.Context) ([]*entity.AgentRegis
 	return s.agentRepo.GetActive(ctx)
 }
 
-// generateAgentID creates a unique agent identifier
+// generateAgentID creates a unique agent identifier. Returning machines are
+// recognised by fingerprint, so the ID does not need to be derived from the host.
 func generateAgentID(hostname, ipAddress string) string {
-	data := fmt.Sprintf("%s-%s-%d", hostname, ipAddress, time.Now().Unix())
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		// Fallback to a timestamp-based nonce
+		nonce = []byte(time.Now().String())
+	}
+	data := fmt.Sprintf("%s-%s-%x", hostname, ipAddress, nonce)
 	hash := sha256.Sum256([]byte(data))
 	return fmt.Sprintf("agent-%x", hash[:8])
 }
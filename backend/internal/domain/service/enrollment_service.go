@@ -0,0 +1,157 @@
+// Package service implements business logic
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/repository"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInvalidEnrollmentToken is returned when a token fails validation
+	ErrInvalidEnrollmentToken = errors.New("invalid enrollment token")
+
+	// ErrEnrollmentTokenNotFound is returned for an unknown token ID
+	ErrEnrollmentTokenNotFound = errors.New("enrollment token not found")
+
+	// ErrEnrollmentDenied is returned when an agent may not register
+	ErrEnrollmentDenied = errors.New("enrollment denied")
+)
+
+// EnrollmentService manages the enrollment tokens that gate agent registration
+type EnrollmentService struct {
+	tokenRepo  repository.EnrollmentTokenRepository
+	policyRepo repository.PolicyRepository
+	required   bool // reject new agents without an enrollment token
+
+	mu sync.Mutex // serializes redemptions so use limits hold
+}
+
+// NewEnrollmentService creates a new enrollment service. When required is
+// false, agents may still register without a token.
+func NewEnrollmentService(tokenRepo repository.EnrollmentTokenRepository, policyRepo repository.PolicyRepository, required bool) *EnrollmentService {
+	return &EnrollmentService{
+		tokenRepo:  tokenRepo,
+		policyRepo: policyRepo,
+		required:   required,
+	}
+}
+
+// Required reports whether new agents need an enrollment token
+func (s *EnrollmentService) Required() bool {
+	return s.required
+}
+
+// CreateToken stores a new enrollment token and returns it with its secret,
+// which is not stored and cannot be retrieved later. A zero ttl or maxUses
+// means no limit.
+func (s *EnrollmentService) CreateToken(ctx context.Context, description, hostnamePattern string, labels map[string]string, policyIDs []string, maxUses int, ttl time.Duration) (*entity.EnrollmentToken, string, error) {
+	if ttl < 0 {
+		return nil, "", fmt.Errorf("%w: ttl must not be negative", ErrInvalidEnrollmentToken)
+	}
+	for _, policyID := range policyIDs {
+		if _, err := s.policyRepo.GetByID(policyID); err != nil {
+			return nil, "", fmt.Errorf("%w: policy %s not found", ErrInvalidEnrollmentToken, policyID)
+		}
+	}
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	token, secret := entity.NewEnrollmentToken(generateEnrollmentTokenID(description), description, hostnamePattern, labels, policyIDs, maxUses, expiresAt)
+	if err := token.Validate(); err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrInvalidEnrollmentToken, err)
+	}
+	if err := s.tokenRepo.Create(ctx, token); err != nil {
+		return nil, "", fmt.Errorf("failed to store enrollment token: %w", err)
+	}
+
+	return token, secret, nil
+}
+
+// ListTokens retrieves all enrollment tokens
+func (s *EnrollmentService) ListTokens(ctx context.Context) ([]*entity.EnrollmentToken, error) {
+	return s.tokenRepo.GetAll(ctx)
+}
+
+// RevokeToken stops a token from enrolling further agents. Agents it
+// enrolled keep their registration.
+func (s *EnrollmentService) RevokeToken(ctx context.Context, tokenID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, err := s.tokenRepo.GetByID(ctx, tokenID)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrEnrollmentTokenNotFound, tokenID)
+	}
+
+	token.Revoke()
+	if err := s.tokenRepo.Update(ctx, token); err != nil {
+		return fmt.Errorf("failed to revoke enrollment token: %w", err)
+	}
+
+	return nil
+}
+
+// Redeem checks the secret an agent presented and counts a use of its token.
+// It returns nil when no secret was presented and none is required.
+func (s *EnrollmentService) Redeem(ctx context.Context, secret, hostname string) (*entity.EnrollmentToken, error) {
+	if secret == "" {
+		if s.required {
+			return nil, fmt.Errorf("%w: an enrollment token is required", ErrEnrollmentDenied)
+		}
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, err := s.tokenRepo.GetByID(ctx, entity.EnrollmentTokenID(secret))
+	if err != nil || !token.MatchesSecret(secret) {
+		return nil, fmt.Errorf("%w: unknown enrollment token", ErrEnrollmentDenied)
+	}
+	if err := token.CheckUsable(hostname); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEnrollmentDenied, err)
+	}
+
+	token.Use()
+	if err := s.tokenRepo.Update(ctx, token); err != nil {
+		return nil, fmt.Errorf("failed to record enrollment: %w", err)
+	}
+
+	return token, nil
+}
+
+// ApplyPolicies applies the policies a token pre-assigns to an enrolled agent.
+// Policies deleted since the token was created are skipped.
+func (s *EnrollmentService) ApplyPolicies(token *entity.EnrollmentToken, agentID string) {
+	for _, policyID := range token.PolicyIDs {
+		policy, err := s.policyRepo.GetByID(policyID)
+		if err != nil {
+			log.Printf("⚠ Enrollment token %s: policy %s not found, skipped", token.TokenID, policyID)
+			continue
+		}
+		if policy.IsAppliedTo(agentID) {
+			continue
+		}
+		if err := s.policyRepo.ApplyToAgent(policyID, agentID); err != nil {
+			log.Printf("⚠ Enrollment token %s: failed to apply policy %s to agent %s: %v", token.TokenID, policyID, agentID, err)
+		}
+	}
+}
+
+// generateEnrollmentTokenID generates a unique enrollment token ID
+func generateEnrollmentTokenID(description string) string {
+	data := fmt.Sprintf("%s-%d", description, time.Now().UnixNano())
+	hash := sha256.Sum256([]byte(data))
+	return "enr-" + hex.EncodeToString(hash[:])[:8]
+}
@@ -0,0 +1,235 @@
+// Package service implements business logic
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/repository"
+	"sync"
+	"time"
+)
+
+// LivenessThresholds decides when a host that stopped reporting is stale or
+// offline, as multiples of the interval its agent reports at
+type LivenessThresholds struct {
+	DefaultReportInterval time.Duration // used for agents that do not report their interval
+	StaleAfterIntervals   float64
+	OfflineAfterIntervals float64
+}
+
+// after returns how long a host reporting every reportInterval may stay
+// silent before it is stale and before it is offline
+func (t LivenessThresholds) after(reportInterval time.Duration) (time.Duration, time.Duration) {
+	if reportInterval <= 0 {
+		reportInterval = t.DefaultReportInterval
+	}
+	stale := time.Duration(float64(reportInterval) * t.StaleAfterIntervals)
+	offline := time.Duration(float64(reportInterval) * t.OfflineAfterIntervals)
+	return stale, offline
+}
+
+// hostLiveness is the last status the monitor saw for a host and the
+// availability alert it opened, if any
+type hostLiveness struct {
+	status  entity.HostStatus
+	agentID string
+	alertID string
+}
+
+// HostLivenessMonitor moves hosts between online, stale and offline as their
+// samples arrive or stop. Every transition is logged as an availability event;
+// a stale or offline host has an open alert that resolves once it reports again.
+type HostLivenessMonitor struct {
+	hostRepo     repository.HostRepository
+	statsService *StatsService
+	eventRepo    repository.EventRepository
+	alertRepo    repository.AlertRepository
+	thresholds   LivenessThresholds
+
+	mu    sync.Mutex // serializes checks
+	hosts map[string]*hostLiveness
+}
+
+// NewHostLivenessMonitor creates a new host liveness monitor
+func NewHostLivenessMonitor(
+	hostRepo repository.HostRepository,
+	statsService *StatsService,
+	eventRepo repository.EventRepository,
+	alertRepo repository.AlertRepository,
+	thresholds LivenessThresholds,
+) *HostLivenessMonitor {
+	return &HostLivenessMonitor{
+		hostRepo:     hostRepo,
+		statsService: statsService,
+		eventRepo:    eventRepo,
+		alertRepo:    alertRepo,
+		thresholds:   thresholds,
+		hosts:        make(map[string]*hostLiveness),
+	}
+}
+
+// Run checks host liveness every checkInterval until ctx is done
+func (m *HostLivenessMonitor) Run(ctx context.Context, checkInterval time.Duration) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.Check(ctx); err != nil {
+				log.Printf("⚠ Host liveness check failed: %v", err)
+			}
+		}
+	}
+}
+
+// Check updates the status of every host from the time since its last sample
+func (m *HostLivenessMonitor) Check(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	hosts, err := m.hostRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list hosts: %w", err)
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		seen[host.Hostname] = true
+
+		state, ok := m.hosts[host.Hostname]
+		if !ok {
+			state = &hostLiveness{status: host.Status}
+			m.hosts[host.Hostname] = state
+		}
+		state.agentID = host.AgentID
+
+		staleAfter, offlineAfter := m.thresholds.after(host.ReportInterval)
+		status := host.StatusAt(now, staleAfter, offlineAfter)
+		if status != host.Status {
+			updated, err := m.statsService.UpdateHostStatus(ctx, host.Hostname, status, host.LastSeenAt)
+			if err != nil {
+				log.Printf("⚠ Failed to update status of host %s: %v", host.Hostname, err)
+				continue
+			}
+			if !updated {
+				// A sample arrived meanwhile, the next check sees it
+				continue
+			}
+		}
+
+		if status != state.status {
+			m.transition(ctx, host, state, status, now)
+		} else if !ok && status == entity.HostStatusOnline {
+			// The host came back before the first check since a restart
+			m.resolveAlerts(ctx, host.Hostname, state)
+		}
+	}
+
+	// Hosts removed since the last check have nothing left to alert about
+	for hostname, state := range m.hosts {
+		if seen[hostname] {
+			continue
+		}
+		m.resolveAlerts(ctx, hostname, state)
+		delete(m.hosts, hostname)
+	}
+
+	return nil
+}
+
+// transition records that host went from its last known status to status
+func (m *HostLivenessMonitor) transition(ctx context.Context, host *entity.Host, state *hostLiveness, status entity.HostStatus, now time.Time) {
+	previous := state.status
+	state.status = status
+	silent := now.Sub(host.LastSeenAt).Round(time.Second)
+
+	var level entity.EventLevel
+	var message string
+	switch status {
+	case entity.HostStatusOnline:
+		level = entity.EventLevelInfo
+		message = fmt.Sprintf("Host %s is reporting again after being %s", host.Hostname, previous)
+		log.Printf("✓ %s", message)
+	case entity.HostStatusStale:
+		level = entity.EventLevelWarning
+		message = fmt.Sprintf("Host %s has not reported for %v", host.Hostname, silent)
+		log.Printf("⚠ %s", message)
+	default:
+		level = entity.EventLevelError
+		message = fmt.Sprintf("Host %s is offline, last report %v ago", host.Hostname, silent)
+		log.Printf("⚠ %s", message)
+	}
+
+	event := entity.NewEvent(host.Hostname, host.AgentID, entity.EventTypeAvailability, "host_"+string(status), level, message)
+	event.Details["previous_status"] = string(previous)
+	event.Details["status"] = string(status)
+	event.Details["last_seen_at"] = host.LastSeenAt.Format(time.RFC3339)
+	if _, err := m.eventRepo.Log(ctx, event); err != nil {
+		log.Printf("⚠ Failed to log availability event for host %s: %v", host.Hostname, err)
+	}
+
+	// A stale alert is replaced by a critical one once the host is offline
+	m.resolveAlerts(ctx, host.Hostname, state)
+	if status == entity.HostStatusOnline {
+		return
+	}
+
+	severity := entity.AlertSeverityMedium
+	title := fmt.Sprintf("Host %s is not reporting", host.Hostname)
+	if status == entity.HostStatusOffline {
+		severity = entity.AlertSeverityCritical
+		title = fmt.Sprintf("Host %s is offline", host.Hostname)
+	}
+	staleAfter, offlineAfter := m.thresholds.after(host.ReportInterval)
+	threshold := staleAfter
+	if status == entity.HostStatusOffline {
+		threshold = offlineAfter
+	}
+
+	alert := entity.NewAlert(host.Hostname, host.AgentID, entity.AlertTypeAvailability, severity, title, message)
+	alert.Metric = "seconds_since_last_report"
+	alert.Value = silent.Seconds()
+	alert.Threshold = threshold.Seconds()
+	alert.Metadata["status"] = string(status)
+	alert.Metadata["last_seen_at"] = host.LastSeenAt.Format(time.RFC3339)
+	alertID, err := m.alertRepo.Create(ctx, alert)
+	if err != nil {
+		log.Printf("⚠ Failed to create availability alert for host %s: %v", host.Hostname, err)
+		return
+	}
+	state.alertID = alertID
+}
+
+// resolveAlerts resolves the availability alerts open for a host. Besides the
+// one the monitor opened, the alert store is searched for alerts opened
+// before a restart.
+func (m *HostLivenessMonitor) resolveAlerts(ctx context.Context, hostname string, state *hostLiveness) {
+	var alertIDs []string
+	if state.alertID != "" {
+		alertIDs = append(alertIDs, state.alertID)
+	}
+	if state.agentID != "" {
+		alerts, err := m.alertRepo.ListActive(ctx, repository.AlertFilter{AgentID: state.agentID, AlertType: entity.AlertTypeAvailability})
+		if err != nil {
+			log.Printf("⚠ Failed to list availability alerts for host %s: %v", hostname, err)
+		}
+		for _, alert := range alerts {
+			if alert.Hostname == hostname && alert.ID != state.alertID {
+				alertIDs = append(alertIDs, alert.ID)
+			}
+		}
+	}
+
+	for _, alertID := range alertIDs {
+		if err := m.alertRepo.Resolve(ctx, alertID); err != nil {
+			log.Printf("⚠ Failed to resolve availability alert %s for host %s: %v", alertID, hostname, err)
+		}
+	}
+	state.alertID = ""
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/repository"
+	"smart-monitor/backend/internal/infrastructure/persistence"
+)
+
+func TestHostLivenessMonitorResolvesAlertsAfterRestart(t *testing.T) {
+	tests := []struct {
+		name string
+		// whether the restarted monitor checks once before the host reports
+		checkedWhileOffline bool
+	}{
+		{name: "host back before the first check"},
+		{name: "host back after the first check", checkedWhileOffline: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			hostRepo := persistence.NewInMemoryHostRepository()
+			eventRepo := persistence.NewInMemoryEventRepository()
+			alertRepo := persistence.NewInMemoryAlertRepository()
+			statsService := NewStatsService(persistence.NewInMemoryStatsRepository(10), hostRepo)
+			thresholds := LivenessThresholds{DefaultReportInterval: 10 * time.Second, StaleAfterIntervals: 3, OfflineAfterIntervals: 6}
+			newMonitor := func() *HostLivenessMonitor {
+				return NewHostLivenessMonitor(hostRepo, statsService, eventRepo, alertRepo, thresholds)
+			}
+			activeAlerts := func() []*entity.Alert {
+				alerts, err := alertRepo.ListActive(ctx, repository.AlertFilter{AgentID: "agent-1", AlertType: entity.AlertTypeAvailability})
+				if err != nil {
+					t.Fatalf("ListActive() error = %v", err)
+				}
+				return alerts
+			}
+
+			host := entity.NewHost("web-01", "10.0.0.1", "agent-1")
+			host.LastSeenAt = time.Now().Add(-10 * time.Minute)
+			if err := hostRepo.Create(ctx, host); err != nil {
+				t.Fatal(err)
+			}
+			if err := newMonitor().Check(ctx); err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if alerts := activeAlerts(); len(alerts) != 1 || alerts[0].Severity != entity.AlertSeverityCritical {
+				t.Fatalf("active alerts = %v, want one critical alert", alerts)
+			}
+
+			restarted := newMonitor()
+			if tt.checkedWhileOffline {
+				if err := restarted.Check(ctx); err != nil {
+					t.Fatalf("Check() error = %v", err)
+				}
+			}
+			if err := statsService.ProcessStats(ctx, entity.NewStats("web-01", "agent-1", "10.0.0.1", 10, 10, 10), "1.0.0"); err != nil {
+				t.Fatalf("ProcessStats() error = %v", err)
+			}
+			if err := restarted.Check(ctx); err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+
+			if alerts := activeAlerts(); len(alerts) != 0 {
+				t.Errorf("%d availability alerts open after the host reported again, want 0", len(alerts))
+			}
+		})
+	}
+}
@@ -0,0 +1,380 @@
+// Package service defines domain services
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/repository"
+)
+
+// PolicyEvaluator compares incoming stats against the policies applied to the reporting agent
+type PolicyEvaluator struct {
+	policyRepo  repository.PolicyRepository
+	alertRepo   repository.AlertRepository
+	historyRepo repository.StatsHistoryRepository // optional, used to recover pending breaches
+
+	// evalMu is held for reading by evaluations and for writing while states
+	// are pruned, so no evaluation of a stale policy outlives the pruning
+	evalMu sync.RWMutex
+	mu     sync.Mutex              // guards states
+	states map[string]*policyState // key: policyID/agentID
+}
+
+// policyState tracks the rule states of one policy on one agent.
+// mu is held across a whole rule transition, including the alert it opens or
+// resolves, so concurrent streams of one agent cannot both open an alert.
+type policyState struct {
+	policyID string
+	agentID  string
+
+	mu    sync.Mutex
+	rules map[string]*ruleState // key: rule expression
+}
+
+// ruleState is the state machine of a single rule.
+// It is OK when both fields are zero, pending when only pendingSince is set,
+// and firing when alertID is set.
+type ruleState struct {
+	pendingSince time.Time
+	alertID      string
+}
+
+// NewPolicyEvaluator creates a new PolicyEvaluator.
+// historyRepo may be nil when the stats store does not keep history.
+func NewPolicyEvaluator(policyRepo repository.PolicyRepository, alertRepo repository.AlertRepository, historyRepo repository.StatsHistoryRepository) *PolicyEvaluator {
+	return &PolicyEvaluator{
+		policyRepo:  policyRepo,
+		alertRepo:   alertRepo,
+		historyRepo: historyRepo,
+		states:      make(map[string]*policyState),
+	}
+}
+
+// Evaluate checks stats against every enabled policy applied to the agent.
+// A rule opens an alert once its condition has held for the rule's duration,
+// and resolves it when the metric crosses back over the clear level.
+func (e *PolicyEvaluator) Evaluate(ctx context.Context, stats *entity.Stats) error {
+	e.evalMu.RLock()
+	defer e.evalMu.RUnlock()
+
+	policies, err := e.policyRepo.GetByAgent(stats.AgentID)
+	if err != nil {
+		return fmt.Errorf("failed to load policies for agent %s: %w", stats.AgentID, err)
+	}
+
+	var errs []error
+	for _, policy := range policies {
+		if !policy.Enabled {
+			continue
+		}
+
+		for i := range policy.Rules {
+			rule := &policy.Rules[i]
+			value, ok := stats.MetricValue(rule.Metric)
+			if !ok {
+				continue
+			}
+			if err := e.evaluateRule(ctx, policy, rule, stats, value); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// evaluateRule advances the state machine of one rule
+func (e *PolicyEvaluator) evaluateRule(ctx context.Context, policy *entity.Policy, rule *entity.PolicyRule, stats *entity.Stats, value float64) error {
+	ps := e.stateFor(policy.PolicyID, stats.AgentID)
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	state := ps.rule(rule.String())
+	now := stats.Timestamp
+
+	switch {
+	case state.alertID != "":
+		if !rule.IsCleared(value) {
+			return nil
+		}
+		if err := e.alertRepo.Resolve(ctx, state.alertID); err != nil {
+			return fmt.Errorf("failed to resolve alert %s: %w", state.alertID, err)
+		}
+		log.Printf("✓ Alert %s resolved: %s on %s is %.2f (clear level %.2f)", state.alertID, rule.Metric, stats.Hostname, value, rule.Clear)
+		*state = ruleState{}
+		return nil
+
+	case !rule.IsBreached(value):
+		state.pendingSince = time.Time{}
+		return nil
+
+	case state.pendingSince.IsZero():
+		state.pendingSince = e.breachStart(ctx, rule, stats)
+	}
+
+	if now.Sub(state.pendingSince) < rule.For {
+		return nil
+	}
+
+	alertID, err := e.openAlert(ctx, policy, rule, stats, value)
+	if err != nil {
+		return err
+	}
+	*state = ruleState{alertID: alertID}
+	return nil
+}
+
+// Restore rebuilds firing rule states from unresolved policy alerts, so
+// alerts opened before a restart still resolve once their metric clears.
+// Alerts of rules that no longer apply are resolved, as are duplicates of an
+// older alert of the same rule. It returns the number of restored alerts.
+func (e *PolicyEvaluator) Restore(ctx context.Context) (int, error) {
+	alerts, err := e.alertRepo.ListActive(ctx, repository.AlertFilter{AlertType: entity.AlertTypePolicy})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list active policy alerts: %w", err)
+	}
+	if len(alerts) == 0 {
+		return 0, nil
+	}
+
+	policies, err := e.allPolicies()
+	if err != nil {
+		return 0, err
+	}
+
+	e.evalMu.Lock()
+	defer e.evalMu.Unlock()
+
+	restored := 0
+	var errs []error
+	for _, alert := range alerts {
+		rule := alert.Metadata["rule"]
+		if policy := policies[alert.PolicyID]; policy != nil && policy.Enabled &&
+			slices.Contains(policy.AppliedAgents, alert.AgentID) && hasRule(policy, rule) {
+			ps := e.stateFor(alert.PolicyID, alert.AgentID)
+			ps.mu.Lock()
+			state := ps.rule(rule)
+			if state.alertID == "" {
+				*state = ruleState{alertID: alert.ID}
+			}
+			ps.mu.Unlock()
+			if state.alertID == alert.ID {
+				restored++
+				continue
+			}
+		}
+
+		if err := e.alertRepo.Resolve(ctx, alert.ID); err != nil {
+			errs = append(errs, fmt.Errorf("failed to resolve alert %s: %w", alert.ID, err))
+			continue
+		}
+		log.Printf("✓ Alert %s resolved: rule %q of policy %s no longer applies to agent %s", alert.ID, rule, alert.PolicyID, alert.AgentID)
+	}
+
+	return restored, errors.Join(errs...)
+}
+
+// allPolicies returns every policy by ID
+func (e *PolicyEvaluator) allPolicies() (map[string]*entity.Policy, error) {
+	_, total, err := e.policyRepo.GetAll(1, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count policies: %w", err)
+	}
+
+	byID := make(map[string]*entity.Policy, total)
+	if total == 0 {
+		return byID, nil
+	}
+	policies, _, err := e.policyRepo.GetAll(1, total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policies: %w", err)
+	}
+	for _, policy := range policies {
+		byID[policy.PolicyID] = policy
+	}
+
+	return byID, nil
+}
+
+// hasRule reports whether a policy has a rule with the given expression
+func hasRule(policy *entity.Policy, rule string) bool {
+	for i := range policy.Rules {
+		if policy.Rules[i].String() == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// SyncPolicy drops the states of rules and agents a policy no longer
+// evaluates after it changed, resolving their open alerts. Every state is
+// dropped when the policy is disabled.
+func (e *PolicyEvaluator) SyncPolicy(ctx context.Context, policy *entity.Policy) error {
+	applied := make(map[string]bool, len(policy.AppliedAgents))
+	for _, agentID := range policy.AppliedAgents {
+		applied[agentID] = true
+	}
+	rules := make(map[string]bool, len(policy.Rules))
+	for _, rule := range policy.Rules {
+		rules[rule.String()] = true
+	}
+
+	return e.prune(ctx, policy.PolicyID, func(agentID, rule string) bool {
+		return policy.Enabled && applied[agentID] && rules[rule]
+	})
+}
+
+// ForgetPolicy drops every state of a removed policy, resolving its open alerts
+func (e *PolicyEvaluator) ForgetPolicy(ctx context.Context, policyID string) error {
+	return e.prune(ctx, policyID, func(agentID, rule string) bool {
+		return false
+	})
+}
+
+// prune drops the rule states of a policy that keep rejects and resolves
+// their alerts. A state whose alert cannot be resolved is kept, so the next
+// pruning tries again.
+func (e *PolicyEvaluator) prune(ctx context.Context, policyID string, keep func(agentID, rule string) bool) error {
+	e.evalMu.Lock()
+	defer e.evalMu.Unlock()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var errs []error
+	for key, ps := range e.states {
+		if ps.policyID != policyID {
+			continue
+		}
+
+		for rule, state := range ps.rules {
+			if keep(ps.agentID, rule) {
+				continue
+			}
+			if state.alertID != "" {
+				if err := e.alertRepo.Resolve(ctx, state.alertID); err != nil {
+					errs = append(errs, fmt.Errorf("failed to resolve alert %s: %w", state.alertID, err))
+					continue
+				}
+				log.Printf("✓ Alert %s resolved: rule %s of policy %s no longer applies to agent %s", state.alertID, rule, policyID, ps.agentID)
+			}
+			delete(ps.rules, rule)
+		}
+
+		if len(ps.rules) == 0 {
+			delete(e.states, key)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// breachStart finds when the current breach began. Without history the breach
+// starts with the current sample; with history it is the oldest sample of the
+// unbroken run of breaching samples leading up to now.
+func (e *PolicyEvaluator) breachStart(ctx context.Context, rule *entity.PolicyRule, stats *entity.Stats) time.Time {
+	start := stats.Timestamp
+	if rule.For == 0 || e.historyRepo == nil {
+		return start
+	}
+
+	history, err := e.historyRepo.GetHistory(ctx, stats.AgentID, start.Add(-rule.For-time.Minute), start)
+	if err != nil {
+		log.Printf("Failed to load stats history for agent %s: %v", stats.AgentID, err)
+		return start
+	}
+
+	for i := len(history) - 1; i >= 0; i-- {
+		value, _ := history[i].MetricValue(rule.Metric)
+		if !rule.IsBreached(value) {
+			break
+		}
+		if history[i].Timestamp.Before(start) {
+			start = history[i].Timestamp
+		}
+	}
+
+	return start
+}
+
+// openAlert creates an alert for a rule that has fired
+func (e *PolicyEvaluator) openAlert(ctx context.Context, policy *entity.Policy, rule *entity.PolicyRule, stats *entity.Stats, value float64) (string, error) {
+	message := fmt.Sprintf("%s on %s is %.2f, threshold is %s %.2f", rule.Metric, stats.Hostname, value, rule.Comparator, rule.Value)
+	if rule.For > 0 {
+		message += fmt.Sprintf(" for %s", rule.For)
+	}
+	message += fmt.Sprintf(" (policy %s)", policy.PolicyID)
+
+	alert := entity.NewAlert(
+		stats.Hostname,
+		stats.AgentID,
+		entity.AlertTypePolicy,
+		ruleSeverity(policy, rule),
+		fmt.Sprintf("%s: %s %s %.2f", policy.Name, rule.Metric, rule.Comparator, rule.Value),
+		message,
+	)
+	alert.ID = fmt.Sprintf("%s-%s-%s-%d", policy.PolicyID, stats.AgentID, rule.Metric, time.Now().UnixNano())
+	alert.PolicyID = policy.PolicyID
+	alert.Metric = rule.Metric
+	alert.Value = value
+	alert.Threshold = rule.Value
+	alert.Metadata["policy_name"] = policy.Name
+	alert.Metadata["rule"] = rule.String()
+	if len(policy.Actions) > 0 {
+		alert.Metadata["actions"] = strings.Join(policy.Actions, ",")
+	}
+
+	alertID, err := e.alertRepo.Create(ctx, alert)
+	if err != nil {
+		return "", fmt.Errorf("failed to create alert for policy %s: %w", policy.PolicyID, err)
+	}
+
+	log.Printf("⚠ Alert %s [%s]: %s", alertID, alert.Severity, alert.Message)
+	return alertID, nil
+}
+
+// stateFor returns the state of a policy on an agent, creating it if needed
+func (e *PolicyEvaluator) stateFor(policyID, agentID string) *policyState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	key := policyID + "/" + agentID
+	ps, exists := e.states[key]
+	if !exists {
+		ps = &policyState{policyID: policyID, agentID: agentID, rules: make(map[string]*ruleState)}
+		e.states[key] = ps
+	}
+
+	return ps
+}
+
+// rule returns the state of a rule, creating it if needed.
+// The caller must hold ps.mu.
+func (ps *policyState) rule(rule string) *ruleState {
+	rs, exists := ps.rules[rule]
+	if !exists {
+		rs = &ruleState{}
+		ps.rules[rule] = rs
+	}
+
+	return rs
+}
+
+// ruleSeverity returns the rule's severity, falling back to the one configured
+// in policy metadata and finally to high
+func ruleSeverity(policy *entity.Policy, rule *entity.PolicyRule) entity.AlertSeverity {
+	if rule.Severity != "" {
+		return rule.Severity
+	}
+	if severity, ok := entity.ParseAlertSeverity(policy.Metadata["severity"]); ok {
+		return severity
+	}
+	return entity.AlertSeverityHigh
+}
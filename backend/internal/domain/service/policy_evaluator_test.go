@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/repository"
+	"smart-monitor/backend/internal/infrastructure/persistence"
+)
+
+// fakeAlertRepo records alerts; Create is slow so concurrent evaluations overlap
+type fakeAlertRepo struct {
+	mu     sync.Mutex
+	alerts map[string]*entity.Alert
+}
+
+func newFakeAlertRepo() *fakeAlertRepo {
+	return &fakeAlertRepo{alerts: make(map[string]*entity.Alert)}
+}
+
+func (r *fakeAlertRepo) Create(ctx context.Context, alert *entity.Alert) (string, error) {
+	time.Sleep(10 * time.Millisecond)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.alerts[alert.ID]; exists {
+		return "", fmt.Errorf("alert already exists: %s", alert.ID)
+	}
+	r.alerts[alert.ID] = alert
+	return alert.ID, nil
+}
+
+func (r *fakeAlertRepo) Resolve(ctx context.Context, alertID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	alert, exists := r.alerts[alertID]
+	if !exists {
+		return fmt.Errorf("alert not found: %s", alertID)
+	}
+	alert.Resolve()
+	return nil
+}
+
+func (r *fakeAlertRepo) ListActive(ctx context.Context, filter repository.AlertFilter) ([]*entity.Alert, error) {
+	var alerts []*entity.Alert
+	for _, alert := range r.active() {
+		if filter.AlertType == "" || alert.AlertType == filter.AlertType {
+			alerts = append(alerts, alert)
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].CreatedAt.Before(alerts[j].CreatedAt)
+	})
+	return alerts, nil
+}
+
+// active returns the alerts that are not resolved
+func (r *fakeAlertRepo) active() []*entity.Alert {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var active []*entity.Alert
+	for _, alert := range r.alerts {
+		if alert.IsActive() {
+			active = append(active, alert)
+		}
+	}
+	return active
+}
+
+// newTestEvaluator returns an evaluator with policy "cpu-high" (cpu > 80)
+// applied to agent-1
+func newTestEvaluator(t *testing.T) (*PolicyEvaluator, *persistence.InMemoryPolicyRepository, *fakeAlertRepo) {
+	t.Helper()
+
+	policyRepo := persistence.NewInMemoryPolicyRepository()
+	rule := entity.NewPolicyRule("cpu", entity.ComparatorGreaterThan, 80, 0, "")
+	policy := entity.NewPolicy("cpu-high", "CPU high", "", []entity.PolicyRule{rule}, nil, nil)
+	if err := policyRepo.Create(policy); err != nil {
+		t.Fatal(err)
+	}
+	if err := policyRepo.ApplyToAgent("cpu-high", "agent-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	alertRepo := newFakeAlertRepo()
+	return NewPolicyEvaluator(policyRepo, alertRepo, nil), policyRepo, alertRepo
+}
+
+func TestPolicyEvaluatorConcurrentBreachOpensOneAlert(t *testing.T) {
+	evaluator, _, alertRepo := newTestEvaluator(t)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stats := entity.NewStats("web-01", "agent-1", "10.0.0.1", 95, 10, 10)
+			if err := evaluator.Evaluate(context.Background(), stats); err != nil {
+				t.Errorf("Evaluate() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if active := alertRepo.active(); len(active) != 1 {
+		t.Fatalf("%d active alerts, want 1", len(active))
+	}
+
+	stats := entity.NewStats("web-01", "agent-1", "10.0.0.1", 50, 10, 10)
+	if err := evaluator.Evaluate(context.Background(), stats); err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if active := alertRepo.active(); len(active) != 0 {
+		t.Errorf("%d active alerts after the metric cleared, want 0", len(active))
+	}
+}
+
+func TestPolicyChangesResolveStaleAlerts(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(s *PolicyService) error
+		// whether the alert stays open and the rule is still evaluated
+		wantKept bool
+	}{
+		{
+			name: "description updated",
+			change: func(s *PolicyService) error {
+				_, err := s.UpdatePolicy("cpu-high", "", "busy hosts", nil, nil, nil, nil)
+				return err
+			},
+			wantKept: true,
+		},
+		{
+			name: "rule replaced",
+			change: func(s *PolicyService) error {
+				rule := entity.NewPolicyRule("cpu", entity.ComparatorGreaterThan, 90, 0, "")
+				_, err := s.UpdatePolicy("cpu-high", "", "", nil, []entity.PolicyRule{rule}, nil, nil)
+				return err
+			},
+		},
+		{
+			name:   "unapplied",
+			change: func(s *PolicyService) error { return s.UnapplyPolicyFromAgent("cpu-high", "agent-1") },
+		},
+		{
+			name:   "disabled",
+			change: func(s *PolicyService) error { return s.DisablePolicy("cpu-high") },
+		},
+		{
+			name:   "removed",
+			change: func(s *PolicyService) error { return s.RemovePolicy("cpu-high") },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator, policyRepo, alertRepo := newTestEvaluator(t)
+			policyService := NewPolicyService(policyRepo, nil, evaluator)
+
+			stats := entity.NewStats("web-01", "agent-1", "10.0.0.1", 95, 10, 10)
+			if err := evaluator.Evaluate(context.Background(), stats); err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if err := tt.change(policyService); err != nil {
+				t.Fatalf("change error = %v", err)
+			}
+
+			wantActive, wantStates := 0, 0
+			if tt.wantKept {
+				wantActive, wantStates = 1, 1
+			}
+			if active := alertRepo.active(); len(active) != wantActive {
+				t.Errorf("%d active alerts, want %d", len(active), wantActive)
+			}
+			if len(evaluator.states) != wantStates {
+				t.Errorf("%d policy states, want %d", len(evaluator.states), wantStates)
+			}
+		})
+	}
+}
+
+func TestPolicyEvaluatorRestore(t *testing.T) {
+	evaluator, policyRepo, alertRepo := newTestEvaluator(t)
+	ctx := context.Background()
+
+	if err := evaluator.Evaluate(ctx, entity.NewStats("web-01", "agent-1", "10.0.0.1", 95, 10, 10)); err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	firing := alertRepo.active()[0]
+
+	// A duplicate of the firing alert and the alert of a rule that was removed
+	// while the backend was down
+	for id, rule := range map[string]string{"duplicate": firing.Metadata["rule"], "stale": "cpu > 50"} {
+		alert := entity.NewAlert("web-01", "agent-1", entity.AlertTypePolicy, entity.AlertSeverityHigh, "cpu high", "")
+		alert.ID = id
+		alert.PolicyID = "cpu-high"
+		alert.CreatedAt = firing.CreatedAt.Add(time.Second)
+		alert.Metadata["rule"] = rule
+		if _, err := alertRepo.Create(ctx, alert); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	restarted := NewPolicyEvaluator(policyRepo, alertRepo, nil)
+	restored, err := restarted.Restore(ctx)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if restored != 1 {
+		t.Errorf("Restore() = %d, want 1", restored)
+	}
+	if active := alertRepo.active(); len(active) != 1 || active[0].ID != firing.ID {
+		t.Fatalf("active alerts = %v, want only %s", active, firing.ID)
+	}
+
+	// Still breaching: no new alert
+	if err := restarted.Evaluate(ctx, entity.NewStats("web-01", "agent-1", "10.0.0.1", 95, 10, 10)); err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if active := alertRepo.active(); len(active) != 1 {
+		t.Errorf("%d active alerts while still breaching, want 1", len(active))
+	}
+
+	if err := restarted.Evaluate(ctx, entity.NewStats("web-01", "agent-1", "10.0.0.1", 50, 10, 10)); err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if active := alertRepo.active(); len(active) != 0 {
+		t.Errorf("%d active alerts after the metric cleared, want 0", len(active))
+	}
+}
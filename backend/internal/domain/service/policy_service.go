@@ -7,31 +7,44 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"log"
 	"smart-monitor/backend/internal/domain/entity"
 	"smart-monitor/backend/internal/domain/repository"
 	"time"
 )
 
+// ErrInvalidPolicy is returned when a policy fails validation
+var ErrInvalidPolicy = errors.New("invalid policy")
+
 // PolicyService handles policy business logic
 type PolicyService struct {
 	policyRepo repository.PolicyRepository
 	agentRepo  repository.AgentRegistryRepository
+	evaluator  *PolicyEvaluator // optional, told about policy changes
 }
 
-// NewPolicyService creates a new policy service
-func NewPolicyService(policyRepo repository.PolicyRepository, agentRepo repository.AgentRegistryRepository) *PolicyService {
+// NewPolicyService creates a new policy service.
+// evaluator may be nil when policies are not evaluated.
+func NewPolicyService(policyRepo repository.PolicyRepository, agentRepo repository.AgentRegistryRepository, evaluator *PolicyEvaluator) *PolicyService {
 	return &PolicyService{
 		policyRepo: policyRepo,
 		agentRepo:  agentRepo,
+		evaluator:  evaluator,
 	}
 }
 
-// CreatePolicy creates a new policy
-func (s *PolicyService) CreatePolicy(name, description string, thresholds map[string]string, actions []string, metadata map[string]string) (*entity.Policy, error) {
+// CreatePolicy creates a new policy.
+// Legacy map-based thresholds are migrated into typed rules.
+func (s *PolicyService) CreatePolicy(name, description string, thresholds map[string]string, rules []entity.PolicyRule, actions []string, metadata map[string]string) (*entity.Policy, error) {
+	rules, err := buildPolicyRules(thresholds, rules)
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate policy ID
 	policyID := s.generatePolicyID(name)
 
-	policy := entity.NewPolicy(policyID, name, description, thresholds, actions, metadata)
+	policy := entity.NewPolicy(policyID, name, description, rules, actions, metadata)
 
 	if err := s.policyRepo.Create(policy); err != nil {
 		return nil, err
@@ -40,25 +53,41 @@ func (s *PolicyService) CreatePolicy(name, description string, thresholds map[st
 	return policy, nil
 }
 
-// UpdatePolicy updates an existing policy
-func (s *PolicyService) UpdatePolicy(policyID, name, description string, thresholds map[string]string, actions []string, metadata map[string]string) (*entity.Policy, error) {
+// UpdatePolicy updates an existing policy.
+// Rules are replaced only when thresholds or rules are provided.
+func (s *PolicyService) UpdatePolicy(policyID, name, description string, thresholds map[string]string, rules []entity.PolicyRule, actions []string, metadata map[string]string) (*entity.Policy, error) {
+	rules, err := buildPolicyRules(thresholds, rules)
+	if err != nil {
+		return nil, err
+	}
+
 	policy, err := s.policyRepo.GetByID(policyID)
 	if err != nil {
 		return nil, err
 	}
 
-	policy.Update(name, description, thresholds, actions, metadata)
+	policy.Update(name, description, rules, actions, metadata)
 
 	if err := s.policyRepo.Update(policy); err != nil {
 		return nil, err
 	}
+	s.syncEvaluator(policy)
 
 	return policy, nil
 }
 
-// RemovePolicy deletes a policy
+// RemovePolicy deletes a policy and resolves its open alerts
 func (s *PolicyService) RemovePolicy(policyID string) error {
-	return s.policyRepo.Delete(policyID)
+	if err := s.policyRepo.Delete(policyID); err != nil {
+		return err
+	}
+
+	if s.evaluator != nil {
+		if err := s.evaluator.ForgetPolicy(context.Background(), policyID); err != nil {
+			log.Printf("⚠ Failed to resolve alerts of removed policy %s: %v", policyID, err)
+		}
+	}
+	return nil
 }
 
 // GetPolicy retrieves a policy by ID
@@ -92,9 +121,16 @@ func (s *PolicyService) ApplyPolicyToAgent(policyID, agentID string) error {
 	return s.policyRepo.ApplyToAgent(policyID, agentID)
 }
 
-// UnapplyPolicyFromAgent removes policy from agent
+// UnapplyPolicyFromAgent removes policy from agent and resolves its open alerts there
 func (s *PolicyService) UnapplyPolicyFromAgent(policyID, agentID string) error {
-	return s.policyRepo.UnapplyFromAgent(policyID, agentID)
+	if err := s.policyRepo.UnapplyFromAgent(policyID, agentID); err != nil {
+		return err
+	}
+
+	if policy, err := s.policyRepo.GetByID(policyID); err == nil {
+		s.syncEvaluator(policy)
+	}
+	return nil
 }
 
 // GetPoliciesByAgent retrieves policies applied to an agent
@@ -121,7 +157,12 @@ func (s *PolicyService) DisablePolicy(policyID string) error {
 	}
 
 	policy.Disable()
-	return s.policyRepo.Update(policy)
+	if err := s.policyRepo.Update(policy); err != nil {
+		return err
+	}
+
+	s.syncEvaluator(policy)
+	return nil
 }
 
 // AddAllowedUserToPolicy allows a specific user to access/apply a policy
@@ -157,6 +198,72 @@ func (s *PolicyService) ListAllowedUsers(policyID string) ([]string, error) {
 	return append([]string{}, policy.AllowedUserIDs...), nil
 }
 
+// MigrateLegacyPolicies converts map-based thresholds of stored policies into typed rules
+func (s *PolicyService) MigrateLegacyPolicies() (int, error) {
+	_, total, err := s.policyRepo.GetAll(1, 1)
+	if err != nil || total == 0 {
+		return 0, err
+	}
+
+	policies, _, err := s.policyRepo.GetAll(1, total)
+	if err != nil {
+		return 0, err
+	}
+
+	migrated := 0
+	for _, policy := range policies {
+		if !policy.HasLegacyThresholds() {
+			continue
+		}
+		if err := policy.MigrateThresholds(); err != nil {
+			log.Printf("Policy %s: cannot migrate thresholds: %v", policy.PolicyID, err)
+			continue
+		}
+		if err := s.policyRepo.Update(policy); err != nil {
+			return migrated, fmt.Errorf("failed to update policy %s: %w", policy.PolicyID, err)
+		}
+		migrated++
+	}
+
+	return migrated, nil
+}
+
+// syncEvaluator drops evaluation state a changed policy no longer needs.
+// The change is already stored, so failures are only logged.
+func (s *PolicyService) syncEvaluator(policy *entity.Policy) {
+	if s.evaluator == nil {
+		return
+	}
+	if err := s.evaluator.SyncPolicy(context.Background(), policy); err != nil {
+		log.Printf("⚠ Failed to resolve alerts of policy %s: %v", policy.PolicyID, err)
+	}
+}
+
+// buildPolicyRules merges typed rules with migrated legacy thresholds and validates them.
+// It returns nil when neither is provided.
+func buildPolicyRules(thresholds map[string]string, rules []entity.PolicyRule) ([]entity.PolicyRule, error) {
+	if thresholds == nil && rules == nil {
+		return nil, nil
+	}
+
+	legacy, err := entity.RulesFromThresholds(thresholds)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+	}
+
+	all := make([]entity.PolicyRule, 0, len(rules)+len(legacy))
+	all = append(all, rules...)
+	all = append(all, legacy...)
+	for i := range all {
+		all[i].Normalize()
+		if err := all[i].Validate(); err != nil {
+			return nil, fmt.Errorf("%w: rule %d: %v", ErrInvalidPolicy, i+1, err)
+		}
+	}
+
+	return all, nil
+}
+
 // generatePolicyID generates a unique policy ID
 func (s *PolicyService) generatePolicyID(name string) string {
 	data := fmt.Sprintf("%s-%d", name, time.Now().UnixNano())
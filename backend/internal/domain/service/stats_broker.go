@@ -0,0 +1,126 @@
+// Package service implements business logic
+package service
+
+import (
+	"slices"
+	"smart-monitor/backend/internal/domain/entity"
+	"sync"
+	"sync/atomic"
+)
+
+// statsSubscriptionBuffer is how many samples a subscriber may fall behind
+// before further samples are dropped for it
+const statsSubscriptionBuffer = 64
+
+// StatsFilter selects the samples a subscriber receives. Empty fields match
+// every sample.
+type StatsFilter struct {
+	AgentIDs []string
+	Labels   map[string]string // metadata every sample must carry
+}
+
+// Matches checks if a sample passes the filter
+func (f StatsFilter) Matches(stats *entity.Stats) bool {
+	if len(f.AgentIDs) > 0 && !slices.Contains(f.AgentIDs, stats.AgentID) {
+		return false
+	}
+	for key, value := range f.Labels {
+		if stats.Metadata[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// StatsSubscription receives the samples published after it was created
+type StatsSubscription struct {
+	broker  *StatsBroker
+	filter  StatsFilter
+	updates chan *entity.Stats
+	dropped atomic.Uint64
+	closed  bool // guarded by broker.mu
+}
+
+// Updates returns the channel samples are delivered on. It is closed when the
+// subscription is closed.
+func (s *StatsSubscription) Updates() <-chan *entity.Stats {
+	return s.updates
+}
+
+// Dropped returns how many samples were dropped because the subscriber fell behind
+func (s *StatsSubscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close stops the subscription
+func (s *StatsSubscription) Close() {
+	s.broker.unsubscribe(s)
+}
+
+// StatsBroker fans incoming samples out to live subscribers, such as
+// dashboards, in process. Publishing never blocks: a subscriber that falls
+// behind misses samples rather than slowing down ingestion.
+type StatsBroker struct {
+	mu   sync.RWMutex
+	subs map[*StatsSubscription]struct{}
+}
+
+// NewStatsBroker creates a new stats broker
+func NewStatsBroker() *StatsBroker {
+	return &StatsBroker{
+		subs: make(map[*StatsSubscription]struct{}),
+	}
+}
+
+// Subscribe starts delivering the samples that match filter
+func (b *StatsBroker) Subscribe(filter StatsFilter) *StatsSubscription {
+	sub := &StatsSubscription{
+		broker:  b,
+		filter:  filter,
+		updates: make(chan *entity.Stats, statsSubscriptionBuffer),
+	}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub
+}
+
+// Publish delivers a sample to every subscriber whose filter it matches.
+// Subscribers must not modify it.
+func (b *StatsBroker) Publish(stats *entity.Stats) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subs {
+		if !sub.filter.Matches(stats) {
+			continue
+		}
+		select {
+		case sub.updates <- stats:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
+
+// Subscribers returns the number of open subscriptions
+func (b *StatsBroker) Subscribers() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs)
+}
+
+// unsubscribe removes a subscription and closes its channel
+func (b *StatsBroker) unsubscribe(sub *StatsSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	delete(b.subs, sub)
+	close(sub.updates)
+}
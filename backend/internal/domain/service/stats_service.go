@@ -3,15 +3,40 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
+
 	"smart-monitor/backend/internal/domain/entity"
 	"smart-monitor/backend/internal/domain/repository"
 )
 
+var (
+	// ErrStatsNotFound is returned when a host or agent has not reported any stats
+	ErrStatsNotFound = errors.New("no stats available")
+
+	// ErrInvalidStatsQuery is returned when a range query fails validation
+	ErrInvalidStatsQuery = errors.New("invalid stats query")
+
+	// ErrInvalidStats is returned for samples that fail validation and are
+	// skipped, as storing them again would fail again
+	ErrInvalidStats = errors.New("invalid stats data")
+)
+
+// Defaults of range queries
+const (
+	defaultRangeSpan = time.Hour
+	defaultRangeStep = time.Minute
+)
+
 // StatsService provides business logic for stats
 type StatsService struct {
 	statsRepo repository.StatsRepository
 	hostRepo  repository.HostRepository
+
+	hostMu sync.Mutex // serializes host updates from samples and liveness checks
 }
 
 // NewStatsService creates a new StatsService
@@ -34,12 +59,61 @@ func (s *StatsService) ProcessStats(ctx context.Context, stats *entity.Stats, ag
 		return fmt.Errorf("failed to save stats: %w", err)
 	}
 
-	// Update or create host
-	host, err := s.hostRepo.Get(ctx, stats.AgentID)
+	return s.updateHost(ctx, stats, agentVersion)
+}
+
+// ProcessBatch processes a batch of samples from one agent, such as samples
+// buffered during an outage. Samples are stored oldest first at the time they
+// were collected; invalid samples are skipped and reported in the error.
+// It returns the samples that were stored.
+func (s *StatsService) ProcessBatch(ctx context.Context, batch []*entity.Stats, agentVersion string) ([]*entity.Stats, error) {
+	var errs []error
+	valid := make([]*entity.Stats, 0, len(batch))
+	for _, stats := range batch {
+		if !stats.IsValid() {
+			errs = append(errs, fmt.Errorf("%w from agent %s at %s", ErrInvalidStats, stats.AgentID, stats.Timestamp.Format(time.RFC3339)))
+			continue
+		}
+		valid = append(valid, stats)
+	}
+	if len(valid) == 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	sort.SliceStable(valid, func(i, j int) bool {
+		return valid[i].Timestamp.Before(valid[j].Timestamp)
+	})
+
+	if batchRepo, ok := s.statsRepo.(repository.StatsBatchRepository); ok {
+		if err := batchRepo.SaveBatch(ctx, valid); err != nil {
+			return nil, fmt.Errorf("failed to save stats batch: %w", err)
+		}
+	} else {
+		for _, stats := range valid {
+			if err := s.statsRepo.Save(ctx, stats); err != nil {
+				return nil, fmt.Errorf("failed to save stats: %w", err)
+			}
+		}
+	}
+
+	if err := s.updateHost(ctx, valid[len(valid)-1], agentVersion); err != nil {
+		errs = append(errs, err)
+	}
+
+	return valid, errors.Join(errs...)
+}
+
+// updateHost creates or refreshes the host that reported stats
+func (s *StatsService) updateHost(ctx context.Context, stats *entity.Stats, agentVersion string) error {
+	s.hostMu.Lock()
+	defer s.hostMu.Unlock()
+
+	host, err := s.hostRepo.Get(ctx, stats.Hostname)
 	if err != nil {
 		// Create new host if not exists
 		host = entity.NewHost(stats.Hostname, stats.IPAddress, stats.AgentID)
 		host.AgentVersion = agentVersion
+		host.ReportInterval = stats.ReportInterval
 		if stats.Metadata != nil {
 			host.Metadata = stats.Metadata
 		}
@@ -49,9 +123,12 @@ func (s *StatsService) ProcessStats(ctx context.Context, stats *entity.Stats, ag
 	} else {
 		// Update existing host
 		host.MarkSeen()
-		host.Hostname = stats.Hostname   // Update hostname if changed
+		host.AgentID = stats.AgentID     // Update reporting agent if replaced
 		host.IPAddress = stats.IPAddress // Update IP if changed
 		host.AgentVersion = agentVersion // Update version
+		if stats.ReportInterval > 0 {
+			host.ReportInterval = stats.ReportInterval
+		}
 		if stats.Metadata != nil {
 			for k, v := range stats.Metadata {
 				host.UpdateMetadata(k, v)
@@ -65,6 +142,29 @@ func (s *StatsService) ProcessStats(ctx context.Context, stats *entity.Stats, ag
 	return nil
 }
 
+// UpdateHostStatus sets the status of a host unless it reported since
+// lastSeenAt, in which case its status is left to the newer sample. It
+// reports whether the status was updated.
+func (s *StatsService) UpdateHostStatus(ctx context.Context, hostname string, status entity.HostStatus, lastSeenAt time.Time) (bool, error) {
+	s.hostMu.Lock()
+	defer s.hostMu.Unlock()
+
+	host, err := s.hostRepo.Get(ctx, hostname)
+	if err != nil {
+		return false, fmt.Errorf("failed to get host: %w", err)
+	}
+	if !host.LastSeenAt.Equal(lastSeenAt) {
+		return false, nil
+	}
+
+	host.UpdateStatus(status)
+	if err := s.hostRepo.Update(ctx, host); err != nil {
+		return false, fmt.Errorf("failed to update host: %w", err)
+	}
+
+	return true, nil
+}
+
 // GetStats retrieves stats for a hostname
 func (s *StatsService) GetStats(ctx context.Context, hostname string) (*entity.Stats, error) {
 	if hostname == "" {
@@ -72,6 +172,27 @@ func (s *StatsService) GetStats(ctx context.Context, hostname string) (*entity.S
 	}
 
 	stats, err := s.statsRepo.Get(ctx, hostname)
+	if errors.Is(err, repository.ErrStatsNotFound) {
+		return nil, fmt.Errorf("%w for host %s", ErrStatsNotFound, hostname)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetStatsByAgent retrieves the latest stats of the host an agent reported
+// most recently
+func (s *StatsService) GetStatsByAgent(ctx context.Context, agentID string) (*entity.Stats, error) {
+	if agentID == "" {
+		return nil, fmt.Errorf("agent ID is required")
+	}
+
+	stats, err := s.statsRepo.GetByAgent(ctx, agentID)
+	if errors.Is(err, repository.ErrStatsNotFound) {
+		return nil, fmt.Errorf("%w for agent %s", ErrStatsNotFound, agentID)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get stats: %w", err)
 	}
@@ -79,6 +200,37 @@ func (s *StatsService) GetStats(ctx context.Context, hostname string) (*entity.S
 	return stats, nil
 }
 
+// QueryRange returns the metrics of a host or agent over a time range,
+// downsampled to one point per step. Unset fields of the query are filled in:
+// cpu, ram and disk over the last hour, averaged over steps of a minute.
+func (s *StatsService) QueryRange(ctx context.Context, query *entity.StatsRangeQuery) ([]entity.StatsSeries, error) {
+	if len(query.Metrics) == 0 {
+		query.Metrics = []string{entity.MetricCPU, entity.MetricRAM, entity.MetricDisk}
+	}
+	if query.To.IsZero() {
+		query.To = time.Now()
+	}
+	if query.From.IsZero() {
+		query.From = query.To.Add(-defaultRangeSpan)
+	}
+	if query.Step == 0 {
+		query.Step = defaultRangeStep
+	}
+	if query.Aggregation == "" {
+		query.Aggregation = entity.AggregationAvg
+	}
+	if err := query.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidStatsQuery, err)
+	}
+
+	series, err := s.statsRepo.QueryRange(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stats range: %w", err)
+	}
+
+	return series, nil
+}
+
 // GetAllStats retrieves all stats
 func (s *StatsService) GetAllStats(ctx context.Context) ([]*entity.Stats, error) {
 	stats, err := s.statsRepo.GetAll(ctx)
@@ -89,12 +241,20 @@ func (s *StatsService) GetAllStats(ctx context.Context) ([]*entity.Stats, error)
 	return stats, nil
 }
 
-// GetActiveHosts returns list of active hosts
+// GetActiveHosts returns the hostnames of hosts that are online or stale, sorted
 func (s *StatsService) GetActiveHosts(ctx context.Context) ([]string, error) {
-	hosts, err := s.statsRepo.GetActiveHosts(ctx)
+	hosts, err := s.hostRepo.GetAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get active hosts: %w", err)
 	}
 
-	return hosts, nil
+	active := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		if host.IsActive() {
+			active = append(active, host.Hostname)
+		}
+	}
+	sort.Strings(active)
+
+	return active, nil
 }
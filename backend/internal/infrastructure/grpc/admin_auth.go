@@ -0,0 +1,99 @@
+// Package grpc implements gRPC handlers
+package grpc
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+
+	"smart-monitor/backend/internal/domain/service"
+	pb "smart-monitor/pbtypes/monitor"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Roles allowed to call restricted methods
+var (
+	adminOnly       = []string{"admin"}
+	adminOrOperator = []string{"admin", "operator"}
+)
+
+// methodRoles are the RPCs restricted to users with one of the listed roles
+var methodRoles = map[string][]string{
+	pb.MonitorService_ControlAgent_FullMethodName:          adminOnly,
+	pb.MonitorService_ListAgentCommands_FullMethodName:     adminOnly,
+	pb.MonitorService_BlockAgent_FullMethodName:            adminOnly,
+	pb.MonitorService_CreateEnrollmentToken_FullMethodName: adminOnly,
+	pb.MonitorService_ListEnrollmentTokens_FullMethodName:  adminOnly,
+	pb.MonitorService_RevokeEnrollmentToken_FullMethodName: adminOnly,
+	pb.MonitorService_ListAgents_FullMethodName:            adminOrOperator,
+	pb.MonitorService_GetAgent_FullMethodName:              adminOrOperator,
+	pb.MonitorService_RevokeAgent_FullMethodName:           adminOnly,
+	pb.MonitorService_DeleteAgent_FullMethodName:           adminOnly,
+	pb.MonitorService_MergeAgents_FullMethodName:           adminOnly,
+	pb.MonitorService_AddConfigProfile_FullMethodName:      adminOnly,
+	pb.MonitorService_UpdateConfigProfile_FullMethodName:   adminOnly,
+	pb.MonitorService_RemoveConfigProfile_FullMethodName:   adminOnly,
+	pb.MonitorService_AttachConfigProfile_FullMethodName:   adminOnly,
+	pb.MonitorService_DetachConfigProfile_FullMethodName:   adminOnly,
+}
+
+// AdminAuthInterceptor requires a user token with one of the roles listed in
+// methodRoles for restricted RPCs. The HTTP gateway forwards the Authorization header as
+// metadata, so gateway calls are checked as well.
+type AdminAuthInterceptor struct {
+	userAuthService *service.UserAuthService
+}
+
+// NewAdminAuthInterceptor creates a new admin authorization interceptor
+func NewAdminAuthInterceptor(userAuthService *service.UserAuthService) *AdminAuthInterceptor {
+	return &AdminAuthInterceptor{
+		userAuthService: userAuthService,
+	}
+}
+
+// Unary returns the interceptor for unary RPCs
+func (i *AdminAuthInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		roles, restricted := methodRoles[info.FullMethod]
+		if !restricted {
+			return handler(ctx, req)
+		}
+
+		if err := i.authorize(ctx, info.FullMethod, roles); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authorize checks that the user token in the metadata of an incoming call
+// carries one of roles
+func (i *AdminAuthInterceptor) authorize(ctx context.Context, method string, roles []string) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var token string
+	if values := md.Get(authorizationMetadataKey); len(values) > 0 {
+		scheme, credentials, ok := strings.Cut(values[0], " ")
+		if ok && strings.EqualFold(scheme, "bearer") {
+			token = strings.TrimSpace(credentials)
+		}
+	}
+	if token == "" {
+		return status.Error(codes.Unauthenticated, "missing user token")
+	}
+
+	claims, err := i.userAuthService.ParseToken(token)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	if role, _ := claims["role"].(string); !slices.Contains(roles, role) {
+		log.Printf("Denied %s to user %v with role %q", method, claims["sub"], role)
+		return status.Errorf(codes.PermissionDenied, "%s role required", strings.Join(roles, " or "))
+	}
+	return nil
+}
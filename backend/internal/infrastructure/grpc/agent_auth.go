@@ -0,0 +1,198 @@
+// Package grpc implements gRPC handlers
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/service"
+	pb "smart-monitor/pbtypes/monitor"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// authorizationMetadataKey carries "Bearer <access token>"
+	authorizationMetadataKey = "authorization"
+
+	// agentIDMetadataKey carries the agent ID; without it the agent is
+	// looked up by its token
+	agentIDMetadataKey = "x-agent-id"
+
+	// agentRecheckInterval is how often a long-lived stream checks that its
+	// agent was not blocked, revoked or deleted since it authenticated
+	agentRecheckInterval = 30 * time.Second
+)
+
+// agentMethods are the RPCs only registered agents may call
+var agentMethods = map[string]bool{
+	pb.MonitorService_StreamStats_FullMethodName:    true,
+	pb.MonitorService_CommandStream_FullMethodName:  true,
+	pb.MonitorService_GetAgentConfig_FullMethodName: true,
+}
+
+// agentContextKey is the context key of the authenticated agent
+type agentContextKey struct{}
+
+// agentFromContext returns the agent authenticated for a call
+func agentFromContext(ctx context.Context) *entity.AgentRegistry {
+	agent, _ := ctx.Value(agentContextKey{}).(*entity.AgentRegistry)
+	return agent
+}
+
+// AgentAuthInterceptor authenticates agents once per call or stream from the
+// credentials in the request metadata and attaches the agent to the context
+type AgentAuthInterceptor struct {
+	authService *service.AuthService
+}
+
+// NewAgentAuthInterceptor creates a new agent authentication interceptor
+func NewAgentAuthInterceptor(authService *service.AuthService) *AgentAuthInterceptor {
+	return &AgentAuthInterceptor{
+		authService: authService,
+	}
+}
+
+// Unary returns the interceptor for unary RPCs
+func (i *AgentAuthInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !agentMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		agent, err := i.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(context.WithValue(ctx, agentContextKey{}, agent), req)
+	}
+}
+
+// Stream returns the interceptor for streaming RPCs. The stream confirms the
+// agent in its header, so the agent learns about a rejection right away.
+// While the stream is open, the agent's standing is checked every
+// agentRecheckInterval and the stream ends once the agent lost its access.
+func (i *AgentAuthInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !agentMethods[info.FullMethod] {
+			return handler(srv, ss)
+		}
+
+		agent, err := i.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		if err := ss.SendHeader(metadata.Pairs(agentIDMetadataKey, agent.AgentID)); err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithCancelCause(ss.Context())
+		defer cancel(nil)
+		go i.recheck(ctx, cancel, agent.AgentID)
+
+		err = handler(srv, &agentStream{
+			ServerStream: ss,
+			ctx:          context.WithValue(ctx, agentContextKey{}, agent),
+		})
+		if ss.Context().Err() == nil && ctx.Err() != nil {
+			// Ended by recheck rather than by the agent
+			return context.Cause(ctx)
+		}
+		return err
+	}
+}
+
+// recheck checks the standing of the agent of a stream on every tick until
+// ctx ends, and cancels ctx with the reason once the agent lost its access
+func (i *AgentAuthInterceptor) recheck(ctx context.Context, cancel context.CancelCauseFunc, agentID string) {
+	ticker := time.NewTicker(agentRecheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := i.authService.CheckAgent(ctx, agentID); err != nil {
+				log.Printf("Ending stream of agent %s: %v", agentID, err)
+				cancel(agentAuthError(err))
+				return
+			}
+		}
+	}
+}
+
+// authenticate checks the credentials in the metadata of an incoming call
+func (i *AgentAuthInterceptor) authenticate(ctx context.Context, method string) (*entity.AgentRegistry, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var agentID, token string
+	if values := md.Get(agentIDMetadataKey); len(values) > 0 {
+		agentID = values[0]
+	}
+	if values := md.Get(authorizationMetadataKey); len(values) > 0 {
+		scheme, credentials, ok := strings.Cut(values[0], " ")
+		if ok && strings.EqualFold(scheme, "bearer") {
+			token = strings.TrimSpace(credentials)
+		}
+	}
+
+	agent, err := i.authService.AuthenticateAgent(ctx, agentID, token)
+	if err != nil {
+		log.Printf("Authentication failed for agent %s calling %s: %v", agentID, method, err)
+		return nil, agentAuthError(err)
+	}
+	return agent, nil
+}
+
+// agentAuthError maps authentication errors to gRPC status errors
+func agentAuthError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidAgentCredentials):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, service.ErrAgentAccessDenied):
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// agentStream is a server stream of an authenticated agent. Its context is
+// cancelled when the agent loses its access: handlers waiting on the context
+// end right away, and a pending receive ends with the reason once the agent's
+// next message arrives.
+type agentStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the stream context carrying the agent
+func (s *agentStream) Context() context.Context {
+	return s.ctx
+}
+
+// SendMsg sends a message unless the stream was ended
+func (s *agentStream) SendMsg(m interface{}) error {
+	if s.ctx.Err() != nil {
+		return context.Cause(s.ctx)
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+// RecvMsg receives the next message unless the stream was ended
+func (s *agentStream) RecvMsg(m interface{}) error {
+	if s.ctx.Err() != nil {
+		return context.Cause(s.ctx)
+	}
+	err := s.ServerStream.RecvMsg(m)
+	if s.ctx.Err() != nil {
+		return context.Cause(s.ctx)
+	}
+	return err
+}
@@ -0,0 +1,46 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc"
+)
+
+// fakeServerStream delivers the errors sent on recv from RecvMsg
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	recv chan error
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+
+func (s *fakeServerStream) RecvMsg(m interface{}) error { return <-s.recv }
+
+func TestAgentStreamRecvMsgReturnsCauseOnceEnded(t *testing.T) {
+	ss := &fakeServerStream{ctx: context.Background(), recv: make(chan error, 1)}
+	ctx, cancel := context.WithCancelCause(ss.ctx)
+	defer cancel(nil)
+	stream := &agentStream{ServerStream: ss, ctx: ctx}
+
+	ss.recv <- nil
+	if err := stream.RecvMsg(nil); err != nil {
+		t.Fatalf("RecvMsg() error = %v", err)
+	}
+
+	// The message that was pending when the agent lost its access
+	revoked := errors.New("agent revoked")
+	done := make(chan error, 1)
+	go func() { done <- stream.RecvMsg(nil) }()
+	cancel(revoked)
+	ss.recv <- nil
+	if err := <-done; !errors.Is(err, revoked) {
+		t.Errorf("pending RecvMsg() error = %v, want %v", err, revoked)
+	}
+
+	if err := stream.RecvMsg(nil); !errors.Is(err, revoked) {
+		t.Errorf("RecvMsg() after the stream ended error = %v, want %v", err, revoked)
+	}
+}
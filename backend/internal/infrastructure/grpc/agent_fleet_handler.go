@@ -0,0 +1,159 @@
+// Package grpc implements gRPC handlers
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/service"
+	pb "smart-monitor/pbtypes/monitor"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListAgents handles agent listing
+func (s *MonitorServiceServer) ListAgents(ctx context.Context, req *pb.ListAgentsRequest) (*pb.ListAgentsResponse, error) {
+	page := int(req.Page)
+	pageSize := int(req.PageSize)
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	filter := service.AgentFilter{
+		Status:       entity.AgentStatus(req.Status),
+		Blocked:      req.Blocked,
+		AgentVersion: req.AgentVersion,
+		Labels:       req.Labels,
+	}
+	switch filter.Status {
+	case "", entity.AgentStatusActive, entity.AgentStatusSuspended, entity.AgentStatusRevoked, entity.AgentStatusBlocked:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown agent status %q", req.Status)
+	}
+
+	agents, total, err := s.fleetService.ListAgents(ctx, filter, page, pageSize)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	pbAgents := make([]*pb.Agent, 0, len(agents))
+	for _, agent := range agents {
+		pbAgents = append(pbAgents, agentToProto(agent))
+	}
+
+	return &pb.ListAgentsResponse{
+		Agents:   pbAgents,
+		Total:    int32(total),
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	}, nil
+}
+
+// GetAgent returns one agent
+func (s *MonitorServiceServer) GetAgent(ctx context.Context, req *pb.GetAgentRequest) (*pb.GetAgentResponse, error) {
+	if req.AgentId == "" {
+		return nil, status.Error(codes.InvalidArgument, "agent ID is required")
+	}
+
+	agent, err := s.fleetService.GetAgent(ctx, req.AgentId)
+	if err != nil {
+		return nil, agentFleetError(err)
+	}
+
+	return &pb.GetAgentResponse{Agent: agentToProto(agent)}, nil
+}
+
+// RevokeAgent handles agent revocation
+func (s *MonitorServiceServer) RevokeAgent(ctx context.Context, req *pb.RevokeAgentRequest) (*pb.RevokeAgentResponse, error) {
+	log.Printf("Revoke request for agent %s", req.AgentId)
+
+	if req.AgentId == "" {
+		return nil, status.Error(codes.InvalidArgument, "agent ID is required")
+	}
+
+	if err := s.fleetService.RevokeAgent(ctx, req.AgentId); err != nil {
+		if errors.Is(err, service.ErrAgentNotFound) {
+			return nil, agentFleetError(err)
+		}
+		return &pb.RevokeAgentResponse{
+			Success: false,
+			Message: fmt.Sprintf("Revoke failed: %v", err),
+			AgentId: req.AgentId,
+		}, nil
+	}
+
+	return &pb.RevokeAgentResponse{
+		Success:   true,
+		Message:   "Agent revoked successfully",
+		AgentId:   req.AgentId,
+		Timestamp: time.Now().Unix(),
+	}, nil
+}
+
+// DeleteAgent handles agent deletion
+func (s *MonitorServiceServer) DeleteAgent(ctx context.Context, req *pb.DeleteAgentRequest) (*pb.DeleteAgentResponse, error) {
+	log.Printf("Delete request for agent %s", req.AgentId)
+
+	if req.AgentId == "" {
+		return nil, status.Error(codes.InvalidArgument, "agent ID is required")
+	}
+
+	result, err := s.fleetService.DeleteAgent(ctx, req.AgentId)
+	if err != nil {
+		if errors.Is(err, service.ErrAgentNotFound) {
+			return nil, agentFleetError(err)
+		}
+		return &pb.DeleteAgentResponse{
+			Success: false,
+			Message: fmt.Sprintf("Delete failed: %v", err),
+			AgentId: req.AgentId,
+		}, nil
+	}
+
+	log.Printf("✓ Deleted agent %s: %d policies, %d config profiles, %d hosts", req.AgentId, result.PoliciesUnapplied, result.ConfigProfilesDetached, result.HostsRemoved)
+
+	return &pb.DeleteAgentResponse{
+		Success:                true,
+		Message:                "Agent deleted successfully",
+		AgentId:                req.AgentId,
+		PoliciesUnapplied:      int32(result.PoliciesUnapplied),
+		ConfigProfilesDetached: int32(result.ConfigProfilesDetached),
+		HostsRemoved:           int32(result.HostsRemoved),
+		Timestamp:              time.Now().Unix(),
+	}, nil
+}
+
+// agentFleetError maps agent lookup errors to gRPC statuses
+func agentFleetError(err error) error {
+	if errors.Is(err, service.ErrAgentNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// agentToProto converts a domain agent into its protobuf form, without credentials
+func agentToProto(agent *entity.AgentRegistry) *pb.Agent {
+	return &pb.Agent{
+		AgentId:           agent.AgentID,
+		Hostname:          agent.Hostname,
+		IpAddress:         agent.IPAddress,
+		AgentVersion:      agent.AgentVersion,
+		Status:            string(agent.Status),
+		Blocked:           agent.Blocked,
+		BlockReason:       agent.BlockReason,
+		Metadata:          agent.Metadata,
+		Fingerprint:       agent.Fingerprint,
+		EnrollmentTokenId: agent.EnrollmentTokenID,
+		RegisteredAt:      agent.RegisteredAt.Unix(),
+		LastAuthAt:        agent.LastAuthAt.Unix(),
+		TokenExpiresAt:    agent.TokenExpiry.Unix(),
+	}
+}
@@ -0,0 +1,229 @@
+// Package grpc implements gRPC handlers
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/service"
+	pb "smart-monitor/pbtypes/monitor"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AddConfigProfile handles config profile creation
+func (s *MonitorServiceServer) AddConfigProfile(ctx context.Context, req *pb.ConfigProfileRequest) (*pb.ConfigProfileResponse, error) {
+	log.Printf("Add config profile request: name=%s", req.Name)
+
+	var settings entity.AgentSettings
+	if req.Settings != nil {
+		settings = agentSettingsFromProto(req.Settings)
+	}
+
+	profile, err := s.configService.CreateProfile(ctx, req.Name, req.Description, settings, req.Selector)
+	if err != nil {
+		return configProfileError("create", err)
+	}
+
+	return &pb.ConfigProfileResponse{
+		Success:   true,
+		Message:   "Config profile created successfully",
+		Profile:   configProfileToProto(profile),
+		Timestamp: time.Now().Unix(),
+	}, nil
+}
+
+// UpdateConfigProfile handles config profile updates
+func (s *MonitorServiceServer) UpdateConfigProfile(ctx context.Context, req *pb.ConfigProfileRequest) (*pb.ConfigProfileResponse, error) {
+	log.Printf("Update config profile request: profile_id=%s", req.ProfileId)
+
+	if req.ProfileId == "" {
+		return nil, status.Error(codes.InvalidArgument, "profile ID is required")
+	}
+
+	var settings *entity.AgentSettings
+	if req.Settings != nil {
+		updated := agentSettingsFromProto(req.Settings)
+		settings = &updated
+	}
+
+	profile, err := s.configService.UpdateProfile(ctx, req.ProfileId, req.Name, req.Description, settings, req.Selector, req.ExpectedVersion)
+	if err != nil {
+		return configProfileError("update", err)
+	}
+
+	return &pb.ConfigProfileResponse{
+		Success:   true,
+		Message:   fmt.Sprintf("Config profile updated to version %d", profile.Version),
+		Profile:   configProfileToProto(profile),
+		Timestamp: time.Now().Unix(),
+	}, nil
+}
+
+// RemoveConfigProfile handles config profile deletion
+func (s *MonitorServiceServer) RemoveConfigProfile(ctx context.Context, req *pb.RemoveConfigProfileRequest) (*pb.ConfigProfileResponse, error) {
+	log.Printf("Remove config profile request: profile_id=%s", req.ProfileId)
+
+	if req.ProfileId == "" {
+		return nil, status.Error(codes.InvalidArgument, "profile ID is required")
+	}
+
+	if err := s.configService.RemoveProfile(ctx, req.ProfileId); err != nil {
+		return configProfileError("remove", err)
+	}
+
+	return &pb.ConfigProfileResponse{
+		Success:   true,
+		Message:   "Config profile removed successfully",
+		Timestamp: time.Now().Unix(),
+	}, nil
+}
+
+// ListConfigProfiles handles config profile listing
+func (s *MonitorServiceServer) ListConfigProfiles(ctx context.Context, req *pb.ListConfigProfilesRequest) (*pb.ListConfigProfilesResponse, error) {
+	profiles, err := s.configService.ListProfiles(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	pbProfiles := make([]*pb.ConfigProfile, 0, len(profiles))
+	for _, profile := range profiles {
+		pbProfiles = append(pbProfiles, configProfileToProto(profile))
+	}
+
+	return &pb.ListConfigProfilesResponse{
+		Profiles: pbProfiles,
+		Total:    int32(len(pbProfiles)),
+	}, nil
+}
+
+// AttachConfigProfile attaches a config profile to an agent
+func (s *MonitorServiceServer) AttachConfigProfile(ctx context.Context, req *pb.AttachConfigProfileRequest) (*pb.ConfigProfileResponse, error) {
+	log.Printf("Attach config profile request: agent_id=%s, profile_id=%s", req.AgentId, req.ProfileId)
+
+	if req.AgentId == "" || req.ProfileId == "" {
+		return nil, status.Error(codes.InvalidArgument, "agent ID and profile ID are required")
+	}
+
+	if err := s.configService.AttachProfile(ctx, req.ProfileId, req.AgentId); err != nil {
+		return configProfileError("attach", err)
+	}
+
+	return &pb.ConfigProfileResponse{
+		Success:   true,
+		Message:   "Config profile attached successfully",
+		Timestamp: time.Now().Unix(),
+	}, nil
+}
+
+// DetachConfigProfile detaches a config profile from an agent
+func (s *MonitorServiceServer) DetachConfigProfile(ctx context.Context, req *pb.DetachConfigProfileRequest) (*pb.ConfigProfileResponse, error) {
+	log.Printf("Detach config profile request: agent_id=%s, profile_id=%s", req.AgentId, req.ProfileId)
+
+	if req.AgentId == "" || req.ProfileId == "" {
+		return nil, status.Error(codes.InvalidArgument, "agent ID and profile ID are required")
+	}
+
+	if err := s.configService.DetachProfile(ctx, req.ProfileId, req.AgentId); err != nil {
+		return configProfileError("detach", err)
+	}
+
+	return &pb.ConfigProfileResponse{
+		Success:   true,
+		Message:   "Config profile detached successfully",
+		Timestamp: time.Now().Unix(),
+	}, nil
+}
+
+// GetAgentConfig returns the config profile that applies to the calling agent,
+// authenticated by AgentAuthInterceptor
+func (s *MonitorServiceServer) GetAgentConfig(ctx context.Context, req *pb.AgentConfigRequest) (*pb.AgentConfigResponse, error) {
+	profile, err := s.configService.ResolveProfile(ctx, agentFromContext(ctx).AgentID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if profile == nil {
+		return &pb.AgentConfigResponse{Managed: false}, nil
+	}
+
+	return &pb.AgentConfigResponse{
+		Managed:   true,
+		ProfileId: profile.ProfileID,
+		Version:   profile.Version,
+		Revision:  profile.Revision(),
+		Settings:  agentSettingsToProto(profile.Settings),
+	}, nil
+}
+
+// configProfileError maps config service errors to gRPC statuses
+func configProfileError(op string, err error) (*pb.ConfigProfileResponse, error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidConfigProfile):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrConfigProfileNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrConfigVersionConflict):
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+
+	return &pb.ConfigProfileResponse{
+		Success: false,
+		Message: fmt.Sprintf("Failed to %s config profile: %v", op, err),
+	}, nil
+}
+
+// agentSettingsFromProto converts protobuf agent settings into domain settings
+func agentSettingsFromProto(settings *pb.AgentSettings) entity.AgentSettings {
+	result := entity.AgentSettings{
+		MetricsInterval: time.Duration(settings.MetricsIntervalSeconds) * time.Second,
+		Metadata:        settings.Metadata,
+	}
+	if len(settings.Collectors) > 0 {
+		result.Collectors = make(map[string]entity.CollectorSettings, len(settings.Collectors))
+		for name, c := range settings.Collectors {
+			result.Collectors[name] = entity.CollectorSettings{
+				Enabled:  c.GetEnabled(),
+				Interval: time.Duration(c.GetIntervalSeconds()) * time.Second,
+			}
+		}
+	}
+	return result
+}
+
+// agentSettingsToProto converts domain agent settings into protobuf settings
+func agentSettingsToProto(settings entity.AgentSettings) *pb.AgentSettings {
+	result := &pb.AgentSettings{
+		MetricsIntervalSeconds: int64(settings.MetricsInterval / time.Second),
+		Metadata:               settings.Metadata,
+	}
+	if len(settings.Collectors) > 0 {
+		result.Collectors = make(map[string]*pb.CollectorSettings, len(settings.Collectors))
+		for name, c := range settings.Collectors {
+			result.Collectors[name] = &pb.CollectorSettings{
+				Enabled:         c.Enabled,
+				IntervalSeconds: int64(c.Interval / time.Second),
+			}
+		}
+	}
+	return result
+}
+
+// configProfileToProto converts a domain profile into its protobuf form
+func configProfileToProto(profile *entity.AgentConfigProfile) *pb.ConfigProfile {
+	return &pb.ConfigProfile{
+		ProfileId:   profile.ProfileID,
+		Name:        profile.Name,
+		Description: profile.Description,
+		Version:     profile.Version,
+		Settings:    agentSettingsToProto(profile.Settings),
+		AgentIds:    profile.AgentIDs,
+		Selector:    profile.Selector,
+		CreatedAt:   profile.CreatedAt.Unix(),
+		UpdatedAt:   profile.UpdatedAt.Unix(),
+	}
+}
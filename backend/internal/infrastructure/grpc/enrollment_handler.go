@@ -0,0 +1,110 @@
+// Package grpc implements gRPC handlers
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/service"
+	pb "smart-monitor/pbtypes/monitor"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreateEnrollmentToken handles enrollment token creation
+func (s *MonitorServiceServer) CreateEnrollmentToken(ctx context.Context, req *pb.CreateEnrollmentTokenRequest) (*pb.CreateEnrollmentTokenResponse, error) {
+	log.Printf("Create enrollment token request: description=%s", req.Description)
+
+	token, secret, err := s.enrollService.CreateToken(ctx, req.Description, req.HostnamePattern, req.Labels, req.PolicyIds,
+		int(req.MaxUses), time.Duration(req.TtlSeconds)*time.Second)
+	if errors.Is(err, service.ErrInvalidEnrollmentToken) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return &pb.CreateEnrollmentTokenResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to create enrollment token: %v", err),
+		}, nil
+	}
+
+	log.Printf("✓ Enrollment token %s created", token.TokenID)
+
+	return &pb.CreateEnrollmentTokenResponse{
+		Success:         true,
+		Message:         "Enrollment token created successfully; it is shown only once",
+		Token:           secret,
+		EnrollmentToken: enrollmentTokenToProto(token),
+		Timestamp:       time.Now().Unix(),
+	}, nil
+}
+
+// ListEnrollmentTokens handles enrollment token listing
+func (s *MonitorServiceServer) ListEnrollmentTokens(ctx context.Context, req *pb.ListEnrollmentTokensRequest) (*pb.ListEnrollmentTokensResponse, error) {
+	tokens, err := s.enrollService.ListTokens(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	pbTokens := make([]*pb.EnrollmentToken, 0, len(tokens))
+	for _, token := range tokens {
+		pbTokens = append(pbTokens, enrollmentTokenToProto(token))
+	}
+
+	return &pb.ListEnrollmentTokensResponse{
+		Tokens: pbTokens,
+		Total:  int32(len(pbTokens)),
+	}, nil
+}
+
+// RevokeEnrollmentToken handles enrollment token revocation
+func (s *MonitorServiceServer) RevokeEnrollmentToken(ctx context.Context, req *pb.RevokeEnrollmentTokenRequest) (*pb.RevokeEnrollmentTokenResponse, error) {
+	log.Printf("Revoke enrollment token request: token_id=%s", req.TokenId)
+
+	if req.TokenId == "" {
+		return nil, status.Error(codes.InvalidArgument, "token ID is required")
+	}
+
+	err := s.enrollService.RevokeToken(ctx, req.TokenId)
+	if errors.Is(err, service.ErrEnrollmentTokenNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return &pb.RevokeEnrollmentTokenResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to revoke enrollment token: %v", err),
+		}, nil
+	}
+
+	return &pb.RevokeEnrollmentTokenResponse{
+		Success:   true,
+		Message:   "Enrollment token revoked successfully",
+		Timestamp: time.Now().Unix(),
+	}, nil
+}
+
+// enrollmentTokenToProto converts a domain enrollment token into its protobuf form
+func enrollmentTokenToProto(token *entity.EnrollmentToken) *pb.EnrollmentToken {
+	result := &pb.EnrollmentToken{
+		TokenId:         token.TokenID,
+		Description:     token.Description,
+		HostnamePattern: token.HostnamePattern,
+		Labels:          token.Labels,
+		PolicyIds:       token.PolicyIDs,
+		MaxUses:         int32(token.MaxUses),
+		Uses:            int32(token.Uses),
+		Revoked:         token.Revoked,
+		CreatedAt:       token.CreatedAt.Unix(),
+	}
+	if !token.ExpiresAt.IsZero() {
+		result.ExpiresAt = token.ExpiresAt.Unix()
+	}
+	if !token.LastUsedAt.IsZero() {
+		result.LastUsedAt = token.LastUsedAt.Unix()
+	}
+	return result
+}
@@ -3,6 +3,7 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -12,7 +13,12 @@ import (
 	"smart-monitor/backend/internal/application/usecase"
 	"smart-monitor/backend/internal/domain/entity"
 	"smart-monitor/backend/internal/domain/service"
+	diskpb "smart-monitor/pbtypes/disk"
 	pb "smart-monitor/pbtypes/monitor"
+	netpb "smart-monitor/pbtypes/network"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // MonitorServiceServer implements the gRPC MonitorService
@@ -22,6 +28,10 @@ type MonitorServiceServer struct {
 	authService    *service.AuthService
 	controlService *service.AgentControlService
 	policyService  *service.PolicyService
+	configService  *service.AgentConfigService
+	mergeService   *service.AgentMergeService
+	enrollService  *service.EnrollmentService
+	fleetService   *service.AgentFleetService
 }
 
 // NewMonitorServiceServer creates a new gRPC server
@@ -30,12 +40,20 @@ func NewMonitorServiceServer(
 	authService *service.AuthService,
 	controlService *service.AgentControlService,
 	policyService *service.PolicyService,
+	configService *service.AgentConfigService,
+	mergeService *service.AgentMergeService,
+	enrollService *service.EnrollmentService,
+	fleetService *service.AgentFleetService,
 ) *MonitorServiceServer {
 	return &MonitorServiceServer{
 		monitorUseCase: monitorUseCase,
 		authService:    authService,
 		controlService: controlService,
 		policyService:  policyService,
+		configService:  configService,
+		mergeService:   mergeService,
+		enrollService:  enrollService,
+		fleetService:   fleetService,
 	}
 }
 
@@ -52,7 +70,11 @@ func (s *MonitorServiceServer) RegisterAgent(ctx context.Context, req *pb.Regist
 	}
 
 	// Register agent through auth service
-	agent, err := s.authService.RegisterAgent(ctx, req.Hostname, req.IpAddress, req.AgentVersion, req.Metadata)
+	agent, err := s.authService.RegisterAgent(ctx, req.Hostname, req.IpAddress, req.AgentVersion, req.Fingerprint, req.EnrollmentToken, req.PreviousAccessToken, req.Metadata)
+	if errors.Is(err, service.ErrEnrollmentDenied) {
+		log.Printf("Registration of %s denied: %v", req.Hostname, err)
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
 	if err != nil {
 		log.Printf("Failed to register agent: %v", err)
 		return &pb.RegisterResponse{
@@ -72,8 +94,25 @@ func (s *MonitorServiceServer) RegisterAgent(ctx context.Context, req *pb.Regist
 	}, nil
 }
 
-// StreamStats handles bidirectional streaming from agents
+// RenewToken issues a new access token to an authenticated agent
+func (s *MonitorServiceServer) RenewToken(ctx context.Context, req *pb.RenewTokenRequest) (*pb.RenewTokenResponse, error) {
+	agent, err := s.authService.RenewToken(ctx, req.AgentId, req.AccessToken)
+	if err != nil {
+		log.Printf("Token renewal failed for agent %s: %v", req.AgentId, err)
+		return nil, agentAuthError(err)
+	}
+
+	log.Printf("✓ Token renewed for agent %s (expires %s)", agent.AgentID, agent.TokenExpiry.Format(time.RFC3339))
+
+	return &pb.RenewTokenResponse{
+		AccessToken: agent.AccessToken,
+		ExpiresAt:   agent.TokenExpiry.Unix(),
+	}, nil
+}
+
+// StreamStats receives the samples of an agent authenticated by AgentAuthInterceptor
 func (s *MonitorServiceServer) StreamStats(stream pb.MonitorService_StreamStatsServer) error {
+	agent := agentFromContext(stream.Context())
 	for {
 		req, err := stream.Recv()
 		if err == io.EOF {
@@ -84,70 +123,340 @@ func (s *MonitorServiceServer) StreamStats(stream pb.MonitorService_StreamStatsS
 			})
 		}
 		if err != nil {
-			log.Printf("Error receiving stats: %v", err)
-			return fmt.Errorf("failed to receive stats: %w", err)
+			log.Printf("Error receiving stats from agent %s: %v", agent.AgentID, err)
+			return err
 		}
 
-		// Authenticate agent
-		if req.AccessToken == "" {
-			log.Printf("Missing access token from agent %s", req.AgentId)
+		// Samples belong to the agent the stream was authenticated for
+		req.AgentId = agent.AgentID
+
+		// A batch carries samples collected while the agent could not reach us
+		if len(req.Samples) > 0 {
+			stored, err := s.monitorUseCase.RecordStatsBatch(stream.Context(), statsRequestsFromProto(req))
+			if err != nil {
+				log.Printf("Failed to record %d of %d samples from agent %s: %v", len(req.Samples)-stored, len(req.Samples), req.AgentId, err)
+			}
+			// The agent keeps the batch and sends it again unless every sample
+			// was stored or can never be
+			if err != nil && stored < len(req.Samples) && !errors.Is(err, service.ErrInvalidStats) {
+				return status.Errorf(codes.Unavailable, "failed to store samples: %v", err)
+			}
+			if stored > 0 {
+				log.Printf("[Agent:%s | Host:%s | IP:%s] Recorded batch of %d samples", req.AgentId, req.Hostname, req.IpAddress, stored)
+			}
 			continue
 		}
 
-		if err := s.authService.ValidateToken(context.Background(), req.AgentId, req.AccessToken); err != nil {
-			log.Printf("Authentication failed for agent %s: %v", req.AgentId, err)
+		// Process through use case
+		if err := s.monitorUseCase.RecordStats(stream.Context(), statsRequestsFromProto(req)[0]); err != nil {
+			log.Printf("Failed to record stats from agent %s: %v", req.AgentId, err)
 			continue
 		}
 
-		// Convert protobuf to DTO
+		// Log received stats with agent info
+		log.Printf("[Agent:%s | Host:%s | IP:%s] CPU: %.2f%% | RAM: %.2f%% | Disk: %.2f%%",
+			req.AgentId, req.Hostname, req.IpAddress, req.Cpu, req.Ram, req.Disk)
+		for _, e := range req.CollectorErrors {
+			log.Printf("⚠ [Agent:%s] Collector %s failed: %s", req.AgentId, e.Collector, e.Message)
+		}
+	}
+}
+
+// statsRequestsFromProto converts a stats message to DTOs, one per sample of a batch
+func statsRequestsFromProto(req *pb.StatsRequest) []*dto.StatsRequest {
+	newRequest := func(cpu, ram, disk float64, collectedAt int64, extended *pb.ExtendedMetrics, errs []*pb.CollectorError) *dto.StatsRequest {
 		statsReq := &dto.StatsRequest{
 			Hostname:     req.Hostname,
 			AgentID:      req.AgentId,
 			IPAddress:    req.IpAddress,
 			AgentVersion: req.AgentVersion,
-			CPU:          req.Cpu,
-			RAM:          req.Ram,
-			Disk:         req.Disk,
+			CPU:          cpu,
+			RAM:          ram,
+			Disk:         disk,
 			Metadata:     req.Metadata,
-		}
+			Extended:     extendedMetricsFromProto(extended),
 
-		// Process through use case
-		if err := s.monitorUseCase.RecordStats(context.Background(), statsReq); err != nil {
-			log.Printf("Failed to record stats from agent %s: %v", req.AgentId, err)
-			continue
+			ReportInterval: time.Duration(req.ReportIntervalMs) * time.Millisecond,
+		}
+		for _, e := range errs {
+			statsReq.Errors = append(statsReq.Errors, entity.CollectorError{Collector: e.Collector, Message: e.Message})
 		}
+		if collectedAt > 0 {
+			statsReq.CollectedAt = time.UnixMilli(collectedAt)
+		}
+		return statsReq
+	}
 
-		// Log received stats with agent info
-		log.Printf("[Agent:%s | Host:%s | IP:%s] CPU: %.2f%% | RAM: %.2f%% | Disk: %.2f%%",
-			req.AgentId, req.Hostname, req.IpAddress, req.Cpu, req.Ram, req.Disk)
+	if len(req.Samples) == 0 {
+		return []*dto.StatsRequest{newRequest(req.Cpu, req.Ram, req.Disk, req.CollectedAt, req.Extended, req.CollectorErrors)}
+	}
+
+	reqs := make([]*dto.StatsRequest, 0, len(req.Samples))
+	for _, sample := range req.Samples {
+		reqs = append(reqs, newRequest(sample.Cpu, sample.Ram, sample.Disk, sample.CollectedAt, sample.Extended, sample.CollectorErrors))
+	}
+	return reqs
+}
+
+// extendedMetricsFromProto converts extended metrics, which older agents do not send
+func extendedMetricsFromProto(m *pb.ExtendedMetrics) entity.ExtendedMetrics {
+	if m == nil {
+		return entity.ExtendedMetrics{}
+	}
+	extended := entity.ExtendedMetrics{
+		Load1:        m.Load_1,
+		Load5:        m.Load_5,
+		Load15:       m.Load_15,
+		CPUCores:     int(m.CpuCores),
+		RAMTotal:     m.RamTotal,
+		RAMUsed:      m.RamUsed,
+		DiskTotal:    m.DiskTotal,
+		DiskUsed:     m.DiskUsed,
+		Uptime:       m.Uptime,
+		ProcessCount: m.ProcessCount,
+		NetworkSent:  m.NetworkSent,
+		NetworkRecv:  m.NetworkRecv,
+		Custom:       m.Custom,
+
+		NetworkSentRate:   m.NetworkSentRate,
+		NetworkRecvRate:   m.NetworkRecvRate,
+		NetworkErrorsRate: m.NetworkErrorsRate,
+		NetworkDropsRate:  m.NetworkDropsRate,
+	}
+
+	for _, core := range m.Cores {
+		extended.Cores = append(extended.Cores, entity.CPUCoreUsage{
+			Core:    core.Core,
+			Percent: core.Percent,
+		})
 	}
+	for _, p := range m.Partitions {
+		extended.Partitions = append(extended.Partitions, entity.DiskPartition{
+			MountPoint:        p.MountPoint,
+			Device:            p.Device,
+			FSType:            p.Fstype,
+			Total:             uint64(p.Total),
+			Used:              uint64(p.Used),
+			Free:              uint64(p.Free),
+			UsedPercent:       p.UsedPercent,
+			InodesTotal:       p.InodesTotal,
+			InodesUsed:        p.InodesUsed,
+			InodesFree:        p.InodesFree,
+			InodesUsedPercent: p.InodesUsedPercent,
+		})
+	}
+	for _, nic := range m.Interfaces {
+		extended.Interfaces = append(extended.Interfaces, entity.NetworkInterface{
+			Name:        nic.Name,
+			IP:          nic.Ip,
+			BytesSent:   uint64(nic.TxBytes),
+			BytesRecv:   uint64(nic.RxBytes),
+			PacketsSent: nic.TxPackets,
+			PacketsRecv: nic.RxPackets,
+			ErrorsIn:    nic.RxErrors,
+			ErrorsOut:   nic.TxErrors,
+			DropsIn:     nic.RxDropped,
+			DropsOut:    nic.TxDropped,
+
+			BytesSentRate:   nic.TxBytesPerSec,
+			BytesRecvRate:   nic.RxBytesPerSec,
+			PacketsSentRate: nic.TxPacketsPerSec,
+			PacketsRecvRate: nic.RxPacketsPerSec,
+			ErrorsInRate:    nic.RxErrorsPerSec,
+			ErrorsOutRate:   nic.TxErrorsPerSec,
+			DropsInRate:     nic.RxDroppedPerSec,
+			DropsOutRate:    nic.TxDroppedPerSec,
+		})
+	}
+
+	return extended
 }
 
-// GetStats returns stats for a specific hostname
-func (s *MonitorServiceServer) GetStats(ctx context.Context, req *pb.StatsRequest) (*pb.StatsResponse, error) {
-	hostname := req.Hostname
-	if hostname == "" {
-		return nil, fmt.Errorf("hostname is required")
+// extendedMetricsToProto converts extended metrics to protobuf
+func extendedMetricsToProto(m entity.ExtendedMetrics) *pb.ExtendedMetrics {
+	extended := &pb.ExtendedMetrics{
+		Load_1:       m.Load1,
+		Load_5:       m.Load5,
+		Load_15:      m.Load15,
+		CpuCores:     int32(m.CPUCores),
+		RamTotal:     m.RAMTotal,
+		RamUsed:      m.RAMUsed,
+		DiskTotal:    m.DiskTotal,
+		DiskUsed:     m.DiskUsed,
+		Uptime:       m.Uptime,
+		ProcessCount: m.ProcessCount,
+		NetworkSent:  m.NetworkSent,
+		NetworkRecv:  m.NetworkRecv,
+		Custom:       m.Custom,
+
+		NetworkSentRate:   m.NetworkSentRate,
+		NetworkRecvRate:   m.NetworkRecvRate,
+		NetworkErrorsRate: m.NetworkErrorsRate,
+		NetworkDropsRate:  m.NetworkDropsRate,
+	}
+
+	for _, core := range m.Cores {
+		extended.Cores = append(extended.Cores, &pb.CPUCoreUsage{
+			Core:    core.Core,
+			Percent: core.Percent,
+		})
+	}
+	for _, p := range m.Partitions {
+		extended.Partitions = append(extended.Partitions, &diskpb.DiskPartition{
+			MountPoint:        p.MountPoint,
+			Device:            p.Device,
+			Fstype:            p.FSType,
+			Total:             float64(p.Total),
+			Used:              float64(p.Used),
+			Free:              float64(p.Free),
+			UsedPercent:       p.UsedPercent,
+			InodesTotal:       p.InodesTotal,
+			InodesUsed:        p.InodesUsed,
+			InodesFree:        p.InodesFree,
+			InodesUsedPercent: p.InodesUsedPercent,
+		})
+	}
+	for _, nic := range m.Interfaces {
+		extended.Interfaces = append(extended.Interfaces, &netpb.NetworkInterface{
+			Name:      nic.Name,
+			Ip:        nic.IP,
+			TxBytes:   float64(nic.BytesSent),
+			RxBytes:   float64(nic.BytesRecv),
+			TxPackets: nic.PacketsSent,
+			RxPackets: nic.PacketsRecv,
+			RxErrors:  nic.ErrorsIn,
+			TxErrors:  nic.ErrorsOut,
+			RxDropped: nic.DropsIn,
+			TxDropped: nic.DropsOut,
+
+			TxBytesPerSec:   nic.BytesSentRate,
+			RxBytesPerSec:   nic.BytesRecvRate,
+			TxPacketsPerSec: nic.PacketsSentRate,
+			RxPacketsPerSec: nic.PacketsRecvRate,
+			RxErrorsPerSec:  nic.ErrorsInRate,
+			TxErrorsPerSec:  nic.ErrorsOutRate,
+			RxDroppedPerSec: nic.DropsInRate,
+			TxDroppedPerSec: nic.DropsOutRate,
+		})
 	}
 
-	log.Printf("GetStats called for hostname: %s", hostname)
+	return extended
+}
+
+// collectorErrorsToProto converts collector errors to protobuf
+func collectorErrorsToProto(errs []entity.CollectorError) []*pb.CollectorError {
+	result := make([]*pb.CollectorError, 0, len(errs))
+	for _, e := range errs {
+		result = append(result, &pb.CollectorError{Collector: e.Collector, Message: e.Message})
+	}
+	return result
+}
 
-	// Get stats through use case
-	stats, err := s.monitorUseCase.GetStats(ctx, hostname)
+// GetStats returns the latest stats of a host, looked up by hostname or agent ID
+func (s *MonitorServiceServer) GetStats(ctx context.Context, req *pb.GetStatsRequest) (*pb.GetStatsResponse, error) {
+	var stats *dto.StatsResponse
+	var err error
+	switch {
+	case req.Hostname != "" && req.AgentId != "":
+		return nil, status.Error(codes.InvalidArgument, "either hostname or agent ID is required, not both")
+	case req.Hostname != "":
+		stats, err = s.monitorUseCase.GetStats(ctx, req.Hostname)
+	case req.AgentId != "":
+		stats, err = s.monitorUseCase.GetStatsByAgent(ctx, req.AgentId)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "hostname or agent ID is required")
+	}
 	if err != nil {
-		return &pb.StatsResponse{
-			Message:   fmt.Sprintf("No stats available for hostname: %s", hostname),
-			Timestamp: time.Now().Unix(),
-		}, nil
+		if errors.Is(err, service.ErrStatsNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	message := fmt.Sprintf("Stats for %s: CPU=%.2f%%, RAM=%.2f%%, Disk=%.2f%% (Last received: %s)",
-		hostname, stats.CPU, stats.RAM, stats.Disk, stats.LastReceived.Format(time.RFC3339))
+	return &pb.GetStatsResponse{Stats: hostStatsToProto(stats)}, nil
+}
 
-	return &pb.StatsResponse{
-		Message:   message,
-		Timestamp: time.Now().Unix(),
-	}, nil
+// QueryStatsRange returns the downsampled metrics of a host or agent over a time range
+func (s *MonitorServiceServer) QueryStatsRange(ctx context.Context, req *pb.StatsRangeRequest) (*pb.StatsRangeResponse, error) {
+	query := &entity.StatsRangeQuery{
+		Hostname:    req.Hostname,
+		AgentID:     req.AgentId,
+		Metrics:     req.Metrics,
+		Step:        time.Duration(req.StepSeconds) * time.Second,
+		Aggregation: entity.Aggregation(req.Aggregation),
+	}
+	if req.From != 0 {
+		query.From = time.UnixMilli(req.From)
+	}
+	if req.To != 0 {
+		query.To = time.UnixMilli(req.To)
+	}
+
+	series, err := s.monitorUseCase.QueryStatsRange(ctx, query)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidStatsQuery) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &pb.StatsRangeResponse{
+		Series:      make([]*pb.StatsSeries, 0, len(series)),
+		StepSeconds: int64(query.Step / time.Second),
+		Aggregation: string(query.Aggregation),
+	}
+	for _, metric := range series {
+		points := make([]*pb.StatsPoint, 0, len(metric.Points))
+		for _, point := range metric.Points {
+			points = append(points, &pb.StatsPoint{
+				Timestamp: point.Timestamp.UnixMilli(),
+				Value:     point.Value,
+			})
+		}
+		resp.Series = append(resp.Series, &pb.StatsSeries{Metric: metric.Metric, Points: points})
+	}
+
+	return resp, nil
+}
+
+// WatchStats streams the samples matching the request as agents report them
+func (s *MonitorServiceServer) WatchStats(req *pb.WatchStatsRequest, stream pb.MonitorService_WatchStatsServer) error {
+	sub := s.monitorUseCase.WatchStats(service.StatsFilter{
+		AgentIDs: req.AgentIds,
+		Labels:   req.Labels,
+	})
+	defer sub.Close()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			if dropped := sub.Dropped(); dropped > 0 {
+				log.Printf("Stats watcher fell behind, %d samples dropped", dropped)
+			}
+			return nil
+		case stats := <-sub.Updates():
+			if err := stream.Send(hostStatsToProto(dto.NewStatsResponse(stats))); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// hostStatsToProto converts a stats response to protobuf
+func hostStatsToProto(stats *dto.StatsResponse) *pb.HostStats {
+	return &pb.HostStats{
+		Hostname:        stats.Hostname,
+		AgentId:         stats.AgentID,
+		IpAddress:       stats.IPAddress,
+		Cpu:             stats.CPU,
+		Ram:             stats.RAM,
+		Disk:            stats.Disk,
+		CollectedAt:     stats.Timestamp.UnixMilli(),
+		LastReceived:    stats.LastReceived.UnixMilli(),
+		Metadata:        stats.Metadata,
+		Extended:        extendedMetricsToProto(stats.Extended),
+		CollectorErrors: collectorErrorsToProto(stats.Errors),
+	}
 }
 
 // ControlAgent handles agent control operations
@@ -177,8 +486,9 @@ func (s *MonitorServiceServer) ControlAgent(ctx context.Context, req *pb.Control
 		}, nil
 	}
 
-	// Execute control action
-	if err := s.controlService.ControlAgent(req.AgentId, action, req.Reason); err != nil {
+	// Queue control command
+	cmd, err := s.controlService.ControlAgent(ctx, req.AgentId, action, req.Reason)
+	if err != nil {
 		log.Printf("Control action failed: %v", err)
 		return &pb.ControlAgentResponse{
 			Success: false,
@@ -188,10 +498,114 @@ func (s *MonitorServiceServer) ControlAgent(ctx context.Context, req *pb.Control
 
 	return &pb.ControlAgentResponse{
 		Success:   true,
-		Message:   fmt.Sprintf("Control action '%s' sent successfully", req.Action),
+		Message:   fmt.Sprintf("Control action '%s' queued for delivery", req.Action),
 		AgentId:   req.AgentId,
 		Action:    req.Action,
 		Timestamp: time.Now().Unix(),
+		CommandId: cmd.CommandID,
+		Status:    commandStatusToProto(cmd.Status),
+	}, nil
+}
+
+// CommandStream keeps the command channel of an agent authenticated by
+// AgentAuthInterceptor open. Messages from the agent carry acknowledgements.
+func (s *MonitorServiceServer) CommandStream(stream pb.MonitorService_CommandStreamServer) error {
+	ctx := stream.Context()
+	agentID := agentFromContext(ctx).AgentID
+
+	commands, disconnect, err := s.controlService.ConnectAgent(ctx, agentID)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer disconnect()
+
+	log.Printf("✓ Command channel opened for agent %s", agentID)
+	defer log.Printf("Command channel closed for agent %s", agentID)
+
+	// Receive acknowledgements
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			if req.Ack == nil {
+				continue
+			}
+			if err := s.controlService.AcknowledgeCommand(ctx, agentID, req.Ack.CommandId, commandStatusFromProto(req.Ack.Status), req.Ack.Message); err != nil {
+				log.Printf("Invalid acknowledgement from agent %s: %v", agentID, err)
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// The agent lost its access, or went away
+			return context.Cause(ctx)
+
+		case err := <-recvErr:
+			if err == io.EOF {
+				return nil
+			}
+			return err
+
+		case cmd, ok := <-commands:
+			if !ok {
+				// The agent opened a newer channel, or was revoked or deleted
+				return status.Error(codes.Aborted, "command session ended")
+			}
+			if err := stream.Send(&pb.AgentCommand{
+				CommandId: cmd.CommandID,
+				AgentId:   cmd.AgentID,
+				Action:    string(cmd.Action),
+				Reason:    cmd.Reason,
+				IssuedAt:  cmd.CreatedAt.Unix(),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ListAgentCommands returns the commands sent to an agent
+func (s *MonitorServiceServer) ListAgentCommands(ctx context.Context, req *pb.ListAgentCommandsRequest) (*pb.ListAgentCommandsResponse, error) {
+	if req.AgentId == "" {
+		return nil, status.Error(codes.InvalidArgument, "agent ID is required")
+	}
+
+	commands, err := s.controlService.ListCommands(ctx, req.AgentId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = 50
+	}
+
+	pbCommands := make([]*pb.AgentCommandStatus, 0, limit)
+	for i, cmd := range commands {
+		if i == limit {
+			break
+		}
+		pbCommands = append(pbCommands, &pb.AgentCommandStatus{
+			CommandId: cmd.CommandID,
+			AgentId:   cmd.AgentID,
+			Action:    string(cmd.Action),
+			Reason:    cmd.Reason,
+			Status:    commandStatusToProto(cmd.Status),
+			Message:   cmd.Message,
+			CreatedAt: cmd.CreatedAt.Unix(),
+			UpdatedAt: cmd.UpdatedAt.Unix(),
+		})
+	}
+
+	return &pb.ListAgentCommandsResponse{
+		Commands: pbCommands,
+		Total:    int32(len(commands)),
 	}, nil
 }
 
@@ -233,6 +647,38 @@ func (s *MonitorServiceServer) BlockAgent(ctx context.Context, req *pb.BlockAgen
 	}, nil
 }
 
+// MergeAgents merges a duplicate agent into another
+func (s *MonitorServiceServer) MergeAgents(ctx context.Context, req *pb.MergeAgentsRequest) (*pb.MergeAgentsResponse, error) {
+	log.Printf("Merge request: agent %s into %s", req.SourceAgentId, req.AgentId)
+
+	result, err := s.mergeService.MergeAgents(ctx, req.SourceAgentId, req.AgentId)
+	switch {
+	case errors.Is(err, service.ErrInvalidMerge):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrAgentNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return &pb.MergeAgentsResponse{
+			Success: false,
+			Message: fmt.Sprintf("Merge failed: %v", err),
+			AgentId: req.AgentId,
+		}, nil
+	}
+
+	log.Printf("✓ Merged agent %s into %s: %d policies, %d hosts, %d samples", req.SourceAgentId, result.AgentID, result.PoliciesMoved, result.HostsMoved, result.StatsMoved)
+
+	return &pb.MergeAgentsResponse{
+		Success:         true,
+		Message:         fmt.Sprintf("Agent %s merged successfully", req.SourceAgentId),
+		AgentId:         result.AgentID,
+		PoliciesMoved:   int32(result.PoliciesMoved),
+		ConfigProfileId: result.ConfigProfileID,
+		HostsMoved:      int32(result.HostsMoved),
+		StatsMoved:      result.StatsMoved,
+		Timestamp:       time.Now().Unix(),
+	}, nil
+}
+
 // AddPolicy handles policy creation
 func (s *MonitorServiceServer) AddPolicy(ctx context.Context, req *pb.PolicyRequest) (*pb.PolicyResponse, error) {
 	log.Printf("Add policy request: name=%s", req.Name)
@@ -244,7 +690,15 @@ func (s *MonitorServiceServer) AddPolicy(ctx context.Context, req *pb.PolicyRequ
 		}, nil
 	}
 
-	policy, err := s.policyService.CreatePolicy(req.Name, req.Description, req.Thresholds, req.Actions, req.Metadata)
+	rules, err := policyRulesFromProto(req.Rules)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	policy, err := s.policyService.CreatePolicy(req.Name, req.Description, req.Thresholds, rules, req.Actions, req.Metadata)
+	if errors.Is(err, service.ErrInvalidPolicy) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	if err != nil {
 		return &pb.PolicyResponse{
 			Success: false,
@@ -271,7 +725,15 @@ func (s *MonitorServiceServer) UpdatePolicy(ctx context.Context, req *pb.PolicyR
 		}, nil
 	}
 
-	policy, err := s.policyService.UpdatePolicy(req.PolicyId, req.Name, req.Description, req.Thresholds, req.Actions, req.Metadata)
+	rules, err := policyRulesFromProto(req.Rules)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	policy, err := s.policyService.UpdatePolicy(req.PolicyId, req.Name, req.Description, req.Thresholds, rules, req.Actions, req.Metadata)
+	if errors.Is(err, service.ErrInvalidPolicy) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	if err != nil {
 		return &pb.PolicyResponse{
 			Success: false,
@@ -340,7 +802,8 @@ func (s *MonitorServiceServer) ListPolicies(ctx context.Context, req *pb.ListPol
 			PolicyId:      p.PolicyID,
 			Name:          p.Name,
 			Description:   p.Description,
-			Thresholds:    p.Thresholds,
+			Thresholds:    p.LegacyThresholds(),
+			Rules:         policyRulesToProto(p.Rules),
 			Actions:       p.Actions,
 			Metadata:      p.Metadata,
 			Enabled:       p.Enabled,
@@ -407,3 +870,124 @@ func (s *MonitorServiceServer) UnapplyPolicy(ctx context.Context, req *pb.Unappl
 		Timestamp: time.Now().Unix(),
 	}, nil
 }
+
+// policyRulesFromProto converts protobuf rules into domain rules
+func policyRulesFromProto(rules []*pb.PolicyRule) ([]entity.PolicyRule, error) {
+	if rules == nil {
+		return nil, nil
+	}
+
+	result := make([]entity.PolicyRule, 0, len(rules))
+	for i, r := range rules {
+		var comparator entity.Comparator
+		switch r.Comparator {
+		case pb.Comparator_COMPARATOR_UNSPECIFIED, pb.Comparator_COMPARATOR_GREATER_THAN:
+			comparator = entity.ComparatorGreaterThan
+		case pb.Comparator_COMPARATOR_GREATER_OR_EQUAL:
+			comparator = entity.ComparatorGreaterOrEqual
+		case pb.Comparator_COMPARATOR_LESS_THAN:
+			comparator = entity.ComparatorLessThan
+		case pb.Comparator_COMPARATOR_LESS_OR_EQUAL:
+			comparator = entity.ComparatorLessOrEqual
+		default:
+			return nil, fmt.Errorf("rule %d: unknown comparator %d", i+1, r.Comparator)
+		}
+
+		var severity entity.AlertSeverity
+		switch r.Severity {
+		case pb.Severity_SEVERITY_UNSPECIFIED:
+		case pb.Severity_SEVERITY_LOW:
+			severity = entity.AlertSeverityLow
+		case pb.Severity_SEVERITY_MEDIUM:
+			severity = entity.AlertSeverityMedium
+		case pb.Severity_SEVERITY_HIGH:
+			severity = entity.AlertSeverityHigh
+		case pb.Severity_SEVERITY_CRITICAL:
+			severity = entity.AlertSeverityCritical
+		default:
+			return nil, fmt.Errorf("rule %d: unknown severity %d", i+1, r.Severity)
+		}
+
+		rule := entity.NewPolicyRule(r.Metric, comparator, r.Value, time.Duration(r.DurationSeconds)*time.Second, severity)
+		if r.ClearValue != nil {
+			rule.Clear = r.GetClearValue()
+		}
+		result = append(result, rule)
+	}
+
+	return result, nil
+}
+
+// policyRulesToProto converts domain rules into protobuf rules
+func policyRulesToProto(rules []entity.PolicyRule) []*pb.PolicyRule {
+	result := make([]*pb.PolicyRule, 0, len(rules))
+	for _, r := range rules {
+		rule := &pb.PolicyRule{
+			Metric:          r.Metric,
+			Value:           r.Value,
+			DurationSeconds: int64(r.For / time.Second),
+		}
+
+		switch r.Comparator {
+		case entity.ComparatorGreaterOrEqual:
+			rule.Comparator = pb.Comparator_COMPARATOR_GREATER_OR_EQUAL
+		case entity.ComparatorLessThan:
+			rule.Comparator = pb.Comparator_COMPARATOR_LESS_THAN
+		case entity.ComparatorLessOrEqual:
+			rule.Comparator = pb.Comparator_COMPARATOR_LESS_OR_EQUAL
+		default:
+			rule.Comparator = pb.Comparator_COMPARATOR_GREATER_THAN
+		}
+
+		switch r.Severity {
+		case entity.AlertSeverityLow:
+			rule.Severity = pb.Severity_SEVERITY_LOW
+		case entity.AlertSeverityMedium:
+			rule.Severity = pb.Severity_SEVERITY_MEDIUM
+		case entity.AlertSeverityHigh:
+			rule.Severity = pb.Severity_SEVERITY_HIGH
+		case entity.AlertSeverityCritical:
+			rule.Severity = pb.Severity_SEVERITY_CRITICAL
+		}
+
+		if r.Clear != r.Value {
+			level := r.Clear
+			rule.ClearValue = &level
+		}
+		result = append(result, rule)
+	}
+
+	return result
+}
+
+// commandStatusToProto converts a domain command status into its protobuf enum
+func commandStatusToProto(status entity.CommandStatus) pb.CommandStatus {
+	switch status {
+	case entity.CommandStatusPending:
+		return pb.CommandStatus_COMMAND_STATUS_PENDING
+	case entity.CommandStatusDelivered:
+		return pb.CommandStatus_COMMAND_STATUS_DELIVERED
+	case entity.CommandStatusExecuted:
+		return pb.CommandStatus_COMMAND_STATUS_EXECUTED
+	case entity.CommandStatusFailed:
+		return pb.CommandStatus_COMMAND_STATUS_FAILED
+	default:
+		return pb.CommandStatus_COMMAND_STATUS_UNSPECIFIED
+	}
+}
+
+// commandStatusFromProto converts a protobuf command status into its domain value
+func commandStatusFromProto(status pb.CommandStatus) entity.CommandStatus {
+	switch status {
+	case pb.CommandStatus_COMMAND_STATUS_PENDING:
+		return entity.CommandStatusPending
+	case pb.CommandStatus_COMMAND_STATUS_DELIVERED:
+		return entity.CommandStatusDelivered
+	case pb.CommandStatus_COMMAND_STATUS_EXECUTED:
+		return entity.CommandStatusExecuted
+	case pb.CommandStatus_COMMAND_STATUS_FAILED:
+		return entity.CommandStatusFailed
+	default:
+		return ""
+	}
+}
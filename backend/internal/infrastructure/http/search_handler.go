@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"smart-monitor/backend/internal/domain/entity"
 	"smart-monitor/backend/internal/infrastructure/opensearch"
 )
 
@@ -54,15 +55,151 @@ func (h *SearchHandler) SearchStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	result := make([]statsResult, 0, len(stats))
+	for _, s := range stats {
+		result = append(result, newStatsResult(s))
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"total":  len(stats),
+		"total":  len(result),
 		"limit":  limit,
 		"query":  query,
-		"result": stats,
+		"result": result,
 	})
 }
 
+// statsResult is one sample of a stats search
+type statsResult struct {
+	Hostname          string                 `json:"hostname"`
+	AgentID           string                 `json:"agent_id"`
+	IPAddress         string                 `json:"ip_address"`
+	CPU               float64                `json:"cpu"`
+	RAM               float64                `json:"ram"`
+	Disk              float64                `json:"disk"`
+	Load1             float64                `json:"load_1"`
+	Load5             float64                `json:"load_5"`
+	Load15            float64                `json:"load_15"`
+	CPUCores          int                    `json:"cpu_cores"`
+	RAMTotal          uint64                 `json:"ram_total"`
+	RAMUsed           uint64                 `json:"ram_used"`
+	DiskTotal         uint64                 `json:"disk_total"`
+	DiskUsed          uint64                 `json:"disk_used"`
+	Uptime            uint64                 `json:"uptime"`
+	ProcessCount      uint64                 `json:"process_count"`
+	NetworkSent       uint64                 `json:"network_sent"`
+	NetworkRecv       uint64                 `json:"network_recv"`
+	NetworkSentRate   float64                `json:"network_sent_rate"`
+	NetworkRecvRate   float64                `json:"network_recv_rate"`
+	NetworkErrorsRate float64                `json:"network_errors_rate"`
+	NetworkDropsRate  float64                `json:"network_drops_rate"`
+	Cores             []coreResult           `json:"cores,omitempty"`
+	Partitions        []partitionResult      `json:"partitions,omitempty"`
+	Interfaces        []interfaceResult      `json:"interfaces,omitempty"`
+	Custom            map[string]float64     `json:"custom,omitempty"`
+	Errors            []collectorErrorResult `json:"collector_errors,omitempty"`
+	Timestamp         int64                  `json:"timestamp"`
+	LastReceived      int64                  `json:"last_received"`
+	Metadata          map[string]string      `json:"metadata,omitempty"`
+}
+
+// collectorErrorResult reports an agent plugin that failed on the sample
+type collectorErrorResult struct {
+	Collector string `json:"collector"`
+	Message   string `json:"message"`
+}
+
+// coreResult is the usage of one CPU core
+type coreResult struct {
+	Core    string  `json:"core"`
+	Percent float64 `json:"percent"`
+}
+
+// partitionResult is the usage of one filesystem
+type partitionResult struct {
+	MountPoint        string  `json:"mount_point"`
+	Device            string  `json:"device"`
+	FSType            string  `json:"fstype"`
+	Total             uint64  `json:"total"`
+	Used              uint64  `json:"used"`
+	Free              uint64  `json:"free"`
+	UsedPercent       float64 `json:"used_percent"`
+	InodesTotal       uint64  `json:"inodes_total"`
+	InodesUsed        uint64  `json:"inodes_used"`
+	InodesFree        uint64  `json:"inodes_free"`
+	InodesUsedPercent float64 `json:"inodes_used_percent"`
+}
+
+// interfaceResult holds the counters of one network interface
+type interfaceResult struct {
+	Name        string `json:"name"`
+	IP          string `json:"ip"`
+	BytesSent   uint64 `json:"bytes_sent"`
+	BytesRecv   uint64 `json:"bytes_recv"`
+	PacketsSent uint64 `json:"packets_sent"`
+	PacketsRecv uint64 `json:"packets_recv"`
+	ErrorsIn    uint64 `json:"errors_in"`
+	ErrorsOut   uint64 `json:"errors_out"`
+	DropsIn     uint64 `json:"drops_in"`
+	DropsOut    uint64 `json:"drops_out"`
+
+	BytesSentRate   float64 `json:"bytes_sent_rate"`
+	BytesRecvRate   float64 `json:"bytes_recv_rate"`
+	PacketsSentRate float64 `json:"packets_sent_rate"`
+	PacketsRecvRate float64 `json:"packets_recv_rate"`
+	ErrorsInRate    float64 `json:"errors_in_rate"`
+	ErrorsOutRate   float64 `json:"errors_out_rate"`
+	DropsInRate     float64 `json:"drops_in_rate"`
+	DropsOutRate    float64 `json:"drops_out_rate"`
+}
+
+// newStatsResult converts a sample to its search result
+func newStatsResult(s *entity.Stats) statsResult {
+	result := statsResult{
+		Hostname:          s.Hostname,
+		AgentID:           s.AgentID,
+		IPAddress:         s.IPAddress,
+		CPU:               s.CPU,
+		RAM:               s.RAM,
+		Disk:              s.Disk,
+		Load1:             s.Load1,
+		Load5:             s.Load5,
+		Load15:            s.Load15,
+		CPUCores:          s.CPUCores,
+		RAMTotal:          s.RAMTotal,
+		RAMUsed:           s.RAMUsed,
+		DiskTotal:         s.DiskTotal,
+		DiskUsed:          s.DiskUsed,
+		Uptime:            s.Uptime,
+		ProcessCount:      s.ProcessCount,
+		NetworkSent:       s.NetworkSent,
+		NetworkRecv:       s.NetworkRecv,
+		NetworkSentRate:   s.NetworkSentRate,
+		NetworkRecvRate:   s.NetworkRecvRate,
+		NetworkErrorsRate: s.NetworkErrorsRate,
+		NetworkDropsRate:  s.NetworkDropsRate,
+		Timestamp:         s.Timestamp.UnixMilli(),
+		LastReceived:      s.LastReceived.UnixMilli(),
+		Metadata:          s.Metadata,
+		Custom:            s.Custom,
+	}
+
+	for _, c := range s.Cores {
+		result.Cores = append(result.Cores, coreResult(c))
+	}
+	for _, p := range s.Partitions {
+		result.Partitions = append(result.Partitions, partitionResult(p))
+	}
+	for _, nic := range s.Interfaces {
+		result.Interfaces = append(result.Interfaces, interfaceResult(nic))
+	}
+	for _, e := range s.CollectorErrors {
+		result.Errors = append(result.Errors, collectorErrorResult(e))
+	}
+
+	return result
+}
+
 // SearchAlerts searches alert data
 func (h *SearchHandler) SearchAlerts(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost && r.Method != http.MethodGet {
@@ -0,0 +1,103 @@
+// Package http implements HTTP handlers
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"smart-monitor/backend/internal/application/usecase"
+	"smart-monitor/backend/internal/domain/service"
+)
+
+// statsWatchKeepalive is how often an idle stream sends a comment so proxies
+// do not close it
+const statsWatchKeepalive = 15 * time.Second
+
+// StatsWatchHandler streams live stats to dashboards as server-sent events
+type StatsWatchHandler struct {
+	monitorUseCase *usecase.MonitorUseCase
+}
+
+// NewStatsWatchHandler creates a new stats watch handler
+func NewStatsWatchHandler(monitorUseCase *usecase.MonitorUseCase) *StatsWatchHandler {
+	return &StatsWatchHandler{
+		monitorUseCase: monitorUseCase,
+	}
+}
+
+// ServeHTTP streams every sample matching the query as an event until the
+// client disconnects. Filters: agent_id (repeatable) and labels[<key>]=<value>.
+func (h *StatsWatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// The stream outlives the server's write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	sub := h.monitorUseCase.WatchStats(parseStatsFilter(r))
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	keepalive := time.NewTicker(statsWatchKeepalive)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			if dropped := sub.Dropped(); dropped > 0 {
+				log.Printf("Stats watcher %s fell behind, %d samples dropped", r.RemoteAddr, dropped)
+			}
+			return
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		case stats := <-sub.Updates():
+			data, err := json.Marshal(newStatsResult(stats))
+			if err != nil {
+				log.Printf("⚠ Failed to encode stats for %s: %v", stats.Hostname, err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// parseStatsFilter reads the subscription filter from the query string
+func parseStatsFilter(r *http.Request) service.StatsFilter {
+	query := r.URL.Query()
+	filter := service.StatsFilter{
+		AgentIDs: query["agent_id"],
+		Labels:   make(map[string]string),
+	}
+	for key, values := range query {
+		name, ok := strings.CutPrefix(key, "labels[")
+		if !ok || !strings.HasSuffix(name, "]") || len(values) == 0 {
+			continue
+		}
+		filter.Labels[strings.TrimSuffix(name, "]")] = values[0]
+	}
+	return filter
+}
@@ -10,6 +10,9 @@ import (
 	"net/http"
 	"time"
 
+	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/repository"
+
 	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
 )
 
@@ -194,6 +197,11 @@ func (r *AlertsRepository) ListAlerts(ctx context.Context, hostname string, seve
 
 	query["query"].(map[string]interface{})["bool"].(map[string]interface{})["must"] = must
 
+	return r.search(ctx, query)
+}
+
+// search runs a search query against the alerts index
+func (r *AlertsRepository) search(ctx context.Context, query map[string]interface{}) ([]*Alert, error) {
 	body, err := json.Marshal(query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal query: %w", err)
@@ -210,6 +218,11 @@ func (r *AlertsRepository) ListAlerts(ctx context.Context, hostname string, seve
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode >= 400 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("OpenSearch error: %d - %s", resp.StatusCode, string(bodyBytes))
+	}
+
 	var result struct {
 		Hits struct {
 			Hits []struct {
@@ -280,3 +293,127 @@ func (r *AlertsRepository) GetAlertStats(ctx context.Context) (map[string]interf
 
 	return result, nil
 }
+
+// DomainAlertsRepository adapts AlertsRepository to the domain AlertRepository interface
+type DomainAlertsRepository struct {
+	alerts *AlertsRepository
+}
+
+// NewDomainAlertsRepository wraps an AlertsRepository for use by domain services
+func NewDomainAlertsRepository(alerts *AlertsRepository) repository.AlertRepository {
+	return &DomainAlertsRepository{
+		alerts: alerts,
+	}
+}
+
+// Create stores a domain alert as an OpenSearch alert document
+func (r *DomainAlertsRepository) Create(ctx context.Context, alert *entity.Alert) (string, error) {
+	metadata := map[string]interface{}{
+		"agent_id":  alert.AgentID,
+		"policy_id": alert.PolicyID,
+		"metric":    alert.Metric,
+	}
+	for k, v := range alert.Metadata {
+		metadata[k] = v
+	}
+
+	return r.alerts.CreateAlert(ctx, &Alert{
+		ID:        alert.ID,
+		Hostname:  alert.Hostname,
+		AlertType: alert.AlertType,
+		Severity:  string(alert.Severity),
+		Title:     alert.Title,
+		Message:   alert.Message,
+		Value:     alert.Value,
+		Threshold: alert.Threshold,
+		Metadata:  metadata,
+	})
+}
+
+// Resolve marks an alert as resolved
+func (r *DomainAlertsRepository) Resolve(ctx context.Context, alertID string) error {
+	return r.alerts.ResolveAlert(ctx, alertID)
+}
+
+// maxActiveAlerts is the most unresolved alerts ListActive returns, the
+// default result window of an index
+const maxActiveAlerts = 10000
+
+// ListActive retrieves the unresolved alerts matching a filter, oldest first.
+// Agent and policy IDs are kept in the dynamically mapped metadata, so they
+// are matched on its keyword subfields.
+func (r *DomainAlertsRepository) ListActive(ctx context.Context, filter repository.AlertFilter) ([]*entity.Alert, error) {
+	must := []map[string]interface{}{
+		{"term": map[string]interface{}{"status": string(entity.AlertStatusActive)}},
+	}
+	if filter.AlertType != "" {
+		must = append(must, map[string]interface{}{"term": map[string]interface{}{"alert_type": filter.AlertType}})
+	}
+	if filter.AgentID != "" {
+		must = append(must, map[string]interface{}{"term": map[string]interface{}{"metadata.agent_id.keyword": filter.AgentID}})
+	}
+	if filter.PolicyID != "" {
+		must = append(must, map[string]interface{}{"term": map[string]interface{}{"metadata.policy_id.keyword": filter.PolicyID}})
+	}
+
+	docs, err := r.alerts.search(ctx, map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": must,
+			},
+		},
+		"sort": []map[string]interface{}{
+			{"timestamp": map[string]interface{}{"order": "asc"}},
+		},
+		"size": maxActiveAlerts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := make([]*entity.Alert, 0, len(docs))
+	for _, doc := range docs {
+		alerts = append(alerts, toDomainAlert(doc))
+	}
+	return alerts, nil
+}
+
+// toDomainAlert converts an alert document to a domain alert
+func toDomainAlert(doc *Alert) *entity.Alert {
+	alert := &entity.Alert{
+		ID:        doc.ID,
+		Hostname:  doc.Hostname,
+		AlertType: doc.AlertType,
+		Severity:  entity.AlertSeverity(doc.Severity),
+		Title:     doc.Title,
+		Message:   doc.Message,
+		Value:     doc.Value,
+		Threshold: doc.Threshold,
+		Status:    entity.AlertStatus(doc.Status),
+		Metadata:  make(map[string]string),
+		CreatedAt: time.UnixMilli(doc.Timestamp),
+	}
+	if doc.ResolvedAt != nil {
+		resolvedAt := time.UnixMilli(*doc.ResolvedAt)
+		alert.ResolvedAt = &resolvedAt
+	}
+
+	for k, v := range doc.Metadata {
+		value, ok := v.(string)
+		if !ok {
+			continue
+		}
+		switch k {
+		case "agent_id":
+			alert.AgentID = value
+		case "policy_id":
+			alert.PolicyID = value
+		case "metric":
+			alert.Metric = value
+		default:
+			alert.Metadata[k] = value
+		}
+	}
+
+	return alert
+}
@@ -2,8 +2,10 @@
 package opensearch
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
@@ -78,6 +80,34 @@ func (c *Client) CreateIndex(ctx context.Context, indexName string, mapping stri
 	return nil
 }
 
+// UpdateMapping adds the fields of an index definition to an existing index,
+// so indexes created by an older version pick up new fields
+func (c *Client) UpdateMapping(ctx context.Context, indexName string, mapping string) error {
+	var definition struct {
+		Mappings json.RawMessage `json:"mappings"`
+	}
+	if err := json.Unmarshal([]byte(mapping), &definition); err != nil {
+		return fmt.Errorf("failed to parse index mapping: %w", err)
+	}
+
+	req := opensearchapi.IndicesPutMappingRequest{
+		Index: []string{indexName},
+		Body:  bytes.NewReader(definition.Mappings),
+	}
+
+	resp, err := req.Do(ctx, c.Client)
+	if err != nil {
+		return fmt.Errorf("failed to update mapping: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to update mapping: status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
 // IndexExists checks if index exists
 func (c *Client) IndexExists(ctx context.Context, indexName string) (bool, error) {
 	req := opensearchapi.IndicesExistsRequest{
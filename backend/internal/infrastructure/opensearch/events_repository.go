@@ -10,6 +10,9 @@ import (
 	"net/http"
 	"time"
 
+	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/repository"
+
 	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
 )
 
@@ -323,3 +326,37 @@ func (r *EventsRepository) GetEventStats(ctx context.Context) (map[string]interf
 
 	return result, nil
 }
+
+// DomainEventsRepository adapts EventsRepository to the domain EventRepository interface
+type DomainEventsRepository struct {
+	events *EventsRepository
+}
+
+// NewDomainEventsRepository wraps an EventsRepository for use by domain services
+func NewDomainEventsRepository(events *EventsRepository) repository.EventRepository {
+	return &DomainEventsRepository{
+		events: events,
+	}
+}
+
+// Log stores a domain event as an OpenSearch event document
+func (r *DomainEventsRepository) Log(ctx context.Context, event *entity.Event) (string, error) {
+	details := map[string]interface{}{
+		"agent_id": event.AgentID,
+	}
+	for k, v := range event.Details {
+		details[k] = v
+	}
+
+	return r.events.LogEvent(ctx, &Event{
+		ID:        event.ID,
+		Hostname:  event.Hostname,
+		EventType: event.EventType,
+		EventName: event.EventName,
+		Timestamp: event.Timestamp.UnixMilli(),
+		Message:   event.Message,
+		Source:    event.Source,
+		Level:     string(event.Level),
+		Details:   details,
+	})
+}
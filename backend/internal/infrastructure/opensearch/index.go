@@ -19,7 +19,77 @@ const StatsIndexMapping = `{
       "disk": {"type": "float"},
       "timestamp": {"type": "date", "format": "epoch_millis"},
       "last_received": {"type": "date", "format": "epoch_millis"},
-      "metadata": {"type": "object"}
+      "metadata": {"type": "object"},
+      "load_1": {"type": "float"},
+      "load_5": {"type": "float"},
+      "load_15": {"type": "float"},
+      "cpu_cores": {"type": "integer"},
+      "ram_total": {"type": "long"},
+      "ram_used": {"type": "long"},
+      "disk_total": {"type": "long"},
+      "disk_used": {"type": "long"},
+      "uptime": {"type": "long"},
+      "process_count": {"type": "long"},
+      "network_sent": {"type": "long"},
+      "network_recv": {"type": "long"},
+      "network_sent_rate": {"type": "float"},
+      "network_recv_rate": {"type": "float"},
+      "network_errors_rate": {"type": "float"},
+      "network_drops_rate": {"type": "float"},
+      "cores": {
+        "type": "nested",
+        "properties": {
+          "core": {"type": "keyword"},
+          "percent": {"type": "float"}
+        }
+      },
+      "partitions": {
+        "type": "nested",
+        "properties": {
+          "mount_point": {"type": "keyword"},
+          "device": {"type": "keyword"},
+          "fstype": {"type": "keyword"},
+          "total": {"type": "long"},
+          "used": {"type": "long"},
+          "free": {"type": "long"},
+          "used_percent": {"type": "float"},
+          "inodes_total": {"type": "long"},
+          "inodes_used": {"type": "long"},
+          "inodes_free": {"type": "long"},
+          "inodes_used_percent": {"type": "float"}
+        }
+      },
+      "interfaces": {
+        "type": "nested",
+        "properties": {
+          "name": {"type": "keyword"},
+          "ip": {"type": "keyword"},
+          "bytes_sent": {"type": "long"},
+          "bytes_recv": {"type": "long"},
+          "packets_sent": {"type": "long"},
+          "packets_recv": {"type": "long"},
+          "errors_in": {"type": "long"},
+          "errors_out": {"type": "long"},
+          "drops_in": {"type": "long"},
+          "drops_out": {"type": "long"},
+          "bytes_sent_rate": {"type": "float"},
+          "bytes_recv_rate": {"type": "float"},
+          "packets_sent_rate": {"type": "float"},
+          "packets_recv_rate": {"type": "float"},
+          "errors_in_rate": {"type": "float"},
+          "errors_out_rate": {"type": "float"},
+          "drops_in_rate": {"type": "float"},
+          "drops_out_rate": {"type": "float"}
+        }
+      },
+      "custom": {"type": "object"},
+      "collector_errors": {
+        "type": "nested",
+        "properties": {
+          "collector": {"type": "keyword"},
+          "message": {"type": "text"}
+        }
+      }
     }
   }
 }`
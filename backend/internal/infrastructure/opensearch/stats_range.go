@@ -0,0 +1,172 @@
+// Package opensearch provides OpenSearch-based repositories
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"smart-monitor/backend/internal/domain/entity"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+)
+
+// rangeMetricFields maps the range query metrics to their document fields
+var rangeMetricFields = map[string]string{
+	entity.MetricCPU:           "cpu",
+	entity.MetricRAM:           "ram",
+	entity.MetricDisk:          "disk",
+	entity.MetricNetworkTx:     "network_sent_rate",
+	entity.MetricNetworkRx:     "network_recv_rate",
+	entity.MetricNetworkErrors: "network_errors_rate",
+	entity.MetricNetworkDrops:  "network_drops_rate",
+}
+
+// metricAggregation is the value of a metric aggregation within a histogram
+// bucket; percentiles report theirs in Values
+type metricAggregation struct {
+	Value  *float64            `json:"value"`
+	Values map[string]*float64 `json:"values"`
+}
+
+// QueryRange aggregates samples with a date_histogram of one bucket per step
+func (r *OpenSearchStatsRepository) QueryRange(ctx context.Context, query *entity.StatsRangeQuery) ([]entity.StatsSeries, error) {
+	filters := []map[string]interface{}{
+		{
+			"range": map[string]interface{}{
+				"timestamp": map[string]interface{}{
+					"gte": query.From.UnixMilli(),
+					"lte": query.To.UnixMilli(),
+				},
+			},
+		},
+	}
+	if query.Hostname != "" {
+		filters = append(filters, map[string]interface{}{
+			"term": map[string]interface{}{
+				"hostname": query.Hostname,
+			},
+		})
+	}
+	if query.AgentID != "" {
+		filters = append(filters, map[string]interface{}{
+			"term": map[string]interface{}{
+				"agent_id": query.AgentID,
+			},
+		})
+	}
+
+	metricAggs := make(map[string]interface{}, len(query.Metrics))
+	for _, metric := range query.Metrics {
+		field, ok := rangeMetricFields[metric]
+		if !ok {
+			return nil, fmt.Errorf("unsupported metric: %s", metric)
+		}
+		if query.Aggregation == entity.AggregationP95 {
+			metricAggs[metric] = map[string]interface{}{
+				"percentiles": map[string]interface{}{
+					"field":    field,
+					"percents": []float64{95},
+				},
+			}
+			continue
+		}
+		metricAggs[metric] = map[string]interface{}{
+			string(query.Aggregation): map[string]interface{}{
+				"field": field,
+			},
+		}
+	}
+
+	searchQuery := map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": filters,
+			},
+		},
+		"aggs": map[string]interface{}{
+			"steps": map[string]interface{}{
+				"date_histogram": map[string]interface{}{
+					"field":          "timestamp",
+					"fixed_interval": fmt.Sprintf("%dms", query.Step.Milliseconds()),
+					"min_doc_count":  1,
+				},
+				"aggs": metricAggs,
+			},
+		},
+		"size": 0,
+	}
+
+	body, err := json.Marshal(searchQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal query: %w", err)
+	}
+
+	req := opensearchapi.SearchRequest{
+		Index: []string{StatsIndex},
+		Body:  bytes.NewReader(body),
+	}
+
+	resp, err := req.Do(ctx, r.client.Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stats range: %w", err)
+	}
+	defer resp.Body.Close()
+
+	series := make([]entity.StatsSeries, len(query.Metrics))
+	for i, metric := range query.Metrics {
+		series[i] = entity.StatsSeries{Metric: metric, Points: []entity.StatsPoint{}}
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		// The index does not exist before the first sample
+		return series, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to query stats range: status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Aggregations struct {
+			Steps struct {
+				Buckets []map[string]json.RawMessage `json:"buckets"`
+			} `json:"steps"`
+		} `json:"aggregations"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	for _, bucket := range result.Aggregations.Steps.Buckets {
+		var key int64
+		if err := json.Unmarshal(bucket["key"], &key); err != nil {
+			return nil, fmt.Errorf("failed to decode bucket key: %w", err)
+		}
+
+		for i, metric := range query.Metrics {
+			var agg metricAggregation
+			if err := json.Unmarshal(bucket[metric], &agg); err != nil {
+				return nil, fmt.Errorf("failed to decode %s aggregation: %w", metric, err)
+			}
+
+			value := agg.Value
+			if query.Aggregation == entity.AggregationP95 {
+				value = agg.Values["95.0"]
+			}
+			if value == nil {
+				// No sample in the step has the metric
+				continue
+			}
+			series[i].Points = append(series[i].Points, entity.StatsPoint{
+				Timestamp: time.UnixMilli(key),
+				Value:     *value,
+			})
+		}
+	}
+
+	return series, nil
+}
@@ -12,25 +12,97 @@ import (
 	"time"
 
 	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/repository"
 
 	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
 )
 
 // statsDoc is the OpenSearch document representation of Stats
 type statsDoc struct {
-	Hostname     string            `json:"hostname"`
-	AgentID      string            `json:"agent_id"`
-	IPAddress    string            `json:"ip_address"`
-	CPU          float64           `json:"cpu"`
-	RAM          float64           `json:"ram"`
-	Disk         float64           `json:"disk"`
-	Timestamp    int64             `json:"timestamp"`
-	LastReceived int64             `json:"last_received"`
-	Metadata     map[string]string `json:"metadata"`
+	Hostname          string              `json:"hostname"`
+	AgentID           string              `json:"agent_id"`
+	IPAddress         string              `json:"ip_address"`
+	CPU               float64             `json:"cpu"`
+	RAM               float64             `json:"ram"`
+	Disk              float64             `json:"disk"`
+	Timestamp         int64               `json:"timestamp"`
+	LastReceived      int64               `json:"last_received"`
+	Metadata          map[string]string   `json:"metadata"`
+	Load1             float64             `json:"load_1"`
+	Load5             float64             `json:"load_5"`
+	Load15            float64             `json:"load_15"`
+	CPUCores          int                 `json:"cpu_cores"`
+	RAMTotal          uint64              `json:"ram_total"`
+	RAMUsed           uint64              `json:"ram_used"`
+	DiskTotal         uint64              `json:"disk_total"`
+	DiskUsed          uint64              `json:"disk_used"`
+	Uptime            uint64              `json:"uptime"`
+	ProcessCount      uint64              `json:"process_count"`
+	NetworkSent       uint64              `json:"network_sent"`
+	NetworkRecv       uint64              `json:"network_recv"`
+	NetworkSentRate   float64             `json:"network_sent_rate"`
+	NetworkRecvRate   float64             `json:"network_recv_rate"`
+	NetworkErrorsRate float64             `json:"network_errors_rate"`
+	NetworkDropsRate  float64             `json:"network_drops_rate"`
+	Cores             []coreDoc           `json:"cores,omitempty"`
+	Partitions        []partitionDoc      `json:"partitions,omitempty"`
+	Interfaces        []interfaceDoc      `json:"interfaces,omitempty"`
+	Custom            map[string]float64  `json:"custom,omitempty"`
+	Errors            []collectorErrorDoc `json:"collector_errors,omitempty"`
+}
+
+// collectorErrorDoc reports an agent plugin that failed on the sample
+type collectorErrorDoc struct {
+	Collector string `json:"collector"`
+	Message   string `json:"message"`
+}
+
+// coreDoc is the usage of one CPU core, stored as a nested document
+type coreDoc struct {
+	Core    string  `json:"core"`
+	Percent float64 `json:"percent"`
+}
+
+// partitionDoc is the usage of one filesystem, stored as a nested document
+type partitionDoc struct {
+	MountPoint        string  `json:"mount_point"`
+	Device            string  `json:"device"`
+	FSType            string  `json:"fstype"`
+	Total             uint64  `json:"total"`
+	Used              uint64  `json:"used"`
+	Free              uint64  `json:"free"`
+	UsedPercent       float64 `json:"used_percent"`
+	InodesTotal       uint64  `json:"inodes_total"`
+	InodesUsed        uint64  `json:"inodes_used"`
+	InodesFree        uint64  `json:"inodes_free"`
+	InodesUsedPercent float64 `json:"inodes_used_percent"`
+}
+
+// interfaceDoc holds the counters of one network interface, stored as a nested document
+type interfaceDoc struct {
+	Name        string `json:"name"`
+	IP          string `json:"ip"`
+	BytesSent   uint64 `json:"bytes_sent"`
+	BytesRecv   uint64 `json:"bytes_recv"`
+	PacketsSent uint64 `json:"packets_sent"`
+	PacketsRecv uint64 `json:"packets_recv"`
+	ErrorsIn    uint64 `json:"errors_in"`
+	ErrorsOut   uint64 `json:"errors_out"`
+	DropsIn     uint64 `json:"drops_in"`
+	DropsOut    uint64 `json:"drops_out"`
+
+	BytesSentRate   float64 `json:"bytes_sent_rate"`
+	BytesRecvRate   float64 `json:"bytes_recv_rate"`
+	PacketsSentRate float64 `json:"packets_sent_rate"`
+	PacketsRecvRate float64 `json:"packets_recv_rate"`
+	ErrorsInRate    float64 `json:"errors_in_rate"`
+	ErrorsOutRate   float64 `json:"errors_out_rate"`
+	DropsInRate     float64 `json:"drops_in_rate"`
+	DropsOutRate    float64 `json:"drops_out_rate"`
 }
 
 func (d *statsDoc) toEntity() *entity.Stats {
-	return &entity.Stats{
+	stats := &entity.Stats{
 		Hostname:     d.Hostname,
 		AgentID:      d.AgentID,
 		IPAddress:    d.IPAddress,
@@ -40,7 +112,42 @@ func (d *statsDoc) toEntity() *entity.Stats {
 		Timestamp:    time.UnixMilli(d.Timestamp),
 		LastReceived: time.UnixMilli(d.LastReceived),
 		Metadata:     d.Metadata,
+		ExtendedMetrics: entity.ExtendedMetrics{
+			Load1:        d.Load1,
+			Load5:        d.Load5,
+			Load15:       d.Load15,
+			CPUCores:     d.CPUCores,
+			RAMTotal:     d.RAMTotal,
+			RAMUsed:      d.RAMUsed,
+			DiskTotal:    d.DiskTotal,
+			DiskUsed:     d.DiskUsed,
+			Uptime:       d.Uptime,
+			ProcessCount: d.ProcessCount,
+			NetworkSent:  d.NetworkSent,
+			NetworkRecv:  d.NetworkRecv,
+			Custom:       d.Custom,
+
+			NetworkSentRate:   d.NetworkSentRate,
+			NetworkRecvRate:   d.NetworkRecvRate,
+			NetworkErrorsRate: d.NetworkErrorsRate,
+			NetworkDropsRate:  d.NetworkDropsRate,
+		},
 	}
+
+	for _, c := range d.Cores {
+		stats.Cores = append(stats.Cores, entity.CPUCoreUsage(c))
+	}
+	for _, p := range d.Partitions {
+		stats.Partitions = append(stats.Partitions, entity.DiskPartition(p))
+	}
+	for _, nic := range d.Interfaces {
+		stats.Interfaces = append(stats.Interfaces, entity.NetworkInterface(nic))
+	}
+	for _, e := range d.Errors {
+		stats.CollectorErrors = append(stats.CollectorErrors, entity.CollectorError(e))
+	}
+
+	return stats
 }
 
 // OpenSearchStatsRepository implements StatsRepository using OpenSearch
@@ -57,30 +164,83 @@ func NewOpenSearchStatsRepository(client *Client) (*OpenSearchStatsRepository, e
 	if err := client.CreateIndex(ctx, StatsIndex, StatsIndexMapping); err != nil {
 		return nil, fmt.Errorf("failed to create stats index: %w", err)
 	}
+	if err := client.UpdateMapping(ctx, StatsIndex, StatsIndexMapping); err != nil {
+		return nil, fmt.Errorf("failed to update stats index mapping: %w", err)
+	}
 
 	return &OpenSearchStatsRepository{
 		client: client,
 	}, nil
 }
 
+// newStatsDoc converts stats to a document indexed at the time the sample was
+// collected, falling back to the time it was received
+func newStatsDoc(stats *entity.Stats) *statsDoc {
+	timestamp := stats.Timestamp
+	if timestamp.IsZero() {
+		timestamp = stats.LastReceived
+	}
+
+	doc := &statsDoc{
+		Hostname:     stats.Hostname,
+		AgentID:      stats.AgentID,
+		IPAddress:    stats.IPAddress,
+		CPU:          stats.CPU,
+		RAM:          stats.RAM,
+		Disk:         stats.Disk,
+		Timestamp:    timestamp.UnixMilli(),
+		LastReceived: stats.LastReceived.UnixMilli(),
+		Metadata:     stats.Metadata,
+		Load1:        stats.Load1,
+		Load5:        stats.Load5,
+		Load15:       stats.Load15,
+		CPUCores:     stats.CPUCores,
+		RAMTotal:     stats.RAMTotal,
+		RAMUsed:      stats.RAMUsed,
+		DiskTotal:    stats.DiskTotal,
+		DiskUsed:     stats.DiskUsed,
+		Uptime:       stats.Uptime,
+		ProcessCount: stats.ProcessCount,
+		NetworkSent:  stats.NetworkSent,
+		NetworkRecv:  stats.NetworkRecv,
+		Custom:       stats.Custom,
+
+		NetworkSentRate:   stats.NetworkSentRate,
+		NetworkRecvRate:   stats.NetworkRecvRate,
+		NetworkErrorsRate: stats.NetworkErrorsRate,
+		NetworkDropsRate:  stats.NetworkDropsRate,
+	}
+
+	for _, c := range stats.Cores {
+		doc.Cores = append(doc.Cores, coreDoc(c))
+	}
+	for _, p := range stats.Partitions {
+		doc.Partitions = append(doc.Partitions, partitionDoc(p))
+	}
+	for _, nic := range stats.Interfaces {
+		doc.Interfaces = append(doc.Interfaces, interfaceDoc(nic))
+	}
+	for _, e := range stats.CollectorErrors {
+		doc.Errors = append(doc.Errors, collectorErrorDoc(e))
+	}
+
+	return doc
+}
+
+// id returns the document ID. It is derived from the collection time so that
+// a sample delivered twice, e.g. replayed after a lost acknowledgement, is
+// stored once.
+func (d *statsDoc) id() string {
+	return fmt.Sprintf("%s-%d", d.Hostname, d.Timestamp)
+}
+
 // Save stores stats in OpenSearch
 func (r *OpenSearchStatsRepository) Save(ctx context.Context, stats *entity.Stats) error {
 	if stats == nil {
 		return fmt.Errorf("stats cannot be nil")
 	}
 
-	doc := map[string]interface{}{
-		"hostname":      stats.Hostname,
-		"agent_id":      stats.AgentID,
-		"ip_address":    stats.IPAddress,
-		"cpu":           stats.CPU,
-		"ram":           stats.RAM,
-		"disk":          stats.Disk,
-		"timestamp":     stats.Timestamp.UnixMilli(),
-		"last_received": stats.LastReceived.UnixMilli(),
-		"metadata":      stats.Metadata,
-	}
-
+	doc := newStatsDoc(stats)
 	body, err := json.Marshal(doc)
 	if err != nil {
 		return fmt.Errorf("failed to marshal stats: %w", err)
@@ -88,7 +248,7 @@ func (r *OpenSearchStatsRepository) Save(ctx context.Context, stats *entity.Stat
 
 	req := opensearchapi.IndexRequest{
 		Index:      StatsIndex,
-		DocumentID: fmt.Sprintf("%s-%d", stats.Hostname, stats.Timestamp.UnixMilli()),
+		DocumentID: doc.id(),
 		Body:       bytes.NewReader(body),
 	}
 
@@ -106,15 +266,116 @@ func (r *OpenSearchStatsRepository) Save(ctx context.Context, stats *entity.Stat
 	return nil
 }
 
+// SaveBatch stores samples with a single bulk request
+func (r *OpenSearchStatsRepository) SaveBatch(ctx context.Context, stats []*entity.Stats) error {
+	if len(stats) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	for _, s := range stats {
+		if s == nil {
+			return fmt.Errorf("stats cannot be nil")
+		}
+		doc := newStatsDoc(s)
+
+		action := map[string]interface{}{
+			"index": map[string]interface{}{
+				"_index": StatsIndex,
+				"_id":    doc.id(),
+			},
+		}
+		for _, v := range []interface{}{action, doc} {
+			line, err := json.Marshal(v)
+			if err != nil {
+				return fmt.Errorf("failed to marshal stats: %w", err)
+			}
+			body.Write(line)
+			body.WriteByte('\n')
+		}
+	}
+
+	req := opensearchapi.BulkRequest{
+		Body: &body,
+	}
+
+	resp, err := req.Do(ctx, r.client.Client)
+	if err != nil {
+		return fmt.Errorf("failed to bulk index stats: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("OpenSearch error: %d - %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	// A bulk request succeeds as a whole even when single documents fail
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+			Error  struct {
+				Reason string `json:"reason"`
+			} `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode bulk response: %w", err)
+	}
+	if !result.Errors {
+		return nil
+	}
+
+	failed := 0
+	var reason string
+	for _, item := range result.Items {
+		for _, op := range item {
+			if op.Status >= 400 {
+				failed++
+				if reason == "" {
+					reason = op.Error.Reason
+				}
+			}
+		}
+	}
+	return fmt.Errorf("failed to index %d of %d stats: %s", failed, len(stats), reason)
+}
+
 // Get retrieves the latest stats for a hostname
 func (r *OpenSearchStatsRepository) Get(ctx context.Context, hostname string) (*entity.Stats, error) {
+	stats, err := r.latest(ctx, "hostname", hostname)
+	if err != nil {
+		return nil, err
+	}
+	if stats == nil {
+		return nil, fmt.Errorf("%w for hostname: %s", repository.ErrStatsNotFound, hostname)
+	}
+	return stats, nil
+}
+
+// GetByAgent retrieves the latest stats reported by an agent
+func (r *OpenSearchStatsRepository) GetByAgent(ctx context.Context, agentID string) (*entity.Stats, error) {
+	stats, err := r.latest(ctx, "agent_id", agentID)
+	if err != nil {
+		return nil, err
+	}
+	if stats == nil {
+		return nil, fmt.Errorf("%w for agent: %s", repository.ErrStatsNotFound, agentID)
+	}
+	return stats, nil
+}
+
+// latest retrieves the most recent sample whose keyword field equals value,
+// nil if there is none
+func (r *OpenSearchStatsRepository) latest(ctx context.Context, field, value string) (*entity.Stats, error) {
 	query := map[string]interface{}{
 		"query": map[string]interface{}{
 			"bool": map[string]interface{}{
 				"must": []map[string]interface{}{
 					{
 						"term": map[string]interface{}{
-							"hostname": hostname,
+							field: value,
 						},
 					},
 				},
@@ -146,8 +407,12 @@ func (r *OpenSearchStatsRepository) Get(ctx context.Context, hostname string) (*
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		// The index does not exist before the first sample
+		return nil, nil
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("stats not found for hostname: %s", hostname)
+		return nil, fmt.Errorf("failed to search stats: status %d", resp.StatusCode)
 	}
 
 	var result struct {
@@ -163,7 +428,7 @@ func (r *OpenSearchStatsRepository) Get(ctx context.Context, hostname string) (*
 	}
 
 	if len(result.Hits.Hits) == 0 {
-		return nil, fmt.Errorf("stats not found for hostname: %s", hostname)
+		return nil, nil
 	}
 
 	return result.Hits.Hits[0].Source.toEntity(), nil
@@ -219,16 +484,15 @@ func (r *OpenSearchStatsRepository) GetAll(ctx context.Context) ([]*entity.Stats
 	var result struct {
 		Aggregations struct {
 			Hosts struct {
+				// Sub-aggregations are keyed by name in the bucket itself
 				Buckets []struct {
-					Aggregations struct {
-						Latest struct {
-							Hits struct {
-								Hits []struct {
-									Source statsDoc `json:"_source"`
-								} `json:"hits"`
+					Latest struct {
+						Hits struct {
+							Hits []struct {
+								Source statsDoc `json:"_source"`
 							} `json:"hits"`
-						} `json:"latest"`
-					} `json:"aggregations"`
+						} `json:"hits"`
+					} `json:"latest"`
 				} `json:"buckets"`
 			} `json:"hosts"`
 		} `json:"aggregations"`
@@ -240,8 +504,8 @@ func (r *OpenSearchStatsRepository) GetAll(ctx context.Context) ([]*entity.Stats
 
 	var allStats []*entity.Stats
 	for _, bucket := range result.Aggregations.Hosts.Buckets {
-		if len(bucket.Aggregations.Latest.Hits.Hits) > 0 {
-			d := bucket.Aggregations.Latest.Hits.Hits[0].Source
+		if len(bucket.Latest.Hits.Hits) > 0 {
+			d := bucket.Latest.Hits.Hits[0].Source
 			allStats = append(allStats, d.toEntity())
 		}
 	}
@@ -338,6 +602,128 @@ func (r *OpenSearchStatsRepository) GetActiveHosts(ctx context.Context) ([]strin
 	return hosts, nil
 }
 
+// ReassignAgent moves the samples of fromAgentID to toAgentID
+func (r *OpenSearchStatsRepository) ReassignAgent(ctx context.Context, fromAgentID, toAgentID string) (int64, error) {
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"term": map[string]interface{}{
+				"agent_id": fromAgentID,
+			},
+		},
+		"script": map[string]interface{}{
+			"source": "ctx._source.agent_id = params.agent_id",
+			"lang":   "painless",
+			"params": map[string]interface{}{
+				"agent_id": toAgentID,
+			},
+		},
+	}
+
+	body, err := json.Marshal(query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal query: %w", err)
+	}
+
+	refresh := true
+	req := opensearchapi.UpdateByQueryRequest{
+		Index:     []string{StatsIndex},
+		Body:      bytes.NewReader(body),
+		Conflicts: "proceed",
+		Refresh:   &refresh,
+	}
+
+	resp, err := req.Do(ctx, r.client.Client)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reassign stats: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to reassign stats: status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Updated int64 `json:"updated"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Updated, nil
+}
+
+// GetHistory retrieves samples for an agent between from and to, oldest first
+func (r *OpenSearchStatsRepository) GetHistory(ctx context.Context, agentID string, from, to time.Time) ([]*entity.Stats, error) {
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": []map[string]interface{}{
+					{
+						"term": map[string]interface{}{
+							"agent_id": agentID,
+						},
+					},
+					{
+						"range": map[string]interface{}{
+							"timestamp": map[string]interface{}{
+								"gte": from.UnixMilli(),
+								"lte": to.UnixMilli(),
+							},
+						},
+					},
+				},
+			},
+		},
+		"sort": []map[string]interface{}{
+			{
+				"timestamp": map[string]interface{}{
+					"order": "asc",
+				},
+			},
+		},
+		"size": 10000,
+	}
+
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal query: %w", err)
+	}
+
+	req := opensearchapi.SearchRequest{
+		Index: []string{StatsIndex},
+		Body:  bytes.NewReader(body),
+	}
+
+	resp, err := req.Do(ctx, r.client.Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search stats history: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to search stats history: status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Hits struct {
+			Hits []struct {
+				Source statsDoc `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	history := make([]*entity.Stats, 0, len(result.Hits.Hits))
+	for i := range result.Hits.Hits {
+		history = append(history, result.Hits.Hits[i].Source.toEntity())
+	}
+
+	return history, nil
+}
+
 // SearchStats performs full-text search on stats
 func (r *OpenSearchStatsRepository) SearchStats(ctx context.Context, query string, hostname string, limit int) ([]*entity.Stats, error) {
 	searchQuery := map[string]interface{}{
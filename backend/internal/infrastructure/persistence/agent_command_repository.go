@@ -0,0 +1,101 @@
+// Package persistence implements repository interfaces
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/repository"
+	"sort"
+	"sync"
+)
+
+// InMemoryAgentCommandRepository implements AgentCommandRepository with in-memory storage
+type InMemoryAgentCommandRepository struct {
+	mu       sync.RWMutex
+	commands map[string]*entity.AgentCommand // key: commandID
+}
+
+// NewInMemoryAgentCommandRepository creates a new in-memory agent command repository
+func NewInMemoryAgentCommandRepository() repository.AgentCommandRepository {
+	return &InMemoryAgentCommandRepository{
+		commands: make(map[string]*entity.AgentCommand),
+	}
+}
+
+// Create stores a new command
+func (r *InMemoryAgentCommandRepository) Create(ctx context.Context, cmd *entity.AgentCommand) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.commands[cmd.CommandID]; exists {
+		return fmt.Errorf("command already exists: %s", cmd.CommandID)
+	}
+
+	r.commands[cmd.CommandID] = cmd
+	return nil
+}
+
+// GetByID retrieves a command by ID
+func (r *InMemoryAgentCommandRepository) GetByID(ctx context.Context, commandID string) (*entity.AgentCommand, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cmd, exists := r.commands[commandID]
+	if !exists {
+		return nil, fmt.Errorf("command not found: %s", commandID)
+	}
+
+	return cmd, nil
+}
+
+// Update updates a command
+func (r *InMemoryAgentCommandRepository) Update(ctx context.Context, cmd *entity.AgentCommand) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.commands[cmd.CommandID]; !exists {
+		return fmt.Errorf("command not found: %s", cmd.CommandID)
+	}
+
+	r.commands[cmd.CommandID] = cmd
+	return nil
+}
+
+// ListByAgent retrieves the commands of an agent, newest first
+func (r *InMemoryAgentCommandRepository) ListByAgent(ctx context.Context, agentID string) ([]*entity.AgentCommand, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entity.AgentCommand, 0)
+	for _, cmd := range r.commands {
+		if cmd.AgentID == agentID {
+			result = append(result, cmd)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+
+	return result, nil
+}
+
+// ListPending retrieves the commands not yet received by an agent, oldest first
+func (r *InMemoryAgentCommandRepository) ListPending(ctx context.Context, agentID string) ([]*entity.AgentCommand, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entity.AgentCommand, 0)
+	for _, cmd := range r.commands {
+		if cmd.AgentID == agentID && cmd.Status == entity.CommandStatusPending {
+			result = append(result, cmd)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	return result, nil
+}
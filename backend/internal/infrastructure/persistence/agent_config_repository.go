@@ -0,0 +1,94 @@
+// Package persistence implements repository interfaces
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/repository"
+	"sort"
+	"sync"
+)
+
+// InMemoryAgentConfigRepository implements AgentConfigRepository with in-memory storage.
+// Profiles are copied in and out so callers cannot change stored versions.
+type InMemoryAgentConfigRepository struct {
+	mu       sync.RWMutex
+	profiles map[string]*entity.AgentConfigProfile // key: profileID
+}
+
+// NewInMemoryAgentConfigRepository creates a new in-memory agent config repository
+func NewInMemoryAgentConfigRepository() repository.AgentConfigRepository {
+	return &InMemoryAgentConfigRepository{
+		profiles: make(map[string]*entity.AgentConfigProfile),
+	}
+}
+
+// Create stores a new profile
+func (r *InMemoryAgentConfigRepository) Create(ctx context.Context, profile *entity.AgentConfigProfile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.profiles[profile.ProfileID]; exists {
+		return fmt.Errorf("config profile already exists: %s", profile.ProfileID)
+	}
+
+	r.profiles[profile.ProfileID] = profile.Clone()
+	return nil
+}
+
+// GetByID retrieves a profile by ID
+func (r *InMemoryAgentConfigRepository) GetByID(ctx context.Context, profileID string) (*entity.AgentConfigProfile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	profile, exists := r.profiles[profileID]
+	if !exists {
+		return nil, fmt.Errorf("config profile not found: %s", profileID)
+	}
+
+	return profile.Clone(), nil
+}
+
+// Update replaces a stored profile
+func (r *InMemoryAgentConfigRepository) Update(ctx context.Context, profile *entity.AgentConfigProfile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.profiles[profile.ProfileID]; !exists {
+		return fmt.Errorf("config profile not found: %s", profile.ProfileID)
+	}
+
+	r.profiles[profile.ProfileID] = profile.Clone()
+	return nil
+}
+
+// Delete removes a profile
+func (r *InMemoryAgentConfigRepository) Delete(ctx context.Context, profileID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.profiles[profileID]; !exists {
+		return fmt.Errorf("config profile not found: %s", profileID)
+	}
+
+	delete(r.profiles, profileID)
+	return nil
+}
+
+// GetAll retrieves all profiles ordered by ID
+func (r *InMemoryAgentConfigRepository) GetAll(ctx context.Context) ([]*entity.AgentConfigProfile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entity.AgentConfigProfile, 0, len(r.profiles))
+	for _, profile := range r.profiles {
+		result = append(result, profile.Clone())
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ProfileID < result[j].ProfileID
+	})
+
+	return result, nil
+}
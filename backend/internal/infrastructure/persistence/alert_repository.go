@@ -0,0 +1,77 @@
+// Package persistence implements repository interfaces
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/repository"
+	"sort"
+	"sync"
+	"time"
+)
+
+// InMemoryAlertRepository implements AlertRepository with in-memory storage
+type InMemoryAlertRepository struct {
+	mu     sync.RWMutex
+	alerts map[string]*entity.Alert
+}
+
+// NewInMemoryAlertRepository creates a new in-memory alert repository
+func NewInMemoryAlertRepository() repository.AlertRepository {
+	return &InMemoryAlertRepository{
+		alerts: make(map[string]*entity.Alert),
+	}
+}
+
+// Create stores a new alert
+func (r *InMemoryAlertRepository) Create(ctx context.Context, alert *entity.Alert) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if alert.ID == "" {
+		alert.ID = fmt.Sprintf("%s-%d", alert.Hostname, time.Now().UnixNano())
+	}
+	if _, exists := r.alerts[alert.ID]; exists {
+		return "", fmt.Errorf("alert already exists: %s", alert.ID)
+	}
+
+	r.alerts[alert.ID] = alert
+	return alert.ID, nil
+}
+
+// Resolve marks an alert as resolved
+func (r *InMemoryAlertRepository) Resolve(ctx context.Context, alertID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	alert, exists := r.alerts[alertID]
+	if !exists {
+		return fmt.Errorf("alert not found: %s", alertID)
+	}
+
+	alert.Resolve()
+	return nil
+}
+
+// ListActive retrieves the unresolved alerts matching a filter, oldest first
+func (r *InMemoryAlertRepository) ListActive(ctx context.Context, filter repository.AlertFilter) ([]*entity.Alert, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var alerts []*entity.Alert
+	for _, alert := range r.alerts {
+		if !alert.IsActive() ||
+			(filter.AgentID != "" && alert.AgentID != filter.AgentID) ||
+			(filter.PolicyID != "" && alert.PolicyID != filter.PolicyID) ||
+			(filter.AlertType != "" && alert.AlertType != filter.AlertType) {
+			continue
+		}
+		alerts = append(alerts, alert)
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].CreatedAt.Before(alerts[j].CreatedAt)
+	})
+
+	return alerts, nil
+}
@@ -0,0 +1,94 @@
+// Package persistence implements repository interfaces
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/repository"
+	"sort"
+	"sync"
+)
+
+// InMemoryEnrollmentTokenRepository implements EnrollmentTokenRepository with in-memory storage.
+// Tokens are copied in and out so callers cannot change stored tokens.
+type InMemoryEnrollmentTokenRepository struct {
+	mu     sync.RWMutex
+	tokens map[string]*entity.EnrollmentToken // key: tokenID
+}
+
+// NewInMemoryEnrollmentTokenRepository creates a new in-memory enrollment token repository
+func NewInMemoryEnrollmentTokenRepository() repository.EnrollmentTokenRepository {
+	return &InMemoryEnrollmentTokenRepository{
+		tokens: make(map[string]*entity.EnrollmentToken),
+	}
+}
+
+// Create stores a new token
+func (r *InMemoryEnrollmentTokenRepository) Create(ctx context.Context, token *entity.EnrollmentToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.tokens[token.TokenID]; exists {
+		return fmt.Errorf("enrollment token already exists: %s", token.TokenID)
+	}
+
+	r.tokens[token.TokenID] = token.Clone()
+	return nil
+}
+
+// GetByID retrieves a token by ID
+func (r *InMemoryEnrollmentTokenRepository) GetByID(ctx context.Context, tokenID string) (*entity.EnrollmentToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	token, exists := r.tokens[tokenID]
+	if !exists {
+		return nil, fmt.Errorf("enrollment token not found: %s", tokenID)
+	}
+
+	return token.Clone(), nil
+}
+
+// Update replaces a stored token
+func (r *InMemoryEnrollmentTokenRepository) Update(ctx context.Context, token *entity.EnrollmentToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.tokens[token.TokenID]; !exists {
+		return fmt.Errorf("enrollment token not found: %s", token.TokenID)
+	}
+
+	r.tokens[token.TokenID] = token.Clone()
+	return nil
+}
+
+// Delete removes a token
+func (r *InMemoryEnrollmentTokenRepository) Delete(ctx context.Context, tokenID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.tokens[tokenID]; !exists {
+		return fmt.Errorf("enrollment token not found: %s", tokenID)
+	}
+
+	delete(r.tokens, tokenID)
+	return nil
+}
+
+// GetAll retrieves all tokens ordered by ID
+func (r *InMemoryEnrollmentTokenRepository) GetAll(ctx context.Context) ([]*entity.EnrollmentToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entity.EnrollmentToken, 0, len(r.tokens))
+	for _, token := range r.tokens {
+		result = append(result, token.Clone())
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TokenID < result[j].TokenID
+	})
+
+	return result, nil
+}
@@ -0,0 +1,40 @@
+// Package persistence implements repository interfaces
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/repository"
+	"sync"
+)
+
+// maxInMemoryEvents bounds the events kept in memory; the oldest are dropped first
+const maxInMemoryEvents = 10000
+
+// InMemoryEventRepository implements EventRepository with in-memory storage
+type InMemoryEventRepository struct {
+	mu     sync.Mutex
+	events []*entity.Event
+}
+
+// NewInMemoryEventRepository creates a new in-memory event repository
+func NewInMemoryEventRepository() repository.EventRepository {
+	return &InMemoryEventRepository{}
+}
+
+// Log stores an event
+func (r *InMemoryEventRepository) Log(ctx context.Context, event *entity.Event) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if event.ID == "" {
+		event.ID = fmt.Sprintf("%s-%d", event.Hostname, event.Timestamp.UnixNano())
+	}
+
+	if len(r.events) >= maxInMemoryEvents {
+		r.events = r.events[1:]
+	}
+	r.events = append(r.events, event)
+	return event.ID, nil
+}
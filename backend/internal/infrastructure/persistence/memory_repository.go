@@ -4,33 +4,67 @@ package persistence
 import (
 	"context"
 	"fmt"
+	"slices"
 	"smart-monitor/backend/internal/domain/entity"
 	"smart-monitor/backend/internal/domain/repository"
 	"sync"
+	"time"
 )
 
-// InMemoryStatsRepository implements StatsRepository with in-memory storage
+// InMemoryStatsRepository implements StatsRepository with in-memory storage.
+// Besides the latest sample of each host, it keeps the range metrics of the
+// most recent samples in a ring per host for range queries.
 type InMemoryStatsRepository struct {
-	mu    sync.RWMutex
-	stats map[string]*entity.Stats
+	mu          sync.RWMutex
+	stats       map[string]*entity.Stats
+	history     map[string]*statsRing
+	historySize int
 }
 
-// NewInMemoryStatsRepository creates a new in-memory stats repository
-func NewInMemoryStatsRepository() repository.StatsRepository {
+// NewInMemoryStatsRepository creates a new in-memory stats repository that
+// keeps up to historySize samples per host for range queries
+func NewInMemoryStatsRepository(historySize int) repository.StatsRepository {
 	return &InMemoryStatsRepository{
-		stats: make(map[string]*entity.Stats),
+		stats:       make(map[string]*entity.Stats),
+		history:     make(map[string]*statsRing),
+		historySize: historySize,
 	}
 }
 
-// Save stores stats
+// Save stores stats. Only the latest sample per host is returned by Get, so a
+// backfilled sample older than the stored one only goes to the history.
 func (r *InMemoryStatsRepository) Save(ctx context.Context, stats *entity.Stats) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	r.record(stats)
+	if current, exists := r.stats[stats.Hostname]; exists && stats.Timestamp.Before(current.Timestamp) {
+		return nil
+	}
 	r.stats[stats.Hostname] = stats
 	return nil
 }
 
+// record adds a sample to the history of its host
+func (r *InMemoryStatsRepository) record(stats *entity.Stats) {
+	if r.historySize <= 0 {
+		return
+	}
+
+	ring, exists := r.history[stats.Hostname]
+	if !exists {
+		ring = &statsRing{points: make([]statsPoint, 0, min(r.historySize, 1024)), size: r.historySize}
+		r.history[stats.Hostname] = ring
+	}
+
+	metrics := entity.RangeMetrics()
+	point := statsPoint{agentID: stats.AgentID, timestamp: stats.Timestamp, values: make([]float64, len(metrics))}
+	for i, metric := range metrics {
+		point.values[i], _ = stats.MetricValue(metric)
+	}
+	ring.add(point)
+}
+
 // Get retrieves stats by hostname
 func (r *InMemoryStatsRepository) Get(ctx context.Context, hostname string) (*entity.Stats, error) {
 	r.mu.RLock()
@@ -38,12 +72,34 @@ func (r *InMemoryStatsRepository) Get(ctx context.Context, hostname string) (*en
 
 	stats, exists := r.stats[hostname]
 	if !exists {
-		return nil, fmt.Errorf("stats not found for hostname: %s", hostname)
+		return nil, fmt.Errorf("%w for hostname: %s", repository.ErrStatsNotFound, hostname)
 	}
 
 	return stats, nil
 }
 
+// GetByAgent retrieves the latest stats of the host an agent reported most
+// recently
+func (r *InMemoryStatsRepository) GetByAgent(ctx context.Context, agentID string) (*entity.Stats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var latest *entity.Stats
+	for _, stats := range r.stats {
+		if stats.AgentID != agentID {
+			continue
+		}
+		if latest == nil || stats.Timestamp.After(latest.Timestamp) {
+			latest = stats
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("%w for agent: %s", repository.ErrStatsNotFound, agentID)
+	}
+
+	return latest, nil
+}
+
 // GetAll retrieves all stats
 func (r *InMemoryStatsRepository) GetAll(ctx context.Context) ([]*entity.Stats, error) {
 	r.mu.RLock()
@@ -63,6 +119,7 @@ func (r *InMemoryStatsRepository) Delete(ctx context.Context, hostname string) e
 	defer r.mu.Unlock()
 
 	delete(r.stats, hostname)
+	delete(r.history, hostname)
 	return nil
 }
 
@@ -79,7 +136,95 @@ func (r *InMemoryStatsRepository) GetActiveHosts(ctx context.Context) ([]string,
 	return hosts, nil
 }
 
-// InMemoryHostRepository implements HostRepository with in-memory storage
+// ReassignAgent moves the stored samples of fromAgentID to toAgentID
+func (r *InMemoryStatsRepository) ReassignAgent(ctx context.Context, fromAgentID, toAgentID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var moved int64
+	for hostname, stats := range r.stats {
+		if stats.AgentID == fromAgentID {
+			reassigned := *stats
+			reassigned.AgentID = toAgentID
+			r.stats[hostname] = &reassigned
+			moved++
+		}
+	}
+	for _, ring := range r.history {
+		for i := range ring.points {
+			if ring.points[i].agentID == fromAgentID {
+				ring.points[i].agentID = toAgentID
+			}
+		}
+	}
+
+	return moved, nil
+}
+
+// QueryRange aggregates the samples kept in the history
+func (r *InMemoryStatsRepository) QueryRange(ctx context.Context, query *entity.StatsRangeQuery) ([]entity.StatsSeries, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rings := make([]*statsRing, 0, 1)
+	if query.Hostname != "" {
+		if ring, exists := r.history[query.Hostname]; exists {
+			rings = append(rings, ring)
+		}
+	} else {
+		for _, ring := range r.history {
+			rings = append(rings, ring)
+		}
+	}
+
+	metrics := entity.RangeMetrics()
+	builder := entity.NewStatsRangeBuilder(query)
+	for _, ring := range rings {
+		for i := range ring.points {
+			point := &ring.points[i]
+			if query.AgentID != "" && point.agentID != query.AgentID {
+				continue
+			}
+			builder.Add(point.timestamp, func(metric string) (float64, bool) {
+				index := slices.Index(metrics, metric)
+				if index < 0 {
+					return 0, false
+				}
+				return point.values[index], true
+			})
+		}
+	}
+
+	return builder.Series(), nil
+}
+
+// statsPoint holds the range metrics of one sample, in entity.RangeMetrics order
+type statsPoint struct {
+	agentID   string
+	timestamp time.Time
+	values    []float64
+}
+
+// statsRing keeps the most recent samples of a host, overwriting the oldest
+// once full
+type statsRing struct {
+	points []statsPoint
+	size   int
+	next   int // where the next point goes once full
+}
+
+// add appends a point, replacing the oldest one if the ring is full
+func (r *statsRing) add(point statsPoint) {
+	if len(r.points) < r.size {
+		r.points = append(r.points, point)
+		return
+	}
+	r.points[r.next] = point
+	r.next = (r.next + 1) % r.size
+}
+
+// InMemoryHostRepository implements HostRepository with in-memory storage.
+// Hosts are copied in and out so concurrent writers do not share them.
 type InMemoryHostRepository struct {
 	mu    sync.RWMutex
 	hosts map[string]*entity.Host
@@ -97,7 +242,7 @@ func (r *InMemoryHostRepository) Create(ctx context.Context, host *entity.Host)
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.hosts[host.Hostname] = host
+	r.hosts[host.Hostname] = host.Clone()
 	return nil
 }
 
@@ -111,7 +256,7 @@ func (r *InMemoryHostRepository) Get(ctx context.Context, hostname string) (*ent
 		return nil, fmt.Errorf("host not found: %s", hostname)
 	}
 
-	return host, nil
+	return host.Clone(), nil
 }
 
 // GetAll retrieves all hosts
@@ -121,7 +266,7 @@ func (r *InMemoryHostRepository) GetAll(ctx context.Context) ([]*entity.Host, er
 
 	result := make([]*entity.Host, 0, len(r.hosts))
 	for _, host := range r.hosts {
-		result = append(result, host)
+		result = append(result, host.Clone())
 	}
 
 	return result, nil
@@ -132,7 +277,7 @@ func (r *InMemoryHostRepository) Update(ctx context.Context, host *entity.Host)
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.hosts[host.Hostname] = host
+	r.hosts[host.Hostname] = host.Clone()
 	return nil
 }
 
@@ -150,6 +295,7 @@ type InMemoryAgentRegistryRepository struct {
 	mu         sync.RWMutex
 	agents     map[string]*entity.AgentRegistry // key: agentID
 	tokenIndex map[string]string                // key: token, value: agentID
+	tokens     map[string]string                // key: agentID, value: indexed token
 }
 
 // NewInMemoryAgentRegistryRepository creates a new in-memory agent registry repository
@@ -157,6 +303,7 @@ func NewInMemoryAgentRegistryRepository() repository.AgentRegistryRepository {
 	return &InMemoryAgentRegistryRepository{
 		agents:     make(map[string]*entity.AgentRegistry),
 		tokenIndex: make(map[string]string),
+		tokens:     make(map[string]string),
 	}
 }
 
@@ -166,7 +313,7 @@ func (r *InMemoryAgentRegistryRepository) Register(ctx context.Context, agent *e
 	defer r.mu.Unlock()
 
 	r.agents[agent.AgentID] = agent
-	r.tokenIndex[agent.AccessToken] = agent.AgentID
+	r.indexToken(agent)
 	return nil
 }
 
@@ -201,20 +348,27 @@ func (r *InMemoryAgentRegistryRepository) GetByToken(ctx context.Context, token
 	return agent, nil
 }
 
+// GetByFingerprint retrieves an agent by machine fingerprint
+func (r *InMemoryAgentRegistryRepository) GetByFingerprint(ctx context.Context, fingerprint string) (*entity.AgentRegistry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, agent := range r.agents {
+		if fingerprint != "" && agent.Fingerprint == fingerprint {
+			return agent, nil
+		}
+	}
+
+	return nil, fmt.Errorf("agent not found for fingerprint")
+}
+
 // Update updates an agent registry
 func (r *InMemoryAgentRegistryRepository) Update(ctx context.Context, agent *entity.AgentRegistry) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	// Update token index if token changed
-	if oldAgent, exists := r.agents[agent.AgentID]; exists {
-		if oldAgent.AccessToken != agent.AccessToken {
-			delete(r.tokenIndex, oldAgent.AccessToken)
-			r.tokenIndex[agent.AccessToken] = agent.AgentID
-		}
-	}
-
 	r.agents[agent.AgentID] = agent
+	r.indexToken(agent)
 	return nil
 }
 
@@ -223,13 +377,30 @@ func (r *InMemoryAgentRegistryRepository) Delete(ctx context.Context, agentID st
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if agent, exists := r.agents[agentID]; exists {
-		delete(r.tokenIndex, agent.AccessToken)
-	}
+	delete(r.tokenIndex, r.tokens[agentID])
+	delete(r.tokens, agentID)
 	delete(r.agents, agentID)
 	return nil
 }
 
+// indexToken points the token index at the agent's current token. Callers
+// may have changed the token on the stored entry itself, so the previously
+// indexed token is tracked separately.
+func (r *InMemoryAgentRegistryRepository) indexToken(agent *entity.AgentRegistry) {
+	if old, exists := r.tokens[agent.AgentID]; exists {
+		if old == agent.AccessToken {
+			return
+		}
+		delete(r.tokenIndex, old)
+	}
+	if agent.AccessToken == "" {
+		delete(r.tokens, agent.AgentID)
+		return
+	}
+	r.tokenIndex[agent.AccessToken] = agent.AgentID
+	r.tokens[agent.AgentID] = agent.AccessToken
+}
+
 // GetAll retrieves all registered agents
 func (r *InMemoryAgentRegistryRepository) GetAll(ctx context.Context) ([]*entity.AgentRegistry, error) {
 	r.mu.RLock()
@@ -0,0 +1,120 @@
+// Package sqlstore provides SQLite and PostgreSQL repositories
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"smart-monitor/backend/internal/domain/entity"
+)
+
+const agentCommandColumns = `command_id, agent_id, action, reason, status, message, created_at, updated_at`
+
+// SQLAgentCommandRepository implements AgentCommandRepository with a SQL database
+type SQLAgentCommandRepository struct {
+	db *DB
+}
+
+// NewSQLAgentCommandRepository creates a new SQL agent command repository
+func NewSQLAgentCommandRepository(db *DB) *SQLAgentCommandRepository {
+	return &SQLAgentCommandRepository{db: db}
+}
+
+// Create stores a new command
+func (r *SQLAgentCommandRepository) Create(ctx context.Context, cmd *entity.AgentCommand) error {
+	result, err := r.db.exec(ctx, r.db.db, `INSERT INTO agent_commands (`+agentCommandColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (command_id) DO NOTHING`,
+		cmd.CommandID, cmd.AgentID, string(cmd.Action), cmd.Reason, string(cmd.Status), cmd.Message,
+		toUnixNano(cmd.CreatedAt), toUnixNano(cmd.UpdatedAt))
+	if err != nil {
+		return fmt.Errorf("failed to create command: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("command already exists: %s", cmd.CommandID)
+	}
+	return nil
+}
+
+// GetByID retrieves a command by ID
+func (r *SQLAgentCommandRepository) GetByID(ctx context.Context, commandID string) (*entity.AgentCommand, error) {
+	cmd, err := scanAgentCommand(r.db.queryRow(ctx, r.db.db, `SELECT `+agentCommandColumns+` FROM agent_commands WHERE command_id = ?`, commandID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("command not found: %s", commandID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get command: %w", err)
+	}
+
+	return cmd, nil
+}
+
+// Update updates a command
+func (r *SQLAgentCommandRepository) Update(ctx context.Context, cmd *entity.AgentCommand) error {
+	result, err := r.db.exec(ctx, r.db.db, `UPDATE agent_commands SET
+			agent_id = ?, action = ?, reason = ?, status = ?, message = ?, created_at = ?, updated_at = ?
+		WHERE command_id = ?`,
+		cmd.AgentID, string(cmd.Action), cmd.Reason, string(cmd.Status), cmd.Message,
+		toUnixNano(cmd.CreatedAt), toUnixNano(cmd.UpdatedAt), cmd.CommandID)
+	if err != nil {
+		return fmt.Errorf("failed to update command: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("command not found: %s", cmd.CommandID)
+	}
+	return nil
+}
+
+// ListByAgent retrieves the commands of an agent, newest first
+func (r *SQLAgentCommandRepository) ListByAgent(ctx context.Context, agentID string) ([]*entity.AgentCommand, error) {
+	return r.list(ctx, `SELECT `+agentCommandColumns+` FROM agent_commands
+		WHERE agent_id = ? ORDER BY created_at DESC, command_id`, agentID)
+}
+
+// ListPending retrieves the commands not yet received by an agent, oldest first
+func (r *SQLAgentCommandRepository) ListPending(ctx context.Context, agentID string) ([]*entity.AgentCommand, error) {
+	return r.list(ctx, `SELECT `+agentCommandColumns+` FROM agent_commands
+		WHERE agent_id = ? AND status = ? ORDER BY created_at, command_id`, agentID, string(entity.CommandStatusPending))
+}
+
+// list runs a query returning commands
+func (r *SQLAgentCommandRepository) list(ctx context.Context, query string, args ...any) ([]*entity.AgentCommand, error) {
+	rows, err := r.db.query(ctx, r.db.db, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commands: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*entity.AgentCommand, 0)
+	for rows.Next() {
+		cmd, err := scanAgentCommand(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read command: %w", err)
+		}
+		result = append(result, cmd)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list commands: %w", err)
+	}
+
+	return result, nil
+}
+
+// scanAgentCommand reads a row of agentCommandColumns
+func scanAgentCommand(row scanner) (*entity.AgentCommand, error) {
+	var cmd entity.AgentCommand
+	var action, status string
+	var createdAt, updatedAt int64
+
+	if err := row.Scan(&cmd.CommandID, &cmd.AgentID, &action, &cmd.Reason, &status, &cmd.Message, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+
+	cmd.Action = entity.AgentControlAction(action)
+	cmd.Status = entity.CommandStatus(status)
+	cmd.CreatedAt = fromUnixNano(createdAt)
+	cmd.UpdatedAt = fromUnixNano(updatedAt)
+	return &cmd, nil
+}
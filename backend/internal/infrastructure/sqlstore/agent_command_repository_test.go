@@ -0,0 +1,102 @@
+package sqlstore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"smart-monitor/backend/internal/domain/entity"
+)
+
+// testCommand returns a command with every field set, created offset after testTime(0)
+func testCommand(commandID, agentID string, status entity.CommandStatus, offset time.Duration) *entity.AgentCommand {
+	return &entity.AgentCommand{
+		CommandID: commandID,
+		AgentID:   agentID,
+		Action:    entity.AgentActionRestart,
+		Reason:    "upgrade",
+		Status:    status,
+		Message:   "message of " + commandID,
+		CreatedAt: testTime(offset),
+		UpdatedAt: testTime(offset + time.Second),
+	}
+}
+
+// commandIDs returns the IDs of commands in order
+func commandIDs(commands []*entity.AgentCommand) []string {
+	ids := make([]string, len(commands))
+	for i, cmd := range commands {
+		ids[i] = cmd.CommandID
+	}
+	return ids
+}
+
+func TestSQLAgentCommandRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewSQLAgentCommandRepository(openTestDB(t))
+
+	commands := []*entity.AgentCommand{
+		testCommand("cmd-1", "agent-a", entity.CommandStatusExecuted, 0),
+		testCommand("cmd-2", "agent-a", entity.CommandStatusPending, 2*time.Minute),
+		testCommand("cmd-3", "agent-a", entity.CommandStatusPending, time.Minute),
+		testCommand("cmd-4", "agent-b", entity.CommandStatusPending, 0),
+	}
+	for _, cmd := range commands {
+		if err := repo.Create(ctx, cmd); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	if err := repo.Create(ctx, commands[0]); err == nil {
+		t.Error("Create() of an existing command succeeded")
+	}
+
+	got, err := repo.GetByID(ctx, "cmd-1")
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, commands[0]) {
+		t.Errorf("GetByID() = %+v, want %+v", got, commands[0])
+	}
+	if _, err := repo.GetByID(ctx, "cmd-missing"); err == nil {
+		t.Error("GetByID() of a missing command succeeded")
+	}
+
+	tests := []struct {
+		name string
+		list func(ctx context.Context, agentID string) ([]*entity.AgentCommand, error)
+		want []string
+	}{
+		{name: "by agent newest first", list: repo.ListByAgent, want: []string{"cmd-2", "cmd-3", "cmd-1"}},
+		{name: "pending oldest first", list: repo.ListPending, want: []string{"cmd-3", "cmd-2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.list(ctx, "agent-a")
+			if err != nil {
+				t.Fatalf("list error = %v", err)
+			}
+			if ids := commandIDs(got); !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("list = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+
+	cmd := commands[2]
+	if err := cmd.Acknowledge(entity.CommandStatusDelivered, "received"); err != nil {
+		t.Fatalf("Acknowledge() error = %v", err)
+	}
+	cmd.UpdatedAt = cmd.UpdatedAt.Round(0)
+	if err := repo.Update(ctx, cmd); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if got, err := repo.GetByID(ctx, cmd.CommandID); err != nil || !reflect.DeepEqual(got, cmd) {
+		t.Errorf("GetByID() after Update() = %+v, %v, want %+v", got, err, cmd)
+	}
+	if pending, err := repo.ListPending(ctx, "agent-a"); err != nil || !reflect.DeepEqual(commandIDs(pending), []string{"cmd-2"}) {
+		t.Errorf("ListPending() after delivery = %v, %v, want [cmd-2]", commandIDs(pending), err)
+	}
+	if err := repo.Update(ctx, testCommand("cmd-missing", "agent-a", entity.CommandStatusPending, 0)); err == nil {
+		t.Error("Update() of a missing command succeeded")
+	}
+}
@@ -0,0 +1,161 @@
+// Package sqlstore provides SQLite and PostgreSQL repositories
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"smart-monitor/backend/internal/domain/entity"
+)
+
+const agentConfigColumns = `profile_id, name, description, version, settings, agent_ids, selector,
+	created_at, updated_at`
+
+// SQLAgentConfigRepository implements AgentConfigRepository with a SQL database
+type SQLAgentConfigRepository struct {
+	db *DB
+}
+
+// NewSQLAgentConfigRepository creates a new SQL agent config repository
+func NewSQLAgentConfigRepository(db *DB) *SQLAgentConfigRepository {
+	return &SQLAgentConfigRepository{db: db}
+}
+
+// Create stores a new profile
+func (r *SQLAgentConfigRepository) Create(ctx context.Context, profile *entity.AgentConfigProfile) error {
+	args, err := agentConfigArgs(profile)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.exec(ctx, r.db.db, `INSERT INTO agent_config_profiles (`+agentConfigColumns+`)
+		VALUES (`+placeholders(len(args))+`)
+		ON CONFLICT (profile_id) DO NOTHING`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to create config profile: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("config profile already exists: %s", profile.ProfileID)
+	}
+	return nil
+}
+
+// GetByID retrieves a profile by ID
+func (r *SQLAgentConfigRepository) GetByID(ctx context.Context, profileID string) (*entity.AgentConfigProfile, error) {
+	profile, err := scanAgentConfig(r.db.queryRow(ctx, r.db.db, `SELECT `+agentConfigColumns+` FROM agent_config_profiles WHERE profile_id = ?`, profileID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("config profile not found: %s", profileID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config profile: %w", err)
+	}
+
+	return profile, nil
+}
+
+// Update replaces a stored profile
+func (r *SQLAgentConfigRepository) Update(ctx context.Context, profile *entity.AgentConfigProfile) error {
+	args, err := agentConfigArgs(profile)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.exec(ctx, r.db.db, `UPDATE agent_config_profiles SET
+			name = ?, description = ?, version = ?, settings = ?, agent_ids = ?, selector = ?,
+			created_at = ?, updated_at = ?
+		WHERE profile_id = ?`, append(args[1:], profile.ProfileID)...)
+	if err != nil {
+		return fmt.Errorf("failed to update config profile: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("config profile not found: %s", profile.ProfileID)
+	}
+	return nil
+}
+
+// Delete removes a profile
+func (r *SQLAgentConfigRepository) Delete(ctx context.Context, profileID string) error {
+	result, err := r.db.exec(ctx, r.db.db, `DELETE FROM agent_config_profiles WHERE profile_id = ?`, profileID)
+	if err != nil {
+		return fmt.Errorf("failed to delete config profile: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("config profile not found: %s", profileID)
+	}
+	return nil
+}
+
+// GetAll retrieves all profiles ordered by ID
+func (r *SQLAgentConfigRepository) GetAll(ctx context.Context) ([]*entity.AgentConfigProfile, error) {
+	rows, err := r.db.query(ctx, r.db.db, `SELECT `+agentConfigColumns+` FROM agent_config_profiles ORDER BY profile_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list config profiles: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*entity.AgentConfigProfile, 0)
+	for rows.Next() {
+		profile, err := scanAgentConfig(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config profile: %w", err)
+		}
+		result = append(result, profile)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list config profiles: %w", err)
+	}
+
+	return result, nil
+}
+
+// agentConfigArgs returns the column values of a profile in agentConfigColumns order
+func agentConfigArgs(profile *entity.AgentConfigProfile) ([]any, error) {
+	settings, err := toJSON(profile.Settings)
+	if err != nil {
+		return nil, err
+	}
+	agentIDs, err := toJSON(profile.AgentIDs)
+	if err != nil {
+		return nil, err
+	}
+	selector, err := toJSON(profile.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	return []any{
+		profile.ProfileID, profile.Name, profile.Description, profile.Version, settings, agentIDs, selector,
+		toUnixNano(profile.CreatedAt), toUnixNano(profile.UpdatedAt),
+	}, nil
+}
+
+// scanAgentConfig reads a row of agentConfigColumns
+func scanAgentConfig(row scanner) (*entity.AgentConfigProfile, error) {
+	var profile entity.AgentConfigProfile
+	var settings, agentIDs, selector string
+	var createdAt, updatedAt int64
+
+	err := row.Scan(
+		&profile.ProfileID, &profile.Name, &profile.Description, &profile.Version, &settings, &agentIDs, &selector,
+		&createdAt, &updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	profile.CreatedAt = fromUnixNano(createdAt)
+	profile.UpdatedAt = fromUnixNano(updatedAt)
+	if err := fromJSON(settings, &profile.Settings); err != nil {
+		return nil, err
+	}
+	if err := fromJSON(agentIDs, &profile.AgentIDs); err != nil {
+		return nil, err
+	}
+	if err := fromJSON(selector, &profile.Selector); err != nil {
+		return nil, err
+	}
+
+	return &profile, nil
+}
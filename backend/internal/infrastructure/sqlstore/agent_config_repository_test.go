@@ -0,0 +1,95 @@
+package sqlstore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"smart-monitor/backend/internal/domain/entity"
+)
+
+// testAgentConfig returns a profile with every field set
+func testAgentConfig(profileID string) *entity.AgentConfigProfile {
+	return &entity.AgentConfigProfile{
+		ProfileID:   profileID,
+		Name:        "web",
+		Description: "web servers",
+		Version:     3,
+		Settings: entity.AgentSettings{
+			MetricsInterval: 10 * time.Second,
+			Metadata:        map[string]string{"tier": "frontend"},
+			Collectors: map[string]entity.CollectorSettings{
+				"docker": {Enabled: true, Interval: time.Minute},
+				"gpu":    {},
+			},
+		},
+		AgentIDs:  []string{"agent-b", "agent-a"},
+		Selector:  map[string]string{"role": "web"},
+		CreatedAt: testTime(0),
+		UpdatedAt: testTime(time.Minute),
+	}
+}
+
+func TestSQLAgentConfigRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewSQLAgentConfigRepository(openTestDB(t))
+
+	tests := []struct {
+		name    string
+		profile *entity.AgentConfigProfile
+	}{
+		{name: "all fields", profile: testAgentConfig("cfg-2")},
+		{name: "empty settings", profile: &entity.AgentConfigProfile{ProfileID: "cfg-1", Version: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := repo.Create(ctx, tt.profile); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			got, err := repo.GetByID(ctx, tt.profile.ProfileID)
+			if err != nil {
+				t.Fatalf("GetByID() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.profile) {
+				t.Errorf("GetByID() = %+v, want %+v", got, tt.profile)
+			}
+			if err := repo.Create(ctx, tt.profile); err == nil {
+				t.Error("Create() of an existing profile succeeded")
+			}
+		})
+	}
+
+	profiles, err := repo.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+	if len(profiles) != 2 || profiles[0].ProfileID != "cfg-1" || profiles[1].ProfileID != "cfg-2" {
+		t.Errorf("GetAll() = %v, want cfg-1 and cfg-2", profiles)
+	}
+
+	profile := testAgentConfig("cfg-2")
+	profile.Update("", "", &entity.AgentSettings{MetricsInterval: time.Second}, nil)
+	profile.Detach("agent-b")
+	profile.UpdatedAt = profile.UpdatedAt.Round(0)
+	if err := repo.Update(ctx, profile); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if got, err := repo.GetByID(ctx, profile.ProfileID); err != nil || !reflect.DeepEqual(got, profile) {
+		t.Errorf("GetByID() after Update() = %+v, %v, want %+v", got, err, profile)
+	}
+
+	if err := repo.Delete(ctx, "cfg-1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.GetByID(ctx, "cfg-1"); err == nil {
+		t.Error("GetByID() found a deleted profile")
+	}
+	if err := repo.Delete(ctx, "cfg-1"); err == nil {
+		t.Error("Delete() of a missing profile succeeded")
+	}
+	if err := repo.Update(ctx, testAgentConfig("cfg-1")); err == nil {
+		t.Error("Update() of a missing profile succeeded")
+	}
+}
@@ -0,0 +1,221 @@
+// Package sqlstore provides SQLite and PostgreSQL repositories
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"smart-monitor/backend/internal/domain/entity"
+)
+
+const agentColumns = `agent_id, hostname, ip_address, agent_version, fingerprint, access_token,
+	token_expiry, status, blocked, block_reason, metadata, registered_at, last_auth_at,
+	previous_token, previous_expiry, enrollment_token_id, labels`
+
+// SQLAgentRegistryRepository implements AgentRegistryRepository with a SQL database
+type SQLAgentRegistryRepository struct {
+	db *DB
+}
+
+// NewSQLAgentRegistryRepository creates a new SQL agent registry repository
+func NewSQLAgentRegistryRepository(db *DB) *SQLAgentRegistryRepository {
+	return &SQLAgentRegistryRepository{db: db}
+}
+
+// Register creates a new agent registry entry, replacing one with the same agent ID
+func (r *SQLAgentRegistryRepository) Register(ctx context.Context, agent *entity.AgentRegistry) error {
+	args, err := agentArgs(agent)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.exec(ctx, r.db.db, `INSERT INTO agents (`+agentColumns+`)
+		VALUES (`+placeholders(len(args))+`)
+		ON CONFLICT (agent_id) DO UPDATE SET
+			hostname = excluded.hostname, ip_address = excluded.ip_address,
+			agent_version = excluded.agent_version, fingerprint = excluded.fingerprint,
+			access_token = excluded.access_token, token_expiry = excluded.token_expiry,
+			status = excluded.status, blocked = excluded.blocked, block_reason = excluded.block_reason,
+			metadata = excluded.metadata, registered_at = excluded.registered_at,
+			last_auth_at = excluded.last_auth_at, previous_token = excluded.previous_token,
+			previous_expiry = excluded.previous_expiry, enrollment_token_id = excluded.enrollment_token_id,
+			labels = excluded.labels`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to register agent: %w", err)
+	}
+	return nil
+}
+
+// GetByAgentID retrieves an agent by agent ID
+func (r *SQLAgentRegistryRepository) GetByAgentID(ctx context.Context, agentID string) (*entity.AgentRegistry, error) {
+	agent, err := scanAgent(r.db.queryRow(ctx, r.db.db, `SELECT `+agentColumns+` FROM agents WHERE agent_id = ?`, agentID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("agent not found: %s", agentID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent: %w", err)
+	}
+
+	return agent, nil
+}
+
+// GetByToken retrieves an agent by access token
+func (r *SQLAgentRegistryRepository) GetByToken(ctx context.Context, token string) (*entity.AgentRegistry, error) {
+	if token == "" {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	agent, err := scanAgent(r.db.queryRow(ctx, r.db.db, `SELECT `+agentColumns+` FROM agents WHERE access_token = ?`, token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("invalid token")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent: %w", err)
+	}
+
+	return agent, nil
+}
+
+// GetByFingerprint retrieves an agent by machine fingerprint
+func (r *SQLAgentRegistryRepository) GetByFingerprint(ctx context.Context, fingerprint string) (*entity.AgentRegistry, error) {
+	if fingerprint == "" {
+		return nil, fmt.Errorf("agent not found for fingerprint")
+	}
+
+	agent, err := scanAgent(r.db.queryRow(ctx, r.db.db, `SELECT `+agentColumns+` FROM agents WHERE fingerprint = ? LIMIT 1`, fingerprint))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("agent not found for fingerprint")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent: %w", err)
+	}
+
+	return agent, nil
+}
+
+// Update updates an agent registry
+func (r *SQLAgentRegistryRepository) Update(ctx context.Context, agent *entity.AgentRegistry) error {
+	args, err := agentArgs(agent)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.exec(ctx, r.db.db, `UPDATE agents SET
+			hostname = ?, ip_address = ?, agent_version = ?, fingerprint = ?, access_token = ?,
+			token_expiry = ?, status = ?, blocked = ?, block_reason = ?, metadata = ?,
+			registered_at = ?, last_auth_at = ?, previous_token = ?, previous_expiry = ?,
+			enrollment_token_id = ?, labels = ?
+		WHERE agent_id = ?`, append(args[1:], agent.AgentID)...)
+	if err != nil {
+		return fmt.Errorf("failed to update agent: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("agent not found: %s", agent.AgentID)
+	}
+	return nil
+}
+
+// Delete removes an agent registry
+func (r *SQLAgentRegistryRepository) Delete(ctx context.Context, agentID string) error {
+	if _, err := r.db.exec(ctx, r.db.db, `DELETE FROM agents WHERE agent_id = ?`, agentID); err != nil {
+		return fmt.Errorf("failed to delete agent: %w", err)
+	}
+	return nil
+}
+
+// GetAll retrieves all registered agents
+func (r *SQLAgentRegistryRepository) GetAll(ctx context.Context) ([]*entity.AgentRegistry, error) {
+	return r.list(ctx, `SELECT `+agentColumns+` FROM agents ORDER BY agent_id`)
+}
+
+// GetActive retrieves all active agents
+func (r *SQLAgentRegistryRepository) GetActive(ctx context.Context) ([]*entity.AgentRegistry, error) {
+	agents, err := r.list(ctx, `SELECT `+agentColumns+` FROM agents WHERE status = ? ORDER BY agent_id`, string(entity.AgentStatusActive))
+	if err != nil {
+		return nil, err
+	}
+
+	// Expiry is checked against the backend's clock rather than the database's
+	result := make([]*entity.AgentRegistry, 0, len(agents))
+	for _, agent := range agents {
+		if agent.IsValid() {
+			result = append(result, agent)
+		}
+	}
+
+	return result, nil
+}
+
+// list runs a query returning agents
+func (r *SQLAgentRegistryRepository) list(ctx context.Context, query string, args ...any) ([]*entity.AgentRegistry, error) {
+	rows, err := r.db.query(ctx, r.db.db, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*entity.AgentRegistry, 0)
+	for rows.Next() {
+		agent, err := scanAgent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read agent: %w", err)
+		}
+		result = append(result, agent)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+
+	return result, nil
+}
+
+// agentArgs returns the column values of an agent in agentColumns order
+func agentArgs(agent *entity.AgentRegistry) ([]any, error) {
+	metadata, err := toJSON(agent.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := toJSON(agent.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	return []any{
+		agent.AgentID, agent.Hostname, agent.IPAddress, agent.AgentVersion, agent.Fingerprint, agent.AccessToken,
+		toUnixNano(agent.TokenExpiry), string(agent.Status), agent.Blocked, agent.BlockReason, metadata,
+		toUnixNano(agent.RegisteredAt), toUnixNano(agent.LastAuthAt),
+		agent.PreviousToken, toUnixNano(agent.PreviousExpiry), agent.EnrollmentTokenID, labels,
+	}, nil
+}
+
+// scanAgent reads a row of agentColumns
+func scanAgent(row scanner) (*entity.AgentRegistry, error) {
+	var agent entity.AgentRegistry
+	var status, metadata, labels string
+	var tokenExpiry, registeredAt, lastAuthAt, previousExpiry int64
+
+	err := row.Scan(
+		&agent.AgentID, &agent.Hostname, &agent.IPAddress, &agent.AgentVersion, &agent.Fingerprint, &agent.AccessToken,
+		&tokenExpiry, &status, &agent.Blocked, &agent.BlockReason, &metadata, &registeredAt, &lastAuthAt,
+		&agent.PreviousToken, &previousExpiry, &agent.EnrollmentTokenID, &labels,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	agent.Status = entity.AgentStatus(status)
+	agent.TokenExpiry = fromUnixNano(tokenExpiry)
+	agent.RegisteredAt = fromUnixNano(registeredAt)
+	agent.LastAuthAt = fromUnixNano(lastAuthAt)
+	agent.PreviousExpiry = fromUnixNano(previousExpiry)
+	if err := fromJSON(metadata, &agent.Metadata); err != nil {
+		return nil, err
+	}
+	if err := fromJSON(labels, &agent.Labels); err != nil {
+		return nil, err
+	}
+
+	return &agent, nil
+}
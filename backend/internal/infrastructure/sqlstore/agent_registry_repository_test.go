@@ -0,0 +1,180 @@
+package sqlstore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"smart-monitor/backend/internal/domain/entity"
+)
+
+// testAgent returns an agent with every field set
+func testAgent(agentID string) *entity.AgentRegistry {
+	return &entity.AgentRegistry{
+		AgentID:           agentID,
+		Hostname:          "web-01",
+		IPAddress:         "10.0.1.12",
+		AgentVersion:      "2.0.0",
+		Fingerprint:       "fp-" + agentID,
+		AccessToken:       "token-" + agentID,
+		TokenExpiry:       time.Now().Add(time.Hour).Round(0),
+		Status:            entity.AgentStatusActive,
+		Blocked:           true,
+		BlockReason:       "maintenance",
+		Metadata:          map[string]string{"environment": "production", "role": "web"},
+		RegisteredAt:      testTime(0),
+		LastAuthAt:        testTime(time.Minute),
+		PreviousToken:     "previous-" + agentID,
+		PreviousExpiry:    testTime(2 * time.Minute),
+		EnrollmentTokenID: "enr-1a2b3c4d",
+		Labels:            map[string]string{"role": "web"},
+	}
+}
+
+func TestSQLAgentRegistryRepositoryRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	repo := NewSQLAgentRegistryRepository(openTestDB(t))
+
+	tests := []struct {
+		name  string
+		agent *entity.AgentRegistry
+	}{
+		{name: "all fields", agent: testAgent("agent-1")},
+		{
+			name: "zero times and nil maps",
+			agent: &entity.AgentRegistry{
+				AgentID:     "agent-2",
+				Hostname:    "db-01",
+				AccessToken: "token-agent-2",
+				Status:      entity.AgentStatusSuspended,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := repo.Register(ctx, tt.agent); err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+
+			got, err := repo.GetByAgentID(ctx, tt.agent.AgentID)
+			if err != nil {
+				t.Fatalf("GetByAgentID() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.agent) {
+				t.Errorf("GetByAgentID() = %+v, want %+v", got, tt.agent)
+			}
+
+			if got, err := repo.GetByToken(ctx, tt.agent.AccessToken); err != nil || got.AgentID != tt.agent.AgentID {
+				t.Errorf("GetByToken() = %v, %v, want %s", got, err, tt.agent.AgentID)
+			}
+		})
+	}
+}
+
+func TestSQLAgentRegistryRepositoryUpdate(t *testing.T) {
+	ctx := context.Background()
+	repo := NewSQLAgentRegistryRepository(openTestDB(t))
+	agent := testAgent("agent-1")
+	if err := repo.Register(ctx, agent); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	agent.RenewToken(time.Hour)
+	agent.Unblock()
+	agent.AssignLabels(map[string]string{"tier": "gold"})
+	// Times are read back without a monotonic clock reading
+	for _, ts := range []*time.Time{&agent.TokenExpiry, &agent.PreviousExpiry, &agent.LastAuthAt} {
+		*ts = ts.Round(0)
+	}
+	if err := repo.Update(ctx, agent); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	got, err := repo.GetByAgentID(ctx, agent.AgentID)
+	if err != nil {
+		t.Fatalf("GetByAgentID() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, agent) {
+		t.Errorf("GetByAgentID() = %+v, want %+v", got, agent)
+	}
+	if got.PreviousToken != "token-agent-1" {
+		t.Errorf("PreviousToken = %q, want the replaced token", got.PreviousToken)
+	}
+
+	if _, err := repo.GetByToken(ctx, "token-agent-1"); err == nil {
+		t.Error("GetByToken() found the agent by its replaced token")
+	}
+	if err := repo.Update(ctx, testAgent("missing")); err == nil {
+		t.Error("Update() of a missing agent succeeded")
+	}
+}
+
+func TestSQLAgentRegistryRepositoryMisses(t *testing.T) {
+	ctx := context.Background()
+	repo := NewSQLAgentRegistryRepository(openTestDB(t))
+	if err := repo.Register(ctx, testAgent("agent-1")); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		get  func() (*entity.AgentRegistry, error)
+	}{
+		{name: "unknown agent ID", get: func() (*entity.AgentRegistry, error) { return repo.GetByAgentID(ctx, "missing") }},
+		{name: "unknown token", get: func() (*entity.AgentRegistry, error) { return repo.GetByToken(ctx, "missing") }},
+		{name: "empty token", get: func() (*entity.AgentRegistry, error) { return repo.GetByToken(ctx, "") }},
+		{name: "unknown fingerprint", get: func() (*entity.AgentRegistry, error) { return repo.GetByFingerprint(ctx, "missing") }},
+		{name: "empty fingerprint", get: func() (*entity.AgentRegistry, error) { return repo.GetByFingerprint(ctx, "") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent, err := tt.get()
+			if err == nil {
+				t.Errorf("got agent %s, want an error", agent.AgentID)
+			}
+		})
+	}
+
+	if agent, err := repo.GetByFingerprint(ctx, "fp-agent-1"); err != nil || agent.AgentID != "agent-1" {
+		t.Errorf("GetByFingerprint() = %v, %v, want agent-1", agent, err)
+	}
+}
+
+func TestSQLAgentRegistryRepositoryGetActive(t *testing.T) {
+	ctx := context.Background()
+	repo := NewSQLAgentRegistryRepository(openTestDB(t))
+
+	active := testAgent("agent-active")
+	expired := testAgent("agent-expired")
+	expired.TokenExpiry = time.Now().Add(-time.Minute).Round(0)
+	revoked := testAgent("agent-revoked")
+	revoked.Revoke()
+	for _, agent := range []*entity.AgentRegistry{active, expired, revoked} {
+		if err := repo.Register(ctx, agent); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+
+	all, err := repo.GetAll(ctx)
+	if err != nil || len(all) != 3 {
+		t.Fatalf("GetAll() = %d agents, %v, want 3", len(all), err)
+	}
+
+	got, err := repo.GetActive(ctx)
+	if err != nil {
+		t.Fatalf("GetActive() error = %v", err)
+	}
+	if len(got) != 1 || got[0].AgentID != active.AgentID {
+		t.Errorf("GetActive() = %v, want only %s", got, active.AgentID)
+	}
+
+	if err := repo.Delete(ctx, active.AgentID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.GetByAgentID(ctx, active.AgentID); err == nil {
+		t.Error("GetByAgentID() found a deleted agent")
+	}
+}
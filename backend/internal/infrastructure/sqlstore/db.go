@@ -0,0 +1,211 @@
+// Package sqlstore provides SQLite and PostgreSQL repositories
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // registers the "pgx" driver
+	_ "modernc.org/sqlite"             // registers the "sqlite" driver
+)
+
+// Supported database drivers
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// sqlitePragmas are set on every SQLite connection: foreign keys for cascading
+// deletes, WAL so readers do not block the writer, and waiting on locks
+// instead of failing
+var sqlitePragmas = []string{"foreign_keys(1)", "journal_mode(WAL)", "busy_timeout(5000)"}
+
+// DB is a SQLite or PostgreSQL database shared by the SQL repositories.
+// Queries are written with ? placeholders and rebound for PostgreSQL.
+type DB struct {
+	db     *sql.DB
+	driver string
+}
+
+// querier runs queries on the database or within a transaction
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Open connects to a database and applies pending migrations. For SQLite, dsn
+// is a file path; for PostgreSQL, a connection URL or key=value string.
+func Open(driver, dsn string) (*DB, error) {
+	var db *sql.DB
+	var err error
+
+	switch driver {
+	case DriverSQLite:
+		if dsn == "" {
+			return nil, fmt.Errorf("SQLite database file is required")
+		}
+		if !strings.HasPrefix(dsn, "file:") && dsn != ":memory:" {
+			if err := os.MkdirAll(filepath.Dir(dsn), 0700); err != nil {
+				return nil, fmt.Errorf("failed to create database directory: %w", err)
+			}
+		}
+		db, err = sql.Open("sqlite", sqliteDSN(dsn))
+		if err == nil {
+			// A single connection serializes writers, which SQLite does anyway
+			db.SetMaxOpenConns(1)
+		}
+	case DriverPostgres:
+		db, err = sql.Open("pgx", dsn)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q, use %s or %s", driver, DriverSQLite, DriverPostgres)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	d := &DB{db: db, driver: driver}
+	if err := d.migrate(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Close closes the database
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// sqliteDSN adds the connection pragmas to a SQLite file path or URI
+func sqliteDSN(dsn string) string {
+	if !strings.HasPrefix(dsn, "file:") {
+		dsn = "file:" + dsn
+	}
+
+	params := make([]string, 0, len(sqlitePragmas))
+	for _, pragma := range sqlitePragmas {
+		params = append(params, "_pragma="+pragma)
+	}
+
+	separator := "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+	return dsn + separator + strings.Join(params, "&")
+}
+
+// rebind rewrites ? placeholders for the driver
+func (d *DB) rebind(query string) string {
+	if d.driver != DriverPostgres {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// exec runs a statement on q
+func (d *DB) exec(ctx context.Context, q querier, query string, args ...any) (sql.Result, error) {
+	return q.ExecContext(ctx, d.rebind(query), args...)
+}
+
+// query runs a query on q
+func (d *DB) query(ctx context.Context, q querier, query string, args ...any) (*sql.Rows, error) {
+	return q.QueryContext(ctx, d.rebind(query), args...)
+}
+
+// queryRow runs a query returning at most one row on q
+func (d *DB) queryRow(ctx context.Context, q querier, query string, args ...any) *sql.Row {
+	return q.QueryRowContext(ctx, d.rebind(query), args...)
+}
+
+// withTx runs fn in a transaction, committed if fn succeeds
+func (d *DB) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// placeholders returns n comma-separated ? placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// Times are stored as Unix nanoseconds, zero times as 0
+
+func toUnixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func fromUnixNano(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+// Maps and lists are stored as JSON text
+
+func toJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode column: %w", err)
+	}
+	return string(data), nil
+}
+
+func fromJSON(data string, v any) error {
+	if data == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		return fmt.Errorf("failed to decode column: %w", err)
+	}
+	return nil
+}
+
+// scanner is a *sql.Row or *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
@@ -0,0 +1,86 @@
+package sqlstore
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// openTestDB opens a new SQLite database in a temporary directory, closed
+// when the test ends
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+
+	db, err := Open(DriverSQLite, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// testTime returns a fixed time with nanoseconds, as read back from the database
+func testTime(offset time.Duration) time.Time {
+	return time.Unix(1700000000, 123456789).Add(offset)
+}
+
+func TestMigrationsAreIdempotent(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	db, err := Open(DriverSQLite, path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if err := NewSQLUserRepository(db).Create(ctx, testUser()); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// Migrating an up-to-date database again changes nothing
+	if err := db.migrate(ctx); err != nil {
+		t.Fatalf("migrate() again error = %v", err)
+	}
+	db.Close()
+
+	db, err = Open(DriverSQLite, path)
+	if err != nil {
+		t.Fatalf("Open() existing database error = %v", err)
+	}
+	defer db.Close()
+
+	var count, latest int
+	if err := db.queryRow(ctx, db.db, `SELECT COUNT(*), MAX(version) FROM schema_migrations`).Scan(&count, &latest); err != nil {
+		t.Fatalf("failed to read schema_migrations: %v", err)
+	}
+	if count != len(migrations) || latest != migrations[len(migrations)-1].version {
+		t.Errorf("schema_migrations has %d rows up to version %d, want %d up to %d",
+			count, latest, len(migrations), migrations[len(migrations)-1].version)
+	}
+
+	if _, err := NewSQLUserRepository(db).GetByEmail(ctx, testUser().Email); err != nil {
+		t.Errorf("user lost after reopening: %v", err)
+	}
+}
+
+func TestMigrationVersionsIncrease(t *testing.T) {
+	for i, m := range migrations {
+		if m.version != i+1 {
+			t.Errorf("migration %d (%s) has version %d, want %d", i, m.name, m.version, i+1)
+		}
+	}
+}
+
+func TestRebind(t *testing.T) {
+	query := `SELECT a FROM t WHERE b = ? AND c IN (?, ?)`
+
+	sqlite := &DB{driver: DriverSQLite}
+	if got := sqlite.rebind(query); got != query {
+		t.Errorf("SQLite rebind() = %q, want the query unchanged", got)
+	}
+
+	postgres := &DB{driver: DriverPostgres}
+	if got, want := postgres.rebind(query), `SELECT a FROM t WHERE b = $1 AND c IN ($2, $3)`; got != want {
+		t.Errorf("PostgreSQL rebind() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,156 @@
+// Package sqlstore provides SQLite and PostgreSQL repositories
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"smart-monitor/backend/internal/domain/entity"
+)
+
+const enrollmentTokenColumns = `token_id, description, secret_hash, hostname_pattern, labels, policy_ids,
+	max_uses, uses, expires_at, revoked, created_at, last_used_at`
+
+// SQLEnrollmentTokenRepository implements EnrollmentTokenRepository with a SQL database
+type SQLEnrollmentTokenRepository struct {
+	db *DB
+}
+
+// NewSQLEnrollmentTokenRepository creates a new SQL enrollment token repository
+func NewSQLEnrollmentTokenRepository(db *DB) *SQLEnrollmentTokenRepository {
+	return &SQLEnrollmentTokenRepository{db: db}
+}
+
+// Create stores a new token
+func (r *SQLEnrollmentTokenRepository) Create(ctx context.Context, token *entity.EnrollmentToken) error {
+	args, err := enrollmentTokenArgs(token)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.exec(ctx, r.db.db, `INSERT INTO enrollment_tokens (`+enrollmentTokenColumns+`)
+		VALUES (`+placeholders(len(args))+`)
+		ON CONFLICT (token_id) DO NOTHING`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to create enrollment token: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("enrollment token already exists: %s", token.TokenID)
+	}
+	return nil
+}
+
+// GetByID retrieves a token by ID
+func (r *SQLEnrollmentTokenRepository) GetByID(ctx context.Context, tokenID string) (*entity.EnrollmentToken, error) {
+	token, err := scanEnrollmentToken(r.db.queryRow(ctx, r.db.db, `SELECT `+enrollmentTokenColumns+` FROM enrollment_tokens WHERE token_id = ?`, tokenID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("enrollment token not found: %s", tokenID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get enrollment token: %w", err)
+	}
+
+	return token, nil
+}
+
+// Update replaces a stored token
+func (r *SQLEnrollmentTokenRepository) Update(ctx context.Context, token *entity.EnrollmentToken) error {
+	args, err := enrollmentTokenArgs(token)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.exec(ctx, r.db.db, `UPDATE enrollment_tokens SET
+			description = ?, secret_hash = ?, hostname_pattern = ?, labels = ?, policy_ids = ?,
+			max_uses = ?, uses = ?, expires_at = ?, revoked = ?, created_at = ?, last_used_at = ?
+		WHERE token_id = ?`, append(args[1:], token.TokenID)...)
+	if err != nil {
+		return fmt.Errorf("failed to update enrollment token: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("enrollment token not found: %s", token.TokenID)
+	}
+	return nil
+}
+
+// Delete removes a token
+func (r *SQLEnrollmentTokenRepository) Delete(ctx context.Context, tokenID string) error {
+	result, err := r.db.exec(ctx, r.db.db, `DELETE FROM enrollment_tokens WHERE token_id = ?`, tokenID)
+	if err != nil {
+		return fmt.Errorf("failed to delete enrollment token: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("enrollment token not found: %s", tokenID)
+	}
+	return nil
+}
+
+// GetAll retrieves all tokens ordered by ID
+func (r *SQLEnrollmentTokenRepository) GetAll(ctx context.Context) ([]*entity.EnrollmentToken, error) {
+	rows, err := r.db.query(ctx, r.db.db, `SELECT `+enrollmentTokenColumns+` FROM enrollment_tokens ORDER BY token_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list enrollment tokens: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*entity.EnrollmentToken, 0)
+	for rows.Next() {
+		token, err := scanEnrollmentToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read enrollment token: %w", err)
+		}
+		result = append(result, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list enrollment tokens: %w", err)
+	}
+
+	return result, nil
+}
+
+// enrollmentTokenArgs returns the column values of a token in enrollmentTokenColumns order
+func enrollmentTokenArgs(token *entity.EnrollmentToken) ([]any, error) {
+	labels, err := toJSON(token.Labels)
+	if err != nil {
+		return nil, err
+	}
+	policyIDs, err := toJSON(token.PolicyIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return []any{
+		token.TokenID, token.Description, token.SecretHash, token.HostnamePattern, labels, policyIDs,
+		token.MaxUses, token.Uses, toUnixNano(token.ExpiresAt), token.Revoked,
+		toUnixNano(token.CreatedAt), toUnixNano(token.LastUsedAt),
+	}, nil
+}
+
+// scanEnrollmentToken reads a row of enrollmentTokenColumns
+func scanEnrollmentToken(row scanner) (*entity.EnrollmentToken, error) {
+	var token entity.EnrollmentToken
+	var labels, policyIDs string
+	var expiresAt, createdAt, lastUsedAt int64
+
+	err := row.Scan(
+		&token.TokenID, &token.Description, &token.SecretHash, &token.HostnamePattern, &labels, &policyIDs,
+		&token.MaxUses, &token.Uses, &expiresAt, &token.Revoked, &createdAt, &lastUsedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	token.ExpiresAt = fromUnixNano(expiresAt)
+	token.CreatedAt = fromUnixNano(createdAt)
+	token.LastUsedAt = fromUnixNano(lastUsedAt)
+	if err := fromJSON(labels, &token.Labels); err != nil {
+		return nil, err
+	}
+	if err := fromJSON(policyIDs, &token.PolicyIDs); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
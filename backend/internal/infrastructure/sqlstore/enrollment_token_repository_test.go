@@ -0,0 +1,90 @@
+package sqlstore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"smart-monitor/backend/internal/domain/entity"
+)
+
+// testEnrollmentToken returns a token with every field set
+func testEnrollmentToken(tokenID string) *entity.EnrollmentToken {
+	return &entity.EnrollmentToken{
+		TokenID:         tokenID,
+		Description:     "web servers",
+		SecretHash:      "hash-" + tokenID,
+		HostnamePattern: "web-*",
+		Labels:          map[string]string{"role": "web"},
+		PolicyIDs:       []string{"policy-2", "policy-1"},
+		MaxUses:         10,
+		Uses:            3,
+		ExpiresAt:       testTime(time.Hour),
+		Revoked:         true,
+		CreatedAt:       testTime(0),
+		LastUsedAt:      testTime(time.Minute),
+	}
+}
+
+func TestSQLEnrollmentTokenRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewSQLEnrollmentTokenRepository(openTestDB(t))
+
+	tests := []struct {
+		name  string
+		token *entity.EnrollmentToken
+	}{
+		{name: "all fields", token: testEnrollmentToken("enr-2")},
+		{name: "zero times and nil lists", token: &entity.EnrollmentToken{TokenID: "enr-1", SecretHash: "hash"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := repo.Create(ctx, tt.token); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			got, err := repo.GetByID(ctx, tt.token.TokenID)
+			if err != nil {
+				t.Fatalf("GetByID() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.token) {
+				t.Errorf("GetByID() = %+v, want %+v", got, tt.token)
+			}
+			if err := repo.Create(ctx, tt.token); err == nil {
+				t.Error("Create() of an existing token succeeded")
+			}
+		})
+	}
+
+	tokens, err := repo.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+	if len(tokens) != 2 || tokens[0].TokenID != "enr-1" || tokens[1].TokenID != "enr-2" {
+		t.Errorf("GetAll() = %v, want enr-1 and enr-2", tokens)
+	}
+
+	token := testEnrollmentToken("enr-2")
+	token.Use()
+	token.LastUsedAt = token.LastUsedAt.Round(0)
+	if err := repo.Update(ctx, token); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if got, err := repo.GetByID(ctx, token.TokenID); err != nil || !reflect.DeepEqual(got, token) {
+		t.Errorf("GetByID() after Update() = %+v, %v, want %+v", got, err, token)
+	}
+
+	if err := repo.Delete(ctx, "enr-1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.GetByID(ctx, "enr-1"); err == nil {
+		t.Error("GetByID() found a deleted token")
+	}
+	if err := repo.Delete(ctx, "enr-1"); err == nil {
+		t.Error("Delete() of a missing token succeeded")
+	}
+	if err := repo.Update(ctx, testEnrollmentToken("enr-1")); err == nil {
+		t.Error("Update() of a missing token succeeded")
+	}
+}
@@ -0,0 +1,134 @@
+// Package sqlstore provides SQLite and PostgreSQL repositories
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"smart-monitor/backend/internal/domain/entity"
+)
+
+const hostColumns = `hostname, id, ip_address, agent_id, agent_version, status, metadata,
+	created_at, updated_at, last_seen_at, report_interval, status_changed_at`
+
+// SQLHostRepository implements HostRepository with a SQL database
+type SQLHostRepository struct {
+	db *DB
+}
+
+// NewSQLHostRepository creates a new SQL host repository
+func NewSQLHostRepository(db *DB) *SQLHostRepository {
+	return &SQLHostRepository{db: db}
+}
+
+// Create creates a new host, replacing one with the same hostname
+func (r *SQLHostRepository) Create(ctx context.Context, host *entity.Host) error {
+	if err := r.save(ctx, host); err != nil {
+		return fmt.Errorf("failed to create host: %w", err)
+	}
+	return nil
+}
+
+// Get retrieves a host by hostname
+func (r *SQLHostRepository) Get(ctx context.Context, hostname string) (*entity.Host, error) {
+	host, err := scanHost(r.db.queryRow(ctx, r.db.db, `SELECT `+hostColumns+` FROM hosts WHERE hostname = ?`, hostname))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("host not found: %s", hostname)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get host: %w", err)
+	}
+
+	return host, nil
+}
+
+// GetAll retrieves all hosts
+func (r *SQLHostRepository) GetAll(ctx context.Context) ([]*entity.Host, error) {
+	rows, err := r.db.query(ctx, r.db.db, `SELECT `+hostColumns+` FROM hosts ORDER BY hostname`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hosts: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*entity.Host, 0)
+	for rows.Next() {
+		host, err := scanHost(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read host: %w", err)
+		}
+		result = append(result, host)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list hosts: %w", err)
+	}
+
+	return result, nil
+}
+
+// Update updates a host, creating it if it does not exist
+func (r *SQLHostRepository) Update(ctx context.Context, host *entity.Host) error {
+	if err := r.save(ctx, host); err != nil {
+		return fmt.Errorf("failed to update host: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a host
+func (r *SQLHostRepository) Delete(ctx context.Context, hostname string) error {
+	if _, err := r.db.exec(ctx, r.db.db, `DELETE FROM hosts WHERE hostname = ?`, hostname); err != nil {
+		return fmt.Errorf("failed to delete host: %w", err)
+	}
+	return nil
+}
+
+// save inserts or replaces a host
+func (r *SQLHostRepository) save(ctx context.Context, host *entity.Host) error {
+	metadata, err := toJSON(host.Metadata)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.exec(ctx, r.db.db, `INSERT INTO hosts (`+hostColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (hostname) DO UPDATE SET
+			id = excluded.id, ip_address = excluded.ip_address, agent_id = excluded.agent_id,
+			agent_version = excluded.agent_version, status = excluded.status, metadata = excluded.metadata,
+			created_at = excluded.created_at, updated_at = excluded.updated_at,
+			last_seen_at = excluded.last_seen_at, report_interval = excluded.report_interval,
+			status_changed_at = excluded.status_changed_at`,
+		host.Hostname, host.ID, host.IPAddress, host.AgentID, host.AgentVersion, string(host.Status), metadata,
+		toUnixNano(host.CreatedAt), toUnixNano(host.UpdatedAt), toUnixNano(host.LastSeenAt),
+		int64(host.ReportInterval), toUnixNano(host.StatusChangedAt),
+	)
+	return err
+}
+
+// scanHost reads a row of hostColumns
+func scanHost(row scanner) (*entity.Host, error) {
+	var host entity.Host
+	var status, metadata string
+	var createdAt, updatedAt, lastSeenAt, reportInterval, statusChangedAt int64
+
+	err := row.Scan(
+		&host.Hostname, &host.ID, &host.IPAddress, &host.AgentID, &host.AgentVersion, &status, &metadata,
+		&createdAt, &updatedAt, &lastSeenAt, &reportInterval, &statusChangedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	host.Status = entity.HostStatus(status)
+	host.CreatedAt = fromUnixNano(createdAt)
+	host.UpdatedAt = fromUnixNano(updatedAt)
+	host.LastSeenAt = fromUnixNano(lastSeenAt)
+	host.ReportInterval = time.Duration(reportInterval)
+	host.StatusChangedAt = fromUnixNano(statusChangedAt)
+	if err := fromJSON(metadata, &host.Metadata); err != nil {
+		return nil, err
+	}
+
+	return &host, nil
+}
@@ -0,0 +1,200 @@
+// Package sqlstore provides SQLite and PostgreSQL repositories
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// migration is a schema change, applied once in its own transaction. The
+// statements must work on SQLite and PostgreSQL. Applied migrations must
+// never change; add a new one instead.
+type migration struct {
+	version    int
+	name       string
+	statements []string
+}
+
+// migrationLockID serializes migrations of backends sharing a PostgreSQL
+// database with an advisory lock
+const migrationLockID = 7360414261
+
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "create agents, hosts, policies and users",
+		statements: []string{
+			`CREATE TABLE agents (
+				agent_id            TEXT PRIMARY KEY,
+				hostname            TEXT NOT NULL,
+				ip_address          TEXT NOT NULL,
+				agent_version       TEXT NOT NULL,
+				fingerprint         TEXT NOT NULL,
+				access_token        TEXT NOT NULL,
+				token_expiry        BIGINT NOT NULL,
+				status              TEXT NOT NULL,
+				blocked             BOOLEAN NOT NULL,
+				block_reason        TEXT NOT NULL,
+				metadata            TEXT NOT NULL,
+				registered_at       BIGINT NOT NULL,
+				last_auth_at        BIGINT NOT NULL,
+				previous_token      TEXT NOT NULL,
+				previous_expiry     BIGINT NOT NULL,
+				enrollment_token_id TEXT NOT NULL,
+				labels              TEXT NOT NULL
+			)`,
+			`CREATE UNIQUE INDEX agents_access_token ON agents (access_token) WHERE access_token <> ''`,
+			`CREATE INDEX agents_fingerprint ON agents (fingerprint)`,
+
+			`CREATE TABLE hosts (
+				hostname          TEXT PRIMARY KEY,
+				id                TEXT NOT NULL,
+				ip_address        TEXT NOT NULL,
+				agent_id          TEXT NOT NULL,
+				agent_version     TEXT NOT NULL,
+				status            TEXT NOT NULL,
+				metadata          TEXT NOT NULL,
+				created_at        BIGINT NOT NULL,
+				updated_at        BIGINT NOT NULL,
+				last_seen_at      BIGINT NOT NULL,
+				report_interval   BIGINT NOT NULL,
+				status_changed_at BIGINT NOT NULL
+			)`,
+			`CREATE INDEX hosts_agent_id ON hosts (agent_id)`,
+
+			`CREATE TABLE policies (
+				policy_id        TEXT PRIMARY KEY,
+				name             TEXT NOT NULL,
+				description      TEXT NOT NULL,
+				thresholds       TEXT NOT NULL,
+				rules            TEXT NOT NULL,
+				actions          TEXT NOT NULL,
+				metadata         TEXT NOT NULL,
+				enabled          BOOLEAN NOT NULL,
+				created_at       BIGINT NOT NULL,
+				updated_at       BIGINT NOT NULL,
+				allowed_user_ids TEXT NOT NULL
+			)`,
+			`CREATE TABLE policy_agents (
+				policy_id TEXT NOT NULL REFERENCES policies (policy_id) ON DELETE CASCADE,
+				agent_id  TEXT NOT NULL,
+				position  INTEGER NOT NULL,
+				PRIMARY KEY (policy_id, agent_id)
+			)`,
+			`CREATE INDEX policy_agents_agent_id ON policy_agents (agent_id)`,
+
+			`CREATE TABLE users (
+				id            TEXT PRIMARY KEY,
+				email         TEXT NOT NULL UNIQUE,
+				username      TEXT NOT NULL,
+				role          TEXT NOT NULL,
+				password_hash TEXT NOT NULL,
+				created_at    BIGINT NOT NULL,
+				updated_at    BIGINT NOT NULL
+			)`,
+		},
+	},
+	{
+		version: 2,
+		name:    "create enrollment tokens and agent config profiles",
+		statements: []string{
+			`CREATE TABLE enrollment_tokens (
+				token_id         TEXT PRIMARY KEY,
+				description      TEXT NOT NULL,
+				secret_hash      TEXT NOT NULL,
+				hostname_pattern TEXT NOT NULL,
+				labels           TEXT NOT NULL,
+				policy_ids       TEXT NOT NULL,
+				max_uses         INTEGER NOT NULL,
+				uses             INTEGER NOT NULL,
+				expires_at       BIGINT NOT NULL,
+				revoked          BOOLEAN NOT NULL,
+				created_at       BIGINT NOT NULL,
+				last_used_at     BIGINT NOT NULL
+			)`,
+
+			`CREATE TABLE agent_config_profiles (
+				profile_id  TEXT PRIMARY KEY,
+				name        TEXT NOT NULL,
+				description TEXT NOT NULL,
+				version     BIGINT NOT NULL,
+				settings    TEXT NOT NULL,
+				agent_ids   TEXT NOT NULL,
+				selector    TEXT NOT NULL,
+				created_at  BIGINT NOT NULL,
+				updated_at  BIGINT NOT NULL
+			)`,
+		},
+	},
+	{
+		version: 3,
+		name:    "create agent commands",
+		statements: []string{
+			`CREATE TABLE agent_commands (
+				command_id TEXT PRIMARY KEY,
+				agent_id   TEXT NOT NULL,
+				action     TEXT NOT NULL,
+				reason     TEXT NOT NULL,
+				status     TEXT NOT NULL,
+				message    TEXT NOT NULL,
+				created_at BIGINT NOT NULL,
+				updated_at BIGINT NOT NULL
+			)`,
+			`CREATE INDEX agent_commands_agent_id ON agent_commands (agent_id, created_at)`,
+		},
+	},
+}
+
+// migrate applies the migrations the database has not seen yet
+func (d *DB) migrate(ctx context.Context) error {
+	_, err := d.exec(ctx, d.db, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at BIGINT NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	for _, m := range migrations {
+		applied := false
+		err := d.withTx(ctx, func(tx *sql.Tx) error {
+			if d.driver == DriverPostgres {
+				if _, err := d.exec(ctx, tx, `SELECT pg_advisory_xact_lock(?)`, migrationLockID); err != nil {
+					return fmt.Errorf("failed to lock migrations: %w", err)
+				}
+			}
+
+			var count int
+			if err := d.queryRow(ctx, tx, `SELECT COUNT(*) FROM schema_migrations WHERE version = ?`, m.version).Scan(&count); err != nil {
+				return fmt.Errorf("failed to check migration %d: %w", m.version, err)
+			}
+			if count > 0 {
+				return nil
+			}
+
+			for _, statement := range m.statements {
+				if _, err := d.exec(ctx, tx, statement); err != nil {
+					return fmt.Errorf("failed to apply migration %d (%s): %w", m.version, m.name, err)
+				}
+			}
+			if _, err := d.exec(ctx, tx, `INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+				m.version, m.name, time.Now().UnixNano()); err != nil {
+				return fmt.Errorf("failed to record migration %d: %w", m.version, err)
+			}
+			applied = true
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if applied {
+			log.Printf("✓ Applied database migration %d: %s", m.version, m.name)
+		}
+	}
+
+	return nil
+}
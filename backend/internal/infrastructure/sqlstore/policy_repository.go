@@ -0,0 +1,362 @@
+// Package sqlstore provides SQLite and PostgreSQL repositories
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"smart-monitor/backend/internal/domain/entity"
+)
+
+const policyColumns = `policy_id, name, description, thresholds, rules, actions, metadata,
+	enabled, created_at, updated_at, allowed_user_ids`
+
+// policyRuleColumn is how a policy rule is stored in the rules column
+type policyRuleColumn struct {
+	Metric     string  `json:"metric"`
+	Comparator string  `json:"comparator"`
+	Value      float64 `json:"value"`
+	Clear      float64 `json:"clear"`
+	For        int64   `json:"for_ns"`
+	Severity   string  `json:"severity,omitempty"`
+}
+
+// SQLPolicyRepository implements PolicyRepository with a SQL database. The
+// agents a policy is applied to are rows of policy_agents, in the order they
+// were applied.
+type SQLPolicyRepository struct {
+	db *DB
+}
+
+// NewSQLPolicyRepository creates a new SQL policy repository
+func NewSQLPolicyRepository(db *DB) *SQLPolicyRepository {
+	return &SQLPolicyRepository{db: db}
+}
+
+// Create adds a new policy
+func (r *SQLPolicyRepository) Create(policy *entity.Policy) error {
+	ctx := context.Background()
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		var count int
+		if err := r.db.queryRow(ctx, tx, `SELECT COUNT(*) FROM policies WHERE policy_id = ?`, policy.PolicyID).Scan(&count); err != nil {
+			return fmt.Errorf("failed to check policy: %w", err)
+		}
+		if count > 0 {
+			return errors.New("policy already exists")
+		}
+
+		args, err := policyArgs(policy)
+		if err != nil {
+			return err
+		}
+		if _, err := r.db.exec(ctx, tx, `INSERT INTO policies (`+policyColumns+`) VALUES (`+placeholders(len(args))+`)`, args...); err != nil {
+			return fmt.Errorf("failed to create policy: %w", err)
+		}
+
+		return r.replaceAgents(ctx, tx, policy)
+	})
+}
+
+// Update modifies an existing policy
+func (r *SQLPolicyRepository) Update(policy *entity.Policy) error {
+	ctx := context.Background()
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		args, err := policyArgs(policy)
+		if err != nil {
+			return err
+		}
+
+		result, err := r.db.exec(ctx, tx, `UPDATE policies SET
+				name = ?, description = ?, thresholds = ?, rules = ?, actions = ?, metadata = ?,
+				enabled = ?, created_at = ?, updated_at = ?, allowed_user_ids = ?
+			WHERE policy_id = ?`, append(args[1:], policy.PolicyID)...)
+		if err != nil {
+			return fmt.Errorf("failed to update policy: %w", err)
+		}
+		if n, err := result.RowsAffected(); err == nil && n == 0 {
+			return errors.New("policy not found")
+		}
+
+		return r.replaceAgents(ctx, tx, policy)
+	})
+}
+
+// Delete removes a policy
+func (r *SQLPolicyRepository) Delete(policyID string) error {
+	ctx := context.Background()
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := r.db.exec(ctx, tx, `DELETE FROM policy_agents WHERE policy_id = ?`, policyID); err != nil {
+			return fmt.Errorf("failed to delete policy: %w", err)
+		}
+
+		result, err := r.db.exec(ctx, tx, `DELETE FROM policies WHERE policy_id = ?`, policyID)
+		if err != nil {
+			return fmt.Errorf("failed to delete policy: %w", err)
+		}
+		if n, err := result.RowsAffected(); err == nil && n == 0 {
+			return errors.New("policy not found")
+		}
+		return nil
+	})
+}
+
+// GetByID retrieves a policy by ID
+func (r *SQLPolicyRepository) GetByID(policyID string) (*entity.Policy, error) {
+	return r.get(context.Background(), r.db.db, policyID)
+}
+
+// GetAll retrieves all policies with pagination, oldest first
+func (r *SQLPolicyRepository) GetAll(page, pageSize int) ([]*entity.Policy, int, error) {
+	ctx := context.Background()
+
+	var total int
+	if err := r.db.queryRow(ctx, r.db.db, `SELECT COUNT(*) FROM policies`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count policies: %w", err)
+	}
+
+	// Simple pagination
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if page <= 0 {
+		page = 1
+	}
+
+	policies, err := r.list(ctx, r.db.db, `SELECT `+policyColumns+` FROM policies
+		ORDER BY created_at, policy_id LIMIT ? OFFSET ?`, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return policies, total, nil
+}
+
+// GetByAgent retrieves policies applied to an agent
+func (r *SQLPolicyRepository) GetByAgent(agentID string) ([]*entity.Policy, error) {
+	return r.list(context.Background(), r.db.db, `SELECT `+policyColumns+` FROM policies
+		WHERE policy_id IN (SELECT policy_id FROM policy_agents WHERE agent_id = ?)
+		ORDER BY created_at, policy_id`, agentID)
+}
+
+// ApplyToAgent applies a policy to an agent
+func (r *SQLPolicyRepository) ApplyToAgent(policyID, agentID string) error {
+	ctx := context.Background()
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		policy, err := r.get(ctx, tx, policyID)
+		if err != nil {
+			return err
+		}
+
+		if !policy.ApplyToAgent(agentID) {
+			return errors.New("policy already applied to agent")
+		}
+
+		_, err = r.db.exec(ctx, tx, `INSERT INTO policy_agents (policy_id, agent_id, position)
+			SELECT ?, ?, COALESCE(MAX(position), -1) + 1 FROM policy_agents WHERE policy_id = ?`,
+			policyID, agentID, policyID)
+		if err != nil {
+			return fmt.Errorf("failed to apply policy: %w", err)
+		}
+
+		return r.touch(ctx, tx, policy)
+	})
+}
+
+// UnapplyFromAgent removes policy from agent
+func (r *SQLPolicyRepository) UnapplyFromAgent(policyID, agentID string) error {
+	ctx := context.Background()
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		policy, err := r.get(ctx, tx, policyID)
+		if err != nil {
+			return err
+		}
+
+		if !policy.UnapplyFromAgent(agentID) {
+			return errors.New("policy not applied to agent")
+		}
+
+		if _, err := r.db.exec(ctx, tx, `DELETE FROM policy_agents WHERE policy_id = ? AND agent_id = ?`, policyID, agentID); err != nil {
+			return fmt.Errorf("failed to unapply policy: %w", err)
+		}
+
+		return r.touch(ctx, tx, policy)
+	})
+}
+
+// get retrieves a policy with its agents
+func (r *SQLPolicyRepository) get(ctx context.Context, q querier, policyID string) (*entity.Policy, error) {
+	policies, err := r.list(ctx, q, `SELECT `+policyColumns+` FROM policies WHERE policy_id = ?`, policyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return nil, errors.New("policy not found")
+	}
+
+	return policies[0], nil
+}
+
+// list runs a query returning policies and loads the agents they are applied to
+func (r *SQLPolicyRepository) list(ctx context.Context, q querier, query string, args ...any) ([]*entity.Policy, error) {
+	rows, err := r.db.query(ctx, q, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list policies: %w", err)
+	}
+
+	policies := make([]*entity.Policy, 0)
+	byID := make(map[string]*entity.Policy)
+	for rows.Next() {
+		policy, err := scanPolicy(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to read policy: %w", err)
+		}
+		policies = append(policies, policy)
+		byID[policy.PolicyID] = policy
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list policies: %w", err)
+	}
+	if len(policies) == 0 {
+		return policies, nil
+	}
+
+	// Loaded after the policy rows are closed, as SQLite has a single connection
+	ids := make([]any, 0, len(policies))
+	for _, policy := range policies {
+		ids = append(ids, policy.PolicyID)
+	}
+	agentRows, err := r.db.query(ctx, q, `SELECT policy_id, agent_id FROM policy_agents
+		WHERE policy_id IN (`+placeholders(len(ids))+`) ORDER BY policy_id, position`, ids...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list policy agents: %w", err)
+	}
+	defer agentRows.Close()
+
+	for agentRows.Next() {
+		var policyID, agentID string
+		if err := agentRows.Scan(&policyID, &agentID); err != nil {
+			return nil, fmt.Errorf("failed to read policy agent: %w", err)
+		}
+		if policy, ok := byID[policyID]; ok {
+			policy.AppliedAgents = append(policy.AppliedAgents, agentID)
+		}
+	}
+	if err := agentRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list policy agents: %w", err)
+	}
+
+	return policies, nil
+}
+
+// replaceAgents stores the agents a policy is applied to
+func (r *SQLPolicyRepository) replaceAgents(ctx context.Context, tx *sql.Tx, policy *entity.Policy) error {
+	if _, err := r.db.exec(ctx, tx, `DELETE FROM policy_agents WHERE policy_id = ?`, policy.PolicyID); err != nil {
+		return fmt.Errorf("failed to update policy agents: %w", err)
+	}
+
+	seen := make(map[string]bool, len(policy.AppliedAgents))
+	for i, agentID := range policy.AppliedAgents {
+		if seen[agentID] {
+			continue
+		}
+		seen[agentID] = true
+		if _, err := r.db.exec(ctx, tx, `INSERT INTO policy_agents (policy_id, agent_id, position) VALUES (?, ?, ?)`,
+			policy.PolicyID, agentID, i); err != nil {
+			return fmt.Errorf("failed to update policy agents: %w", err)
+		}
+	}
+	return nil
+}
+
+// touch stores the updated time of a policy whose agents changed
+func (r *SQLPolicyRepository) touch(ctx context.Context, tx *sql.Tx, policy *entity.Policy) error {
+	if _, err := r.db.exec(ctx, tx, `UPDATE policies SET updated_at = ? WHERE policy_id = ?`,
+		toUnixNano(policy.UpdatedAt), policy.PolicyID); err != nil {
+		return fmt.Errorf("failed to update policy: %w", err)
+	}
+	return nil
+}
+
+// policyArgs returns the column values of a policy in policyColumns order
+func policyArgs(policy *entity.Policy) ([]any, error) {
+	rules := make([]policyRuleColumn, len(policy.Rules))
+	for i, rule := range policy.Rules {
+		rules[i] = policyRuleColumn{
+			Metric:     rule.Metric,
+			Comparator: string(rule.Comparator),
+			Value:      rule.Value,
+			Clear:      rule.Clear,
+			For:        int64(rule.For),
+			Severity:   string(rule.Severity),
+		}
+	}
+
+	columns := make([]string, 0, 5)
+	for _, v := range []any{policy.Thresholds, rules, policy.Actions, policy.Metadata, policy.AllowedUserIDs} {
+		column, err := toJSON(v)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+
+	return []any{
+		policy.PolicyID, policy.Name, policy.Description, columns[0], columns[1], columns[2], columns[3],
+		policy.Enabled, toUnixNano(policy.CreatedAt), toUnixNano(policy.UpdatedAt), columns[4],
+	}, nil
+}
+
+// scanPolicy reads a row of policyColumns; its agents are loaded separately
+func scanPolicy(row scanner) (*entity.Policy, error) {
+	policy := entity.Policy{AppliedAgents: []string{}}
+	var thresholds, rules, actions, metadata, allowedUserIDs string
+	var createdAt, updatedAt int64
+
+	err := row.Scan(
+		&policy.PolicyID, &policy.Name, &policy.Description, &thresholds, &rules, &actions, &metadata,
+		&policy.Enabled, &createdAt, &updatedAt, &allowedUserIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	policy.CreatedAt = fromUnixNano(createdAt)
+	policy.UpdatedAt = fromUnixNano(updatedAt)
+
+	var ruleColumns []policyRuleColumn
+	columns := []struct {
+		data string
+		v    any
+	}{
+		{thresholds, &policy.Thresholds},
+		{rules, &ruleColumns},
+		{actions, &policy.Actions},
+		{metadata, &policy.Metadata},
+		{allowedUserIDs, &policy.AllowedUserIDs},
+	}
+	for _, column := range columns {
+		if err := fromJSON(column.data, column.v); err != nil {
+			return nil, err
+		}
+	}
+
+	policy.Rules = make([]entity.PolicyRule, len(ruleColumns))
+	for i, rule := range ruleColumns {
+		policy.Rules[i] = entity.PolicyRule{
+			Metric:     rule.Metric,
+			Comparator: entity.Comparator(rule.Comparator),
+			Value:      rule.Value,
+			Clear:      rule.Clear,
+			For:        time.Duration(rule.For),
+			Severity:   entity.AlertSeverity(rule.Severity),
+		}
+	}
+
+	return &policy, nil
+}
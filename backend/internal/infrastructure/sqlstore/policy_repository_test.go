@@ -0,0 +1,184 @@
+package sqlstore
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"smart-monitor/backend/internal/domain/entity"
+)
+
+// testPolicy returns a policy with every field set
+func testPolicy(policyID string, created time.Time) *entity.Policy {
+	return &entity.Policy{
+		PolicyID:    policyID,
+		Name:        "High CPU",
+		Description: "CPU above 80% for 5 minutes",
+		Thresholds:  map[string]string{"cpu": "80"},
+		Rules: []entity.PolicyRule{
+			{Metric: "cpu", Comparator: entity.ComparatorGreaterThan, Value: 80, Clear: 70, For: 5 * time.Minute, Severity: entity.AlertSeverityCritical},
+			{Metric: "disk", Comparator: entity.ComparatorGreaterOrEqual, Value: 90.5, Clear: 90.5},
+		},
+		Actions:        []string{"alert", "email"},
+		Metadata:       map[string]string{"team": "infra"},
+		Enabled:        true,
+		CreatedAt:      created,
+		UpdatedAt:      created.Add(time.Second),
+		AppliedAgents:  []string{"agent-b", "agent-a"},
+		AllowedUserIDs: []string{"user-1", "user-2"},
+	}
+}
+
+func TestSQLPolicyRepositoryRoundTrip(t *testing.T) {
+	repo := NewSQLPolicyRepository(openTestDB(t))
+
+	tests := []struct {
+		name   string
+		policy *entity.Policy
+	}{
+		{name: "all fields", policy: testPolicy("policy-1", testTime(0))},
+		{
+			name: "empty fields",
+			policy: &entity.Policy{
+				PolicyID:      "policy-2",
+				Name:          "Empty",
+				Rules:         []entity.PolicyRule{},
+				AppliedAgents: []string{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := repo.Create(tt.policy); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			got, err := repo.GetByID(tt.policy.PolicyID)
+			if err != nil {
+				t.Fatalf("GetByID() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.policy) {
+				t.Errorf("GetByID() = %+v, want %+v", got, tt.policy)
+			}
+
+			if err := repo.Create(tt.policy); err == nil {
+				t.Error("Create() of an existing policy succeeded")
+			}
+		})
+	}
+}
+
+func TestSQLPolicyRepositoryUpdateDelete(t *testing.T) {
+	repo := NewSQLPolicyRepository(openTestDB(t))
+	policy := testPolicy("policy-1", testTime(0))
+	if err := repo.Create(policy); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	policy.Rules = policy.Rules[:1]
+	policy.Rules[0].For = 0
+	policy.Enabled = false
+	policy.AppliedAgents = []string{"agent-c"}
+	if err := repo.Update(policy); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	got, err := repo.GetByID(policy.PolicyID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, policy) {
+		t.Errorf("GetByID() = %+v, want %+v", got, policy)
+	}
+
+	if err := repo.Update(testPolicy("missing", testTime(0))); err == nil {
+		t.Error("Update() of a missing policy succeeded")
+	}
+
+	if err := repo.Delete(policy.PolicyID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.GetByID(policy.PolicyID); err == nil {
+		t.Error("GetByID() found a deleted policy")
+	}
+	if policies, err := repo.GetByAgent("agent-c"); err != nil || len(policies) != 0 {
+		t.Errorf("GetByAgent() = %v, %v, want no policies after delete", policies, err)
+	}
+	if err := repo.Delete(policy.PolicyID); err == nil {
+		t.Error("Delete() of a missing policy succeeded")
+	}
+}
+
+func TestSQLPolicyRepositoryApply(t *testing.T) {
+	repo := NewSQLPolicyRepository(openTestDB(t))
+	if err := repo.Create(testPolicy("policy-1", testTime(0))); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if err := repo.ApplyToAgent("policy-1", "agent-c"); err != nil {
+		t.Fatalf("ApplyToAgent() error = %v", err)
+	}
+	if err := repo.ApplyToAgent("policy-1", "agent-c"); err == nil {
+		t.Error("ApplyToAgent() twice succeeded")
+	}
+	if err := repo.ApplyToAgent("missing", "agent-c"); err == nil {
+		t.Error("ApplyToAgent() of a missing policy succeeded")
+	}
+	if err := repo.UnapplyFromAgent("policy-1", "agent-b"); err != nil {
+		t.Fatalf("UnapplyFromAgent() error = %v", err)
+	}
+	if err := repo.UnapplyFromAgent("policy-1", "agent-b"); err == nil {
+		t.Error("UnapplyFromAgent() of an agent not applied succeeded")
+	}
+
+	got, err := repo.GetByID("policy-1")
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if want := []string{"agent-a", "agent-c"}; !reflect.DeepEqual(got.AppliedAgents, want) {
+		t.Errorf("AppliedAgents = %v, want %v in the order applied", got.AppliedAgents, want)
+	}
+
+	policies, err := repo.GetByAgent("agent-c")
+	if err != nil || len(policies) != 1 || policies[0].PolicyID != "policy-1" {
+		t.Errorf("GetByAgent() = %v, %v, want policy-1", policies, err)
+	}
+}
+
+func TestSQLPolicyRepositoryGetAll(t *testing.T) {
+	repo := NewSQLPolicyRepository(openTestDB(t))
+	for i := range 5 {
+		if err := repo.Create(testPolicy(fmt.Sprintf("policy-%d", i), testTime(time.Duration(i)*time.Minute))); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		page, pageSize int
+		want           []string
+	}{
+		{page: 1, pageSize: 2, want: []string{"policy-0", "policy-1"}},
+		{page: 3, pageSize: 2, want: []string{"policy-4"}},
+		{page: 4, pageSize: 2, want: []string{}},
+		{page: 0, pageSize: 0, want: []string{"policy-0", "policy-1", "policy-2", "policy-3", "policy-4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("page %d of %d", tt.page, tt.pageSize), func(t *testing.T) {
+			policies, total, err := repo.GetAll(tt.page, tt.pageSize)
+			if err != nil {
+				t.Fatalf("GetAll() error = %v", err)
+			}
+			if total != 5 {
+				t.Errorf("total = %d, want 5", total)
+			}
+			ids := make([]string, len(policies))
+			for i, policy := range policies {
+				ids[i] = policy.PolicyID
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("GetAll() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Package sqlstore provides SQLite and PostgreSQL repositories
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"smart-monitor/backend/internal/domain/entity"
+)
+
+const userColumns = `id, email, username, role, password_hash, created_at, updated_at`
+
+// SQLUserRepository implements UserRepository with a SQL database
+type SQLUserRepository struct {
+	db *DB
+}
+
+// NewSQLUserRepository creates a new SQL user repository
+func NewSQLUserRepository(db *DB) *SQLUserRepository {
+	return &SQLUserRepository{db: db}
+}
+
+// Create adds a user unless one with the same email exists
+func (r *SQLUserRepository) Create(ctx context.Context, user *entity.User) error {
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		var count int
+		if err := r.db.queryRow(ctx, tx, `SELECT COUNT(*) FROM users WHERE email = ? OR id = ?`, user.Email, user.ID).Scan(&count); err != nil {
+			return fmt.Errorf("failed to check user: %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("user already exists")
+		}
+
+		_, err := r.db.exec(ctx, tx, `INSERT INTO users (`+userColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			user.ID, user.Email, user.Username, user.Role, user.PasswordHash,
+			toUnixNano(user.CreatedAt), toUnixNano(user.UpdatedAt))
+		if err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetByEmail retrieves a user by email
+func (r *SQLUserRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	return r.get(ctx, `SELECT `+userColumns+` FROM users WHERE email = ?`, email)
+}
+
+// GetByID retrieves a user by ID
+func (r *SQLUserRepository) GetByID(ctx context.Context, id string) (*entity.User, error) {
+	return r.get(ctx, `SELECT `+userColumns+` FROM users WHERE id = ?`, id)
+}
+
+// Update updates a user
+func (r *SQLUserRepository) Update(ctx context.Context, user *entity.User) error {
+	result, err := r.db.exec(ctx, r.db.db, `UPDATE users SET email = ?, username = ?, role = ?, password_hash = ?,
+			created_at = ?, updated_at = ?
+		WHERE id = ?`,
+		user.Email, user.Username, user.Role, user.PasswordHash,
+		toUnixNano(user.CreatedAt), toUnixNano(user.UpdatedAt), user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// List retrieves all users
+func (r *SQLUserRepository) List(ctx context.Context) ([]*entity.User, error) {
+	rows, err := r.db.query(ctx, r.db.db, `SELECT `+userColumns+` FROM users ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]*entity.User, 0)
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read user: %w", err)
+		}
+		out = append(out, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	return out, nil
+}
+
+// get runs a query returning one user
+func (r *SQLUserRepository) get(ctx context.Context, query string, arg any) (*entity.User, error) {
+	user, err := scanUser(r.db.queryRow(ctx, r.db.db, query, arg))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("user not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
+
+// scanUser reads a row of userColumns
+func scanUser(row scanner) (*entity.User, error) {
+	var user entity.User
+	var createdAt, updatedAt int64
+
+	if err := row.Scan(&user.ID, &user.Email, &user.Username, &user.Role, &user.PasswordHash, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+
+	user.CreatedAt = fromUnixNano(createdAt)
+	user.UpdatedAt = fromUnixNano(updatedAt)
+	return &user, nil
+}
@@ -0,0 +1,57 @@
+package sqlstore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"smart-monitor/backend/internal/domain/entity"
+)
+
+func testUser() *entity.User {
+	return &entity.User{
+		ID:           "user-1",
+		Email:        "admin@example.com",
+		Username:     "admin",
+		Role:         "admin",
+		PasswordHash: "$2a$10$hash",
+		CreatedAt:    testTime(0),
+		UpdatedAt:    testTime(0),
+	}
+}
+
+func TestSQLUserRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewSQLUserRepository(openTestDB(t))
+	user := testUser()
+
+	if err := repo.Create(ctx, user); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := repo.Create(ctx, user); err == nil {
+		t.Error("Create() of an existing user succeeded")
+	}
+
+	got, err := repo.GetByEmail(ctx, user.Email)
+	if err != nil {
+		t.Fatalf("GetByEmail() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, user) {
+		t.Errorf("GetByEmail() = %+v, want %+v", got, user)
+	}
+
+	user.Role = "viewer"
+	if err := repo.Update(ctx, user); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if got, err := repo.GetByID(ctx, user.ID); err != nil || got.Role != "viewer" {
+		t.Errorf("GetByID() = %+v, %v, want the updated role", got, err)
+	}
+
+	if _, err := repo.GetByID(ctx, "missing"); err == nil {
+		t.Error("GetByID() of a missing user succeeded")
+	}
+	if err := repo.Update(ctx, &entity.User{ID: "missing"}); err == nil {
+		t.Error("Update() of a missing user succeeded")
+	}
+}
@@ -0,0 +1,325 @@
+// Package tsdb implements an embedded on-disk time-series store for stats
+package tsdb
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const blockExt = ".block"
+
+// blockMagic starts and ends every block file
+var blockMagic = []byte("SMTSDB01")
+
+// footerSize is the size of the index length, its checksum and the magic
+// ending a block file
+var footerSize = 8 + len(blockMagic)
+
+// sample holds the range metrics of one stats sample
+type sample struct {
+	t      int64     // Unix milliseconds
+	values []float64 // in the order of the metrics of the head or block
+}
+
+// blockMeta is the index of a block, stored at its end
+type blockMeta struct {
+	WALSegment uint64      `json:"wal_segment"` // last WAL segment written to the block
+	MinTime    int64       `json:"min_time"`
+	MaxTime    int64       `json:"max_time"`
+	Metrics    []string    `json:"metrics"`
+	Chunks     []chunkMeta `json:"chunks"`
+}
+
+// chunkMeta locates the compressed samples of one host and agent in a block
+type chunkMeta struct {
+	Hostname string `json:"hostname"`
+	AgentID  string `json:"agent_id"`
+	MinTime  int64  `json:"min_time"`
+	MaxTime  int64  `json:"max_time"`
+	Samples  int    `json:"samples"`
+	Offset   int64  `json:"offset"`
+	Length   int64  `json:"length"`
+}
+
+// block is an immutable file of samples written from the head at a checkpoint
+type block struct {
+	path string
+	size int64
+	meta blockMeta
+}
+
+// chunkKey groups the samples of a block into chunks
+type chunkKey struct {
+	hostname string
+	agentID  string
+}
+
+// writeBlock writes the samples of a head to a new block file named after the
+// last WAL segment they were logged in. The file only appears once complete.
+func writeBlock(dir string, walSegment uint64, metrics []string, chunks map[chunkKey][]sample) (*block, error) {
+	keys := make([]chunkKey, 0, len(chunks))
+	for key := range chunks {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].hostname != keys[j].hostname {
+			return keys[i].hostname < keys[j].hostname
+		}
+		return keys[i].agentID < keys[j].agentID
+	})
+
+	path := filepath.Join(dir, fmt.Sprintf("%012d%s", walSegment, blockExt))
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create block: %w", err)
+	}
+	defer os.Remove(tmp)
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	offset := int64(len(blockMagic))
+	w.Write(blockMagic)
+
+	meta := blockMeta{
+		WALSegment: walSegment,
+		MinTime:    math.MaxInt64,
+		MaxTime:    math.MinInt64,
+		Metrics:    metrics,
+		Chunks:     make([]chunkMeta, 0, len(keys)),
+	}
+	for _, key := range keys {
+		samples := chunks[key]
+		sort.SliceStable(samples, func(i, j int) bool { return samples[i].t < samples[j].t })
+
+		data, err := encodeChunk(samples, len(metrics))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, fmt.Errorf("failed to write block: %w", err)
+		}
+
+		chunk := chunkMeta{
+			Hostname: key.hostname,
+			AgentID:  key.agentID,
+			MinTime:  samples[0].t,
+			MaxTime:  samples[len(samples)-1].t,
+			Samples:  len(samples),
+			Offset:   offset,
+			Length:   int64(len(data)),
+		}
+		meta.Chunks = append(meta.Chunks, chunk)
+		meta.MinTime = min(meta.MinTime, chunk.MinTime)
+		meta.MaxTime = max(meta.MaxTime, chunk.MaxTime)
+		offset += chunk.Length
+	}
+
+	index, err := json.Marshal(meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode block index: %w", err)
+	}
+	footer := make([]byte, 8, footerSize)
+	binary.BigEndian.PutUint32(footer[:4], uint32(len(index)))
+	binary.BigEndian.PutUint32(footer[4:], crc32.ChecksumIEEE(index))
+	footer = append(footer, blockMagic...)
+	w.Write(index)
+	if _, err := w.Write(footer); err != nil {
+		return nil, fmt.Errorf("failed to write block: %w", err)
+	}
+
+	if err := w.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to write block: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return nil, fmt.Errorf("failed to sync block: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close block: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, fmt.Errorf("failed to finish block: %w", err)
+	}
+	syncDir(dir)
+
+	return &block{
+		path: path,
+		size: offset + int64(len(index)+footerSize),
+		meta: meta,
+	}, nil
+}
+
+// openBlock reads the index of a block file
+func openBlock(path string) (*block, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open block: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open block: %w", err)
+	}
+	size := info.Size()
+	if size < int64(len(blockMagic)+footerSize) {
+		return nil, errors.New("block is truncated")
+	}
+
+	footer := make([]byte, footerSize)
+	if _, err := f.ReadAt(footer, size-int64(footerSize)); err != nil {
+		return nil, fmt.Errorf("failed to read block footer: %w", err)
+	}
+	if !bytes.Equal(footer[8:], blockMagic) {
+		return nil, errors.New("block footer is damaged")
+	}
+
+	length := int64(binary.BigEndian.Uint32(footer[:4]))
+	if length > size-int64(len(blockMagic)+footerSize) {
+		return nil, fmt.Errorf("invalid block index length %d", length)
+	}
+	index := make([]byte, length)
+	if _, err := f.ReadAt(index, size-int64(footerSize)-length); err != nil {
+		return nil, fmt.Errorf("failed to read block index: %w", err)
+	}
+	if crc32.ChecksumIEEE(index) != binary.BigEndian.Uint32(footer[4:8]) {
+		return nil, errors.New("block index checksum mismatch")
+	}
+
+	b := &block{path: path, size: size}
+	if err := json.Unmarshal(index, &b.meta); err != nil {
+		return nil, fmt.Errorf("failed to decode block index: %w", err)
+	}
+	return b, nil
+}
+
+// readChunks reads the samples of some chunks of the block
+func (b *block) readChunks(chunks []chunkMeta) ([][]sample, error) {
+	f, err := os.Open(b.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open block: %w", err)
+	}
+	defer f.Close()
+
+	result := make([][]sample, 0, len(chunks))
+	for _, chunk := range chunks {
+		data := make([]byte, chunk.Length)
+		if _, err := f.ReadAt(data, chunk.Offset); err != nil {
+			return nil, fmt.Errorf("failed to read chunk: %w", err)
+		}
+		samples, err := decodeChunk(data, len(b.meta.Metrics))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode chunk of %s in %s: %w", chunk.Hostname, filepath.Base(b.path), err)
+		}
+		result = append(result, samples)
+	}
+	return result, nil
+}
+
+// encodeChunk compresses samples sorted by time. Timestamps are stored as
+// deltas of deltas and each metric as the XOR of consecutive values, which
+// leaves mostly zero bytes for deflate to squeeze out.
+func encodeChunk(samples []sample, metricCount int) ([]byte, error) {
+	raw := binary.AppendUvarint(nil, uint64(len(samples)))
+
+	var prevT, prevDelta int64
+	for i, s := range samples {
+		if i == 0 {
+			raw = binary.AppendVarint(raw, s.t)
+		} else {
+			delta := s.t - prevT
+			raw = binary.AppendVarint(raw, delta-prevDelta)
+			prevDelta = delta
+		}
+		prevT = s.t
+	}
+
+	for m := 0; m < metricCount; m++ {
+		var prev uint64
+		for _, s := range samples {
+			bits := math.Float64bits(s.values[m])
+			raw = binary.BigEndian.AppendUint64(raw, bits^prev)
+			prev = bits
+		}
+	}
+
+	var buf bytes.Buffer
+	zw, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress chunk: %w", err)
+	}
+	zw.Write(raw)
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress chunk: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeChunk reverses encodeChunk
+func decodeChunk(data []byte, metricCount int) ([]sample, error) {
+	raw, err := io.ReadAll(flate.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	count, n := binary.Uvarint(raw)
+	if n <= 0 || count > uint64(len(raw)) {
+		return nil, errors.New("invalid sample count")
+	}
+	raw = raw[n:]
+
+	samples := make([]sample, count)
+	var prevT, prevDelta int64
+	for i := range samples {
+		v, n := binary.Varint(raw)
+		if n <= 0 {
+			return nil, errors.New("truncated timestamps")
+		}
+		raw = raw[n:]
+		if i == 0 {
+			samples[i].t = v
+		} else {
+			prevDelta += v
+			samples[i].t = prevT + prevDelta
+		}
+		prevT = samples[i].t
+	}
+
+	if len(raw) < 8*metricCount*len(samples) {
+		return nil, errors.New("truncated values")
+	}
+	values := make([]float64, metricCount*len(samples))
+	for i := range samples {
+		samples[i].values = values[i*metricCount : (i+1)*metricCount]
+	}
+	for m := 0; m < metricCount; m++ {
+		var prev uint64
+		for i := range samples {
+			bits := binary.BigEndian.Uint64(raw) ^ prev
+			raw = raw[8:]
+			samples[i].values[m] = math.Float64frombits(bits)
+			prev = bits
+		}
+	}
+
+	return samples, nil
+}
+
+// syncDir makes a rename in dir durable
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
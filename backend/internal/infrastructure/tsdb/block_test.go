@@ -0,0 +1,161 @@
+package tsdb
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncodeDecodeChunk(t *testing.T) {
+	tests := []struct {
+		name        string
+		metricCount int
+		samples     []sample
+	}{
+		{
+			name:        "empty",
+			metricCount: 3,
+			samples:     []sample{},
+		},
+		{
+			name:        "single sample",
+			metricCount: 2,
+			samples:     []sample{{t: 1700000000000, values: []float64{12.5, 80}}},
+		},
+		{
+			name:        "regular interval",
+			metricCount: 3,
+			samples: []sample{
+				{t: 1700000000000, values: []float64{10, 20, 30}},
+				{t: 1700000010000, values: []float64{10, 20, 30}},
+				{t: 1700000020000, values: []float64{11.25, 20, 29.5}},
+				{t: 1700000030000, values: []float64{12, 21, 29}},
+			},
+		},
+		{
+			name:        "irregular interval and duplicate time",
+			metricCount: 1,
+			samples: []sample{
+				{t: 1700000000000, values: []float64{1}},
+				{t: 1700000000001, values: []float64{2}},
+				{t: 1700000000001, values: []float64{3}},
+				{t: 1700003600000, values: []float64{4}},
+				{t: 1700003600500, values: []float64{5}},
+			},
+		},
+		{
+			name:        "special values",
+			metricCount: 4,
+			samples: []sample{
+				{t: 0, values: []float64{0, -1.5, math.MaxFloat64, math.SmallestNonzeroFloat64}},
+				{t: -1000, values: []float64{math.Inf(1), math.Inf(-1), math.Copysign(0, -1), 1e-300}},
+			},
+		},
+		{
+			name:        "no metrics",
+			metricCount: 0,
+			samples:     []sample{{t: 1, values: []float64{}}, {t: 2, values: []float64{}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := encodeChunk(tt.samples, tt.metricCount)
+			if err != nil {
+				t.Fatalf("encodeChunk() error = %v", err)
+			}
+			got, err := decodeChunk(data, tt.metricCount)
+			if err != nil {
+				t.Fatalf("decodeChunk() error = %v", err)
+			}
+			assertSamples(t, got, tt.samples)
+		})
+	}
+}
+
+func TestEncodeDecodeChunkNaN(t *testing.T) {
+	samples := []sample{{t: 1, values: []float64{math.NaN()}}, {t: 2, values: []float64{1}}}
+
+	data, err := encodeChunk(samples, 1)
+	if err != nil {
+		t.Fatalf("encodeChunk() error = %v", err)
+	}
+	got, err := decodeChunk(data, 1)
+	if err != nil {
+		t.Fatalf("decodeChunk() error = %v", err)
+	}
+	if len(got) != 2 || !math.IsNaN(got[0].values[0]) || got[1].values[0] != 1 {
+		t.Errorf("decodeChunk() = %v, want [NaN 1]", got)
+	}
+}
+
+func TestDecodeChunkRejectsDamagedData(t *testing.T) {
+	data, err := encodeChunk([]sample{{t: 1, values: []float64{1, 2}}, {t: 2, values: []float64{3, 4}}}, 2)
+	if err != nil {
+		t.Fatalf("encodeChunk() error = %v", err)
+	}
+
+	if _, err := decodeChunk(data[:len(data)/2], 2); err == nil {
+		t.Error("decodeChunk() of a truncated chunk succeeded")
+	}
+	if _, err := decodeChunk(data, 3); err == nil {
+		t.Error("decodeChunk() with more metrics than encoded succeeded")
+	}
+}
+
+func TestWriteOpenBlock(t *testing.T) {
+	dir := t.TempDir()
+	metrics := []string{"cpu", "ram"}
+	chunks := map[chunkKey][]sample{
+		{hostname: "web-01", agentID: "agent-a"}: {
+			{t: 1000, values: []float64{1, 2}},
+			{t: 2000, values: []float64{3, 4}},
+		},
+		{hostname: "web-02", agentID: "agent-b"}: {
+			{t: 1500, values: []float64{5, 6}},
+		},
+	}
+
+	written, err := writeBlock(dir, 7, metrics, chunks)
+	if err != nil {
+		t.Fatalf("writeBlock() error = %v", err)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(matches) > 0 {
+		t.Errorf("writeBlock() left temporary files %v", matches)
+	}
+
+	b, err := openBlock(written.path)
+	if err != nil {
+		t.Fatalf("openBlock() error = %v", err)
+	}
+	if b.meta.WALSegment != 7 || b.meta.MinTime != 1000 || b.meta.MaxTime != 2000 || len(b.meta.Chunks) != 2 {
+		t.Fatalf("openBlock() meta = %+v", b.meta)
+	}
+
+	read, err := b.readChunks(b.meta.Chunks)
+	if err != nil {
+		t.Fatalf("readChunks() error = %v", err)
+	}
+	for i, chunk := range b.meta.Chunks {
+		assertSamples(t, read[i], chunks[chunkKey{hostname: chunk.Hostname, agentID: chunk.AgentID}])
+	}
+}
+
+// assertSamples compares samples by time and value bits
+func assertSamples(t *testing.T, got, want []sample) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d samples, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].t != want[i].t || len(got[i].values) != len(want[i].values) {
+			t.Fatalf("sample %d = %v, want %v", i, got[i], want[i])
+		}
+		for m := range want[i].values {
+			if math.Float64bits(got[i].values[m]) != math.Float64bits(want[i].values[m]) {
+				t.Fatalf("sample %d metric %d = %v, want %v", i, m, got[i].values[m], want[i].values[m])
+			}
+		}
+	}
+}
@@ -0,0 +1,711 @@
+// Package tsdb implements an embedded on-disk time-series store for stats
+package tsdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/repository"
+)
+
+const (
+	walDir    = "wal"
+	blocksDir = "blocks"
+	stateFile = "state.gob"
+
+	defaultBlockDuration = 2 * time.Hour
+
+	// maintenanceInterval is how often the store checks whether the head is
+	// due to be written to a block and old blocks are due to be removed
+	maintenanceInterval = time.Minute
+)
+
+// Options configures a TSDBStatsRepository
+type Options struct {
+	BlockDuration time.Duration // how long samples stay in the head before they are written to a block
+	Retention     time.Duration // blocks with only older samples are removed, zero keeps them
+	MaxBytes      int64         // oldest blocks are removed beyond this total size, zero is unlimited
+}
+
+// headSample is a sample not yet written to a block
+type headSample struct {
+	agentID string
+	sample
+}
+
+// dbState is what the store keeps besides samples, saved at every checkpoint
+type dbState struct {
+	Latest       map[string]*entity.Stats // latest sample per host
+	AgentAliases map[string]string        // merged agent ID -> agent it was merged into
+	Tombstones   map[string]uint64        // deleted host -> samples in blocks before this WAL segment are gone
+}
+
+// TSDBStatsRepository implements StatsRepository with an embedded time-series
+// store in a directory. Samples are logged to a WAL, kept in memory as the
+// head and written to compressed, immutable blocks every BlockDuration. The
+// range metrics of every sample are kept; for Get and GetAll, the full latest
+// sample of each host.
+type TSDBStatsRepository struct {
+	dir     string
+	options Options
+	metrics []string // range metrics, in the order head samples hold them
+
+	mu        sync.RWMutex
+	state     dbState
+	head      map[string][]headSample // by hostname
+	headSince time.Time               // when the first head sample arrived
+	flushing  map[string][]headSample // head being written to a block
+	blocks    []*block
+	wal       *wal
+
+	checkpointMu sync.Mutex // serializes checkpoints
+	stop         chan struct{}
+	done         chan struct{}
+}
+
+// NewTSDBStatsRepository opens or creates a store in dir and recovers the
+// samples logged since the last checkpoint
+func NewTSDBStatsRepository(dir string, options Options) (*TSDBStatsRepository, error) {
+	for _, sub := range []string{walDir, blocksDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0700); err != nil {
+			return nil, fmt.Errorf("failed to create TSDB directory: %w", err)
+		}
+	}
+
+	if options.BlockDuration <= 0 {
+		options.BlockDuration = defaultBlockDuration
+	}
+
+	r := &TSDBStatsRepository{
+		dir:     dir,
+		options: options,
+		metrics: entity.RangeMetrics(),
+		state: dbState{
+			Latest:       make(map[string]*entity.Stats),
+			AgentAliases: make(map[string]string),
+			Tombstones:   make(map[string]uint64),
+		},
+		head: make(map[string][]headSample),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	if err := r.loadBlocks(); err != nil {
+		return nil, err
+	}
+	if err := r.loadState(); err != nil {
+		return nil, err
+	}
+
+	// Segments up to the newest block were written to it already; they only
+	// bring the latest samples and other state up to date
+	var flushed uint64
+	for _, b := range r.blocks {
+		flushed = max(flushed, b.meta.WALSegment)
+	}
+	last := flushed
+	replayed := 0
+	err := replayWAL(filepath.Join(dir, walDir), func(seq uint64, record *walRecord) {
+		r.apply(seq, record, seq > flushed)
+		last = max(last, seq)
+		replayed++
+	})
+	if err != nil {
+		return nil, err
+	}
+	if replayed > 0 {
+		log.Printf("✓ TSDB recovered %d WAL records", replayed)
+	}
+
+	if r.wal, err = openWAL(filepath.Join(dir, walDir), last); err != nil {
+		return nil, err
+	}
+
+	r.applyRetention()
+	go r.maintain()
+
+	return r, nil
+}
+
+// Close writes the head to a block and closes the store
+func (r *TSDBStatsRepository) Close() error {
+	close(r.stop)
+	<-r.done
+
+	err := r.checkpoint()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return errors.Join(err, r.wal.close())
+}
+
+// Save stores stats
+func (r *TSDBStatsRepository) Save(ctx context.Context, stats *entity.Stats) error {
+	return r.SaveBatch(ctx, []*entity.Stats{stats})
+}
+
+// SaveBatch stores samples with a single WAL write
+func (r *TSDBStatsRepository) SaveBatch(ctx context.Context, stats []*entity.Stats) error {
+	records := make([]*walRecord, len(stats))
+	for i, s := range stats {
+		records[i] = &walRecord{Op: walOpSave, Stats: s}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.wal.append(records...); err != nil {
+		return err
+	}
+	for _, record := range records {
+		r.apply(r.wal.seq, record, true)
+	}
+	return nil
+}
+
+// Get retrieves the latest stats of a host
+func (r *TSDBStatsRepository) Get(ctx context.Context, hostname string) (*entity.Stats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stats, exists := r.state.Latest[hostname]
+	if !exists {
+		return nil, fmt.Errorf("%w for hostname: %s", repository.ErrStatsNotFound, hostname)
+	}
+
+	return stats, nil
+}
+
+// GetByAgent retrieves the latest stats of the host an agent reported most
+// recently
+func (r *TSDBStatsRepository) GetByAgent(ctx context.Context, agentID string) (*entity.Stats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var latest *entity.Stats
+	for _, stats := range r.state.Latest {
+		if stats.AgentID != agentID {
+			continue
+		}
+		if latest == nil || stats.Timestamp.After(latest.Timestamp) {
+			latest = stats
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("%w for agent: %s", repository.ErrStatsNotFound, agentID)
+	}
+
+	return latest, nil
+}
+
+// GetAll retrieves the latest stats of every host
+func (r *TSDBStatsRepository) GetAll(ctx context.Context) ([]*entity.Stats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entity.Stats, 0, len(r.state.Latest))
+	for _, stats := range r.state.Latest {
+		result = append(result, stats)
+	}
+
+	return result, nil
+}
+
+// Delete removes the stats and history of a host
+func (r *TSDBStatsRepository) Delete(ctx context.Context, hostname string) error {
+	record := &walRecord{Op: walOpDelete, Hostname: hostname}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.wal.append(record); err != nil {
+		return err
+	}
+	r.apply(r.wal.seq, record, true)
+	return nil
+}
+
+// GetActiveHosts returns the hostnames with stats
+func (r *TSDBStatsRepository) GetActiveHosts(ctx context.Context) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	hosts := make([]string, 0, len(r.state.Latest))
+	for hostname := range r.state.Latest {
+		hosts = append(hosts, hostname)
+	}
+
+	return hosts, nil
+}
+
+// ReassignAgent moves the samples of fromAgentID to toAgentID. Samples in
+// blocks keep their agent ID on disk and are read as toAgentID.
+func (r *TSDBStatsRepository) ReassignAgent(ctx context.Context, fromAgentID, toAgentID string) (int64, error) {
+	record := &walRecord{Op: walOpReassign, FromAgentID: fromAgentID, ToAgentID: toAgentID}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var moved int64
+	for _, b := range r.blocks {
+		for _, chunk := range b.meta.Chunks {
+			if r.visible(b, &chunk) && r.resolveAgent(chunk.AgentID) == fromAgentID {
+				moved += int64(chunk.Samples)
+			}
+		}
+	}
+	for _, samples := range []map[string][]headSample{r.head, r.flushing} {
+		for _, host := range samples {
+			for _, s := range host {
+				if s.agentID == fromAgentID {
+					moved++
+				}
+			}
+		}
+	}
+
+	if err := r.wal.append(record); err != nil {
+		return 0, err
+	}
+	r.apply(r.wal.seq, record, true)
+	return moved, nil
+}
+
+// QueryRange aggregates the samples of the head and the blocks
+func (r *TSDBStatsRepository) QueryRange(ctx context.Context, query *entity.StatsRangeQuery) ([]entity.StatsSeries, error) {
+	builder := entity.NewStatsRangeBuilder(query)
+	err := r.scan(query.Hostname, query.AgentID, query.From, query.To, func(hostname, agentID string, metrics []string, s *sample) {
+		builder.Add(time.UnixMilli(s.t), func(metric string) (float64, bool) {
+			index := slices.Index(metrics, metric)
+			if index < 0 {
+				return 0, false
+			}
+			return s.values[index], true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return builder.Series(), nil
+}
+
+// GetHistory retrieves samples for an agent between from and to, oldest
+// first. Only the range metrics of past samples are kept, other fields are zero.
+func (r *TSDBStatsRepository) GetHistory(ctx context.Context, agentID string, from, to time.Time) ([]*entity.Stats, error) {
+	var history []*entity.Stats
+	err := r.scan("", agentID, from, to, func(hostname, agentID string, metrics []string, s *sample) {
+		t := time.UnixMilli(s.t)
+		stats := &entity.Stats{Hostname: hostname, AgentID: agentID, Timestamp: t, LastReceived: t}
+		for i, metric := range metrics {
+			stats.SetMetricValue(metric, s.values[i])
+		}
+		history = append(history, stats)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(history, func(i, j int) bool { return history[i].Timestamp.Before(history[j].Timestamp) })
+	return history, nil
+}
+
+// scan calls fn for the samples of a host, an agent or both between from and
+// to, in no particular order
+func (r *TSDBStatsRepository) scan(hostname, agentID string, from, to time.Time, fn func(hostname, agentID string, metrics []string, s *sample)) error {
+	fromMs, toMs := from.UnixMilli(), to.UnixMilli()
+
+	// Collect what to read under the lock, read the block files without it
+	type blockChunks struct {
+		block  *block
+		chunks []chunkMeta
+	}
+	var reads []blockChunks
+
+	r.mu.RLock()
+	for _, b := range r.blocks {
+		if b.meta.MaxTime < fromMs || b.meta.MinTime > toMs {
+			continue
+		}
+		read := blockChunks{block: b}
+		for _, chunk := range b.meta.Chunks {
+			if hostname != "" && chunk.Hostname != hostname {
+				continue
+			}
+			if chunk.MaxTime < fromMs || chunk.MinTime > toMs || !r.visible(b, &chunk) {
+				continue
+			}
+			chunk.AgentID = r.resolveAgent(chunk.AgentID)
+			if agentID != "" && chunk.AgentID != agentID {
+				continue
+			}
+			read.chunks = append(read.chunks, chunk)
+		}
+		if len(read.chunks) > 0 {
+			reads = append(reads, read)
+		}
+	}
+
+	for _, samples := range []map[string][]headSample{r.flushing, r.head} {
+		for host, hostSamples := range samples {
+			if hostname != "" && host != hostname {
+				continue
+			}
+			for i := range hostSamples {
+				s := &hostSamples[i]
+				if s.t < fromMs || s.t > toMs || (agentID != "" && s.agentID != agentID) {
+					continue
+				}
+				fn(host, s.agentID, r.metrics, &s.sample)
+			}
+		}
+	}
+	r.mu.RUnlock()
+
+	for _, read := range reads {
+		chunks, err := read.block.readChunks(read.chunks)
+		if errors.Is(err, os.ErrNotExist) {
+			// Removed by retention meanwhile
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for i, samples := range chunks {
+			chunk := &read.chunks[i]
+			for j := range samples {
+				if samples[j].t < fromMs || samples[j].t > toMs {
+					continue
+				}
+				fn(chunk.Hostname, chunk.AgentID, read.block.meta.Metrics, &samples[j])
+			}
+		}
+	}
+
+	return nil
+}
+
+// apply applies a logged change. Samples go to the head only if addToHead,
+// as they are in a block already otherwise. The caller holds mu.
+func (r *TSDBStatsRepository) apply(seq uint64, record *walRecord, addToHead bool) {
+	switch record.Op {
+	case walOpSave:
+		stats := record.Stats
+		if current, exists := r.state.Latest[stats.Hostname]; !exists || !stats.Timestamp.Before(current.Timestamp) {
+			r.state.Latest[stats.Hostname] = stats
+		}
+		if !addToHead {
+			return
+		}
+
+		s := headSample{agentID: stats.AgentID, sample: sample{t: stats.Timestamp.UnixMilli(), values: make([]float64, len(r.metrics))}}
+		for i, metric := range r.metrics {
+			s.values[i], _ = stats.MetricValue(metric)
+		}
+		if len(r.head) == 0 {
+			r.headSince = time.Now()
+		}
+		r.head[stats.Hostname] = append(r.head[stats.Hostname], s)
+
+	case walOpDelete:
+		delete(r.state.Latest, record.Hostname)
+		delete(r.head, record.Hostname)
+		r.state.Tombstones[record.Hostname] = seq
+		if r.flushing != nil {
+			// Written to a block from a segment before seq, so the tombstone covers it
+			delete(r.flushing, record.Hostname)
+		}
+
+	case walOpReassign:
+		if record.FromAgentID == record.ToAgentID {
+			return
+		}
+		r.state.AgentAliases[record.FromAgentID] = record.ToAgentID
+		for hostname, stats := range r.state.Latest {
+			if stats.AgentID == record.FromAgentID {
+				reassigned := *stats
+				reassigned.AgentID = record.ToAgentID
+				r.state.Latest[hostname] = &reassigned
+			}
+		}
+		for _, samples := range []map[string][]headSample{r.head, r.flushing} {
+			for hostname, hostSamples := range samples {
+				// Copied so scans that already took the slice are not affected
+				reassigned := slices.Clone(hostSamples)
+				for i := range reassigned {
+					if reassigned[i].agentID == record.FromAgentID {
+						reassigned[i].agentID = record.ToAgentID
+					}
+				}
+				samples[hostname] = reassigned
+			}
+		}
+	}
+}
+
+// visible reports whether a chunk of a block was not deleted. The caller holds mu.
+func (r *TSDBStatsRepository) visible(b *block, chunk *chunkMeta) bool {
+	deletedAt, deleted := r.state.Tombstones[chunk.Hostname]
+	return !deleted || b.meta.WALSegment >= deletedAt
+}
+
+// resolveAgent follows merges of an agent ID. The caller holds mu.
+func (r *TSDBStatsRepository) resolveAgent(agentID string) string {
+	for range len(r.state.AgentAliases) {
+		merged, ok := r.state.AgentAliases[agentID]
+		if !ok {
+			break
+		}
+		agentID = merged
+	}
+	return agentID
+}
+
+// maintain writes the head to a block every BlockDuration and applies the
+// retention limits until the store is closed
+func (r *TSDBStatsRepository) maintain() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(min(maintenanceInterval, r.options.BlockDuration))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.mu.RLock()
+			due := len(r.head) > 0 && time.Since(r.headSince) >= r.options.BlockDuration
+			r.mu.RUnlock()
+
+			if due {
+				if err := r.checkpoint(); err != nil {
+					log.Printf("⚠ TSDB checkpoint failed: %v", err)
+				}
+			}
+			r.applyRetention()
+		}
+	}
+}
+
+// checkpoint writes the head to a block, saves the state and removes the WAL
+// segments that are no longer needed
+func (r *TSDBStatsRepository) checkpoint() error {
+	r.checkpointMu.Lock()
+	defer r.checkpointMu.Unlock()
+
+	r.mu.Lock()
+	seq, err := r.wal.rotate()
+	if err != nil {
+		r.mu.Unlock()
+		return err
+	}
+	r.flushing = r.head
+	r.head = make(map[string][]headSample)
+	state := r.copyState()
+
+	// Built under the lock, as Delete and ReassignAgent change r.flushing
+	chunks := make(map[chunkKey][]sample)
+	for hostname, samples := range r.flushing {
+		for _, s := range samples {
+			key := chunkKey{hostname: hostname, agentID: s.agentID}
+			chunks[key] = append(chunks[key], s.sample)
+		}
+	}
+	r.mu.Unlock()
+
+	var written *block
+	if len(chunks) > 0 {
+		written, err = writeBlock(filepath.Join(r.dir, blocksDir), seq, r.metrics, chunks)
+		if err != nil {
+			// Keep the samples in the head; their WAL segments are kept too
+			r.mu.Lock()
+			for hostname, samples := range r.flushing {
+				r.head[hostname] = append(samples, r.head[hostname]...)
+			}
+			r.flushing = nil
+			r.headSince = time.Now()
+			r.mu.Unlock()
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	if written != nil {
+		r.blocks = append(r.blocks, written)
+	}
+	r.flushing = nil
+	r.mu.Unlock()
+
+	if err := r.saveState(state); err != nil {
+		return err
+	}
+	if err := r.wal.removeThrough(seq); err != nil {
+		return err
+	}
+
+	if written != nil {
+		log.Printf("✓ TSDB wrote block %s (%d series, %d bytes)", filepath.Base(written.path), len(written.meta.Chunks), written.size)
+	}
+	return nil
+}
+
+// applyRetention removes blocks past the retention period or size limit
+func (r *TSDBStatsRepository) applyRetention() {
+	r.mu.Lock()
+	sort.Slice(r.blocks, func(i, j int) bool { return r.blocks[i].meta.MaxTime < r.blocks[j].meta.MaxTime })
+
+	var removed []*block
+	if r.options.Retention > 0 {
+		cutoff := time.Now().Add(-r.options.Retention).UnixMilli()
+		for len(r.blocks) > 0 && r.blocks[0].meta.MaxTime < cutoff {
+			removed = append(removed, r.blocks[0])
+			r.blocks = r.blocks[1:]
+		}
+	}
+	if r.options.MaxBytes > 0 {
+		var total int64
+		for _, b := range r.blocks {
+			total += b.size
+		}
+		for len(r.blocks) > 0 && total > r.options.MaxBytes {
+			total -= r.blocks[0].size
+			removed = append(removed, r.blocks[0])
+			r.blocks = r.blocks[1:]
+		}
+	}
+
+	// A tombstone is done once no block from before the deletion is left
+	for hostname, deletedAt := range r.state.Tombstones {
+		if !slices.ContainsFunc(r.blocks, func(b *block) bool { return b.meta.WALSegment < deletedAt }) {
+			delete(r.state.Tombstones, hostname)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, b := range removed {
+		if err := os.Remove(b.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Printf("⚠ Failed to remove TSDB block %s: %v", filepath.Base(b.path), err)
+		}
+	}
+	if len(removed) > 0 {
+		log.Printf("✓ TSDB removed %d blocks past the retention limits", len(removed))
+	}
+}
+
+// loadBlocks opens the block files, skipping damaged ones and removing
+// blocks that were not finished
+func (r *TSDBStatsRepository) loadBlocks() error {
+	dir := filepath.Join(r.dir, blocksDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read TSDB blocks: %w", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasSuffix(name, blockExt+".tmp") {
+			os.Remove(filepath.Join(dir, name))
+			continue
+		}
+		if entry.IsDir() || !strings.HasSuffix(name, blockExt) {
+			continue
+		}
+
+		b, err := openBlock(filepath.Join(dir, name))
+		if err != nil {
+			log.Printf("⚠ Skipping TSDB block %s: %v", name, err)
+			continue
+		}
+		r.blocks = append(r.blocks, b)
+	}
+
+	return nil
+}
+
+// loadState reads the state saved at the last checkpoint
+func (r *TSDBStatsRepository) loadState() error {
+	data, err := os.ReadFile(filepath.Join(r.dir, stateFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read TSDB state: %w", err)
+	}
+
+	var state dbState
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&state); err != nil {
+		return fmt.Errorf("failed to decode TSDB state: %w", err)
+	}
+	if state.Latest != nil {
+		r.state.Latest = state.Latest
+	}
+	if state.AgentAliases != nil {
+		r.state.AgentAliases = state.AgentAliases
+	}
+	if state.Tombstones != nil {
+		r.state.Tombstones = state.Tombstones
+	}
+	return nil
+}
+
+// saveState replaces the saved state
+func (r *TSDBStatsRepository) saveState(state dbState) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(state); err != nil {
+		return fmt.Errorf("failed to encode TSDB state: %w", err)
+	}
+
+	path := filepath.Join(r.dir, stateFile)
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to save TSDB state: %w", err)
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to save TSDB state: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to save TSDB state: %w", err)
+	}
+	f.Close()
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to save TSDB state: %w", err)
+	}
+	syncDir(r.dir)
+	return nil
+}
+
+// copyState copies the maps of the state so it can be saved without mu. The
+// samples are never modified once stored. The caller holds mu.
+func (r *TSDBStatsRepository) copyState() dbState {
+	state := dbState{
+		Latest:       make(map[string]*entity.Stats, len(r.state.Latest)),
+		AgentAliases: make(map[string]string, len(r.state.AgentAliases)),
+		Tombstones:   make(map[string]uint64, len(r.state.Tombstones)),
+	}
+	for k, v := range r.state.Latest {
+		state.Latest[k] = v
+	}
+	for k, v := range r.state.AgentAliases {
+		state.AgentAliases[k] = v
+	}
+	for k, v := range r.state.Tombstones {
+		state.Tombstones[k] = v
+	}
+	return state
+}
@@ -0,0 +1,313 @@
+package tsdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"smart-monitor/backend/internal/domain/entity"
+	"smart-monitor/backend/internal/domain/repository"
+)
+
+// openTestRepository opens a store in dir that only writes blocks when a test
+// checkpoints, closed when the test ends unless the test closed it
+func openTestRepository(t *testing.T, dir string, options Options) *TSDBStatsRepository {
+	t.Helper()
+
+	if options.BlockDuration == 0 {
+		options.BlockDuration = time.Hour
+	}
+	r, err := NewTSDBStatsRepository(dir, options)
+	if err != nil {
+		t.Fatalf("NewTSDBStatsRepository() error = %v", err)
+	}
+
+	t.Cleanup(func() {
+		select {
+		case <-r.stop:
+			// Closed or crashed by the test
+		default:
+			r.Close()
+		}
+	})
+	return r
+}
+
+// crash stops a store the way a killed process would, without writing the
+// head to a block
+func crash(r *TSDBStatsRepository) {
+	close(r.stop)
+	<-r.done
+	r.wal.close()
+}
+
+// testStats returns a sample of a host with all range metrics set to value
+func testStats(hostname, agentID string, timestamp time.Time, value float64) *entity.Stats {
+	return &entity.Stats{
+		Hostname:     hostname,
+		AgentID:      agentID,
+		CPU:          value,
+		RAM:          value,
+		Disk:         value,
+		Timestamp:    timestamp,
+		LastReceived: timestamp,
+	}
+}
+
+// history returns all samples of an agent
+func history(t *testing.T, r *TSDBStatsRepository, agentID string) []*entity.Stats {
+	t.Helper()
+
+	samples, err := r.GetHistory(context.Background(), agentID, time.Unix(0, 0), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetHistory(%s) error = %v", agentID, err)
+	}
+	return samples
+}
+
+func TestCheckpointConcurrentWithDeleteAndReassign(t *testing.T) {
+	ctx := context.Background()
+	r := openTestRepository(t, t.TempDir(), Options{})
+	start := time.Now().Add(-time.Hour)
+
+	for round := range 20 {
+		var batch []*entity.Stats
+		for host := range 10 {
+			hostname := fmt.Sprintf("host-%d", host)
+			for i := range 5 {
+				batch = append(batch, testStats(hostname, fmt.Sprintf("agent-%d-%d", round, host), start.Add(time.Duration(round*5+i)*time.Second), float64(i)))
+			}
+		}
+		if err := r.SaveBatch(ctx, batch); err != nil {
+			t.Fatalf("SaveBatch() error = %v", err)
+		}
+
+		var wg sync.WaitGroup
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			if err := r.checkpoint(); err != nil {
+				t.Errorf("checkpoint() error = %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := r.Delete(ctx, fmt.Sprintf("host-%d", round%10)); err != nil {
+				t.Errorf("Delete() error = %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			from := fmt.Sprintf("agent-%d-%d", round, (round+1)%10)
+			if _, err := r.ReassignAgent(ctx, from, "merged"); err != nil {
+				t.Errorf("ReassignAgent() error = %v", err)
+			}
+		}()
+		wg.Wait()
+	}
+
+	if len(history(t, r, "merged")) == 0 {
+		t.Error("reassigned samples are missing")
+	}
+}
+
+// saveSamples saves count samples of a host one second apart from start, with
+// values 0, 1, ...
+func saveSamples(t *testing.T, r *TSDBStatsRepository, hostname, agentID string, start time.Time, count int) {
+	t.Helper()
+
+	for i := range count {
+		if err := r.Save(context.Background(), testStats(hostname, agentID, start.Add(time.Duration(i)*time.Second), float64(i))); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+}
+
+// assertHistory checks that the samples of an agent have the given values in order
+func assertHistory(t *testing.T, r *TSDBStatsRepository, agentID string, want ...float64) {
+	t.Helper()
+
+	got := history(t, r, agentID)
+	values := make([]float64, len(got))
+	for i, s := range got {
+		values[i] = s.CPU
+	}
+	if fmt.Sprint(values) != fmt.Sprint(want) {
+		t.Errorf("history of %s = %v, want %v", agentID, values, want)
+	}
+}
+
+func TestRecoveryAfterCrashDuringCheckpoint(t *testing.T) {
+	tests := []struct {
+		name      string
+		saveState bool
+	}{
+		{name: "after block written", saveState: false},
+		{name: "after state saved", saveState: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			dir := t.TempDir()
+			r := openTestRepository(t, dir, Options{})
+			start := time.Now().Add(-time.Hour)
+			saveSamples(t, r, "web-01", "agent-a", start, 3)
+
+			// What checkpoint does, up to removing the WAL segments
+			r.mu.Lock()
+			seq, err := r.wal.rotate()
+			if err != nil {
+				r.mu.Unlock()
+				t.Fatalf("rotate() error = %v", err)
+			}
+			chunks := make(map[chunkKey][]sample)
+			for hostname, samples := range r.head {
+				for _, s := range samples {
+					key := chunkKey{hostname: hostname, agentID: s.agentID}
+					chunks[key] = append(chunks[key], s.sample)
+				}
+			}
+			state := r.copyState()
+			r.mu.Unlock()
+
+			if _, err := writeBlock(filepath.Join(dir, blocksDir), seq, r.metrics, chunks); err != nil {
+				t.Fatalf("writeBlock() error = %v", err)
+			}
+			if tt.saveState {
+				if err := r.saveState(state); err != nil {
+					t.Fatalf("saveState() error = %v", err)
+				}
+			}
+			if err := r.Save(ctx, testStats("web-01", "agent-a", start.Add(time.Minute), 3)); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+			crash(r)
+
+			r = openTestRepository(t, dir, Options{})
+			assertHistory(t, r, "agent-a", 0, 1, 2, 3)
+
+			latest, err := r.Get(ctx, "web-01")
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if latest.CPU != 3 {
+				t.Errorf("latest CPU = %v, want 3", latest.CPU)
+			}
+
+			// A later checkpoint writes the samples logged after the crash once
+			if err := r.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			r = openTestRepository(t, dir, Options{})
+			assertHistory(t, r, "agent-a", 0, 1, 2, 3)
+		})
+	}
+}
+
+func TestDeleteHidesSamplesInBlocks(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	r := openTestRepository(t, dir, Options{})
+	start := time.Now().Add(-time.Hour)
+
+	saveSamples(t, r, "web-01", "agent-a", start, 3)
+	saveSamples(t, r, "web-02", "agent-b", start, 2)
+	if err := r.checkpoint(); err != nil {
+		t.Fatalf("checkpoint() error = %v", err)
+	}
+
+	if err := r.Delete(ctx, "web-01"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := r.checkpoint(); err != nil {
+		t.Fatalf("checkpoint() error = %v", err)
+	}
+	assertHistory(t, r, "agent-a")
+	assertHistory(t, r, "agent-b", 0, 1)
+	if _, err := r.Get(ctx, "web-01"); !errors.Is(err, repository.ErrStatsNotFound) {
+		t.Errorf("Get() of deleted host error = %v, want ErrStatsNotFound", err)
+	}
+
+	// Samples of the host saved after the deletion are visible
+	if err := r.Save(ctx, testStats("web-01", "agent-a", start.Add(time.Minute), 7)); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := r.checkpoint(); err != nil {
+		t.Fatalf("checkpoint() error = %v", err)
+	}
+	assertHistory(t, r, "agent-a", 7)
+
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	r = openTestRepository(t, dir, Options{})
+	assertHistory(t, r, "agent-a", 7)
+	assertHistory(t, r, "agent-b", 0, 1)
+}
+
+func TestRetentionByAge(t *testing.T) {
+	ctx := context.Background()
+	r := openTestRepository(t, t.TempDir(), Options{Retention: 24 * time.Hour})
+
+	saveSamples(t, r, "web-01", "agent-a", time.Now().Add(-72*time.Hour), 2)
+	if err := r.checkpoint(); err != nil {
+		t.Fatalf("checkpoint() error = %v", err)
+	}
+	old := r.blocks[0].path
+	if err := r.Delete(ctx, "web-02"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := r.Save(ctx, testStats("web-01", "agent-a", time.Now().Add(-time.Hour), 5)); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := r.checkpoint(); err != nil {
+		t.Fatalf("checkpoint() error = %v", err)
+	}
+
+	r.applyRetention()
+
+	if len(r.blocks) != 1 {
+		t.Fatalf("%d blocks left, want 1", len(r.blocks))
+	}
+	if _, err := os.Stat(old); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expired block %s not removed: %v", filepath.Base(old), err)
+	}
+	if len(r.state.Tombstones) != 0 {
+		t.Errorf("tombstones %v kept after the blocks they cover were removed", r.state.Tombstones)
+	}
+	assertHistory(t, r, "agent-a", 5)
+}
+
+func TestRetentionByMaxBytes(t *testing.T) {
+	dir := t.TempDir()
+	r := openTestRepository(t, dir, Options{})
+	start := time.Now().Add(-3 * time.Hour)
+
+	var sizes []int64
+	for i := range 3 {
+		saveSamples(t, r, "web-01", fmt.Sprintf("agent-%d", i), start.Add(time.Duration(i)*time.Hour), 10)
+		if err := r.checkpoint(); err != nil {
+			t.Fatalf("checkpoint() error = %v", err)
+		}
+		sizes = append(sizes, r.blocks[len(r.blocks)-1].size)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// Opening applies the limit, which leaves room for the two newest blocks
+	r = openTestRepository(t, dir, Options{MaxBytes: sizes[1] + sizes[2]})
+
+	if len(r.blocks) != 2 {
+		t.Fatalf("%d blocks left, want 2", len(r.blocks))
+	}
+	assertHistory(t, r, "agent-0")
+	assertHistory(t, r, "agent-1", 0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	assertHistory(t, r, "agent-2", 0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+}
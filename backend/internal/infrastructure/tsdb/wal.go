@@ -0,0 +1,238 @@
+// Package tsdb implements an embedded on-disk time-series store for stats
+package tsdb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"smart-monitor/backend/internal/domain/entity"
+)
+
+const walExt = ".wal"
+
+// maxRecordSize guards against reading garbage as a record length
+const maxRecordSize = 16 << 20
+
+// walOp is the kind of change a WAL record holds
+type walOp uint8
+
+const (
+	walOpSave walOp = iota + 1
+	walOpDelete
+	walOpReassign
+)
+
+// walRecord is one change to the store, replayed into the head after a restart
+type walRecord struct {
+	Op          walOp
+	Stats       *entity.Stats // walOpSave
+	Hostname    string        // walOpDelete
+	FromAgentID string        // walOpReassign
+	ToAgentID   string        // walOpReassign
+}
+
+// wal is the write-ahead log of changes not yet written to a block. It is a
+// sequence of numbered segment files; a checkpoint starts a new segment and
+// removes the ones it wrote to a block.
+type wal struct {
+	dir  string
+	seq  uint64 // segment being written
+	file *os.File
+}
+
+// walSegments returns the sequence numbers of the segments in dir, oldest first
+func walSegments(dir string) ([]uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read WAL directory: %w", err)
+	}
+
+	var segments []uint64
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, walExt) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, walExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, seq)
+	}
+
+	sort.Slice(segments, func(i, j int) bool { return segments[i] < segments[j] })
+	return segments, nil
+}
+
+// replayWAL calls fn for every record in the segments of dir, oldest first.
+// A partially written record at the end of a segment is truncated.
+func replayWAL(dir string, fn func(seq uint64, record *walRecord)) error {
+	segments, err := walSegments(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, seq := range segments {
+		if err := replaySegment(walPath(dir, seq), func(record *walRecord) { fn(seq, record) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replaySegment reads the records of one segment
+func replaySegment(path string, fn func(record *walRecord)) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open WAL segment: %w", err)
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	count := 0
+	var valid int64
+	for {
+		data, size, err := readRecord(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			log.Printf("⚠ WAL segment %s is damaged after %d records, truncating: %v", filepath.Base(path), count, err)
+			if err := f.Truncate(valid); err != nil {
+				return fmt.Errorf("failed to truncate WAL segment: %w", err)
+			}
+			return nil
+		}
+		count++
+		valid += size
+
+		record := &walRecord{}
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(record); err != nil {
+			log.Printf("⚠ Skipping undecodable WAL record in %s: %v", filepath.Base(path), err)
+			continue
+		}
+		fn(record)
+	}
+}
+
+// openWAL starts a new segment after seq
+func openWAL(dir string, seq uint64) (*wal, error) {
+	w := &wal{dir: dir, seq: seq}
+	if _, err := w.rotate(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// append writes records and syncs them to disk
+func (w *wal) append(records ...*walRecord) error {
+	var buf bytes.Buffer
+	for _, record := range records {
+		var data bytes.Buffer
+		if err := gob.NewEncoder(&data).Encode(record); err != nil {
+			return fmt.Errorf("failed to encode WAL record: %w", err)
+		}
+
+		var header [8]byte
+		binary.BigEndian.PutUint32(header[:4], uint32(data.Len()))
+		binary.BigEndian.PutUint32(header[4:], crc32.ChecksumIEEE(data.Bytes()))
+		buf.Write(header[:])
+		buf.Write(data.Bytes())
+	}
+
+	if _, err := w.file.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write WAL: %w", err)
+	}
+	if err := w.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync WAL: %w", err)
+	}
+	return nil
+}
+
+// rotate closes the segment being written and starts the next one. It returns
+// the sequence number of the closed segment.
+func (w *wal) rotate() (uint64, error) {
+	f, err := os.OpenFile(walPath(w.dir, w.seq+1), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create WAL segment: %w", err)
+	}
+
+	if w.file != nil {
+		w.file.Close()
+	}
+	w.file = f
+	w.seq++
+	return w.seq - 1, nil
+}
+
+// removeThrough removes the segments up to and including seq
+func (w *wal) removeThrough(seq uint64) error {
+	segments, err := walSegments(w.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range segments {
+		if s > seq {
+			break
+		}
+		if err := os.Remove(walPath(w.dir, s)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove WAL segment: %w", err)
+		}
+	}
+	return nil
+}
+
+// close closes the segment being written
+func (w *wal) close() error {
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// walPath returns the file of a segment
+func walPath(dir string, seq uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%012d%s", seq, walExt))
+}
+
+// readRecord reads one length-prefixed, checksummed record and returns it
+// with its size on disk
+func readRecord(r *bufio.Reader) ([]byte, int64, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, 0, errors.New("truncated record header")
+		}
+		return nil, 0, err
+	}
+
+	length := binary.BigEndian.Uint32(header[:4])
+	if length > maxRecordSize {
+		return nil, 0, fmt.Errorf("invalid record length %d", length)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, 0, errors.New("truncated record")
+	}
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(header[4:]) {
+		return nil, 0, errors.New("record checksum mismatch")
+	}
+
+	return data, int64(8 + length), nil
+}
@@ -0,0 +1,176 @@
+package tsdb
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+// replayHostnames replays the WAL in dir and returns the hostnames of its
+// delete records by segment
+func replayHostnames(t *testing.T, dir string) map[uint64][]string {
+	t.Helper()
+
+	got := make(map[uint64][]string)
+	err := replayWAL(dir, func(seq uint64, record *walRecord) {
+		got[seq] = append(got[seq], record.Hostname)
+	})
+	if err != nil {
+		t.Fatalf("replayWAL() error = %v", err)
+	}
+	return got
+}
+
+func TestWALAppendRotateReplay(t *testing.T) {
+	dir := t.TempDir()
+
+	w, err := openWAL(dir, 0)
+	if err != nil {
+		t.Fatalf("openWAL() error = %v", err)
+	}
+	if err := w.append(&walRecord{Op: walOpDelete, Hostname: "a"}, &walRecord{Op: walOpDelete, Hostname: "b"}); err != nil {
+		t.Fatalf("append() error = %v", err)
+	}
+	closed, err := w.rotate()
+	if err != nil {
+		t.Fatalf("rotate() error = %v", err)
+	}
+	if err := w.append(&walRecord{Op: walOpDelete, Hostname: "c"}); err != nil {
+		t.Fatalf("append() error = %v", err)
+	}
+	w.close()
+
+	got := replayHostnames(t, dir)
+	if len(got) != 2 || len(got[closed]) != 2 || got[closed][1] != "b" || len(got[closed+1]) != 1 || got[closed+1][0] != "c" {
+		t.Fatalf("replayed %v", got)
+	}
+
+	if err := w.removeThrough(closed); err != nil {
+		t.Fatalf("removeThrough() error = %v", err)
+	}
+	if got := replayHostnames(t, dir); len(got) != 1 || len(got[closed+1]) != 1 {
+		t.Errorf("replayed %v after removeThrough(%d)", got, closed)
+	}
+}
+
+func TestWALTruncatesTornTail(t *testing.T) {
+	tests := []struct {
+		name string
+		tear func(t *testing.T, path string, size int64)
+		// whether the second record survives, when garbage follows it
+		keepsSecond bool
+	}{
+		{
+			name: "partial header",
+			tear: func(t *testing.T, path string, size int64) {
+				appendBytes(t, path, []byte{0, 0, 0})
+			},
+			keepsSecond: true,
+		},
+		{
+			name: "partial record",
+			tear: func(t *testing.T, path string, size int64) {
+				if err := os.Truncate(path, size-3); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "checksum mismatch",
+			tear: func(t *testing.T, path string, size int64) {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				data[len(data)-1] ^= 0xff
+				if err := os.WriteFile(path, data, 0600); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "invalid length",
+			tear: func(t *testing.T, path string, size int64) {
+				appendBytes(t, path, []byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0, 1})
+			},
+			keepsSecond: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			w, err := openWAL(dir, 0)
+			if err != nil {
+				t.Fatalf("openWAL() error = %v", err)
+			}
+			seq := w.seq
+			path := walPath(dir, seq)
+
+			if err := w.append(&walRecord{Op: walOpDelete, Hostname: "kept"}); err != nil {
+				t.Fatalf("append() error = %v", err)
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			intact := info.Size()
+
+			if err := w.append(&walRecord{Op: walOpDelete, Hostname: "second"}); err != nil {
+				t.Fatalf("append() error = %v", err)
+			}
+			w.close()
+			info, err = os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			full := info.Size()
+			tt.tear(t, path, full)
+
+			want, wantSize := []string{"kept"}, intact
+			if tt.keepsSecond {
+				want, wantSize = []string{"kept", "second"}, full
+			}
+			got := replayHostnames(t, dir)
+			if !slices.Equal(got[seq], want) {
+				t.Fatalf("replayed %v, want %v", got[seq], want)
+			}
+			info, err = os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Size() != wantSize {
+				t.Fatalf("segment is %d bytes after replay, want %d", info.Size(), wantSize)
+			}
+
+			// Records appended after a restart follow the intact ones
+			w, err = openWAL(dir, seq)
+			if err != nil {
+				t.Fatalf("openWAL() error = %v", err)
+			}
+			if err := w.append(&walRecord{Op: walOpDelete, Hostname: "after"}); err != nil {
+				t.Fatalf("append() error = %v", err)
+			}
+			w.close()
+
+			got = replayHostnames(t, dir)
+			if !slices.Equal(got[seq], want) || !slices.Equal(got[seq+1], []string{"after"}) {
+				t.Errorf("replayed %v after restart", got)
+			}
+		})
+	}
+}
+
+// appendBytes appends data to a file
+func appendBytes(t *testing.T, path string, data []byte) {
+	t.Helper()
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -18,9 +18,11 @@ import (
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 )
 
 // Config holds server configuration
@@ -78,10 +80,10 @@ func (s *server) StreamStats(stream pb.MonitorService_StreamStatsServer) error {
 }
 
 // GetStats returns stats for a specific hostname
-func (s *server) GetStats(ctx context.Context, req *pb.StatsRequest) (*pb.StatsResponse, error) {
+func (s *server) GetStats(ctx context.Context, req *pb.GetStatsRequest) (*pb.GetStatsResponse, error) {
 	hostname := req.Hostname
 	if hostname == "" {
-		return nil, fmt.Errorf("hostname is required")
+		return nil, status.Error(codes.InvalidArgument, "hostname is required")
 	}
 
 	log.Printf("GetStats called for hostname: %s", hostname)
@@ -92,18 +94,18 @@ func (s *server) GetStats(ctx context.Context, req *pb.StatsRequest) (*pb.StatsR
 	s.mu.RUnlock()
 
 	if !exists {
-		return &pb.StatsResponse{
-			Message:   fmt.Sprintf("No stats available for hostname: %s", hostname),
-			Timestamp: time.Now().Unix(),
-		}, nil
+		return nil, status.Errorf(codes.NotFound, "no stats available for hostname: %s", hostname)
 	}
 
-	message := fmt.Sprintf("Stats for %s: CPU=%.2f%%, RAM=%.2f%%, Disk=%.2f%% (Last received: %s)",
-		hostname, stats.Cpu, stats.Ram, stats.Disk, lastReceived.Format(time.RFC3339))
-
-	return &pb.StatsResponse{
-		Message:   message,
-		Timestamp: time.Now().Unix(),
+	return &pb.GetStatsResponse{
+		Stats: &pb.HostStats{
+			Hostname:     hostname,
+			AgentId:      stats.AgentId,
+			Cpu:          stats.Cpu,
+			Ram:          stats.Ram,
+			Disk:         stats.Disk,
+			LastReceived: lastReceived.UnixMilli(),
+		},
 	}, nil
 }
 
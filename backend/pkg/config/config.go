@@ -4,6 +4,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"time"
 )
 
 // Config holds application configuration
@@ -15,11 +16,66 @@ type Config struct {
 type ServerConfig struct {
 	GRPCPort string
 	HTTPPort string
+	TLS      TLSConfig
+}
+
+// TLSConfig holds gRPC transport security settings
+type TLSConfig struct {
+	Enabled           bool
+	CertFile          string
+	KeyFile           string
+	ClientCAFile      string // CA used to verify agent client certificates
+	RequireClientCert bool   // reject agents without a valid client certificate
+
+	// Client certificate the HTTP gateway presents on its loopback connection,
+	// defaults to CertFile/KeyFile
+	GatewayCertFile string
+	GatewayKeyFile  string
 }
 
 // AuthConfig holds authentication settings
 type AuthConfig struct {
-	JWTSecret string
+	JWTSecret     string
+	AgentTokenTTL time.Duration // lifetime of agent access tokens
+
+	// Reject agents registering without an enrollment token
+	AgentEnrollmentRequired bool
+}
+
+// LivenessConfig holds host liveness monitoring settings
+type LivenessConfig struct {
+	CheckInterval         time.Duration // how often host liveness is evaluated
+	DefaultReportInterval time.Duration // assumed for agents that do not report their interval
+
+	// Missed reporting intervals after which a host is stale or offline
+	StaleAfterIntervals   float64
+	OfflineAfterIntervals float64
+}
+
+// DatabaseConfig holds the database for agents, hosts, policies and users
+type DatabaseConfig struct {
+	Driver string // memory, sqlite or postgres
+	DSN    string // SQLite file or PostgreSQL connection string
+}
+
+// Stats storage backends
+const (
+	StatsStorageOpenSearch = "opensearch" // OpenSearch, in memory while it is unavailable
+	StatsStorageMemory     = "memory"
+	StatsStorageTSDB       = "tsdb" // embedded on-disk time-series store
+)
+
+// StatsConfig holds stats storage settings
+type StatsConfig struct {
+	Storage string
+
+	// Samples per host the in-memory store keeps for range queries
+	MemoryHistorySize int
+
+	TSDBDir           string
+	TSDBRetention     time.Duration // zero keeps samples until TSDBMaxBytes is reached
+	TSDBMaxBytes      int64         // zero is unlimited
+	TSDBBlockDuration time.Duration // how long samples stay in memory before they are compressed to disk
 }
 
 // OpenSearchConfig holds OpenSearch configuration
@@ -37,13 +93,88 @@ func Load() *Config {
 		Server: ServerConfig{
 			GRPCPort: getEnv("GRPC_PORT", "50051"),
 			HTTPPort: getEnv("HTTP_PORT", "8080"),
+			TLS: TLSConfig{
+				Enabled:           os.Getenv("GRPC_TLS_ENABLED") == "true",
+				CertFile:          getEnv("GRPC_TLS_CERT_FILE", ""),
+				KeyFile:           getEnv("GRPC_TLS_KEY_FILE", ""),
+				ClientCAFile:      getEnv("GRPC_TLS_CLIENT_CA_FILE", ""),
+				RequireClientCert: os.Getenv("GRPC_TLS_REQUIRE_CLIENT_CERT") == "true",
+				GatewayCertFile:   getEnv("GRPC_TLS_GATEWAY_CERT_FILE", ""),
+				GatewayKeyFile:    getEnv("GRPC_TLS_GATEWAY_KEY_FILE", ""),
+			},
 		},
 	}
 }
 
 // LoadAuthConfig loads authentication configuration
 func LoadAuthConfig() *AuthConfig {
-	return &AuthConfig{JWTSecret: getEnv("JWT_SECRET", "dev-secret-change-me")}
+	tokenTTL := 365 * 24 * time.Hour
+	if ttlStr := os.Getenv("AGENT_TOKEN_TTL"); ttlStr != "" {
+		if ttl, err := time.ParseDuration(ttlStr); err == nil && ttl > 0 {
+			tokenTTL = ttl
+		}
+	}
+
+	return &AuthConfig{
+		JWTSecret:     getEnv("JWT_SECRET", "dev-secret-change-me"),
+		AgentTokenTTL: tokenTTL,
+
+		AgentEnrollmentRequired: os.Getenv("AGENT_ENROLLMENT_REQUIRED") != "false",
+	}
+}
+
+// LoadLivenessConfig loads host liveness monitoring configuration
+func LoadLivenessConfig() *LivenessConfig {
+	cfg := &LivenessConfig{
+		CheckInterval:         getEnvDuration("HOST_LIVENESS_CHECK_INTERVAL", 5*time.Second),
+		DefaultReportInterval: getEnvDuration("HOST_DEFAULT_REPORT_INTERVAL", 5*time.Second),
+		StaleAfterIntervals:   getEnvFloat("HOST_STALE_AFTER_INTERVALS", 3),
+		OfflineAfterIntervals: getEnvFloat("HOST_OFFLINE_AFTER_INTERVALS", 10),
+	}
+	if cfg.OfflineAfterIntervals < cfg.StaleAfterIntervals {
+		cfg.OfflineAfterIntervals = cfg.StaleAfterIntervals
+	}
+	return cfg
+}
+
+// LoadStatsConfig loads stats storage configuration
+func LoadStatsConfig() *StatsConfig {
+	retention := 30 * 24 * time.Hour
+	if value := os.Getenv("STATS_TSDB_RETENTION"); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+			retention = d
+		}
+	}
+
+	var maxBytes int64
+	if value, err := strconv.ParseInt(os.Getenv("STATS_TSDB_MAX_BYTES"), 10, 64); err == nil && value > 0 {
+		maxBytes = value
+	}
+
+	return &StatsConfig{
+		Storage: getEnv("STATS_STORAGE", StatsStorageOpenSearch),
+		// A day of samples at the agent's default 5s interval
+		MemoryHistorySize: getEnvInt("STATS_MEMORY_HISTORY_SIZE", 17280),
+		TSDBDir:           getEnv("STATS_TSDB_DIR", "./data/tsdb"),
+		TSDBRetention:     retention,
+		TSDBMaxBytes:      maxBytes,
+		TSDBBlockDuration: getEnvDuration("STATS_TSDB_BLOCK_DURATION", 2*time.Hour),
+	}
+}
+
+// LoadDatabaseConfig loads database configuration
+func LoadDatabaseConfig() *DatabaseConfig {
+	driver := getEnv("DB_DRIVER", "memory")
+
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" && driver == "sqlite" {
+		dsn = "./data/smart-monitor.db"
+	}
+
+	return &DatabaseConfig{
+		Driver: driver,
+		DSN:    dsn,
+	}
 }
 
 // LoadOpenSearchConfig loads OpenSearch configuration
@@ -74,3 +205,27 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+// getEnvDuration gets a positive duration from an environment variable with default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvFloat gets a positive number from an environment variable with default value
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvInt gets a positive integer from an environment variable with default value
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ServerTLSConfig builds the TLS configuration of the gRPC server
+func (c *TLSConfig) ServerTLSConfig() (*tls.Config, error) {
+	if c.CertFile == "" || c.KeyFile == "" {
+		return nil, errors.New("GRPC_TLS_CERT_FILE and GRPC_TLS_KEY_FILE are required when TLS is enabled")
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if c.ClientCAFile == "" {
+		if c.RequireClientCert {
+			return nil, errors.New("GRPC_TLS_CLIENT_CA_FILE is required to verify client certificates")
+		}
+		return tlsConfig, nil
+	}
+
+	pool, err := loadCertPool(c.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client CA: %w", err)
+	}
+	tlsConfig.ClientCAs = pool
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	if c.RequireClientCert {
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
+// GatewayTLSConfig builds the TLS configuration of the gateway's loopback
+// connection to the gRPC server. The server certificate is pinned, so it does
+// not need to be valid for localhost.
+func (c *TLSConfig) GatewayTLSConfig() (*tls.Config, error) {
+	serverCert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+	pinned := serverCert.Certificate[0]
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// Verification is replaced by pinning the server's own certificate
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 || !bytes.Equal(rawCerts[0], pinned) {
+				return errors.New("gRPC server certificate does not match the configured certificate")
+			}
+			return nil
+		},
+	}
+
+	if c.ClientCAFile != "" {
+		certFile, keyFile := c.GatewayCertFile, c.GatewayKeyFile
+		if certFile == "" || keyFile == "" {
+			certFile, keyFile = c.CertFile, c.KeyFile
+		}
+		clientCert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load gateway client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+
+	return tlsConfig, nil
+}
+
+// loadCertPool reads PEM certificates from a file
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
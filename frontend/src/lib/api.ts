@@ -36,12 +36,12 @@ export interface Policy {
   updated_at?: number;
 }
 
-// Fetch agents list
-export async function fetchAgents(): Promise<Agent[]> {
+// Fetch agents list; requires an admin or operator access token
+export async function fetchAgents(accessToken?: string): Promise<Agent[]> {
   try {
     const res = await fetch(`${BACKEND_URL}/v1/agents`, {
       method: "GET",
-      headers: { "Content-Type": "application/json" },
+      headers: jsonHeaders(accessToken),
     });
     if (!res.ok) throw new Error(`HTTP ${res.status}`);
     const data = await res.json();
@@ -59,9 +59,18 @@ export async function fetchMetrics(hostname: string): Promise<Metrics | null> {
       method: "GET",
       headers: { "Content-Type": "application/json" },
     });
+    if (res.status === 404) return null;
     if (!res.ok) throw new Error(`HTTP ${res.status}`);
-    const data = await res.json();
-    return data as Metrics;
+    const { stats } = await res.json();
+    return {
+      hostname: stats.hostname,
+      agent_id: stats.agentId,
+      cpu: stats.cpu ?? 0,
+      ram: stats.ram ?? 0,
+      disk: stats.disk ?? 0,
+      timestamp: Number(stats.collectedAt),
+      ip_address: stats.ipAddress,
+    };
   } catch (err) {
     console.error(`Failed to fetch metrics for ${hostname}:`, err);
     return null;
@@ -73,7 +82,7 @@ export function streamMetrics(
   onMetrics: (metrics: Metrics) => void,
   onError: (error: Error) => void
 ): () => void {
-  const eventSource = new EventSource(`${BACKEND_URL}/v1/stats/stream`);
+  const eventSource = new EventSource(`${BACKEND_URL}/v1/stats/watch`);
 
   eventSource.onmessage = (event) => {
     try {
@@ -128,15 +137,16 @@ export async function applyPolicy(
   }
 }
 
-// Control agent (start, restart, shutdown)
+// Control agent (start, restart, shutdown); requires an admin access token
 export async function controlAgent(
   agentId: string,
-  action: "start" | "restart" | "shutdown"
+  action: "start" | "restart" | "shutdown",
+  accessToken?: string
 ): Promise<boolean> {
   try {
     const res = await fetch(`${BACKEND_URL}/v1/agent/${agentId}/control`, {
       method: "POST",
-      headers: { "Content-Type": "application/json" },
+      headers: jsonHeaders(accessToken),
       body: JSON.stringify({ action }),
     });
     return res.ok;
@@ -146,15 +156,16 @@ export async function controlAgent(
   }
 }
 
-// Block/unblock agent
+// Block/unblock agent; requires an admin access token
 export async function blockAgent(
   agentId: string,
-  blocked: boolean
+  blocked: boolean,
+  accessToken?: string
 ): Promise<boolean> {
   try {
     const res = await fetch(`${BACKEND_URL}/v1/agent/${agentId}/block`, {
       method: "POST",
-      headers: { "Content-Type": "application/json" },
+      headers: jsonHeaders(accessToken),
       body: JSON.stringify({ blocked }),
     });
     return res.ok;
@@ -163,3 +174,12 @@ export async function blockAgent(
     return false;
   }
 }
+
+// JSON request headers, with the user's backend token when signed in
+function jsonHeaders(accessToken?: string): Record<string, string> {
+  const headers: Record<string, string> = { "Content-Type": "application/json" };
+  if (accessToken) {
+    headers.Authorization = `Bearer ${accessToken}`;
+  }
+  return headers;
+}
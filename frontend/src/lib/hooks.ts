@@ -1,6 +1,7 @@
 "use client";
 
 import { useEffect, useState, useCallback } from "react";
+import { useSession } from "next-auth/react";
 import type { Agent, Metrics, Policy } from "@/lib/api";
 import {
   fetchAgents,
@@ -12,6 +13,8 @@ import {
 } from "@/lib/api";
 
 export function useAgents() {
+  const { data: session } = useSession();
+  const accessToken = (session as any)?.accessToken as string | undefined;
   const [agents, setAgents] = useState<Agent[]>([]);
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
@@ -19,7 +22,7 @@ export function useAgents() {
   useEffect(() => {
     const load = async () => {
       setLoading(true);
-      const data = await fetchAgents();
+      const data = await fetchAgents(accessToken);
       if (data) {
         setAgents(data);
       } else {
@@ -31,7 +34,7 @@ export function useAgents() {
     load();
     const timer = setInterval(load, 5000); // Refresh every 5s
     return () => clearInterval(timer);
-  }, []);
+  }, [accessToken]);
 
   return { agents, loading, error };
 }
@@ -107,35 +110,37 @@ export function usePolicies() {
 }
 
 export function useAgentControl() {
+  const { data: session } = useSession();
+  const accessToken = (session as any)?.accessToken as string | undefined;
   const [loading, setLoading] = useState(false);
   const [error, setError] = useState<string | null>(null);
 
   const restart = useCallback(async (agentId: string) => {
     setLoading(true);
     setError(null);
-    const ok = await controlAgent(agentId, "restart");
+    const ok = await controlAgent(agentId, "restart", accessToken);
     setLoading(false);
     if (!ok) setError("Failed to restart agent");
     return ok;
-  }, []);
+  }, [accessToken]);
 
   const block = useCallback(async (agentId: string) => {
     setLoading(true);
     setError(null);
-    const ok = await blockAgent(agentId, true);
+    const ok = await blockAgent(agentId, true, accessToken);
     setLoading(false);
     if (!ok) setError("Failed to block agent");
     return ok;
-  }, []);
+  }, [accessToken]);
 
   const unblock = useCallback(async (agentId: string) => {
     setLoading(true);
     setError(null);
-    const ok = await blockAgent(agentId, false);
+    const ok = await blockAgent(agentId, false, accessToken);
     setLoading(false);
     if (!ok) setError("Failed to unblock agent");
     return ok;
-  }, []);
+  }, [accessToken]);
 
   return { restart, block, unblock, loading, error };
 }
@@ -119,13 +119,20 @@ func (x *GetDiskStatsResponse) GetTimestamp() int64 {
 }
 
 type DiskPartition struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	MountPoint    string                 `protobuf:"bytes,1,opt,name=mount_point,json=mountPoint,proto3" json:"mount_point,omitempty"`
-	Total         float64                `protobuf:"fixed64,2,opt,name=total,proto3" json:"total,omitempty"`
-	Used          float64                `protobuf:"fixed64,3,opt,name=used,proto3" json:"used,omitempty"`
-	Free          float64                `protobuf:"fixed64,4,opt,name=free,proto3" json:"free,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	MountPoint        string                 `protobuf:"bytes,1,opt,name=mount_point,json=mountPoint,proto3" json:"mount_point,omitempty"`
+	Total             float64                `protobuf:"fixed64,2,opt,name=total,proto3" json:"total,omitempty"`
+	Used              float64                `protobuf:"fixed64,3,opt,name=used,proto3" json:"used,omitempty"`
+	Free              float64                `protobuf:"fixed64,4,opt,name=free,proto3" json:"free,omitempty"`
+	Device            string                 `protobuf:"bytes,5,opt,name=device,proto3" json:"device,omitempty"`
+	Fstype            string                 `protobuf:"bytes,6,opt,name=fstype,proto3" json:"fstype,omitempty"`
+	UsedPercent       float64                `protobuf:"fixed64,7,opt,name=used_percent,json=usedPercent,proto3" json:"used_percent,omitempty"`
+	InodesTotal       uint64                 `protobuf:"varint,8,opt,name=inodes_total,json=inodesTotal,proto3" json:"inodes_total,omitempty"`
+	InodesUsed        uint64                 `protobuf:"varint,9,opt,name=inodes_used,json=inodesUsed,proto3" json:"inodes_used,omitempty"`
+	InodesFree        uint64                 `protobuf:"varint,10,opt,name=inodes_free,json=inodesFree,proto3" json:"inodes_free,omitempty"`
+	InodesUsedPercent float64                `protobuf:"fixed64,11,opt,name=inodes_used_percent,json=inodesUsedPercent,proto3" json:"inodes_used_percent,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *DiskPartition) Reset() {
@@ -186,6 +193,55 @@ func (x *DiskPartition) GetFree() float64 {
 	return 0
 }
 
+func (x *DiskPartition) GetDevice() string {
+	if x != nil {
+		return x.Device
+	}
+	return ""
+}
+
+func (x *DiskPartition) GetFstype() string {
+	if x != nil {
+		return x.Fstype
+	}
+	return ""
+}
+
+func (x *DiskPartition) GetUsedPercent() float64 {
+	if x != nil {
+		return x.UsedPercent
+	}
+	return 0
+}
+
+func (x *DiskPartition) GetInodesTotal() uint64 {
+	if x != nil {
+		return x.InodesTotal
+	}
+	return 0
+}
+
+func (x *DiskPartition) GetInodesUsed() uint64 {
+	if x != nil {
+		return x.InodesUsed
+	}
+	return 0
+}
+
+func (x *DiskPartition) GetInodesFree() uint64 {
+	if x != nil {
+		return x.InodesFree
+	}
+	return 0
+}
+
+func (x *DiskPartition) GetInodesUsedPercent() float64 {
+	if x != nil {
+		return x.InodesUsedPercent
+	}
+	return 0
+}
+
 var File_disk_disk_proto protoreflect.FileDescriptor
 
 const file_disk_disk_proto_rawDesc = "" +
@@ -197,13 +253,23 @@ const file_disk_disk_proto_rawDesc = "" +
 	"\n" +
 	"partitions\x18\x01 \x03(\v2\x13.disk.DiskPartitionR\n" +
 	"partitions\x12\x1c\n" +
-	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\"n\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\"\xd6\x02\n" +
 	"\rDiskPartition\x12\x1f\n" +
 	"\vmount_point\x18\x01 \x01(\tR\n" +
 	"mountPoint\x12\x14\n" +
 	"\x05total\x18\x02 \x01(\x01R\x05total\x12\x12\n" +
 	"\x04used\x18\x03 \x01(\x01R\x04used\x12\x12\n" +
-	"\x04free\x18\x04 \x01(\x01R\x04free2l\n" +
+	"\x04free\x18\x04 \x01(\x01R\x04free\x12\x16\n" +
+	"\x06device\x18\x05 \x01(\tR\x06device\x12\x16\n" +
+	"\x06fstype\x18\x06 \x01(\tR\x06fstype\x12!\n" +
+	"\fused_percent\x18\a \x01(\x01R\vusedPercent\x12!\n" +
+	"\finodes_total\x18\b \x01(\x04R\vinodesTotal\x12\x1f\n" +
+	"\vinodes_used\x18\t \x01(\x04R\n" +
+	"inodesUsed\x12\x1f\n" +
+	"\vinodes_free\x18\n" +
+	" \x01(\x04R\n" +
+	"inodesFree\x12.\n" +
+	"\x13inodes_used_percent\x18\v \x01(\x01R\x11inodesUsedPercent2l\n" +
 	"\vDiskService\x12]\n" +
 	"\fGetDiskStats\x12\x19.disk.GetDiskStatsRequest\x1a\x1a.disk.GetDiskStatsResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/disk/statsB\x1cZ\x1asmart-monitor/pbtypes/diskb\x06proto3"
 
@@ -12,6 +12,8 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
+	disk "smart-monitor/pbtypes/disk"
+	network "smart-monitor/pbtypes/network"
 	sync "sync"
 	unsafe "unsafe"
 )
@@ -23,19 +25,191 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Agent Command Messages
+type CommandStatus int32
+
+const (
+	CommandStatus_COMMAND_STATUS_UNSPECIFIED CommandStatus = 0
+	CommandStatus_COMMAND_STATUS_PENDING     CommandStatus = 1 // queued, not yet received by the agent
+	CommandStatus_COMMAND_STATUS_DELIVERED   CommandStatus = 2 // received by the agent
+	CommandStatus_COMMAND_STATUS_EXECUTED    CommandStatus = 3
+	CommandStatus_COMMAND_STATUS_FAILED      CommandStatus = 4
+)
+
+// Enum value maps for CommandStatus.
+var (
+	CommandStatus_name = map[int32]string{
+		0: "COMMAND_STATUS_UNSPECIFIED",
+		1: "COMMAND_STATUS_PENDING",
+		2: "COMMAND_STATUS_DELIVERED",
+		3: "COMMAND_STATUS_EXECUTED",
+		4: "COMMAND_STATUS_FAILED",
+	}
+	CommandStatus_value = map[string]int32{
+		"COMMAND_STATUS_UNSPECIFIED": 0,
+		"COMMAND_STATUS_PENDING":     1,
+		"COMMAND_STATUS_DELIVERED":   2,
+		"COMMAND_STATUS_EXECUTED":    3,
+		"COMMAND_STATUS_FAILED":      4,
+	}
+)
+
+func (x CommandStatus) Enum() *CommandStatus {
+	p := new(CommandStatus)
+	*p = x
+	return p
+}
+
+func (x CommandStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CommandStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_monitor_proto_enumTypes[0].Descriptor()
+}
+
+func (CommandStatus) Type() protoreflect.EnumType {
+	return &file_monitor_proto_enumTypes[0]
+}
+
+func (x CommandStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CommandStatus.Descriptor instead.
+func (CommandStatus) EnumDescriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{0}
+}
+
+// Policy Messages
+type Comparator int32
+
+const (
+	Comparator_COMPARATOR_UNSPECIFIED      Comparator = 0 // treated as greater than
+	Comparator_COMPARATOR_GREATER_THAN     Comparator = 1
+	Comparator_COMPARATOR_GREATER_OR_EQUAL Comparator = 2
+	Comparator_COMPARATOR_LESS_THAN        Comparator = 3
+	Comparator_COMPARATOR_LESS_OR_EQUAL    Comparator = 4
+)
+
+// Enum value maps for Comparator.
+var (
+	Comparator_name = map[int32]string{
+		0: "COMPARATOR_UNSPECIFIED",
+		1: "COMPARATOR_GREATER_THAN",
+		2: "COMPARATOR_GREATER_OR_EQUAL",
+		3: "COMPARATOR_LESS_THAN",
+		4: "COMPARATOR_LESS_OR_EQUAL",
+	}
+	Comparator_value = map[string]int32{
+		"COMPARATOR_UNSPECIFIED":      0,
+		"COMPARATOR_GREATER_THAN":     1,
+		"COMPARATOR_GREATER_OR_EQUAL": 2,
+		"COMPARATOR_LESS_THAN":        3,
+		"COMPARATOR_LESS_OR_EQUAL":    4,
+	}
+)
+
+func (x Comparator) Enum() *Comparator {
+	p := new(Comparator)
+	*p = x
+	return p
+}
+
+func (x Comparator) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Comparator) Descriptor() protoreflect.EnumDescriptor {
+	return file_monitor_proto_enumTypes[1].Descriptor()
+}
+
+func (Comparator) Type() protoreflect.EnumType {
+	return &file_monitor_proto_enumTypes[1]
+}
+
+func (x Comparator) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Comparator.Descriptor instead.
+func (Comparator) EnumDescriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{1}
+}
+
+type Severity int32
+
+const (
+	Severity_SEVERITY_UNSPECIFIED Severity = 0 // falls back to the policy's metadata "severity", then high
+	Severity_SEVERITY_LOW         Severity = 1
+	Severity_SEVERITY_MEDIUM      Severity = 2
+	Severity_SEVERITY_HIGH        Severity = 3
+	Severity_SEVERITY_CRITICAL    Severity = 4
+)
+
+// Enum value maps for Severity.
+var (
+	Severity_name = map[int32]string{
+		0: "SEVERITY_UNSPECIFIED",
+		1: "SEVERITY_LOW",
+		2: "SEVERITY_MEDIUM",
+		3: "SEVERITY_HIGH",
+		4: "SEVERITY_CRITICAL",
+	}
+	Severity_value = map[string]int32{
+		"SEVERITY_UNSPECIFIED": 0,
+		"SEVERITY_LOW":         1,
+		"SEVERITY_MEDIUM":      2,
+		"SEVERITY_HIGH":        3,
+		"SEVERITY_CRITICAL":    4,
+	}
+)
+
+func (x Severity) Enum() *Severity {
+	p := new(Severity)
+	*p = x
+	return p
+}
+
+func (x Severity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Severity) Descriptor() protoreflect.EnumDescriptor {
+	return file_monitor_proto_enumTypes[2].Descriptor()
+}
+
+func (Severity) Type() protoreflect.EnumType {
+	return &file_monitor_proto_enumTypes[2]
+}
+
+func (x Severity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Severity.Descriptor instead.
+func (Severity) EnumDescriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{2}
+}
+
 type StatsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Hostname      string                 `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
-	Cpu           float64                `protobuf:"fixed64,2,opt,name=cpu,proto3" json:"cpu,omitempty"`
-	Ram           float64                `protobuf:"fixed64,3,opt,name=ram,proto3" json:"ram,omitempty"`
-	Disk          float64                `protobuf:"fixed64,4,opt,name=disk,proto3" json:"disk,omitempty"`
-	AgentId       string                 `protobuf:"bytes,5,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	IpAddress     string                 `protobuf:"bytes,6,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
-	AgentVersion  string                 `protobuf:"bytes,7,opt,name=agent_version,json=agentVersion,proto3" json:"agent_version,omitempty"`
-	Metadata      map[string]string      `protobuf:"bytes,8,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	AccessToken   string                 `protobuf:"bytes,9,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Hostname         string                 `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Cpu              float64                `protobuf:"fixed64,2,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	Ram              float64                `protobuf:"fixed64,3,opt,name=ram,proto3" json:"ram,omitempty"`
+	Disk             float64                `protobuf:"fixed64,4,opt,name=disk,proto3" json:"disk,omitempty"`
+	AgentId          string                 `protobuf:"bytes,5,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	IpAddress        string                 `protobuf:"bytes,6,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	AgentVersion     string                 `protobuf:"bytes,7,opt,name=agent_version,json=agentVersion,proto3" json:"agent_version,omitempty"`
+	Metadata         map[string]string      `protobuf:"bytes,8,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	AccessToken      string                 `protobuf:"bytes,9,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	CollectedAt      int64                  `protobuf:"varint,10,opt,name=collected_at,json=collectedAt,proto3" json:"collected_at,omitempty"`
+	Samples          []*StatsSample         `protobuf:"bytes,11,rep,name=samples,proto3" json:"samples,omitempty"`
+	Extended         *ExtendedMetrics       `protobuf:"bytes,12,opt,name=extended,proto3" json:"extended,omitempty"`
+	CollectorErrors  []*CollectorError      `protobuf:"bytes,13,rep,name=collector_errors,json=collectorErrors,proto3" json:"collector_errors,omitempty"`
+	ReportIntervalMs int64                  `protobuf:"varint,14,opt,name=report_interval_ms,json=reportIntervalMs,proto3" json:"report_interval_ms,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *StatsRequest) Reset() {
@@ -131,28 +305,64 @@ func (x *StatsRequest) GetAccessToken() string {
 	return ""
 }
 
-type StatsResponse struct {
+func (x *StatsRequest) GetCollectedAt() int64 {
+	if x != nil {
+		return x.CollectedAt
+	}
+	return 0
+}
+
+func (x *StatsRequest) GetSamples() []*StatsSample {
+	if x != nil {
+		return x.Samples
+	}
+	return nil
+}
+
+func (x *StatsRequest) GetExtended() *ExtendedMetrics {
+	if x != nil {
+		return x.Extended
+	}
+	return nil
+}
+
+func (x *StatsRequest) GetCollectorErrors() []*CollectorError {
+	if x != nil {
+		return x.CollectorErrors
+	}
+	return nil
+}
+
+func (x *StatsRequest) GetReportIntervalMs() int64 {
+	if x != nil {
+		return x.ReportIntervalMs
+	}
+	return 0
+}
+
+// CollectorError reports a collector plugin that failed on the agent
+type CollectorError struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
-	Timestamp     int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Collector     string                 `protobuf:"bytes,1,opt,name=collector,proto3" json:"collector,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StatsResponse) Reset() {
-	*x = StatsResponse{}
+func (x *CollectorError) Reset() {
+	*x = CollectorError{}
 	mi := &file_monitor_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StatsResponse) String() string {
+func (x *CollectorError) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StatsResponse) ProtoMessage() {}
+func (*CollectorError) ProtoMessage() {}
 
-func (x *StatsResponse) ProtoReflect() protoreflect.Message {
+func (x *CollectorError) ProtoReflect() protoreflect.Message {
 	mi := &file_monitor_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -164,50 +374,48 @@ func (x *StatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StatsResponse.ProtoReflect.Descriptor instead.
-func (*StatsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use CollectorError.ProtoReflect.Descriptor instead.
+func (*CollectorError) Descriptor() ([]byte, []int) {
 	return file_monitor_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *StatsResponse) GetMessage() string {
+func (x *CollectorError) GetCollector() string {
 	if x != nil {
-		return x.Message
+		return x.Collector
 	}
 	return ""
 }
 
-func (x *StatsResponse) GetTimestamp() int64 {
+func (x *CollectorError) GetMessage() string {
 	if x != nil {
-		return x.Timestamp
+		return x.Message
 	}
-	return 0
+	return ""
 }
 
-// Registration messages
-type RegisterRequest struct {
+// CPUCoreUsage is the utilisation of one logical core
+type CPUCoreUsage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Hostname      string                 `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
-	IpAddress     string                 `protobuf:"bytes,2,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
-	AgentVersion  string                 `protobuf:"bytes,3,opt,name=agent_version,json=agentVersion,proto3" json:"agent_version,omitempty"`
-	Metadata      map[string]string      `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Core          string                 `protobuf:"bytes,1,opt,name=core,proto3" json:"core,omitempty"`
+	Percent       float64                `protobuf:"fixed64,2,opt,name=percent,proto3" json:"percent,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RegisterRequest) Reset() {
-	*x = RegisterRequest{}
+func (x *CPUCoreUsage) Reset() {
+	*x = CPUCoreUsage{}
 	mi := &file_monitor_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RegisterRequest) String() string {
+func (x *CPUCoreUsage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RegisterRequest) ProtoMessage() {}
+func (*CPUCoreUsage) ProtoMessage() {}
 
-func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
+func (x *CPUCoreUsage) ProtoReflect() protoreflect.Message {
 	mi := &file_monitor_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -219,64 +427,66 @@ func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RegisterRequest.ProtoReflect.Descriptor instead.
-func (*RegisterRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CPUCoreUsage.ProtoReflect.Descriptor instead.
+func (*CPUCoreUsage) Descriptor() ([]byte, []int) {
 	return file_monitor_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *RegisterRequest) GetHostname() string {
-	if x != nil {
-		return x.Hostname
-	}
-	return ""
-}
-
-func (x *RegisterRequest) GetIpAddress() string {
-	if x != nil {
-		return x.IpAddress
-	}
-	return ""
-}
-
-func (x *RegisterRequest) GetAgentVersion() string {
+func (x *CPUCoreUsage) GetCore() string {
 	if x != nil {
-		return x.AgentVersion
+		return x.Core
 	}
 	return ""
 }
 
-func (x *RegisterRequest) GetMetadata() map[string]string {
+func (x *CPUCoreUsage) GetPercent() float64 {
 	if x != nil {
-		return x.Metadata
+		return x.Percent
 	}
-	return nil
-}
-
-type RegisterResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	AgentId       string                 `protobuf:"bytes,3,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	AccessToken   string                 `protobuf:"bytes,4,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
-	ExpiresAt     int64                  `protobuf:"varint,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	return 0
 }
 
-func (x *RegisterResponse) Reset() {
-	*x = RegisterResponse{}
+// ExtendedMetrics holds the metrics reported next to the cpu, ram and disk percentages
+type ExtendedMetrics struct {
+	state             protoimpl.MessageState      `protogen:"open.v1"`
+	Load_1            float64                     `protobuf:"fixed64,1,opt,name=load_1,json=load1,proto3" json:"load_1,omitempty"`
+	Load_5            float64                     `protobuf:"fixed64,2,opt,name=load_5,json=load5,proto3" json:"load_5,omitempty"`
+	Load_15           float64                     `protobuf:"fixed64,3,opt,name=load_15,json=load15,proto3" json:"load_15,omitempty"`
+	CpuCores          int32                       `protobuf:"varint,4,opt,name=cpu_cores,json=cpuCores,proto3" json:"cpu_cores,omitempty"`
+	RamTotal          uint64                      `protobuf:"varint,5,opt,name=ram_total,json=ramTotal,proto3" json:"ram_total,omitempty"`
+	RamUsed           uint64                      `protobuf:"varint,6,opt,name=ram_used,json=ramUsed,proto3" json:"ram_used,omitempty"`
+	DiskTotal         uint64                      `protobuf:"varint,7,opt,name=disk_total,json=diskTotal,proto3" json:"disk_total,omitempty"`
+	DiskUsed          uint64                      `protobuf:"varint,8,opt,name=disk_used,json=diskUsed,proto3" json:"disk_used,omitempty"`
+	Uptime            uint64                      `protobuf:"varint,9,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	ProcessCount      uint64                      `protobuf:"varint,10,opt,name=process_count,json=processCount,proto3" json:"process_count,omitempty"`
+	NetworkSent       uint64                      `protobuf:"varint,11,opt,name=network_sent,json=networkSent,proto3" json:"network_sent,omitempty"`
+	NetworkRecv       uint64                      `protobuf:"varint,12,opt,name=network_recv,json=networkRecv,proto3" json:"network_recv,omitempty"`
+	Cores             []*CPUCoreUsage             `protobuf:"bytes,13,rep,name=cores,proto3" json:"cores,omitempty"`
+	Partitions        []*disk.DiskPartition       `protobuf:"bytes,14,rep,name=partitions,proto3" json:"partitions,omitempty"`
+	Interfaces        []*network.NetworkInterface `protobuf:"bytes,15,rep,name=interfaces,proto3" json:"interfaces,omitempty"`
+	Custom            map[string]float64          `protobuf:"bytes,16,rep,name=custom,proto3" json:"custom,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	NetworkSentRate   float64                     `protobuf:"fixed64,17,opt,name=network_sent_rate,json=networkSentRate,proto3" json:"network_sent_rate,omitempty"`
+	NetworkRecvRate   float64                     `protobuf:"fixed64,18,opt,name=network_recv_rate,json=networkRecvRate,proto3" json:"network_recv_rate,omitempty"`
+	NetworkErrorsRate float64                     `protobuf:"fixed64,19,opt,name=network_errors_rate,json=networkErrorsRate,proto3" json:"network_errors_rate,omitempty"`
+	NetworkDropsRate  float64                     `protobuf:"fixed64,20,opt,name=network_drops_rate,json=networkDropsRate,proto3" json:"network_drops_rate,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ExtendedMetrics) Reset() {
+	*x = ExtendedMetrics{}
 	mi := &file_monitor_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RegisterResponse) String() string {
+func (x *ExtendedMetrics) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RegisterResponse) ProtoMessage() {}
+func (*ExtendedMetrics) ProtoMessage() {}
 
-func (x *RegisterResponse) ProtoReflect() protoreflect.Message {
+func (x *ExtendedMetrics) ProtoReflect() protoreflect.Message {
 	mi := &file_monitor_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -288,133 +498,179 @@ func (x *RegisterResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RegisterResponse.ProtoReflect.Descriptor instead.
-func (*RegisterResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ExtendedMetrics.ProtoReflect.Descriptor instead.
+func (*ExtendedMetrics) Descriptor() ([]byte, []int) {
 	return file_monitor_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *RegisterResponse) GetSuccess() bool {
+func (x *ExtendedMetrics) GetLoad_1() float64 {
 	if x != nil {
-		return x.Success
+		return x.Load_1
 	}
-	return false
+	return 0
 }
 
-func (x *RegisterResponse) GetMessage() string {
+func (x *ExtendedMetrics) GetLoad_5() float64 {
 	if x != nil {
-		return x.Message
+		return x.Load_5
 	}
-	return ""
+	return 0
 }
 
-func (x *RegisterResponse) GetAgentId() string {
+func (x *ExtendedMetrics) GetLoad_15() float64 {
 	if x != nil {
-		return x.AgentId
+		return x.Load_15
 	}
-	return ""
+	return 0
 }
 
-func (x *RegisterResponse) GetAccessToken() string {
+func (x *ExtendedMetrics) GetCpuCores() int32 {
 	if x != nil {
-		return x.AccessToken
+		return x.CpuCores
 	}
-	return ""
+	return 0
 }
 
-func (x *RegisterResponse) GetExpiresAt() int64 {
+func (x *ExtendedMetrics) GetRamTotal() uint64 {
 	if x != nil {
-		return x.ExpiresAt
+		return x.RamTotal
 	}
 	return 0
 }
 
-// Agent Control Messages
-type ControlAgentRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	Action        string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"` // "start", "shutdown", "restart"
-	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // Optional reason for the action
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ExtendedMetrics) GetRamUsed() uint64 {
+	if x != nil {
+		return x.RamUsed
+	}
+	return 0
 }
 
-func (x *ControlAgentRequest) Reset() {
-	*x = ControlAgentRequest{}
-	mi := &file_monitor_proto_msgTypes[4]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ExtendedMetrics) GetDiskTotal() uint64 {
+	if x != nil {
+		return x.DiskTotal
+	}
+	return 0
 }
 
-func (x *ControlAgentRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ExtendedMetrics) GetDiskUsed() uint64 {
+	if x != nil {
+		return x.DiskUsed
+	}
+	return 0
 }
 
-func (*ControlAgentRequest) ProtoMessage() {}
+func (x *ExtendedMetrics) GetUptime() uint64 {
+	if x != nil {
+		return x.Uptime
+	}
+	return 0
+}
 
-func (x *ControlAgentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_monitor_proto_msgTypes[4]
+func (x *ExtendedMetrics) GetProcessCount() uint64 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.ProcessCount
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use ControlAgentRequest.ProtoReflect.Descriptor instead.
-func (*ControlAgentRequest) Descriptor() ([]byte, []int) {
-	return file_monitor_proto_rawDescGZIP(), []int{4}
+func (x *ExtendedMetrics) GetNetworkSent() uint64 {
+	if x != nil {
+		return x.NetworkSent
+	}
+	return 0
 }
 
-func (x *ControlAgentRequest) GetAgentId() string {
+func (x *ExtendedMetrics) GetNetworkRecv() uint64 {
 	if x != nil {
-		return x.AgentId
+		return x.NetworkRecv
 	}
-	return ""
+	return 0
 }
 
-func (x *ControlAgentRequest) GetAction() string {
+func (x *ExtendedMetrics) GetCores() []*CPUCoreUsage {
 	if x != nil {
-		return x.Action
+		return x.Cores
 	}
-	return ""
+	return nil
 }
 
-func (x *ControlAgentRequest) GetReason() string {
+func (x *ExtendedMetrics) GetPartitions() []*disk.DiskPartition {
 	if x != nil {
-		return x.Reason
+		return x.Partitions
 	}
-	return ""
+	return nil
 }
 
-type ControlAgentResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	AgentId       string                 `protobuf:"bytes,3,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	Action        string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
-	Timestamp     int64                  `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ExtendedMetrics) GetInterfaces() []*network.NetworkInterface {
+	if x != nil {
+		return x.Interfaces
+	}
+	return nil
 }
 
-func (x *ControlAgentResponse) Reset() {
-	*x = ControlAgentResponse{}
-	mi := &file_monitor_proto_msgTypes[5]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ExtendedMetrics) GetCustom() map[string]float64 {
+	if x != nil {
+		return x.Custom
+	}
+	return nil
 }
 
-func (x *ControlAgentResponse) String() string {
+func (x *ExtendedMetrics) GetNetworkSentRate() float64 {
+	if x != nil {
+		return x.NetworkSentRate
+	}
+	return 0
+}
+
+func (x *ExtendedMetrics) GetNetworkRecvRate() float64 {
+	if x != nil {
+		return x.NetworkRecvRate
+	}
+	return 0
+}
+
+func (x *ExtendedMetrics) GetNetworkErrorsRate() float64 {
+	if x != nil {
+		return x.NetworkErrorsRate
+	}
+	return 0
+}
+
+func (x *ExtendedMetrics) GetNetworkDropsRate() float64 {
+	if x != nil {
+		return x.NetworkDropsRate
+	}
+	return 0
+}
+
+// StatsSample is one sample of a StatsRequest batch
+type StatsSample struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Cpu             float64                `protobuf:"fixed64,1,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	Ram             float64                `protobuf:"fixed64,2,opt,name=ram,proto3" json:"ram,omitempty"`
+	Disk            float64                `protobuf:"fixed64,3,opt,name=disk,proto3" json:"disk,omitempty"`
+	CollectedAt     int64                  `protobuf:"varint,4,opt,name=collected_at,json=collectedAt,proto3" json:"collected_at,omitempty"`
+	Extended        *ExtendedMetrics       `protobuf:"bytes,5,opt,name=extended,proto3" json:"extended,omitempty"`
+	CollectorErrors []*CollectorError      `protobuf:"bytes,6,rep,name=collector_errors,json=collectorErrors,proto3" json:"collector_errors,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *StatsSample) Reset() {
+	*x = StatsSample{}
+	mi := &file_monitor_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsSample) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ControlAgentResponse) ProtoMessage() {}
+func (*StatsSample) ProtoMessage() {}
 
-func (x *ControlAgentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_monitor_proto_msgTypes[5]
+func (x *StatsSample) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -425,70 +681,129 @@ func (x *ControlAgentResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ControlAgentResponse.ProtoReflect.Descriptor instead.
-func (*ControlAgentResponse) Descriptor() ([]byte, []int) {
-	return file_monitor_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use StatsSample.ProtoReflect.Descriptor instead.
+func (*StatsSample) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *ControlAgentResponse) GetSuccess() bool {
+func (x *StatsSample) GetCpu() float64 {
 	if x != nil {
-		return x.Success
+		return x.Cpu
 	}
-	return false
+	return 0
 }
 
-func (x *ControlAgentResponse) GetMessage() string {
+func (x *StatsSample) GetRam() float64 {
 	if x != nil {
-		return x.Message
+		return x.Ram
 	}
-	return ""
+	return 0
 }
 
-func (x *ControlAgentResponse) GetAgentId() string {
+func (x *StatsSample) GetDisk() float64 {
 	if x != nil {
-		return x.AgentId
+		return x.Disk
 	}
-	return ""
+	return 0
 }
 
-func (x *ControlAgentResponse) GetAction() string {
+func (x *StatsSample) GetCollectedAt() int64 {
 	if x != nil {
-		return x.Action
+		return x.CollectedAt
+	}
+	return 0
+}
+
+func (x *StatsSample) GetExtended() *ExtendedMetrics {
+	if x != nil {
+		return x.Extended
+	}
+	return nil
+}
+
+func (x *StatsSample) GetCollectorErrors() []*CollectorError {
+	if x != nil {
+		return x.CollectorErrors
+	}
+	return nil
+}
+
+type StatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsResponse) Reset() {
+	*x = StatsResponse{}
+	mi := &file_monitor_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsResponse) ProtoMessage() {}
+
+func (x *StatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsResponse.ProtoReflect.Descriptor instead.
+func (*StatsResponse) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StatsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
 	}
 	return ""
 }
 
-func (x *ControlAgentResponse) GetTimestamp() int64 {
+func (x *StatsResponse) GetTimestamp() int64 {
 	if x != nil {
 		return x.Timestamp
 	}
 	return 0
 }
 
-// Block Agent Messages
-type BlockAgentRequest struct {
+// Exactly one of hostname and agent_id is set. By agent ID, the host the
+// agent reported most recently is returned.
+type GetStatsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	Blocked       bool                   `protobuf:"varint,2,opt,name=blocked,proto3" json:"blocked,omitempty"` // true to block, false to unblock
-	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Hostname      string                 `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BlockAgentRequest) Reset() {
-	*x = BlockAgentRequest{}
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
 	mi := &file_monitor_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BlockAgentRequest) String() string {
+func (x *GetStatsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BlockAgentRequest) ProtoMessage() {}
+func (*GetStatsRequest) ProtoMessage() {}
 
-func (x *BlockAgentRequest) ProtoReflect() protoreflect.Message {
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_monitor_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -500,56 +815,46 @@ func (x *BlockAgentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BlockAgentRequest.ProtoReflect.Descriptor instead.
-func (*BlockAgentRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
 	return file_monitor_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *BlockAgentRequest) GetAgentId() string {
+func (x *GetStatsRequest) GetHostname() string {
 	if x != nil {
-		return x.AgentId
+		return x.Hostname
 	}
 	return ""
 }
 
-func (x *BlockAgentRequest) GetBlocked() bool {
-	if x != nil {
-		return x.Blocked
-	}
-	return false
-}
-
-func (x *BlockAgentRequest) GetReason() string {
+func (x *GetStatsRequest) GetAgentId() string {
 	if x != nil {
-		return x.Reason
+		return x.AgentId
 	}
 	return ""
 }
 
-type BlockAgentResponse struct {
+type GetStatsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	AgentId       string                 `protobuf:"bytes,3,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	Blocked       bool                   `protobuf:"varint,4,opt,name=blocked,proto3" json:"blocked,omitempty"`
+	Stats         *HostStats             `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BlockAgentResponse) Reset() {
-	*x = BlockAgentResponse{}
+func (x *GetStatsResponse) Reset() {
+	*x = GetStatsResponse{}
 	mi := &file_monitor_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BlockAgentResponse) String() string {
+func (x *GetStatsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BlockAgentResponse) ProtoMessage() {}
+func (*GetStatsResponse) ProtoMessage() {}
 
-func (x *BlockAgentResponse) ProtoReflect() protoreflect.Message {
+func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_monitor_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -561,68 +866,99 @@ func (x *BlockAgentResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BlockAgentResponse.ProtoReflect.Descriptor instead.
-func (*BlockAgentResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetStatsResponse) Descriptor() ([]byte, []int) {
 	return file_monitor_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *BlockAgentResponse) GetSuccess() bool {
+func (x *GetStatsResponse) GetStats() *HostStats {
 	if x != nil {
-		return x.Success
+		return x.Stats
 	}
-	return false
+	return nil
 }
 
-func (x *BlockAgentResponse) GetMessage() string {
+type WatchStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentIds      []string               `protobuf:"bytes,1,rep,name=agent_ids,json=agentIds,proto3" json:"agent_ids,omitempty"`                                                       // empty for all agents
+	Labels        map[string]string      `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // metadata every sample must carry
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchStatsRequest) Reset() {
+	*x = WatchStatsRequest{}
+	mi := &file_monitor_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchStatsRequest) ProtoMessage() {}
+
+func (x *WatchStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[8]
 	if x != nil {
-		return x.Message
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *BlockAgentResponse) GetAgentId() string {
+// Deprecated: Use WatchStatsRequest.ProtoReflect.Descriptor instead.
+func (*WatchStatsRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *WatchStatsRequest) GetAgentIds() []string {
 	if x != nil {
-		return x.AgentId
+		return x.AgentIds
 	}
-	return ""
+	return nil
 }
 
-func (x *BlockAgentResponse) GetBlocked() bool {
+func (x *WatchStatsRequest) GetLabels() map[string]string {
 	if x != nil {
-		return x.Blocked
+		return x.Labels
 	}
-	return false
+	return nil
 }
 
-// Policy Messages
-type PolicyRequest struct {
+// Set hostname, agent_id or both. Unset fields take the defaults noted.
+type StatsRangeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	PolicyId      string                 `protobuf:"bytes,1,opt,name=policy_id,json=policyId,proto3" json:"policy_id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Thresholds    map[string]string      `protobuf:"bytes,4,rep,name=thresholds,proto3" json:"thresholds,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // e.g., {"cpu": "80", "ram": "90"}
-	Actions       []string               `protobuf:"bytes,5,rep,name=actions,proto3" json:"actions,omitempty"`                                                                                 // e.g., ["alert", "restart"]
-	Metadata      map[string]string      `protobuf:"bytes,6,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	Enabled       bool                   `protobuf:"varint,7,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Hostname      string                 `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Metrics       []string               `protobuf:"bytes,3,rep,name=metrics,proto3" json:"metrics,omitempty"`                             // cpu, ram, disk, network_tx, network_rx, network_errors, network_drops; defaults to cpu, ram and disk
+	From          int64                  `protobuf:"varint,4,opt,name=from,proto3" json:"from,omitempty"`                                  // Unix milliseconds, defaults to an hour before to
+	To            int64                  `protobuf:"varint,5,opt,name=to,proto3" json:"to,omitempty"`                                      // Unix milliseconds, defaults to now
+	StepSeconds   int64                  `protobuf:"varint,6,opt,name=step_seconds,json=stepSeconds,proto3" json:"step_seconds,omitempty"` // defaults to 60
+	Aggregation   string                 `protobuf:"bytes,7,opt,name=aggregation,proto3" json:"aggregation,omitempty"`                     // avg (default), max, min or p95
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PolicyRequest) Reset() {
-	*x = PolicyRequest{}
-	mi := &file_monitor_proto_msgTypes[8]
+func (x *StatsRangeRequest) Reset() {
+	*x = StatsRangeRequest{}
+	mi := &file_monitor_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PolicyRequest) String() string {
+func (x *StatsRangeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PolicyRequest) ProtoMessage() {}
+func (*StatsRangeRequest) ProtoMessage() {}
 
-func (x *PolicyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_monitor_proto_msgTypes[8]
+func (x *StatsRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -633,85 +969,3525 @@ func (x *PolicyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PolicyRequest.ProtoReflect.Descriptor instead.
-func (*PolicyRequest) Descriptor() ([]byte, []int) {
-	return file_monitor_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use StatsRangeRequest.ProtoReflect.Descriptor instead.
+func (*StatsRangeRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *PolicyRequest) GetPolicyId() string {
+func (x *StatsRangeRequest) GetHostname() string {
 	if x != nil {
-		return x.PolicyId
+		return x.Hostname
 	}
 	return ""
 }
 
-func (x *PolicyRequest) GetName() string {
+func (x *StatsRangeRequest) GetAgentId() string {
 	if x != nil {
-		return x.Name
+		return x.AgentId
 	}
 	return ""
 }
 
-func (x *PolicyRequest) GetDescription() string {
+func (x *StatsRangeRequest) GetMetrics() []string {
 	if x != nil {
-		return x.Description
+		return x.Metrics
 	}
-	return ""
+	return nil
 }
 
-func (x *PolicyRequest) GetThresholds() map[string]string {
+func (x *StatsRangeRequest) GetFrom() int64 {
 	if x != nil {
-		return x.Thresholds
+		return x.From
 	}
-	return nil
+	return 0
 }
 
-func (x *PolicyRequest) GetActions() []string {
+func (x *StatsRangeRequest) GetTo() int64 {
 	if x != nil {
-		return x.Actions
+		return x.To
 	}
-	return nil
+	return 0
 }
 
-func (x *PolicyRequest) GetMetadata() map[string]string {
+func (x *StatsRangeRequest) GetStepSeconds() int64 {
 	if x != nil {
-		return x.Metadata
+		return x.StepSeconds
 	}
-	return nil
+	return 0
 }
 
-func (x *PolicyRequest) GetEnabled() bool {
+func (x *StatsRangeRequest) GetAggregation() string {
 	if x != nil {
-		return x.Enabled
+		return x.Aggregation
 	}
-	return false
+	return ""
 }
 
-type PolicyResponse struct {
+type StatsRangeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Series        []*StatsSeries         `protobuf:"bytes,1,rep,name=series,proto3" json:"series,omitempty"` // in the order the metrics were asked for
+	StepSeconds   int64                  `protobuf:"varint,2,opt,name=step_seconds,json=stepSeconds,proto3" json:"step_seconds,omitempty"`
+	Aggregation   string                 `protobuf:"bytes,3,opt,name=aggregation,proto3" json:"aggregation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsRangeResponse) Reset() {
+	*x = StatsRangeResponse{}
+	mi := &file_monitor_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsRangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsRangeResponse) ProtoMessage() {}
+
+func (x *StatsRangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsRangeResponse.ProtoReflect.Descriptor instead.
+func (*StatsRangeResponse) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StatsRangeResponse) GetSeries() []*StatsSeries {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+func (x *StatsRangeResponse) GetStepSeconds() int64 {
+	if x != nil {
+		return x.StepSeconds
+	}
+	return 0
+}
+
+func (x *StatsRangeResponse) GetAggregation() string {
+	if x != nil {
+		return x.Aggregation
+	}
+	return ""
+}
+
+// Points of one metric, oldest first. Steps without samples have no point.
+type StatsSeries struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metric        string                 `protobuf:"bytes,1,opt,name=metric,proto3" json:"metric,omitempty"`
+	Points        []*StatsPoint          `protobuf:"bytes,2,rep,name=points,proto3" json:"points,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsSeries) Reset() {
+	*x = StatsSeries{}
+	mi := &file_monitor_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsSeries) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsSeries) ProtoMessage() {}
+
+func (x *StatsSeries) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsSeries.ProtoReflect.Descriptor instead.
+func (*StatsSeries) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *StatsSeries) GetMetric() string {
+	if x != nil {
+		return x.Metric
+	}
+	return ""
+}
+
+func (x *StatsSeries) GetPoints() []*StatsPoint {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+type StatsPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // start of the step, Unix milliseconds
+	Value         float64                `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsPoint) Reset() {
+	*x = StatsPoint{}
+	mi := &file_monitor_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsPoint) ProtoMessage() {}
+
+func (x *StatsPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsPoint.ProtoReflect.Descriptor instead.
+func (*StatsPoint) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *StatsPoint) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *StatsPoint) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+// Latest sample of a host
+type HostStats struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Hostname        string                 `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	AgentId         string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	IpAddress       string                 `protobuf:"bytes,3,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	Cpu             float64                `protobuf:"fixed64,4,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	Ram             float64                `protobuf:"fixed64,5,opt,name=ram,proto3" json:"ram,omitempty"`
+	Disk            float64                `protobuf:"fixed64,6,opt,name=disk,proto3" json:"disk,omitempty"`
+	CollectedAt     int64                  `protobuf:"varint,7,opt,name=collected_at,json=collectedAt,proto3" json:"collected_at,omitempty"`    // when the agent took the sample, Unix milliseconds
+	LastReceived    int64                  `protobuf:"varint,8,opt,name=last_received,json=lastReceived,proto3" json:"last_received,omitempty"` // when the backend received it, Unix milliseconds
+	Metadata        map[string]string      `protobuf:"bytes,9,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Extended        *ExtendedMetrics       `protobuf:"bytes,10,opt,name=extended,proto3" json:"extended,omitempty"`
+	CollectorErrors []*CollectorError      `protobuf:"bytes,11,rep,name=collector_errors,json=collectorErrors,proto3" json:"collector_errors,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *HostStats) Reset() {
+	*x = HostStats{}
+	mi := &file_monitor_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostStats) ProtoMessage() {}
+
+func (x *HostStats) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostStats.ProtoReflect.Descriptor instead.
+func (*HostStats) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *HostStats) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *HostStats) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *HostStats) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *HostStats) GetCpu() float64 {
+	if x != nil {
+		return x.Cpu
+	}
+	return 0
+}
+
+func (x *HostStats) GetRam() float64 {
+	if x != nil {
+		return x.Ram
+	}
+	return 0
+}
+
+func (x *HostStats) GetDisk() float64 {
+	if x != nil {
+		return x.Disk
+	}
+	return 0
+}
+
+func (x *HostStats) GetCollectedAt() int64 {
+	if x != nil {
+		return x.CollectedAt
+	}
+	return 0
+}
+
+func (x *HostStats) GetLastReceived() int64 {
+	if x != nil {
+		return x.LastReceived
+	}
+	return 0
+}
+
+func (x *HostStats) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *HostStats) GetExtended() *ExtendedMetrics {
+	if x != nil {
+		return x.Extended
+	}
+	return nil
+}
+
+func (x *HostStats) GetCollectorErrors() []*CollectorError {
+	if x != nil {
+		return x.CollectorErrors
+	}
+	return nil
+}
+
+// Registration messages
+type RegisterRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Hostname            string                 `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	IpAddress           string                 `protobuf:"bytes,2,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	AgentVersion        string                 `protobuf:"bytes,3,opt,name=agent_version,json=agentVersion,proto3" json:"agent_version,omitempty"`
+	Metadata            map[string]string      `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Fingerprint         string                 `protobuf:"bytes,5,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	EnrollmentToken     string                 `protobuf:"bytes,6,opt,name=enrollment_token,json=enrollmentToken,proto3" json:"enrollment_token,omitempty"`
+	PreviousAccessToken string                 `protobuf:"bytes,7,opt,name=previous_access_token,json=previousAccessToken,proto3" json:"previous_access_token,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *RegisterRequest) Reset() {
+	*x = RegisterRequest{}
+	mi := &file_monitor_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterRequest) ProtoMessage() {}
+
+func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterRequest.ProtoReflect.Descriptor instead.
+func (*RegisterRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RegisterRequest) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetAgentVersion() string {
+	if x != nil {
+		return x.AgentVersion
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *RegisterRequest) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetEnrollmentToken() string {
+	if x != nil {
+		return x.EnrollmentToken
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetPreviousAccessToken() string {
+	if x != nil {
+		return x.PreviousAccessToken
+	}
+	return ""
+}
+
+type RegisterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	AgentId       string                 `protobuf:"bytes,3,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	AccessToken   string                 `protobuf:"bytes,4,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterResponse) Reset() {
+	*x = RegisterResponse{}
+	mi := &file_monitor_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterResponse) ProtoMessage() {}
+
+func (x *RegisterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterResponse.ProtoReflect.Descriptor instead.
+func (*RegisterResponse) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RegisterResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RegisterResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RegisterResponse) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *RegisterResponse) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *RegisterResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type RenewTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	AccessToken   string                 `protobuf:"bytes,2,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"` // current token, still accepted for a short grace period after renewal
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenewTokenRequest) Reset() {
+	*x = RenewTokenRequest{}
+	mi := &file_monitor_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenewTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenewTokenRequest) ProtoMessage() {}
+
+func (x *RenewTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenewTokenRequest.ProtoReflect.Descriptor instead.
+func (*RenewTokenRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RenewTokenRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *RenewTokenRequest) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+type RenewTokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Unix timestamp when the new token expires
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenewTokenResponse) Reset() {
+	*x = RenewTokenResponse{}
+	mi := &file_monitor_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenewTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenewTokenResponse) ProtoMessage() {}
+
+func (x *RenewTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenewTokenResponse.ProtoReflect.Descriptor instead.
+func (*RenewTokenResponse) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RenewTokenResponse) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *RenewTokenResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+// Agent Control Messages
+type ControlAgentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Action        string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"` // "start", "shutdown", "restart"
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // Optional reason for the action
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ControlAgentRequest) Reset() {
+	*x = ControlAgentRequest{}
+	mi := &file_monitor_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ControlAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ControlAgentRequest) ProtoMessage() {}
+
+func (x *ControlAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ControlAgentRequest.ProtoReflect.Descriptor instead.
+func (*ControlAgentRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ControlAgentRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *ControlAgentRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ControlAgentRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ControlAgentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	AgentId       string                 `protobuf:"bytes,3,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Action        string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	CommandId     string                 `protobuf:"bytes,6,opt,name=command_id,json=commandId,proto3" json:"command_id,omitempty"` // use ListAgentCommands to follow delivery
+	Status        CommandStatus          `protobuf:"varint,7,opt,name=status,proto3,enum=monitor.CommandStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ControlAgentResponse) Reset() {
+	*x = ControlAgentResponse{}
+	mi := &file_monitor_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ControlAgentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ControlAgentResponse) ProtoMessage() {}
+
+func (x *ControlAgentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ControlAgentResponse.ProtoReflect.Descriptor instead.
+func (*ControlAgentResponse) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ControlAgentResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ControlAgentResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ControlAgentResponse) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *ControlAgentResponse) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ControlAgentResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *ControlAgentResponse) GetCommandId() string {
+	if x != nil {
+		return x.CommandId
+	}
+	return ""
+}
+
+func (x *ControlAgentResponse) GetStatus() CommandStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CommandStatus_COMMAND_STATUS_UNSPECIFIED
+}
+
+type AgentCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CommandId     string                 `protobuf:"bytes,1,opt,name=command_id,json=commandId,proto3" json:"command_id,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Action        string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"` // "start", "shutdown", "restart", or "reconfigure" sent by the backend when the agent's config profile changes
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	IssuedAt      int64                  `protobuf:"varint,5,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentCommand) Reset() {
+	*x = AgentCommand{}
+	mi := &file_monitor_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentCommand) ProtoMessage() {}
+
+func (x *AgentCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentCommand.ProtoReflect.Descriptor instead.
+func (*AgentCommand) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *AgentCommand) GetCommandId() string {
+	if x != nil {
+		return x.CommandId
+	}
+	return ""
+}
+
+func (x *AgentCommand) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *AgentCommand) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AgentCommand) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *AgentCommand) GetIssuedAt() int64 {
+	if x != nil {
+		return x.IssuedAt
+	}
+	return 0
+}
+
+type CommandAck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CommandId     string                 `protobuf:"bytes,1,opt,name=command_id,json=commandId,proto3" json:"command_id,omitempty"`
+	Status        CommandStatus          `protobuf:"varint,2,opt,name=status,proto3,enum=monitor.CommandStatus" json:"status,omitempty"` // delivered, executed or failed
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`                           // result or error reported by the agent
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommandAck) Reset() {
+	*x = CommandAck{}
+	mi := &file_monitor_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandAck) ProtoMessage() {}
+
+func (x *CommandAck) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandAck.ProtoReflect.Descriptor instead.
+func (*CommandAck) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *CommandAck) GetCommandId() string {
+	if x != nil {
+		return x.CommandId
+	}
+	return ""
+}
+
+func (x *CommandAck) GetStatus() CommandStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CommandStatus_COMMAND_STATUS_UNSPECIFIED
+}
+
+func (x *CommandAck) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type CommandStreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	AccessToken   string                 `protobuf:"bytes,2,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"` // ignored, credentials are sent in metadata
+	Ack           *CommandAck            `protobuf:"bytes,3,opt,name=ack,proto3" json:"ack,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommandStreamRequest) Reset() {
+	*x = CommandStreamRequest{}
+	mi := &file_monitor_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandStreamRequest) ProtoMessage() {}
+
+func (x *CommandStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandStreamRequest.ProtoReflect.Descriptor instead.
+func (*CommandStreamRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *CommandStreamRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *CommandStreamRequest) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *CommandStreamRequest) GetAck() *CommandAck {
+	if x != nil {
+		return x.Ack
+	}
+	return nil
+}
+
+type AgentCommandStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CommandId     string                 `protobuf:"bytes,1,opt,name=command_id,json=commandId,proto3" json:"command_id,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Action        string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Status        CommandStatus          `protobuf:"varint,5,opt,name=status,proto3,enum=monitor.CommandStatus" json:"status,omitempty"`
+	Message       string                 `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentCommandStatus) Reset() {
+	*x = AgentCommandStatus{}
+	mi := &file_monitor_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentCommandStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentCommandStatus) ProtoMessage() {}
+
+func (x *AgentCommandStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentCommandStatus.ProtoReflect.Descriptor instead.
+func (*AgentCommandStatus) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *AgentCommandStatus) GetCommandId() string {
+	if x != nil {
+		return x.CommandId
+	}
+	return ""
+}
+
+func (x *AgentCommandStatus) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *AgentCommandStatus) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AgentCommandStatus) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *AgentCommandStatus) GetStatus() CommandStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CommandStatus_COMMAND_STATUS_UNSPECIFIED
+}
+
+func (x *AgentCommandStatus) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *AgentCommandStatus) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *AgentCommandStatus) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+type ListAgentCommandsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // default 50
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAgentCommandsRequest) Reset() {
+	*x = ListAgentCommandsRequest{}
+	mi := &file_monitor_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAgentCommandsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentCommandsRequest) ProtoMessage() {}
+
+func (x *ListAgentCommandsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentCommandsRequest.ProtoReflect.Descriptor instead.
+func (*ListAgentCommandsRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListAgentCommandsRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *ListAgentCommandsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListAgentCommandsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Commands      []*AgentCommandStatus  `protobuf:"bytes,1,rep,name=commands,proto3" json:"commands,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAgentCommandsResponse) Reset() {
+	*x = ListAgentCommandsResponse{}
+	mi := &file_monitor_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAgentCommandsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentCommandsResponse) ProtoMessage() {}
+
+func (x *ListAgentCommandsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentCommandsResponse.ProtoReflect.Descriptor instead.
+func (*ListAgentCommandsResponse) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListAgentCommandsResponse) GetCommands() []*AgentCommandStatus {
+	if x != nil {
+		return x.Commands
+	}
+	return nil
+}
+
+func (x *ListAgentCommandsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// Block Agent Messages
+type BlockAgentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Blocked       bool                   `protobuf:"varint,2,opt,name=blocked,proto3" json:"blocked,omitempty"` // true to block, false to unblock
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockAgentRequest) Reset() {
+	*x = BlockAgentRequest{}
+	mi := &file_monitor_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockAgentRequest) ProtoMessage() {}
+
+func (x *BlockAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockAgentRequest.ProtoReflect.Descriptor instead.
+func (*BlockAgentRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *BlockAgentRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *BlockAgentRequest) GetBlocked() bool {
+	if x != nil {
+		return x.Blocked
+	}
+	return false
+}
+
+func (x *BlockAgentRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type BlockAgentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	AgentId       string                 `protobuf:"bytes,3,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Blocked       bool                   `protobuf:"varint,4,opt,name=blocked,proto3" json:"blocked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockAgentResponse) Reset() {
+	*x = BlockAgentResponse{}
+	mi := &file_monitor_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockAgentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockAgentResponse) ProtoMessage() {}
+
+func (x *BlockAgentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockAgentResponse.ProtoReflect.Descriptor instead.
+func (*BlockAgentResponse) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *BlockAgentResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *BlockAgentResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *BlockAgentResponse) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *BlockAgentResponse) GetBlocked() bool {
+	if x != nil {
+		return x.Blocked
+	}
+	return false
+}
+
+type MergeAgentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`                     // agent that remains
+	SourceAgentId string                 `protobuf:"bytes,2,opt,name=source_agent_id,json=sourceAgentId,proto3" json:"source_agent_id,omitempty"` // duplicate merged into it and removed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MergeAgentsRequest) Reset() {
+	*x = MergeAgentsRequest{}
+	mi := &file_monitor_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeAgentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeAgentsRequest) ProtoMessage() {}
+
+func (x *MergeAgentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeAgentsRequest.ProtoReflect.Descriptor instead.
+func (*MergeAgentsRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *MergeAgentsRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *MergeAgentsRequest) GetSourceAgentId() string {
+	if x != nil {
+		return x.SourceAgentId
+	}
+	return ""
+}
+
+type MergeAgentsResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Success         bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message         string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	AgentId         string                 `protobuf:"bytes,3,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	PoliciesMoved   int32                  `protobuf:"varint,4,opt,name=policies_moved,json=policiesMoved,proto3" json:"policies_moved,omitempty"`
+	ConfigProfileId string                 `protobuf:"bytes,5,opt,name=config_profile_id,json=configProfileId,proto3" json:"config_profile_id,omitempty"` // config profile taken over from the source, if any
+	HostsMoved      int32                  `protobuf:"varint,6,opt,name=hosts_moved,json=hostsMoved,proto3" json:"hosts_moved,omitempty"`
+	StatsMoved      int64                  `protobuf:"varint,7,opt,name=stats_moved,json=statsMoved,proto3" json:"stats_moved,omitempty"` // stored samples reassigned to agent_id
+	Timestamp       int64                  `protobuf:"varint,8,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MergeAgentsResponse) Reset() {
+	*x = MergeAgentsResponse{}
+	mi := &file_monitor_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeAgentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeAgentsResponse) ProtoMessage() {}
+
+func (x *MergeAgentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeAgentsResponse.ProtoReflect.Descriptor instead.
+func (*MergeAgentsResponse) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *MergeAgentsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *MergeAgentsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *MergeAgentsResponse) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *MergeAgentsResponse) GetPoliciesMoved() int32 {
+	if x != nil {
+		return x.PoliciesMoved
+	}
+	return 0
+}
+
+func (x *MergeAgentsResponse) GetConfigProfileId() string {
+	if x != nil {
+		return x.ConfigProfileId
+	}
+	return ""
+}
+
+func (x *MergeAgentsResponse) GetHostsMoved() int32 {
+	if x != nil {
+		return x.HostsMoved
+	}
+	return 0
+}
+
+func (x *MergeAgentsResponse) GetStatsMoved() int64 {
+	if x != nil {
+		return x.StatsMoved
+	}
+	return 0
+}
+
+func (x *MergeAgentsResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+// Registered agent as shown to admins; credentials are never included
+type Agent struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	AgentId           string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Hostname          string                 `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	IpAddress         string                 `protobuf:"bytes,3,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	AgentVersion      string                 `protobuf:"bytes,4,opt,name=agent_version,json=agentVersion,proto3" json:"agent_version,omitempty"`
+	Status            string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"` // active, suspended, revoked or blocked
+	Blocked           bool                   `protobuf:"varint,6,opt,name=blocked,proto3" json:"blocked,omitempty"`
+	BlockReason       string                 `protobuf:"bytes,7,opt,name=block_reason,json=blockReason,proto3" json:"block_reason,omitempty"`
+	Metadata          map[string]string      `protobuf:"bytes,8,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Fingerprint       string                 `protobuf:"bytes,9,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	EnrollmentTokenId string                 `protobuf:"bytes,10,opt,name=enrollment_token_id,json=enrollmentTokenId,proto3" json:"enrollment_token_id,omitempty"`
+	RegisteredAt      int64                  `protobuf:"varint,11,opt,name=registered_at,json=registeredAt,proto3" json:"registered_at,omitempty"`
+	LastAuthAt        int64                  `protobuf:"varint,12,opt,name=last_auth_at,json=lastAuthAt,proto3" json:"last_auth_at,omitempty"`
+	TokenExpiresAt    int64                  `protobuf:"varint,13,opt,name=token_expires_at,json=tokenExpiresAt,proto3" json:"token_expires_at,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Agent) Reset() {
+	*x = Agent{}
+	mi := &file_monitor_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Agent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Agent) ProtoMessage() {}
+
+func (x *Agent) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Agent.ProtoReflect.Descriptor instead.
+func (*Agent) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *Agent) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *Agent) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *Agent) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *Agent) GetAgentVersion() string {
+	if x != nil {
+		return x.AgentVersion
+	}
+	return ""
+}
+
+func (x *Agent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Agent) GetBlocked() bool {
+	if x != nil {
+		return x.Blocked
+	}
+	return false
+}
+
+func (x *Agent) GetBlockReason() string {
+	if x != nil {
+		return x.BlockReason
+	}
+	return ""
+}
+
+func (x *Agent) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Agent) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+func (x *Agent) GetEnrollmentTokenId() string {
+	if x != nil {
+		return x.EnrollmentTokenId
+	}
+	return ""
+}
+
+func (x *Agent) GetRegisteredAt() int64 {
+	if x != nil {
+		return x.RegisteredAt
+	}
+	return 0
+}
+
+func (x *Agent) GetLastAuthAt() int64 {
+	if x != nil {
+		return x.LastAuthAt
+	}
+	return 0
+}
+
+func (x *Agent) GetTokenExpiresAt() int64 {
+	if x != nil {
+		return x.TokenExpiresAt
+	}
+	return 0
+}
+
+type ListAgentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`          // empty matches any status
+	Blocked       *bool                  `protobuf:"varint,4,opt,name=blocked,proto3,oneof" json:"blocked,omitempty"` // unset matches blocked and unblocked agents
+	AgentVersion  string                 `protobuf:"bytes,5,opt,name=agent_version,json=agentVersion,proto3" json:"agent_version,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // metadata labels an agent must carry
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAgentsRequest) Reset() {
+	*x = ListAgentsRequest{}
+	mi := &file_monitor_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAgentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentsRequest) ProtoMessage() {}
+
+func (x *ListAgentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentsRequest.ProtoReflect.Descriptor instead.
+func (*ListAgentsRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListAgentsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListAgentsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListAgentsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListAgentsRequest) GetBlocked() bool {
+	if x != nil && x.Blocked != nil {
+		return *x.Blocked
+	}
+	return false
+}
+
+func (x *ListAgentsRequest) GetAgentVersion() string {
+	if x != nil {
+		return x.AgentVersion
+	}
+	return ""
+}
+
+func (x *ListAgentsRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type ListAgentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agents        []*Agent               `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"` // agents matching the filters
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAgentsResponse) Reset() {
+	*x = ListAgentsResponse{}
+	mi := &file_monitor_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAgentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentsResponse) ProtoMessage() {}
+
+func (x *ListAgentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentsResponse.ProtoReflect.Descriptor instead.
+func (*ListAgentsResponse) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ListAgentsResponse) GetAgents() []*Agent {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
+func (x *ListAgentsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListAgentsResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListAgentsResponse) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type GetAgentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAgentRequest) Reset() {
+	*x = GetAgentRequest{}
+	mi := &file_monitor_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAgentRequest) ProtoMessage() {}
+
+func (x *GetAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAgentRequest.ProtoReflect.Descriptor instead.
+func (*GetAgentRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetAgentRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+type GetAgentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agent         *Agent                 `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAgentResponse) Reset() {
+	*x = GetAgentResponse{}
+	mi := &file_monitor_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAgentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAgentResponse) ProtoMessage() {}
+
+func (x *GetAgentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAgentResponse.ProtoReflect.Descriptor instead.
+func (*GetAgentResponse) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetAgentResponse) GetAgent() *Agent {
+	if x != nil {
+		return x.Agent
+	}
+	return nil
+}
+
+type RevokeAgentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAgentRequest) Reset() {
+	*x = RevokeAgentRequest{}
+	mi := &file_monitor_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAgentRequest) ProtoMessage() {}
+
+func (x *RevokeAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAgentRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAgentRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *RevokeAgentRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+type RevokeAgentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	AgentId       string                 `protobuf:"bytes,3,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAgentResponse) Reset() {
+	*x = RevokeAgentResponse{}
+	mi := &file_monitor_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAgentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAgentResponse) ProtoMessage() {}
+
+func (x *RevokeAgentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAgentResponse.ProtoReflect.Descriptor instead.
+func (*RevokeAgentResponse) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *RevokeAgentResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RevokeAgentResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RevokeAgentResponse) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *RevokeAgentResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type DeleteAgentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAgentRequest) Reset() {
+	*x = DeleteAgentRequest{}
+	mi := &file_monitor_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAgentRequest) ProtoMessage() {}
+
+func (x *DeleteAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAgentRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAgentRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *DeleteAgentRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+type DeleteAgentResponse struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Success                bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message                string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	AgentId                string                 `protobuf:"bytes,3,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	PoliciesUnapplied      int32                  `protobuf:"varint,4,opt,name=policies_unapplied,json=policiesUnapplied,proto3" json:"policies_unapplied,omitempty"`
+	ConfigProfilesDetached int32                  `protobuf:"varint,5,opt,name=config_profiles_detached,json=configProfilesDetached,proto3" json:"config_profiles_detached,omitempty"`
+	HostsRemoved           int32                  `protobuf:"varint,6,opt,name=hosts_removed,json=hostsRemoved,proto3" json:"hosts_removed,omitempty"`
+	Timestamp              int64                  `protobuf:"varint,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *DeleteAgentResponse) Reset() {
+	*x = DeleteAgentResponse{}
+	mi := &file_monitor_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAgentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAgentResponse) ProtoMessage() {}
+
+func (x *DeleteAgentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAgentResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAgentResponse) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *DeleteAgentResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeleteAgentResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *DeleteAgentResponse) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *DeleteAgentResponse) GetPoliciesUnapplied() int32 {
+	if x != nil {
+		return x.PoliciesUnapplied
+	}
+	return 0
+}
+
+func (x *DeleteAgentResponse) GetConfigProfilesDetached() int32 {
+	if x != nil {
+		return x.ConfigProfilesDetached
+	}
+	return 0
+}
+
+func (x *DeleteAgentResponse) GetHostsRemoved() int32 {
+	if x != nil {
+		return x.HostsRemoved
+	}
+	return 0
+}
+
+func (x *DeleteAgentResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type EnrollmentToken struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	TokenId         string                 `protobuf:"bytes,1,opt,name=token_id,json=tokenId,proto3" json:"token_id,omitempty"`
+	Description     string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	HostnamePattern string                 `protobuf:"bytes,3,opt,name=hostname_pattern,json=hostnamePattern,proto3" json:"hostname_pattern,omitempty"`                                  // glob the agent's hostname must match, empty matches any
+	Labels          map[string]string      `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // merged into the metadata of enrolled agents
+	PolicyIds       []string               `protobuf:"bytes,5,rep,name=policy_ids,json=policyIds,proto3" json:"policy_ids,omitempty"`                                                    // applied to enrolled agents
+	MaxUses         int32                  `protobuf:"varint,6,opt,name=max_uses,json=maxUses,proto3" json:"max_uses,omitempty"`                                                         // 0 means unlimited
+	Uses            int32                  `protobuf:"varint,7,opt,name=uses,proto3" json:"uses,omitempty"`
+	ExpiresAt       int64                  `protobuf:"varint,8,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // 0 means it never expires
+	Revoked         bool                   `protobuf:"varint,9,opt,name=revoked,proto3" json:"revoked,omitempty"`
+	CreatedAt       int64                  `protobuf:"varint,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LastUsedAt      int64                  `protobuf:"varint,11,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *EnrollmentToken) Reset() {
+	*x = EnrollmentToken{}
+	mi := &file_monitor_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollmentToken) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollmentToken) ProtoMessage() {}
+
+func (x *EnrollmentToken) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollmentToken.ProtoReflect.Descriptor instead.
+func (*EnrollmentToken) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *EnrollmentToken) GetTokenId() string {
+	if x != nil {
+		return x.TokenId
+	}
+	return ""
+}
+
+func (x *EnrollmentToken) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *EnrollmentToken) GetHostnamePattern() string {
+	if x != nil {
+		return x.HostnamePattern
+	}
+	return ""
+}
+
+func (x *EnrollmentToken) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *EnrollmentToken) GetPolicyIds() []string {
+	if x != nil {
+		return x.PolicyIds
+	}
+	return nil
+}
+
+func (x *EnrollmentToken) GetMaxUses() int32 {
+	if x != nil {
+		return x.MaxUses
+	}
+	return 0
+}
+
+func (x *EnrollmentToken) GetUses() int32 {
+	if x != nil {
+		return x.Uses
+	}
+	return 0
+}
+
+func (x *EnrollmentToken) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *EnrollmentToken) GetRevoked() bool {
+	if x != nil {
+		return x.Revoked
+	}
+	return false
+}
+
+func (x *EnrollmentToken) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *EnrollmentToken) GetLastUsedAt() int64 {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return 0
+}
+
+type CreateEnrollmentTokenRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Description     string                 `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+	TtlSeconds      int64                  `protobuf:"varint,2,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"` // 0 means it never expires
+	MaxUses         int32                  `protobuf:"varint,3,opt,name=max_uses,json=maxUses,proto3" json:"max_uses,omitempty"`          // 0 means unlimited
+	HostnamePattern string                 `protobuf:"bytes,4,opt,name=hostname_pattern,json=hostnamePattern,proto3" json:"hostname_pattern,omitempty"`
+	Labels          map[string]string      `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	PolicyIds       []string               `protobuf:"bytes,6,rep,name=policy_ids,json=policyIds,proto3" json:"policy_ids,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CreateEnrollmentTokenRequest) Reset() {
+	*x = CreateEnrollmentTokenRequest{}
+	mi := &file_monitor_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEnrollmentTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEnrollmentTokenRequest) ProtoMessage() {}
+
+func (x *CreateEnrollmentTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEnrollmentTokenRequest.ProtoReflect.Descriptor instead.
+func (*CreateEnrollmentTokenRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *CreateEnrollmentTokenRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateEnrollmentTokenRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+func (x *CreateEnrollmentTokenRequest) GetMaxUses() int32 {
+	if x != nil {
+		return x.MaxUses
+	}
+	return 0
+}
+
+func (x *CreateEnrollmentTokenRequest) GetHostnamePattern() string {
+	if x != nil {
+		return x.HostnamePattern
+	}
+	return ""
+}
+
+func (x *CreateEnrollmentTokenRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *CreateEnrollmentTokenRequest) GetPolicyIds() []string {
+	if x != nil {
+		return x.PolicyIds
+	}
+	return nil
+}
+
+type CreateEnrollmentTokenResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Success         bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message         string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Token           string                 `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"` // secret agents present, not retrievable later
+	EnrollmentToken *EnrollmentToken       `protobuf:"bytes,4,opt,name=enrollment_token,json=enrollmentToken,proto3" json:"enrollment_token,omitempty"`
+	Timestamp       int64                  `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CreateEnrollmentTokenResponse) Reset() {
+	*x = CreateEnrollmentTokenResponse{}
+	mi := &file_monitor_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEnrollmentTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEnrollmentTokenResponse) ProtoMessage() {}
+
+func (x *CreateEnrollmentTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEnrollmentTokenResponse.ProtoReflect.Descriptor instead.
+func (*CreateEnrollmentTokenResponse) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *CreateEnrollmentTokenResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CreateEnrollmentTokenResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CreateEnrollmentTokenResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *CreateEnrollmentTokenResponse) GetEnrollmentToken() *EnrollmentToken {
+	if x != nil {
+		return x.EnrollmentToken
+	}
+	return nil
+}
+
+func (x *CreateEnrollmentTokenResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type ListEnrollmentTokensRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEnrollmentTokensRequest) Reset() {
+	*x = ListEnrollmentTokensRequest{}
+	mi := &file_monitor_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEnrollmentTokensRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEnrollmentTokensRequest) ProtoMessage() {}
+
+func (x *ListEnrollmentTokensRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEnrollmentTokensRequest.ProtoReflect.Descriptor instead.
+func (*ListEnrollmentTokensRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{42}
+}
+
+type ListEnrollmentTokensResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tokens        []*EnrollmentToken     `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEnrollmentTokensResponse) Reset() {
+	*x = ListEnrollmentTokensResponse{}
+	mi := &file_monitor_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEnrollmentTokensResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEnrollmentTokensResponse) ProtoMessage() {}
+
+func (x *ListEnrollmentTokensResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEnrollmentTokensResponse.ProtoReflect.Descriptor instead.
+func (*ListEnrollmentTokensResponse) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ListEnrollmentTokensResponse) GetTokens() []*EnrollmentToken {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+func (x *ListEnrollmentTokensResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type RevokeEnrollmentTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TokenId       string                 `protobuf:"bytes,1,opt,name=token_id,json=tokenId,proto3" json:"token_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeEnrollmentTokenRequest) Reset() {
+	*x = RevokeEnrollmentTokenRequest{}
+	mi := &file_monitor_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeEnrollmentTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeEnrollmentTokenRequest) ProtoMessage() {}
+
+func (x *RevokeEnrollmentTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeEnrollmentTokenRequest.ProtoReflect.Descriptor instead.
+func (*RevokeEnrollmentTokenRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *RevokeEnrollmentTokenRequest) GetTokenId() string {
+	if x != nil {
+		return x.TokenId
+	}
+	return ""
+}
+
+type RevokeEnrollmentTokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeEnrollmentTokenResponse) Reset() {
+	*x = RevokeEnrollmentTokenResponse{}
+	mi := &file_monitor_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeEnrollmentTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeEnrollmentTokenResponse) ProtoMessage() {}
+
+func (x *RevokeEnrollmentTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeEnrollmentTokenResponse.ProtoReflect.Descriptor instead.
+func (*RevokeEnrollmentTokenResponse) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *RevokeEnrollmentTokenResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RevokeEnrollmentTokenResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RevokeEnrollmentTokenResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type PolicyRule struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Metric          string                 `protobuf:"bytes,1,opt,name=metric,proto3" json:"metric,omitempty"` // cpu, ram, disk, network_tx, network_rx, network_errors or network_drops
+	Comparator      Comparator             `protobuf:"varint,2,opt,name=comparator,proto3,enum=monitor.Comparator" json:"comparator,omitempty"`
+	Value           float64                `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`                                           // percentage 0-100 for cpu, ram and disk; per-second rate for network metrics
+	DurationSeconds int64                  `protobuf:"varint,4,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"` // condition must hold this long before alerting
+	Severity        Severity               `protobuf:"varint,5,opt,name=severity,proto3,enum=monitor.Severity" json:"severity,omitempty"`
+	ClearValue      *float64               `protobuf:"fixed64,6,opt,name=clear_value,json=clearValue,proto3,oneof" json:"clear_value,omitempty"` // level at which the alert resolves, defaults to value
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PolicyRule) Reset() {
+	*x = PolicyRule{}
+	mi := &file_monitor_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PolicyRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolicyRule) ProtoMessage() {}
+
+func (x *PolicyRule) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolicyRule.ProtoReflect.Descriptor instead.
+func (*PolicyRule) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *PolicyRule) GetMetric() string {
+	if x != nil {
+		return x.Metric
+	}
+	return ""
+}
+
+func (x *PolicyRule) GetComparator() Comparator {
+	if x != nil {
+		return x.Comparator
+	}
+	return Comparator_COMPARATOR_UNSPECIFIED
+}
+
+func (x *PolicyRule) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *PolicyRule) GetDurationSeconds() int64 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+func (x *PolicyRule) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
+}
+
+func (x *PolicyRule) GetClearValue() float64 {
+	if x != nil && x.ClearValue != nil {
+		return *x.ClearValue
+	}
+	return 0
+}
+
+type PolicyRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	PolicyId    string                 `protobuf:"bytes,1,opt,name=policy_id,json=policyId,proto3" json:"policy_id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// Deprecated: Marked as deprecated in monitor.proto.
+	Thresholds    map[string]string `protobuf:"bytes,4,rep,name=thresholds,proto3" json:"thresholds,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // legacy, e.g., {"cpu": "80", "ram": "90"}; migrated into rules
+	Actions       []string          `protobuf:"bytes,5,rep,name=actions,proto3" json:"actions,omitempty"`                                                                                 // e.g., ["alert", "restart"]
+	Metadata      map[string]string `protobuf:"bytes,6,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Enabled       bool              `protobuf:"varint,7,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Rules         []*PolicyRule     `protobuf:"bytes,8,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PolicyRequest) Reset() {
+	*x = PolicyRequest{}
+	mi := &file_monitor_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolicyRequest) ProtoMessage() {}
+
+func (x *PolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolicyRequest.ProtoReflect.Descriptor instead.
+func (*PolicyRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *PolicyRequest) GetPolicyId() string {
+	if x != nil {
+		return x.PolicyId
+	}
+	return ""
+}
+
+func (x *PolicyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PolicyRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in monitor.proto.
+func (x *PolicyRequest) GetThresholds() map[string]string {
+	if x != nil {
+		return x.Thresholds
+	}
+	return nil
+}
+
+func (x *PolicyRequest) GetActions() []string {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
+func (x *PolicyRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *PolicyRequest) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *PolicyRequest) GetRules() []*PolicyRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type PolicyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	PolicyId      string                 `protobuf:"bytes,3,opt,name=policy_id,json=policyId,proto3" json:"policy_id,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PolicyResponse) Reset() {
+	*x = PolicyResponse{}
+	mi := &file_monitor_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolicyResponse) ProtoMessage() {}
+
+func (x *PolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolicyResponse.ProtoReflect.Descriptor instead.
+func (*PolicyResponse) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *PolicyResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *PolicyResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *PolicyResponse) GetPolicyId() string {
+	if x != nil {
+		return x.PolicyId
+	}
+	return ""
+}
+
+func (x *PolicyResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type RemovePolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PolicyId      string                 `protobuf:"bytes,1,opt,name=policy_id,json=policyId,proto3" json:"policy_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemovePolicyRequest) Reset() {
+	*x = RemovePolicyRequest{}
+	mi := &file_monitor_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePolicyRequest) ProtoMessage() {}
+
+func (x *RemovePolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePolicyRequest.ProtoReflect.Descriptor instead.
+func (*RemovePolicyRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *RemovePolicyRequest) GetPolicyId() string {
+	if x != nil {
+		return x.PolicyId
+	}
+	return ""
+}
+
+type ListPoliciesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPoliciesRequest) Reset() {
+	*x = ListPoliciesRequest{}
+	mi := &file_monitor_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPoliciesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPoliciesRequest) ProtoMessage() {}
+
+func (x *ListPoliciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPoliciesRequest.ProtoReflect.Descriptor instead.
+func (*ListPoliciesRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ListPoliciesRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListPoliciesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListPoliciesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policies      []*Policy              `protobuf:"bytes,1,rep,name=policies,proto3" json:"policies,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPoliciesResponse) Reset() {
+	*x = ListPoliciesResponse{}
+	mi := &file_monitor_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPoliciesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPoliciesResponse) ProtoMessage() {}
+
+func (x *ListPoliciesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPoliciesResponse.ProtoReflect.Descriptor instead.
+func (*ListPoliciesResponse) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *ListPoliciesResponse) GetPolicies() []*Policy {
+	if x != nil {
+		return x.Policies
+	}
+	return nil
+}
+
+func (x *ListPoliciesResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type Policy struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	PolicyId    string                 `protobuf:"bytes,1,opt,name=policy_id,json=policyId,proto3" json:"policy_id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// Deprecated: Marked as deprecated in monitor.proto.
+	Thresholds    map[string]string `protobuf:"bytes,4,rep,name=thresholds,proto3" json:"thresholds,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // legacy view of rules
+	Actions       []string          `protobuf:"bytes,5,rep,name=actions,proto3" json:"actions,omitempty"`
+	Metadata      map[string]string `protobuf:"bytes,6,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Enabled       bool              `protobuf:"varint,7,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	CreatedAt     int64             `protobuf:"varint,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     int64             `protobuf:"varint,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	AppliedAgents []string          `protobuf:"bytes,10,rep,name=applied_agents,json=appliedAgents,proto3" json:"applied_agents,omitempty"` // List of agent IDs this policy is applied to
+	Rules         []*PolicyRule     `protobuf:"bytes,11,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Policy) Reset() {
+	*x = Policy{}
+	mi := &file_monitor_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Policy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Policy) ProtoMessage() {}
+
+func (x *Policy) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Policy.ProtoReflect.Descriptor instead.
+func (*Policy) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *Policy) GetPolicyId() string {
+	if x != nil {
+		return x.PolicyId
+	}
+	return ""
+}
+
+func (x *Policy) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Policy) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in monitor.proto.
+func (x *Policy) GetThresholds() map[string]string {
+	if x != nil {
+		return x.Thresholds
+	}
+	return nil
+}
+
+func (x *Policy) GetActions() []string {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
+func (x *Policy) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Policy) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *Policy) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Policy) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *Policy) GetAppliedAgents() []string {
+	if x != nil {
+		return x.AppliedAgents
+	}
+	return nil
+}
+
+func (x *Policy) GetRules() []*PolicyRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type ApplyPolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	PolicyId      string                 `protobuf:"bytes,2,opt,name=policy_id,json=policyId,proto3" json:"policy_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyPolicyRequest) Reset() {
+	*x = ApplyPolicyRequest{}
+	mi := &file_monitor_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyPolicyRequest) ProtoMessage() {}
+
+func (x *ApplyPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyPolicyRequest.ProtoReflect.Descriptor instead.
+func (*ApplyPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ApplyPolicyRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *ApplyPolicyRequest) GetPolicyId() string {
+	if x != nil {
+		return x.PolicyId
+	}
+	return ""
+}
+
+type UnapplyPolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	PolicyId      string                 `protobuf:"bytes,2,opt,name=policy_id,json=policyId,proto3" json:"policy_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnapplyPolicyRequest) Reset() {
+	*x = UnapplyPolicyRequest{}
+	mi := &file_monitor_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnapplyPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnapplyPolicyRequest) ProtoMessage() {}
+
+func (x *UnapplyPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnapplyPolicyRequest.ProtoReflect.Descriptor instead.
+func (*UnapplyPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *UnapplyPolicyRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *UnapplyPolicyRequest) GetPolicyId() string {
+	if x != nil {
+		return x.PolicyId
+	}
+	return ""
+}
+
+// Agent settings controlled by a config profile. Settings left unset keep the
+// agent's local configuration.
+type AgentSettings struct {
+	state                  protoimpl.MessageState        `protogen:"open.v1"`
+	MetricsIntervalSeconds int64                         `protobuf:"varint,1,opt,name=metrics_interval_seconds,json=metricsIntervalSeconds,proto3" json:"metrics_interval_seconds,omitempty"`
+	Metadata               map[string]string             `protobuf:"bytes,2,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`     // merged into the agent's labels
+	Collectors             map[string]*CollectorSettings `protobuf:"bytes,3,rep,name=collectors,proto3" json:"collectors,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // keyed by plugin name
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *AgentSettings) Reset() {
+	*x = AgentSettings{}
+	mi := &file_monitor_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentSettings) ProtoMessage() {}
+
+func (x *AgentSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentSettings.ProtoReflect.Descriptor instead.
+func (*AgentSettings) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *AgentSettings) GetMetricsIntervalSeconds() int64 {
+	if x != nil {
+		return x.MetricsIntervalSeconds
+	}
+	return 0
+}
+
+func (x *AgentSettings) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *AgentSettings) GetCollectors() map[string]*CollectorSettings {
+	if x != nil {
+		return x.Collectors
+	}
+	return nil
+}
+
+type CollectorSettings struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Enabled         bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	IntervalSeconds int64                  `protobuf:"varint,2,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"` // 0 runs the plugin for every sample
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CollectorSettings) Reset() {
+	*x = CollectorSettings{}
+	mi := &file_monitor_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectorSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectorSettings) ProtoMessage() {}
+
+func (x *CollectorSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectorSettings.ProtoReflect.Descriptor instead.
+func (*CollectorSettings) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *CollectorSettings) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *CollectorSettings) GetIntervalSeconds() int64 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+type ConfigProfile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProfileId     string                 `protobuf:"bytes,1,opt,name=profile_id,json=profileId,proto3" json:"profile_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Version       int64                  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	Settings      *AgentSettings         `protobuf:"bytes,5,opt,name=settings,proto3" json:"settings,omitempty"`
+	AgentIds      []string               `protobuf:"bytes,6,rep,name=agent_ids,json=agentIds,proto3" json:"agent_ids,omitempty"`                                                           // agents the profile is attached to
+	Selector      map[string]string      `protobuf:"bytes,7,rep,name=selector,proto3" json:"selector,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // agents carrying all these labels receive the profile
+	CreatedAt     int64                  `protobuf:"varint,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigProfile) Reset() {
+	*x = ConfigProfile{}
+	mi := &file_monitor_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigProfile) ProtoMessage() {}
+
+func (x *ConfigProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigProfile.ProtoReflect.Descriptor instead.
+func (*ConfigProfile) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ConfigProfile) GetProfileId() string {
+	if x != nil {
+		return x.ProfileId
+	}
+	return ""
+}
+
+func (x *ConfigProfile) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ConfigProfile) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ConfigProfile) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ConfigProfile) GetSettings() *AgentSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *ConfigProfile) GetAgentIds() []string {
+	if x != nil {
+		return x.AgentIds
+	}
+	return nil
+}
+
+func (x *ConfigProfile) GetSelector() map[string]string {
+	if x != nil {
+		return x.Selector
+	}
+	return nil
+}
+
+func (x *ConfigProfile) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *ConfigProfile) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+type ConfigProfileRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ProfileId       string                 `protobuf:"bytes,1,opt,name=profile_id,json=profileId,proto3" json:"profile_id,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description     string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Settings        *AgentSettings         `protobuf:"bytes,4,opt,name=settings,proto3" json:"settings,omitempty"`                                                                           // on update, replaced only when set
+	Selector        map[string]string      `protobuf:"bytes,5,rep,name=selector,proto3" json:"selector,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // on update, replaced only when set
+	ExpectedVersion int64                  `protobuf:"varint,6,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`                                     // on update, rejects the change if the profile moved past this version
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ConfigProfileRequest) Reset() {
+	*x = ConfigProfileRequest{}
+	mi := &file_monitor_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigProfileRequest) ProtoMessage() {}
+
+func (x *ConfigProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigProfileRequest.ProtoReflect.Descriptor instead.
+func (*ConfigProfileRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *ConfigProfileRequest) GetProfileId() string {
+	if x != nil {
+		return x.ProfileId
+	}
+	return ""
+}
+
+func (x *ConfigProfileRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ConfigProfileRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ConfigProfileRequest) GetSettings() *AgentSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *ConfigProfileRequest) GetSelector() map[string]string {
+	if x != nil {
+		return x.Selector
+	}
+	return nil
+}
+
+func (x *ConfigProfileRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type ConfigProfileResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	PolicyId      string                 `protobuf:"bytes,3,opt,name=policy_id,json=policyId,proto3" json:"policy_id,omitempty"`
+	Profile       *ConfigProfile         `protobuf:"bytes,3,opt,name=profile,proto3" json:"profile,omitempty"`
 	Timestamp     int64                  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PolicyResponse) Reset() {
-	*x = PolicyResponse{}
-	mi := &file_monitor_proto_msgTypes[9]
+func (x *ConfigProfileResponse) Reset() {
+	*x = ConfigProfileResponse{}
+	mi := &file_monitor_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PolicyResponse) String() string {
+func (x *ConfigProfileResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PolicyResponse) ProtoMessage() {}
+func (*ConfigProfileResponse) ProtoMessage() {}
 
-func (x *PolicyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_monitor_proto_msgTypes[9]
+func (x *ConfigProfileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -722,61 +4498,61 @@ func (x *PolicyResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PolicyResponse.ProtoReflect.Descriptor instead.
-func (*PolicyResponse) Descriptor() ([]byte, []int) {
-	return file_monitor_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use ConfigProfileResponse.ProtoReflect.Descriptor instead.
+func (*ConfigProfileResponse) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{59}
 }
 
-func (x *PolicyResponse) GetSuccess() bool {
+func (x *ConfigProfileResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *PolicyResponse) GetMessage() string {
+func (x *ConfigProfileResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-func (x *PolicyResponse) GetPolicyId() string {
+func (x *ConfigProfileResponse) GetProfile() *ConfigProfile {
 	if x != nil {
-		return x.PolicyId
+		return x.Profile
 	}
-	return ""
+	return nil
 }
 
-func (x *PolicyResponse) GetTimestamp() int64 {
+func (x *ConfigProfileResponse) GetTimestamp() int64 {
 	if x != nil {
 		return x.Timestamp
 	}
 	return 0
 }
 
-type RemovePolicyRequest struct {
+type RemoveConfigProfileRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	PolicyId      string                 `protobuf:"bytes,1,opt,name=policy_id,json=policyId,proto3" json:"policy_id,omitempty"`
+	ProfileId     string                 `protobuf:"bytes,1,opt,name=profile_id,json=profileId,proto3" json:"profile_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RemovePolicyRequest) Reset() {
-	*x = RemovePolicyRequest{}
-	mi := &file_monitor_proto_msgTypes[10]
+func (x *RemoveConfigProfileRequest) Reset() {
+	*x = RemoveConfigProfileRequest{}
+	mi := &file_monitor_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RemovePolicyRequest) String() string {
+func (x *RemoveConfigProfileRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemovePolicyRequest) ProtoMessage() {}
+func (*RemoveConfigProfileRequest) ProtoMessage() {}
 
-func (x *RemovePolicyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_monitor_proto_msgTypes[10]
+func (x *RemoveConfigProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -787,41 +4563,39 @@ func (x *RemovePolicyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemovePolicyRequest.ProtoReflect.Descriptor instead.
-func (*RemovePolicyRequest) Descriptor() ([]byte, []int) {
-	return file_monitor_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use RemoveConfigProfileRequest.ProtoReflect.Descriptor instead.
+func (*RemoveConfigProfileRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{60}
 }
 
-func (x *RemovePolicyRequest) GetPolicyId() string {
+func (x *RemoveConfigProfileRequest) GetProfileId() string {
 	if x != nil {
-		return x.PolicyId
+		return x.ProfileId
 	}
 	return ""
 }
 
-type ListPoliciesRequest struct {
+type ListConfigProfilesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
-	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListPoliciesRequest) Reset() {
-	*x = ListPoliciesRequest{}
-	mi := &file_monitor_proto_msgTypes[11]
+func (x *ListConfigProfilesRequest) Reset() {
+	*x = ListConfigProfilesRequest{}
+	mi := &file_monitor_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListPoliciesRequest) String() string {
+func (x *ListConfigProfilesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListPoliciesRequest) ProtoMessage() {}
+func (*ListConfigProfilesRequest) ProtoMessage() {}
 
-func (x *ListPoliciesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_monitor_proto_msgTypes[11]
+func (x *ListConfigProfilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -832,48 +4606,34 @@ func (x *ListPoliciesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListPoliciesRequest.ProtoReflect.Descriptor instead.
-func (*ListPoliciesRequest) Descriptor() ([]byte, []int) {
-	return file_monitor_proto_rawDescGZIP(), []int{11}
-}
-
-func (x *ListPoliciesRequest) GetPage() int32 {
-	if x != nil {
-		return x.Page
-	}
-	return 0
-}
-
-func (x *ListPoliciesRequest) GetPageSize() int32 {
-	if x != nil {
-		return x.PageSize
-	}
-	return 0
+// Deprecated: Use ListConfigProfilesRequest.ProtoReflect.Descriptor instead.
+func (*ListConfigProfilesRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{61}
 }
 
-type ListPoliciesResponse struct {
+type ListConfigProfilesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Policies      []*Policy              `protobuf:"bytes,1,rep,name=policies,proto3" json:"policies,omitempty"`
+	Profiles      []*ConfigProfile       `protobuf:"bytes,1,rep,name=profiles,proto3" json:"profiles,omitempty"`
 	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListPoliciesResponse) Reset() {
-	*x = ListPoliciesResponse{}
-	mi := &file_monitor_proto_msgTypes[12]
+func (x *ListConfigProfilesResponse) Reset() {
+	*x = ListConfigProfilesResponse{}
+	mi := &file_monitor_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListPoliciesResponse) String() string {
+func (x *ListConfigProfilesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListPoliciesResponse) ProtoMessage() {}
+func (*ListConfigProfilesResponse) ProtoMessage() {}
 
-func (x *ListPoliciesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_monitor_proto_msgTypes[12]
+func (x *ListConfigProfilesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -884,56 +4644,48 @@ func (x *ListPoliciesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListPoliciesResponse.ProtoReflect.Descriptor instead.
-func (*ListPoliciesResponse) Descriptor() ([]byte, []int) {
-	return file_monitor_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use ListConfigProfilesResponse.ProtoReflect.Descriptor instead.
+func (*ListConfigProfilesResponse) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{62}
 }
 
-func (x *ListPoliciesResponse) GetPolicies() []*Policy {
+func (x *ListConfigProfilesResponse) GetProfiles() []*ConfigProfile {
 	if x != nil {
-		return x.Policies
+		return x.Profiles
 	}
 	return nil
 }
 
-func (x *ListPoliciesResponse) GetTotal() int32 {
+func (x *ListConfigProfilesResponse) GetTotal() int32 {
 	if x != nil {
 		return x.Total
 	}
 	return 0
 }
 
-type Policy struct {
+type AttachConfigProfileRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	PolicyId      string                 `protobuf:"bytes,1,opt,name=policy_id,json=policyId,proto3" json:"policy_id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Thresholds    map[string]string      `protobuf:"bytes,4,rep,name=thresholds,proto3" json:"thresholds,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	Actions       []string               `protobuf:"bytes,5,rep,name=actions,proto3" json:"actions,omitempty"`
-	Metadata      map[string]string      `protobuf:"bytes,6,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	Enabled       bool                   `protobuf:"varint,7,opt,name=enabled,proto3" json:"enabled,omitempty"`
-	CreatedAt     int64                  `protobuf:"varint,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     int64                  `protobuf:"varint,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	AppliedAgents []string               `protobuf:"bytes,10,rep,name=applied_agents,json=appliedAgents,proto3" json:"applied_agents,omitempty"` // List of agent IDs this policy is applied to
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	ProfileId     string                 `protobuf:"bytes,2,opt,name=profile_id,json=profileId,proto3" json:"profile_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Policy) Reset() {
-	*x = Policy{}
-	mi := &file_monitor_proto_msgTypes[13]
+func (x *AttachConfigProfileRequest) Reset() {
+	*x = AttachConfigProfileRequest{}
+	mi := &file_monitor_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Policy) String() string {
+func (x *AttachConfigProfileRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Policy) ProtoMessage() {}
+func (*AttachConfigProfileRequest) ProtoMessage() {}
 
-func (x *Policy) ProtoReflect() protoreflect.Message {
-	mi := &file_monitor_proto_msgTypes[13]
+func (x *AttachConfigProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -944,104 +4696,100 @@ func (x *Policy) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Policy.ProtoReflect.Descriptor instead.
-func (*Policy) Descriptor() ([]byte, []int) {
-	return file_monitor_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use AttachConfigProfileRequest.ProtoReflect.Descriptor instead.
+func (*AttachConfigProfileRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{63}
 }
 
-func (x *Policy) GetPolicyId() string {
+func (x *AttachConfigProfileRequest) GetAgentId() string {
 	if x != nil {
-		return x.PolicyId
+		return x.AgentId
 	}
 	return ""
 }
 
-func (x *Policy) GetName() string {
+func (x *AttachConfigProfileRequest) GetProfileId() string {
 	if x != nil {
-		return x.Name
+		return x.ProfileId
 	}
 	return ""
 }
 
-func (x *Policy) GetDescription() string {
-	if x != nil {
-		return x.Description
-	}
-	return ""
+type DetachConfigProfileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	ProfileId     string                 `protobuf:"bytes,2,opt,name=profile_id,json=profileId,proto3" json:"profile_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Policy) GetThresholds() map[string]string {
-	if x != nil {
-		return x.Thresholds
-	}
-	return nil
+func (x *DetachConfigProfileRequest) Reset() {
+	*x = DetachConfigProfileRequest{}
+	mi := &file_monitor_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *Policy) GetActions() []string {
-	if x != nil {
-		return x.Actions
-	}
-	return nil
+func (x *DetachConfigProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *Policy) GetMetadata() map[string]string {
-	if x != nil {
-		return x.Metadata
-	}
-	return nil
-}
+func (*DetachConfigProfileRequest) ProtoMessage() {}
 
-func (x *Policy) GetEnabled() bool {
+func (x *DetachConfigProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[64]
 	if x != nil {
-		return x.Enabled
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return false
+	return mi.MessageOf(x)
 }
 
-func (x *Policy) GetCreatedAt() int64 {
-	if x != nil {
-		return x.CreatedAt
-	}
-	return 0
+// Deprecated: Use DetachConfigProfileRequest.ProtoReflect.Descriptor instead.
+func (*DetachConfigProfileRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{64}
 }
 
-func (x *Policy) GetUpdatedAt() int64 {
+func (x *DetachConfigProfileRequest) GetAgentId() string {
 	if x != nil {
-		return x.UpdatedAt
+		return x.AgentId
 	}
-	return 0
+	return ""
 }
 
-func (x *Policy) GetAppliedAgents() []string {
+func (x *DetachConfigProfileRequest) GetProfileId() string {
 	if x != nil {
-		return x.AppliedAgents
+		return x.ProfileId
 	}
-	return nil
+	return ""
 }
 
-type ApplyPolicyRequest struct {
+type AgentConfigRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	PolicyId      string                 `protobuf:"bytes,2,opt,name=policy_id,json=policyId,proto3" json:"policy_id,omitempty"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`             // ignored, credentials are sent in metadata
+	AccessToken   string                 `protobuf:"bytes,2,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"` // ignored, credentials are sent in metadata
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ApplyPolicyRequest) Reset() {
-	*x = ApplyPolicyRequest{}
-	mi := &file_monitor_proto_msgTypes[14]
+func (x *AgentConfigRequest) Reset() {
+	*x = AgentConfigRequest{}
+	mi := &file_monitor_proto_msgTypes[65]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ApplyPolicyRequest) String() string {
+func (x *AgentConfigRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ApplyPolicyRequest) ProtoMessage() {}
+func (*AgentConfigRequest) ProtoMessage() {}
 
-func (x *ApplyPolicyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_monitor_proto_msgTypes[14]
+func (x *AgentConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[65]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1052,48 +4800,51 @@ func (x *ApplyPolicyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ApplyPolicyRequest.ProtoReflect.Descriptor instead.
-func (*ApplyPolicyRequest) Descriptor() ([]byte, []int) {
-	return file_monitor_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use AgentConfigRequest.ProtoReflect.Descriptor instead.
+func (*AgentConfigRequest) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{65}
 }
 
-func (x *ApplyPolicyRequest) GetAgentId() string {
+func (x *AgentConfigRequest) GetAgentId() string {
 	if x != nil {
 		return x.AgentId
 	}
 	return ""
 }
 
-func (x *ApplyPolicyRequest) GetPolicyId() string {
+func (x *AgentConfigRequest) GetAccessToken() string {
 	if x != nil {
-		return x.PolicyId
+		return x.AccessToken
 	}
 	return ""
 }
 
-type UnapplyPolicyRequest struct {
+type AgentConfigResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	PolicyId      string                 `protobuf:"bytes,2,opt,name=policy_id,json=policyId,proto3" json:"policy_id,omitempty"`
+	Managed       bool                   `protobuf:"varint,1,opt,name=managed,proto3" json:"managed,omitempty"` // false when no profile applies; the agent uses its local configuration
+	ProfileId     string                 `protobuf:"bytes,2,opt,name=profile_id,json=profileId,proto3" json:"profile_id,omitempty"`
+	Version       int64                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Revision      string                 `protobuf:"bytes,4,opt,name=revision,proto3" json:"revision,omitempty"` // "<profile_id>@<version>", reported back in StatsRequest.metadata["config_version"]
+	Settings      *AgentSettings         `protobuf:"bytes,5,opt,name=settings,proto3" json:"settings,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UnapplyPolicyRequest) Reset() {
-	*x = UnapplyPolicyRequest{}
-	mi := &file_monitor_proto_msgTypes[15]
+func (x *AgentConfigResponse) Reset() {
+	*x = AgentConfigResponse{}
+	mi := &file_monitor_proto_msgTypes[66]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UnapplyPolicyRequest) String() string {
+func (x *AgentConfigResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UnapplyPolicyRequest) ProtoMessage() {}
+func (*AgentConfigResponse) ProtoMessage() {}
 
-func (x *UnapplyPolicyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_monitor_proto_msgTypes[15]
+func (x *AgentConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[66]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1104,30 +4855,51 @@ func (x *UnapplyPolicyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UnapplyPolicyRequest.ProtoReflect.Descriptor instead.
-func (*UnapplyPolicyRequest) Descriptor() ([]byte, []int) {
-	return file_monitor_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use AgentConfigResponse.ProtoReflect.Descriptor instead.
+func (*AgentConfigResponse) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{66}
 }
 
-func (x *UnapplyPolicyRequest) GetAgentId() string {
+func (x *AgentConfigResponse) GetManaged() bool {
 	if x != nil {
-		return x.AgentId
+		return x.Managed
+	}
+	return false
+}
+
+func (x *AgentConfigResponse) GetProfileId() string {
+	if x != nil {
+		return x.ProfileId
 	}
 	return ""
 }
 
-func (x *UnapplyPolicyRequest) GetPolicyId() string {
+func (x *AgentConfigResponse) GetVersion() int64 {
 	if x != nil {
-		return x.PolicyId
+		return x.Version
+	}
+	return 0
+}
+
+func (x *AgentConfigResponse) GetRevision() string {
+	if x != nil {
+		return x.Revision
 	}
 	return ""
 }
 
+func (x *AgentConfigResponse) GetSettings() *AgentSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
 var File_monitor_proto protoreflect.FileDescriptor
 
 const file_monitor_proto_rawDesc = "" +
 	"\n" +
-	"\rmonitor.proto\x12\amonitor\x1a\x1cgoogle/api/annotations.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\"\xc9\a\n" +
+	"\rmonitor.proto\x12\amonitor\x1a\x1cgoogle/api/annotations.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\x1a\x0fdisk/disk.proto\x1a\x15network/network.proto\"\xff\x0e\n" +
 	"\fStatsRequest\x12N\n" +
 	"\bhostname\x18\x01 \x01(\tB2\x92A/2 Hostname of the monitored serverJ\v\"server-01\"R\bhostname\x129\n" +
 	"\x03cpu\x18\x02 \x01(\x01B'\x92A$2\x1cCPU usage percentage (0-100)J\x0445.2R\x03cpu\x129\n" +
@@ -1137,21 +4909,122 @@ const file_monitor_proto_rawDesc = "" +
 	"\n" +
 	"ip_address\x18\x06 \x01(\tB,\x92A)2\x17IP address of the agentJ\x0e\"192.168.1.10\"R\tipAddress\x12R\n" +
 	"\ragent_version\x18\a \x01(\tB-\x92A*2\x1fVersion of the monitoring agentJ\a\"1.0.0\"R\fagentVersion\x12\xcf\x01\n" +
-	"\bmetadata\x18\b \x03(\v2#.monitor.StatsRequest.MetadataEntryB\x8d\x01\x92A\x89\x012AAdditional metadata about the agent (location, environment, etc.)JD{\"location\":\"datacenter-01\",\"environment\":\"production\",\"os\":\"linux\"}R\bmetadata\x12\x9c\x01\n" +
-	"\faccess_token\x18\t \x01(\tBy\x92Av21Authentication token obtained during registrationJA\"3f4a8b2c1d9e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f2\"R\vaccessToken\x1a;\n" +
+	"\bmetadata\x18\b \x03(\v2#.monitor.StatsRequest.MetadataEntryB\x8d\x01\x92A\x89\x012AAdditional metadata about the agent (location, environment, etc.)JD{\"location\":\"datacenter-01\",\"environment\":\"production\",\"os\":\"linux\"}R\bmetadata\x12\xaa\x01\n" +
+	"\faccess_token\x18\t \x01(\tB\x86\x01\x92A\x82\x012=Ignored, the access token is sent in the Authorization headerJA\"3f4a8b2c1d9e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f2\"R\vaccessToken\x12\x99\x01\n" +
+	"\fcollected_at\x18\n" +
+	" \x01(\x03Bv\x92As2bWhen the agent took the sample, in Unix milliseconds. Defaults to the time the backend receives itJ\r1737882600000R\vcollectedAt\x12\xe8\x01\n" +
+	"\asamples\x18\v \x03(\v2\x14.monitor.StatsSampleB\xb7\x01\x92A\xb3\x012\xb0\x01Batch of samples sent in one message. When set, cpu, ram, disk, collected_at and extended of the request itself are ignored and every sample is recorded for the request's agentR\asamples\x12\x9e\x01\n" +
+	"\bextended\x18\f \x01(\v2\x18.monitor.ExtendedMetricsBh\x92Ae2cLoad, capacity and counter metrics of the sample, with per-core, per-mount and per-interface seriesR\bextended\x12\xaf\x01\n" +
+	"\x10collector_errors\x18\r \x03(\v2\x17.monitor.CollectorErrorBk\x92Ah2fCollector plugins that failed while taking the sample. Their metrics repeat the last successful valuesR\x0fcollectorErrors\x12\xcb\x01\n" +
+	"\x12report_interval_ms\x18\x0e \x01(\x03B\x9c\x01\x92A\x98\x012\x8f\x01How often the agent takes a sample, in milliseconds. The backend marks the host stale or offline after a multiple of it passes without a sampleJ\x045000R\x10reportIntervalMs\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xcf\x01\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa0\x01\n" +
+	"\x0eCollectorError\x12<\n" +
+	"\tcollector\x18\x01 \x01(\tB\x1e\x92A\x1b2\vPlugin nameJ\f\"partitions\"R\tcollector\x12P\n" +
+	"\amessage\x18\x02 \x01(\tB6\x92A32\x1cError reported by the pluginJ\x13\"permission denied\"R\amessage\"z\n" +
+	"\fCPUCoreUsage\x12+\n" +
+	"\x04core\x18\x01 \x01(\tB\x17\x92A\x142\n" +
+	"Core labelJ\x06\"cpu0\"R\x04core\x12=\n" +
+	"\apercent\x18\x02 \x01(\x01B#\x92A 2\x18Usage percentage (0-100)J\x0437.5R\apercent\"\xf2\x0e\n" +
+	"\x0fExtendedMetrics\x127\n" +
+	"\x06load_1\x18\x01 \x01(\x01B \x92A\x1d2\x151 minute load averageJ\x040.82R\x05load1\x127\n" +
+	"\x06load_5\x18\x02 \x01(\x01B \x92A\x1d2\x155 minute load averageJ\x040.64R\x05load5\x12:\n" +
+	"\aload_15\x18\x03 \x01(\x01B!\x92A\x1e2\x1615 minute load averageJ\x040.51R\x06load15\x12@\n" +
+	"\tcpu_cores\x18\x04 \x01(\x05B#\x92A 2\x1bNumber of logical CPU coresJ\x018R\bcpuCores\x12C\n" +
+	"\tram_total\x18\x05 \x01(\x04B&\x92A#2\x12Total RAM in bytesJ\r\"17179869184\"R\bramTotal\x12@\n" +
+	"\bram_used\x18\x06 \x01(\x04B%\x92A\"2\x11Used RAM in bytesJ\r\"11767709286\"R\aramUsed\x12M\n" +
+	"\n" +
+	"disk_total\x18\a \x01(\x04B.\x92A+2\x19Total disk space in bytesJ\x0e\"536870912000\"R\tdiskTotal\x12J\n" +
+	"\tdisk_used\x18\b \x01(\x04B-\x92A*2\x18Used disk space in bytesJ\x0e\"388176363520\"R\bdiskUsed\x12=\n" +
+	"\x06uptime\x18\t \x01(\x04B%\x92A\"2\x16Host uptime in secondsJ\b\"864000\"R\x06uptime\x12L\n" +
+	"\rprocess_count\x18\n" +
+	" \x01(\x04B'\x92A$2\x1bNumber of running processesJ\x05\"312\"R\fprocessCount\x12[\n" +
+	"\fnetwork_sent\x18\v \x01(\x04B8\x92A52'Bytes sent on all interfaces since bootJ\n" +
+	"\"73400320\"R\vnetworkSent\x12`\n" +
+	"\fnetwork_recv\x18\f \x01(\x04B=\x92A:2+Bytes received on all interfaces since bootJ\v\"157286400\"R\vnetworkRecv\x12L\n" +
+	"\x05cores\x18\r \x03(\v2\x15.monitor.CPUCoreUsageB\x1f\x92A\x1c2\x1aUsage of each logical coreR\x05cores\x12\x80\x01\n" +
+	"\n" +
+	"partitions\x18\x0e \x03(\v2\x13.disk.DiskPartitionBK\x92AH2FUsage of each mounted filesystem, including inodes. Sizes are in bytesR\n" +
+	"partitions\x12m\n" +
+	"\n" +
+	"interfaces\x18\x0f \x03(\v2\x19.network.NetworkInterfaceB2\x92A/2-Counters of each network interface since bootR\n" +
+	"interfaces\x12\xa3\x01\n" +
+	"\x06custom\x18\x10 \x03(\v2$.monitor.ExtendedMetrics.CustomEntryBe\x92Ab2EValues reported by additional collector plugins, keyed by metric nameJ\x19{\"mysql.connections\": 42}R\x06custom\x12p\n" +
+	"\x11network_sent_rate\x18\x11 \x01(\x01BD\x92AA27Bytes sent per second on all interfaces except loopbackJ\x06125000R\x0fnetworkSentRate\x12u\n" +
+	"\x11network_recv_rate\x18\x12 \x01(\x01BI\x92AF2;Bytes received per second on all interfaces except loopbackJ\a2500000R\x0fnetworkRecvRate\x12\x80\x01\n" +
+	"\x13network_errors_rate\x18\x13 \x01(\x01BP\x92AM2HReceive and transmit errors per second on all interfaces except loopbackJ\x010R\x11networkErrorsRate\x12t\n" +
+	"\x12network_drops_rate\x18\x14 \x01(\x01BF\x92AC2<Dropped packets per second on all interfaces except loopbackJ\x030.2R\x10networkDropsRate\x1a9\n" +
+	"\vCustomEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"\xcf\x04\n" +
+	"\vStatsSample\x129\n" +
+	"\x03cpu\x18\x01 \x01(\x01B'\x92A$2\x1cCPU usage percentage (0-100)J\x0445.2R\x03cpu\x129\n" +
+	"\x03ram\x18\x02 \x01(\x01B'\x92A$2\x1cRAM usage percentage (0-100)J\x0468.5R\x03ram\x12<\n" +
+	"\x04disk\x18\x03 \x01(\x01B(\x92A%2\x1dDisk usage percentage (0-100)J\x0472.3R\x04disk\x12k\n" +
+	"\fcollected_at\x18\x04 \x01(\x03BH\x92AE24When the agent took the sample, in Unix millisecondsJ\r1737882600000R\vcollectedAt\x12\x9e\x01\n" +
+	"\bextended\x18\x05 \x01(\v2\x18.monitor.ExtendedMetricsBh\x92Ae2cLoad, capacity and counter metrics of the sample, with per-core, per-mount and per-interface seriesR\bextended\x12~\n" +
+	"\x10collector_errors\x18\x06 \x03(\v2\x17.monitor.CollectorErrorB:\x92A725Collector plugins that failed while taking the sampleR\x0fcollectorErrors\"\xdb\x01\n" +
 	"\rStatsResponse\x12o\n" +
 	"\amessage\x18\x01 \x01(\tBU\x92AR21Response message with stats information or statusJ\x1d\"Stats recorded successfully\"R\amessage\x12M\n" +
 	"\ttimestamp\x18\x02 \x01(\x03B/\x92A,2\x1eUnix timestamp of the responseJ\n" +
-	"1737882600R\ttimestamp\"\xb7\x04\n" +
+	"1737882600R\ttimestampJ\x04\b\x03\x10\x04J\x04\b\x04\x10\x05\"H\n" +
+	"\x0fGetStatsRequest\x12\x1a\n" +
+	"\bhostname\x18\x01 \x01(\tR\bhostname\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\"<\n" +
+	"\x10GetStatsResponse\x12(\n" +
+	"\x05stats\x18\x01 \x01(\v2\x12.monitor.HostStatsR\x05stats\"\xab\x01\n" +
+	"\x11WatchStatsRequest\x12\x1b\n" +
+	"\tagent_ids\x18\x01 \x03(\tR\bagentIds\x12>\n" +
+	"\x06labels\x18\x02 \x03(\v2&.monitor.WatchStatsRequest.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xcd\x01\n" +
+	"\x11StatsRangeRequest\x12\x1a\n" +
+	"\bhostname\x18\x01 \x01(\tR\bhostname\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x18\n" +
+	"\ametrics\x18\x03 \x03(\tR\ametrics\x12\x12\n" +
+	"\x04from\x18\x04 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x05 \x01(\x03R\x02to\x12!\n" +
+	"\fstep_seconds\x18\x06 \x01(\x03R\vstepSeconds\x12 \n" +
+	"\vaggregation\x18\a \x01(\tR\vaggregation\"\x87\x01\n" +
+	"\x12StatsRangeResponse\x12,\n" +
+	"\x06series\x18\x01 \x03(\v2\x14.monitor.StatsSeriesR\x06series\x12!\n" +
+	"\fstep_seconds\x18\x02 \x01(\x03R\vstepSeconds\x12 \n" +
+	"\vaggregation\x18\x03 \x01(\tR\vaggregation\"R\n" +
+	"\vStatsSeries\x12\x16\n" +
+	"\x06metric\x18\x01 \x01(\tR\x06metric\x12+\n" +
+	"\x06points\x18\x02 \x03(\v2\x13.monitor.StatsPointR\x06points\"@\n" +
+	"\n" +
+	"StatsPoint\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value\"\xd6\x03\n" +
+	"\tHostStats\x12\x1a\n" +
+	"\bhostname\x18\x01 \x01(\tR\bhostname\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x1d\n" +
+	"\n" +
+	"ip_address\x18\x03 \x01(\tR\tipAddress\x12\x10\n" +
+	"\x03cpu\x18\x04 \x01(\x01R\x03cpu\x12\x10\n" +
+	"\x03ram\x18\x05 \x01(\x01R\x03ram\x12\x12\n" +
+	"\x04disk\x18\x06 \x01(\x01R\x04disk\x12!\n" +
+	"\fcollected_at\x18\a \x01(\x03R\vcollectedAt\x12#\n" +
+	"\rlast_received\x18\b \x01(\x03R\flastReceived\x12<\n" +
+	"\bmetadata\x18\t \x03(\v2 .monitor.HostStats.MetadataEntryR\bmetadata\x124\n" +
+	"\bextended\x18\n" +
+	" \x01(\v2\x18.monitor.ExtendedMetricsR\bextended\x12B\n" +
+	"\x10collector_errors\x18\v \x03(\v2\x17.monitor.CollectorErrorR\x0fcollectorErrors\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x84\t\n" +
 	"\x0fRegisterRequest\x12]\n" +
 	"\bhostname\x18\x01 \x01(\tBA\x92A>2/Hostname of the server where agent is installedJ\v\"server-01\"R\bhostname\x12K\n" +
 	"\n" +
 	"ip_address\x18\x02 \x01(\tB,\x92A)2\x17IP address of the agentJ\x0e\"192.168.1.10\"R\tipAddress\x12R\n" +
 	"\ragent_version\x18\x03 \x01(\tB-\x92A*2\x1fVersion of the monitoring agentJ\a\"1.0.0\"R\fagentVersion\x12\xe6\x01\n" +
-	"\bmetadata\x18\x04 \x03(\v2&.monitor.RegisterRequest.MetadataEntryB\xa1\x01\x92A\x9d\x012=Additional metadata (location, environment, team, tier, etc.)J\\{\"location\":\"datacenter-01\",\"environment\":\"production\",\"os\":\"linux\",\"team\":\"infrastructure\"}R\bmetadata\x1a;\n" +
+	"\bmetadata\x18\x04 \x03(\v2&.monitor.RegisterRequest.MetadataEntryB\xa1\x01\x92A\x9d\x012=Additional metadata (location, environment, team, tier, etc.)J\\{\"location\":\"datacenter-01\",\"environment\":\"production\",\"os\":\"linux\",\"team\":\"infrastructure\"}R\bmetadata\x12\xb7\x01\n" +
+	"\vfingerprint\x18\x05 \x01(\tB\x94\x01\x92A\x90\x012JStable machine fingerprint; a machine registering again keeps its agent IDJB\"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08\"R\vfingerprint\x12\xbf\x01\n" +
+	"\x10enrollment_token\x18\x06 \x01(\tB\x93\x01\x92A\x8f\x012uEnrollment token; required unless the fingerprint belongs to a registered machine that presents previous_access_tokenJ\x16\"enr-1a2b3c4d.5e6f...\"R\x0fenrollmentToken\x12\xce\x01\n" +
+	"\x15previous_access_token\x18\a \x01(\tB\x99\x01\x92A\x95\x012\x92\x01Access token last issued to this machine, even if expired; proves that a machine registering again by its fingerprint is the one registered beforeR\x13previousAccessToken\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xe4\x04\n" +
@@ -1162,17 +5035,62 @@ const file_monitor_proto_rawDesc = "" +
 	"\faccess_token\x18\x04 \x01(\tB\x88\x01\x92A\x84\x012?Authentication token for API requests (64-character hex string)JA\"3f4a8b2c1d9e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f2\"R\vaccessToken\x12z\n" +
 	"\n" +
 	"expires_at\x18\x05 \x01(\x03B[\x92AX2JUnix timestamp when the token expires (typically 1 year from registration)J\n" +
-	"1737849600R\texpiresAt\"`\n" +
+	"1737849600R\texpiresAt\"Q\n" +
+	"\x11RenewTokenRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12!\n" +
+	"\faccess_token\x18\x02 \x01(\tR\vaccessToken\"V\n" +
+	"\x12RenewTokenResponse\x12!\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\x03R\texpiresAt\"`\n" +
 	"\x13ControlAgentRequest\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x16\n" +
 	"\x06action\x18\x02 \x01(\tR\x06action\x12\x16\n" +
-	"\x06reason\x18\x03 \x01(\tR\x06reason\"\x9b\x01\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\xea\x01\n" +
 	"\x14ControlAgentResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x19\n" +
 	"\bagent_id\x18\x03 \x01(\tR\aagentId\x12\x16\n" +
 	"\x06action\x18\x04 \x01(\tR\x06action\x12\x1c\n" +
-	"\ttimestamp\x18\x05 \x01(\x03R\ttimestamp\"`\n" +
+	"\ttimestamp\x18\x05 \x01(\x03R\ttimestamp\x12\x1d\n" +
+	"\n" +
+	"command_id\x18\x06 \x01(\tR\tcommandId\x12.\n" +
+	"\x06status\x18\a \x01(\x0e2\x16.monitor.CommandStatusR\x06status\"\x95\x01\n" +
+	"\fAgentCommand\x12\x1d\n" +
+	"\n" +
+	"command_id\x18\x01 \x01(\tR\tcommandId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x1b\n" +
+	"\tissued_at\x18\x05 \x01(\x03R\bissuedAt\"u\n" +
+	"\n" +
+	"CommandAck\x12\x1d\n" +
+	"\n" +
+	"command_id\x18\x01 \x01(\tR\tcommandId\x12.\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x16.monitor.CommandStatusR\x06status\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"{\n" +
+	"\x14CommandStreamRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12!\n" +
+	"\faccess_token\x18\x02 \x01(\tR\vaccessToken\x12%\n" +
+	"\x03ack\x18\x03 \x01(\v2\x13.monitor.CommandAckR\x03ack\"\x86\x02\n" +
+	"\x12AgentCommandStatus\x12\x1d\n" +
+	"\n" +
+	"command_id\x18\x01 \x01(\tR\tcommandId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12.\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x16.monitor.CommandStatusR\x06status\x12\x18\n" +
+	"\amessage\x18\x06 \x01(\tR\amessage\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\x03R\tupdatedAt\"K\n" +
+	"\x18ListAgentCommandsRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"j\n" +
+	"\x19ListAgentCommandsResponse\x127\n" +
+	"\bcommands\x18\x01 \x03(\v2\x1b.monitor.AgentCommandStatusR\bcommands\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"`\n" +
 	"\x11BlockAgentRequest\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x18\n" +
 	"\ablocked\x18\x02 \x01(\bR\ablocked\x12\x16\n" +
@@ -1181,17 +5099,150 @@ const file_monitor_proto_rawDesc = "" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x19\n" +
 	"\bagent_id\x18\x03 \x01(\tR\aagentId\x12\x18\n" +
-	"\ablocked\x18\x04 \x01(\bR\ablocked\"\x9c\x03\n" +
+	"\ablocked\x18\x04 \x01(\bR\ablocked\"W\n" +
+	"\x12MergeAgentsRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12&\n" +
+	"\x0fsource_agent_id\x18\x02 \x01(\tR\rsourceAgentId\"\x97\x02\n" +
+	"\x13MergeAgentsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x19\n" +
+	"\bagent_id\x18\x03 \x01(\tR\aagentId\x12%\n" +
+	"\x0epolicies_moved\x18\x04 \x01(\x05R\rpoliciesMoved\x12*\n" +
+	"\x11config_profile_id\x18\x05 \x01(\tR\x0fconfigProfileId\x12\x1f\n" +
+	"\vhosts_moved\x18\x06 \x01(\x05R\n" +
+	"hostsMoved\x12\x1f\n" +
+	"\vstats_moved\x18\a \x01(\x03R\n" +
+	"statsMoved\x12\x1c\n" +
+	"\ttimestamp\x18\b \x01(\x03R\ttimestamp\"\x91\x04\n" +
+	"\x05Agent\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x1a\n" +
+	"\bhostname\x18\x02 \x01(\tR\bhostname\x12\x1d\n" +
+	"\n" +
+	"ip_address\x18\x03 \x01(\tR\tipAddress\x12#\n" +
+	"\ragent_version\x18\x04 \x01(\tR\fagentVersion\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x18\n" +
+	"\ablocked\x18\x06 \x01(\bR\ablocked\x12!\n" +
+	"\fblock_reason\x18\a \x01(\tR\vblockReason\x128\n" +
+	"\bmetadata\x18\b \x03(\v2\x1c.monitor.Agent.MetadataEntryR\bmetadata\x12 \n" +
+	"\vfingerprint\x18\t \x01(\tR\vfingerprint\x12.\n" +
+	"\x13enrollment_token_id\x18\n" +
+	" \x01(\tR\x11enrollmentTokenId\x12#\n" +
+	"\rregistered_at\x18\v \x01(\x03R\fregisteredAt\x12 \n" +
+	"\flast_auth_at\x18\f \x01(\x03R\n" +
+	"lastAuthAt\x12(\n" +
+	"\x10token_expires_at\x18\r \x01(\x03R\x0etokenExpiresAt\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa7\x02\n" +
+	"\x11ListAgentsRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1d\n" +
+	"\ablocked\x18\x04 \x01(\bH\x00R\ablocked\x88\x01\x01\x12#\n" +
+	"\ragent_version\x18\x05 \x01(\tR\fagentVersion\x12>\n" +
+	"\x06labels\x18\x06 \x03(\v2&.monitor.ListAgentsRequest.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\n" +
+	"\n" +
+	"\b_blocked\"\x83\x01\n" +
+	"\x12ListAgentsResponse\x12&\n" +
+	"\x06agents\x18\x01 \x03(\v2\x0e.monitor.AgentR\x06agents\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\",\n" +
+	"\x0fGetAgentRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"8\n" +
+	"\x10GetAgentResponse\x12$\n" +
+	"\x05agent\x18\x01 \x01(\v2\x0e.monitor.AgentR\x05agent\"/\n" +
+	"\x12RevokeAgentRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"\x82\x01\n" +
+	"\x13RevokeAgentResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x19\n" +
+	"\bagent_id\x18\x03 \x01(\tR\aagentId\x12\x1c\n" +
+	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp\"/\n" +
+	"\x12DeleteAgentRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"\x90\x02\n" +
+	"\x13DeleteAgentResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x19\n" +
+	"\bagent_id\x18\x03 \x01(\tR\aagentId\x12-\n" +
+	"\x12policies_unapplied\x18\x04 \x01(\x05R\x11policiesUnapplied\x128\n" +
+	"\x18config_profiles_detached\x18\x05 \x01(\x05R\x16configProfilesDetached\x12#\n" +
+	"\rhosts_removed\x18\x06 \x01(\x05R\fhostsRemoved\x12\x1c\n" +
+	"\ttimestamp\x18\a \x01(\x03R\ttimestamp\"\xba\x03\n" +
+	"\x0fEnrollmentToken\x12\x19\n" +
+	"\btoken_id\x18\x01 \x01(\tR\atokenId\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12)\n" +
+	"\x10hostname_pattern\x18\x03 \x01(\tR\x0fhostnamePattern\x12<\n" +
+	"\x06labels\x18\x04 \x03(\v2$.monitor.EnrollmentToken.LabelsEntryR\x06labels\x12\x1d\n" +
+	"\n" +
+	"policy_ids\x18\x05 \x03(\tR\tpolicyIds\x12\x19\n" +
+	"\bmax_uses\x18\x06 \x01(\x05R\amaxUses\x12\x12\n" +
+	"\x04uses\x18\a \x01(\x05R\x04uses\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\b \x01(\x03R\texpiresAt\x12\x18\n" +
+	"\arevoked\x18\t \x01(\bR\arevoked\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\x03R\tcreatedAt\x12 \n" +
+	"\flast_used_at\x18\v \x01(\x03R\n" +
+	"lastUsedAt\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xcc\x02\n" +
+	"\x1cCreateEnrollmentTokenRequest\x12 \n" +
+	"\vdescription\x18\x01 \x01(\tR\vdescription\x12\x1f\n" +
+	"\vttl_seconds\x18\x02 \x01(\x03R\n" +
+	"ttlSeconds\x12\x19\n" +
+	"\bmax_uses\x18\x03 \x01(\x05R\amaxUses\x12)\n" +
+	"\x10hostname_pattern\x18\x04 \x01(\tR\x0fhostnamePattern\x12I\n" +
+	"\x06labels\x18\x05 \x03(\v21.monitor.CreateEnrollmentTokenRequest.LabelsEntryR\x06labels\x12\x1d\n" +
+	"\n" +
+	"policy_ids\x18\x06 \x03(\tR\tpolicyIds\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xcc\x01\n" +
+	"\x1dCreateEnrollmentTokenResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
+	"\x05token\x18\x03 \x01(\tR\x05token\x12C\n" +
+	"\x10enrollment_token\x18\x04 \x01(\v2\x18.monitor.EnrollmentTokenR\x0fenrollmentToken\x12\x1c\n" +
+	"\ttimestamp\x18\x05 \x01(\x03R\ttimestamp\"\x1d\n" +
+	"\x1bListEnrollmentTokensRequest\"f\n" +
+	"\x1cListEnrollmentTokensResponse\x120\n" +
+	"\x06tokens\x18\x01 \x03(\v2\x18.monitor.EnrollmentTokenR\x06tokens\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"9\n" +
+	"\x1cRevokeEnrollmentTokenRequest\x12\x19\n" +
+	"\btoken_id\x18\x01 \x01(\tR\atokenId\"q\n" +
+	"\x1dRevokeEnrollmentTokenResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1c\n" +
+	"\ttimestamp\x18\x03 \x01(\x03R\ttimestamp\"\xff\x01\n" +
+	"\n" +
+	"PolicyRule\x12\x16\n" +
+	"\x06metric\x18\x01 \x01(\tR\x06metric\x123\n" +
+	"\n" +
+	"comparator\x18\x02 \x01(\x0e2\x13.monitor.ComparatorR\n" +
+	"comparator\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\x01R\x05value\x12)\n" +
+	"\x10duration_seconds\x18\x04 \x01(\x03R\x0fdurationSeconds\x12-\n" +
+	"\bseverity\x18\x05 \x01(\x0e2\x11.monitor.SeverityR\bseverity\x12$\n" +
+	"\vclear_value\x18\x06 \x01(\x01H\x00R\n" +
+	"clearValue\x88\x01\x01B\x0e\n" +
+	"\f_clear_value\"\xcb\x03\n" +
 	"\rPolicyRequest\x12\x1b\n" +
 	"\tpolicy_id\x18\x01 \x01(\tR\bpolicyId\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\x12F\n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12J\n" +
 	"\n" +
-	"thresholds\x18\x04 \x03(\v2&.monitor.PolicyRequest.ThresholdsEntryR\n" +
+	"thresholds\x18\x04 \x03(\v2&.monitor.PolicyRequest.ThresholdsEntryB\x02\x18\x01R\n" +
 	"thresholds\x12\x18\n" +
 	"\aactions\x18\x05 \x03(\tR\aactions\x12@\n" +
 	"\bmetadata\x18\x06 \x03(\v2$.monitor.PolicyRequest.MetadataEntryR\bmetadata\x12\x18\n" +
-	"\aenabled\x18\a \x01(\bR\aenabled\x1a=\n" +
+	"\aenabled\x18\a \x01(\bR\aenabled\x12)\n" +
+	"\x05rules\x18\b \x03(\v2\x13.monitor.PolicyRuleR\x05rules\x1a=\n" +
 	"\x0fThresholdsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a;\n" +
@@ -1210,13 +5261,13 @@ const file_monitor_proto_rawDesc = "" +
 	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\"Y\n" +
 	"\x14ListPoliciesResponse\x12+\n" +
 	"\bpolicies\x18\x01 \x03(\v2\x0f.monitor.PolicyR\bpolicies\x12\x14\n" +
-	"\x05total\x18\x02 \x01(\x05R\x05total\"\xec\x03\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"\x9b\x04\n" +
 	"\x06Policy\x12\x1b\n" +
 	"\tpolicy_id\x18\x01 \x01(\tR\bpolicyId\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\x12?\n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12C\n" +
 	"\n" +
-	"thresholds\x18\x04 \x03(\v2\x1f.monitor.Policy.ThresholdsEntryR\n" +
+	"thresholds\x18\x04 \x03(\v2\x1f.monitor.Policy.ThresholdsEntryB\x02\x18\x01R\n" +
 	"thresholds\x12\x18\n" +
 	"\aactions\x18\x05 \x03(\tR\aactions\x129\n" +
 	"\bmetadata\x18\x06 \x03(\v2\x1d.monitor.Policy.MetadataEntryR\bmetadata\x12\x18\n" +
@@ -1226,7 +5277,8 @@ const file_monitor_proto_rawDesc = "" +
 	"\n" +
 	"updated_at\x18\t \x01(\x03R\tupdatedAt\x12%\n" +
 	"\x0eapplied_agents\x18\n" +
-	" \x03(\tR\rappliedAgents\x1a=\n" +
+	" \x03(\tR\rappliedAgents\x12)\n" +
+	"\x05rules\x18\v \x03(\v2\x13.monitor.PolicyRuleR\x05rules\x1a=\n" +
 	"\x0fThresholdsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a;\n" +
@@ -1238,7 +5290,98 @@ const file_monitor_proto_rawDesc = "" +
 	"\tpolicy_id\x18\x02 \x01(\tR\bpolicyId\"N\n" +
 	"\x14UnapplyPolicyRequest\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x1b\n" +
-	"\tpolicy_id\x18\x02 \x01(\tR\bpolicyId2\xb9\x16\n" +
+	"\tpolicy_id\x18\x02 \x01(\tR\bpolicyId\"\xeb\x02\n" +
+	"\rAgentSettings\x128\n" +
+	"\x18metrics_interval_seconds\x18\x01 \x01(\x03R\x16metricsIntervalSeconds\x12@\n" +
+	"\bmetadata\x18\x02 \x03(\v2$.monitor.AgentSettings.MetadataEntryR\bmetadata\x12F\n" +
+	"\n" +
+	"collectors\x18\x03 \x03(\v2&.monitor.AgentSettings.CollectorsEntryR\n" +
+	"collectors\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aY\n" +
+	"\x0fCollectorsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
+	"\x05value\x18\x02 \x01(\v2\x1a.monitor.CollectorSettingsR\x05value:\x028\x01\"X\n" +
+	"\x11CollectorSettings\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12)\n" +
+	"\x10interval_seconds\x18\x02 \x01(\x03R\x0fintervalSeconds\"\x8c\x03\n" +
+	"\rConfigProfile\x12\x1d\n" +
+	"\n" +
+	"profile_id\x18\x01 \x01(\tR\tprofileId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x03R\aversion\x122\n" +
+	"\bsettings\x18\x05 \x01(\v2\x16.monitor.AgentSettingsR\bsettings\x12\x1b\n" +
+	"\tagent_ids\x18\x06 \x03(\tR\bagentIds\x12@\n" +
+	"\bselector\x18\a \x03(\v2$.monitor.ConfigProfile.SelectorEntryR\bselector\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\x03R\tupdatedAt\x1a;\n" +
+	"\rSelectorEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xd0\x02\n" +
+	"\x14ConfigProfileRequest\x12\x1d\n" +
+	"\n" +
+	"profile_id\x18\x01 \x01(\tR\tprofileId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x122\n" +
+	"\bsettings\x18\x04 \x01(\v2\x16.monitor.AgentSettingsR\bsettings\x12G\n" +
+	"\bselector\x18\x05 \x03(\v2+.monitor.ConfigProfileRequest.SelectorEntryR\bselector\x12)\n" +
+	"\x10expected_version\x18\x06 \x01(\x03R\x0fexpectedVersion\x1a;\n" +
+	"\rSelectorEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x9b\x01\n" +
+	"\x15ConfigProfileResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x120\n" +
+	"\aprofile\x18\x03 \x01(\v2\x16.monitor.ConfigProfileR\aprofile\x12\x1c\n" +
+	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp\";\n" +
+	"\x1aRemoveConfigProfileRequest\x12\x1d\n" +
+	"\n" +
+	"profile_id\x18\x01 \x01(\tR\tprofileId\"\x1b\n" +
+	"\x19ListConfigProfilesRequest\"f\n" +
+	"\x1aListConfigProfilesResponse\x122\n" +
+	"\bprofiles\x18\x01 \x03(\v2\x16.monitor.ConfigProfileR\bprofiles\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"V\n" +
+	"\x1aAttachConfigProfileRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x1d\n" +
+	"\n" +
+	"profile_id\x18\x02 \x01(\tR\tprofileId\"V\n" +
+	"\x1aDetachConfigProfileRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x1d\n" +
+	"\n" +
+	"profile_id\x18\x02 \x01(\tR\tprofileId\"R\n" +
+	"\x12AgentConfigRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12!\n" +
+	"\faccess_token\x18\x02 \x01(\tR\vaccessToken\"\xb8\x01\n" +
+	"\x13AgentConfigResponse\x12\x18\n" +
+	"\amanaged\x18\x01 \x01(\bR\amanaged\x12\x1d\n" +
+	"\n" +
+	"profile_id\x18\x02 \x01(\tR\tprofileId\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x03R\aversion\x12\x1a\n" +
+	"\brevision\x18\x04 \x01(\tR\brevision\x122\n" +
+	"\bsettings\x18\x05 \x01(\v2\x16.monitor.AgentSettingsR\bsettings*\xa1\x01\n" +
+	"\rCommandStatus\x12\x1e\n" +
+	"\x1aCOMMAND_STATUS_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16COMMAND_STATUS_PENDING\x10\x01\x12\x1c\n" +
+	"\x18COMMAND_STATUS_DELIVERED\x10\x02\x12\x1b\n" +
+	"\x17COMMAND_STATUS_EXECUTED\x10\x03\x12\x19\n" +
+	"\x15COMMAND_STATUS_FAILED\x10\x04*\x9e\x01\n" +
+	"\n" +
+	"Comparator\x12\x1a\n" +
+	"\x16COMPARATOR_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17COMPARATOR_GREATER_THAN\x10\x01\x12\x1f\n" +
+	"\x1bCOMPARATOR_GREATER_OR_EQUAL\x10\x02\x12\x18\n" +
+	"\x14COMPARATOR_LESS_THAN\x10\x03\x12\x1c\n" +
+	"\x18COMPARATOR_LESS_OR_EQUAL\x10\x04*u\n" +
+	"\bSeverity\x12\x18\n" +
+	"\x14SEVERITY_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fSEVERITY_LOW\x10\x01\x12\x13\n" +
+	"\x0fSEVERITY_MEDIUM\x10\x02\x12\x11\n" +
+	"\rSEVERITY_HIGH\x10\x03\x12\x15\n" +
+	"\x11SEVERITY_CRITICAL\x10\x042\xc9:\n" +
 	"\x0eMonitorService\x12\xbe\x04\n" +
 	"\rRegisterAgent\x12\x18.monitor.RegisterRequest\x1a\x19.monitor.RegisterResponse\"\xf7\x03\x92A\xd6\x03\n" +
 	"\x10Agent Management\x12\x1fRegister a new monitoring agent\x1ajRegister a new agent with the backend system. Returns unique agent ID and access token for authentication.J\xfe\x01\n" +
@@ -1246,12 +5389,35 @@ const file_monitor_proto_rawDesc = "" +
 	"\x1dAgent registered successfully\"\xd4\x01\n" +
 	"\x10application/json\x12\xbf\x01{\"success\":true,\"message\":\"Agent registered successfully\",\"agent_id\":\"agent-a3f5c2d1\",\"access_token\":\"3f4a8b2c1d9e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f2\",\"expires_at\":1737849600}J4\n" +
 	"\x03400\x12-\n" +
-	"+Bad request - missing or invalid parameters\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/v1/agent/register\x12\xf6\x01\n" +
+	"+Bad request - missing or invalid parameters\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/v1/agent/register\x12E\n" +
+	"\n" +
+	"RenewToken\x12\x1a.monitor.RenewTokenRequest\x1a\x1b.monitor.RenewTokenResponse\x12\xf6\x01\n" +
 	"\fControlAgent\x12\x1c.monitor.ControlAgentRequest\x1a\x1d.monitor.ControlAgentResponse\"\xa8\x01\x92A~\n" +
-	"\rAgent Control\x123Control agent operations (start, shutdown, restart)\x1a8Send control commands to agent: start, shutdown, restart\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/agent/{agent_id}/control\x12\xc2\x01\n" +
+	"\rAgent Control\x123Control agent operations (start, shutdown, restart)\x1a8Send control commands to agent: start, shutdown, restart\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/agent/{agent_id}/control\x12I\n" +
+	"\rCommandStream\x12\x1d.monitor.CommandStreamRequest\x1a\x15.monitor.AgentCommand(\x010\x01\x12\x9b\x02\n" +
+	"\x11ListAgentCommands\x12!.monitor.ListAgentCommandsRequest\x1a\".monitor.ListAgentCommandsResponse\"\xbe\x01\x92A\x95\x01\n" +
+	"\rAgent Control\x12\x13List agent commands\x1aoList control commands sent to an agent, newest first, with their status: pending, delivered, executed or failed\x82\xd3\xe4\x93\x02\x1f\x12\x1d/v1/agent/{agent_id}/commands\x12\xc2\x01\n" +
 	"\n" +
 	"BlockAgent\x12\x1a.monitor.BlockAgentRequest\x1a\x1b.monitor.BlockAgentResponse\"{\x92AS\n" +
-	"\rAgent Control\x12\x19Block or unblock an agent\x1a'Enable or disable blocking for an agent\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/v1/agent/{agent_id}/block\x12\xb9\x01\n" +
+	"\rAgent Control\x12\x19Block or unblock an agent\x1a'Enable or disable blocking for an agent\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/v1/agent/{agent_id}/block\x12\xb5\x02\n" +
+	"\vMergeAgents\x12\x1b.monitor.MergeAgentsRequest\x1a\x1c.monitor.MergeAgentsResponse\"\xea\x01\x92A\xc1\x01\n" +
+	"\rAgent Control\x12%Merge a duplicate agent into this one\x1a\x88\x01Moves the applied policies, attached config profile, hosts and stats history of source_agent_id to agent_id and removes the source agent\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/v1/agent/{agent_id}/merge\x12\x82\x02\n" +
+	"\n" +
+	"ListAgents\x12\x1a.monitor.ListAgentsRequest\x1a\x1b.monitor.ListAgentsResponse\"\xba\x01\x92A\xa4\x01\n" +
+	"\vAgent Fleet\x12\vList agents\x1a\x87\x01Registered agents ordered by hostname, filtered by status, blocking, version and metadata labels (e.g. ?labels[environment]=production)\x82\xd3\xe4\x93\x02\f\x12\n" +
+	"/v1/agents\x12{\n" +
+	"\bGetAgent\x12\x18.monitor.GetAgentRequest\x1a\x19.monitor.GetAgentResponse\":\x92A\x1b\n" +
+	"\vAgent Fleet\x12\fGet an agent\x82\xd3\xe4\x93\x02\x16\x12\x14/v1/agent/{agent_id}\x12\xfa\x01\n" +
+	"\vRevokeAgent\x12\x1b.monitor.RevokeAgentRequest\x1a\x1c.monitor.RevokeAgentResponse\"\xaf\x01\x92A\x85\x01\n" +
+	"\vAgent Fleet\x12\x0fRevoke an agent\x1aeInvalidates the agent's token for good. The agent stays listed and its machine cannot register again.\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/agent/{agent_id}/revoke\x12\xf6\x01\n" +
+	"\vDeleteAgent\x12\x1b.monitor.DeleteAgentRequest\x1a\x1c.monitor.DeleteAgentResponse\"\xab\x01\x92A\x8b\x01\n" +
+	"\vAgent Fleet\x12\x0fDelete an agent\x1akRemoves the agent with its policy assignments, config profile attachments and hosts. Stats history is kept.\x82\xd3\xe4\x93\x02\x16*\x14/v1/agent/{agent_id}\x12\x8a\x02\n" +
+	"\x15CreateEnrollmentToken\x12%.monitor.CreateEnrollmentTokenRequest\x1a&.monitor.CreateEnrollmentTokenResponse\"\xa1\x01\x92A~\n" +
+	"\x10Agent Enrollment\x12\x1aCreate an enrollment token\x1aNReturns the token agents present on first registration. It is shown only once.\x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/enrollment-tokens\x12\xaf\x01\n" +
+	"\x14ListEnrollmentTokens\x12$.monitor.ListEnrollmentTokensRequest\x1a%.monitor.ListEnrollmentTokensResponse\"J\x92A*\n" +
+	"\x10Agent Enrollment\x12\x16List enrollment tokens\x82\xd3\xe4\x93\x02\x17\x12\x15/v1/enrollment-tokens\x12\x82\x02\n" +
+	"\x15RevokeEnrollmentToken\x12%.monitor.RevokeEnrollmentTokenRequest\x1a&.monitor.RevokeEnrollmentTokenResponse\"\x99\x01\x92An\n" +
+	"\x10Agent Enrollment\x12\x1aRevoke an enrollment token\x1a>Agents already enrolled with the token keep their registration\x82\xd3\xe4\x93\x02\"* /v1/enrollment-tokens/{token_id}\x12\xb9\x01\n" +
 	"\tAddPolicy\x12\x16.monitor.PolicyRequest\x1a\x17.monitor.PolicyResponse\"{\x92Aa\n" +
 	"\x11Policy Management\x12\x1bAdd a new monitoring policy\x1a/Create a new policy with thresholds and actions\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/policies\x12\xb2\x01\n" +
 	"\fUpdatePolicy\x12\x16.monitor.PolicyRequest\x1a\x17.monitor.PolicyResponse\"q\x92AK\n" +
@@ -1263,19 +5429,36 @@ const file_monitor_proto_rawDesc = "" +
 	"\vApplyPolicy\x12\x1b.monitor.ApplyPolicyRequest\x1a\x17.monitor.PolicyResponse\"\x87\x01\x92AL\n" +
 	"\x11Policy Management\x12\x15Apply policy to agent\x1a Apply a policy to specific agent\x82\xd3\xe4\x93\x022:\x01*\"-/v1/agent/{agent_id}/policy/{policy_id}/apply\x12\xd8\x01\n" +
 	"\rUnapplyPolicy\x12\x1d.monitor.UnapplyPolicyRequest\x1a\x17.monitor.PolicyResponse\"\x8e\x01\x92AQ\n" +
-	"\x11Policy Management\x12\x19Unapply policy from agent\x1a!Remove policy from specific agent\x82\xd3\xe4\x93\x024:\x01*\"//v1/agent/{agent_id}/policy/{policy_id}/unapply\x12\x83\x02\n" +
-	"\vStreamStats\x12\x15.monitor.StatsRequest\x1a\x16.monitor.StatsResponse\"\xc2\x01\x92A\xa3\x01\n" +
-	"\aMetrics\x12 Stream system metrics from agent\x1ahAgent streams real-time system metrics (CPU, RAM, Disk) to backend. Requires valid authentication token.b\f\n" +
+	"\x11Policy Management\x12\x19Unapply policy from agent\x1a!Remove policy from specific agent\x82\xd3\xe4\x93\x024:\x01*\"//v1/agent/{agent_id}/policy/{policy_id}/unapply\x12\x93\x02\n" +
+	"\x10AddConfigProfile\x12\x1d.monitor.ConfigProfileRequest\x1a\x1e.monitor.ConfigProfileResponse\"\xbf\x01\x92A\x9d\x01\n" +
+	"\x13Agent Configuration\x12\x1bAdd an agent config profile\x1aiCreate version 1 of a config profile. The agents it reaches are asked to fetch their configuration again.\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/v1/config-profiles\x12\xbe\x02\n" +
+	"\x13UpdateConfigProfile\x12\x1d.monitor.ConfigProfileRequest\x1a\x1e.monitor.ConfigProfileResponse\"\xe7\x01\x92A\xb8\x01\n" +
+	"\x13Agent Configuration\x12\x1eUpdate an agent config profile\x1a\x80\x01Store a new version of a config profile. With expected_version set, the update fails with 409 if the profile changed in between.\x82\xd3\xe4\x93\x02%:\x01*\x1a /v1/config-profiles/{profile_id}\x12\xb5\x02\n" +
+	"\x13RemoveConfigProfile\x12#.monitor.RemoveConfigProfileRequest\x1a\x1e.monitor.ConfigProfileResponse\"\xd8\x01\x92A\xac\x01\n" +
+	"\x13Agent Configuration\x12\x1eRemove an agent config profile\x1auDelete a config profile. The agents it reached fall back to another matching profile or to their local configuration.\x82\xd3\xe4\x93\x02\"* /v1/config-profiles/{profile_id}\x12\xae\x01\n" +
+	"\x12ListConfigProfiles\x12\".monitor.ListConfigProfilesRequest\x1a#.monitor.ListConfigProfilesResponse\"O\x92A1\n" +
+	"\x13Agent Configuration\x12\x1aList agent config profiles\x82\xd3\xe4\x93\x02\x15\x12\x13/v1/config-profiles\x12\xd1\x02\n" +
+	"\x13AttachConfigProfile\x12#.monitor.AttachConfigProfileRequest\x1a\x1e.monitor.ConfigProfileResponse\"\xf4\x01\x92A\xae\x01\n" +
+	"\x13Agent Configuration\x12#Attach a config profile to an agent\x1arAn attached profile takes precedence over profiles matched by selector. An agent has at most one attached profile.\x82\xd3\xe4\x93\x02<:\x01*\"7/v1/agent/{agent_id}/config-profile/{profile_id}/attach\x12\xde\x01\n" +
+	"\x13DetachConfigProfile\x12#.monitor.DetachConfigProfileRequest\x1a\x1e.monitor.ConfigProfileResponse\"\x81\x01\x92A<\n" +
+	"\x13Agent Configuration\x12%Detach a config profile from an agent\x82\xd3\xe4\x93\x02<:\x01*\"7/v1/agent/{agent_id}/config-profile/{profile_id}/detach\x12K\n" +
+	"\x0eGetAgentConfig\x12\x1b.monitor.AgentConfigRequest\x1a\x1c.monitor.AgentConfigResponse\x12\xb3\x02\n" +
+	"\vStreamStats\x12\x15.monitor.StatsRequest\x1a\x16.monitor.StatsResponse\"\xf2\x01\x92A\xd3\x01\n" +
+	"\aMetrics\x12 Stream system metrics from agent\x1a\x97\x01Agent streams real-time system metrics (CPU, RAM, Disk) to backend. Requires a valid access token in the Authorization header, checked once per stream.b\f\n" +
+	"\n" +
 	"\n" +
+	"\x06bearer\x12\x00\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/v1/stats/stream(\x01\x12\xb6\x04\n" +
+	"\bGetStats\x12\x18.monitor.GetStatsRequest\x1a\x19.monitor.GetStatsResponse\"\xf4\x03\x92A\xb6\x03\n" +
+	"\aMetrics\x12\x1dGet stats for a specific host\x1aGRetrieve the latest system metrics for a specific hostname or agent ID.J\x99\x02\n" +
+	"\x03200\x12\x91\x02\n" +
+	"\x1cStats retrieved successfully\"\xf0\x01\n" +
+	"\x10application/json\x12\xdb\x01{\"stats\":{\"hostname\":\"server-01\",\"agentId\":\"agent-123\",\"ipAddress\":\"192.168.1.100\",\"cpu\":45.2,\"ram\":68.5,\"disk\":72.3,\"collectedAt\":\"1737882599000\",\"lastReceived\":\"1737882600000\",\"metadata\":{\"environment\":\"production\"}}}J'\n" +
+	"\x03404\x12 \n" +
+	"\x1eNo stats for the host or agent\x82\xd3\xe4\x93\x024Z\x1c\x12\x1a/v1/agent/{agent_id}/stats\x12\x14/v1/stats/{hostname}\x12\xab\x02\n" +
+	"\x0fQueryStatsRange\x12\x1a.monitor.StatsRangeRequest\x1a\x1b.monitor.StatsRangeResponse\"\xde\x01\x92A\x94\x01\n" +
+	"\aMetrics\x12\x1dQuery stats over a time range\x1ajAggregate the metrics of a host or agent into steps, e.g. the 5 minute average CPU over the last 24 hours.\x82\xd3\xe4\x93\x02@Z\"\x12 /v1/agent/{agent_id}/stats/range\x12\x1a/v1/stats/{hostname}/range\x12>\n" +
 	"\n" +
-	"\x06bearer\x12\x00\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/v1/stats/stream(\x01\x12\xaa\x03\n" +
-	"\bGetStats\x12\x15.monitor.StatsRequest\x1a\x16.monitor.StatsResponse\"\xee\x02\x92A\xce\x02\n" +
-	"\aMetrics\x12\x1dGet stats for a specific host\x1aGRetrieve the latest system metrics for a specific hostname or agent ID.J\xc1\x01\n" +
-	"\x03200\x12\xb9\x01\n" +
-	"\x1cStats retrieved successfully\"\x98\x01\n" +
-	"\x10application/json\x12\x83\x01{\"message\":\"Stats for server-01: CPU=45.20%, RAM=68.50%, Disk=72.30% (Last received: 2026-01-26T10:30:00Z)\",\"timestamp\":1737882600}J\x17\n" +
-	"\x03404\x12\x10\n" +
-	"\x0eHost not found\x82\xd3\xe4\x93\x02\x16\x12\x14/v1/stats/{hostname}B\xf9\x02\x92A\xd6\x02\x12\xbd\x01\n" +
+	"WatchStats\x12\x1a.monitor.WatchStatsRequest\x1a\x12.monitor.HostStats0\x01B\xf9\x02\x92A\xd6\x02\x12\xbd\x01\n" +
 	"\x11Smart Monitor API\x12iAPI for Smart Monitor - Distributed System Monitoring Platform with Agent Registration and Authentication\"6\n" +
 	"\x12Smart Monitor Team\x1a support@smartmonitor.example.com2\x051.0.0*\x02\x01\x022\x10application/json:\x10application/jsonZ^\n" +
 	"\\\n" +
@@ -1296,66 +5479,217 @@ func file_monitor_proto_rawDescGZIP() []byte {
 	return file_monitor_proto_rawDescData
 }
 
-var file_monitor_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_monitor_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_monitor_proto_msgTypes = make([]protoimpl.MessageInfo, 84)
 var file_monitor_proto_goTypes = []any{
-	(*StatsRequest)(nil),         // 0: monitor.StatsRequest
-	(*StatsResponse)(nil),        // 1: monitor.StatsResponse
-	(*RegisterRequest)(nil),      // 2: monitor.RegisterRequest
-	(*RegisterResponse)(nil),     // 3: monitor.RegisterResponse
-	(*ControlAgentRequest)(nil),  // 4: monitor.ControlAgentRequest
-	(*ControlAgentResponse)(nil), // 5: monitor.ControlAgentResponse
-	(*BlockAgentRequest)(nil),    // 6: monitor.BlockAgentRequest
-	(*BlockAgentResponse)(nil),   // 7: monitor.BlockAgentResponse
-	(*PolicyRequest)(nil),        // 8: monitor.PolicyRequest
-	(*PolicyResponse)(nil),       // 9: monitor.PolicyResponse
-	(*RemovePolicyRequest)(nil),  // 10: monitor.RemovePolicyRequest
-	(*ListPoliciesRequest)(nil),  // 11: monitor.ListPoliciesRequest
-	(*ListPoliciesResponse)(nil), // 12: monitor.ListPoliciesResponse
-	(*Policy)(nil),               // 13: monitor.Policy
-	(*ApplyPolicyRequest)(nil),   // 14: monitor.ApplyPolicyRequest
-	(*UnapplyPolicyRequest)(nil), // 15: monitor.UnapplyPolicyRequest
-	nil,                          // 16: monitor.StatsRequest.MetadataEntry
-	nil,                          // 17: monitor.RegisterRequest.MetadataEntry
-	nil,                          // 18: monitor.PolicyRequest.ThresholdsEntry
-	nil,                          // 19: monitor.PolicyRequest.MetadataEntry
-	nil,                          // 20: monitor.Policy.ThresholdsEntry
-	nil,                          // 21: monitor.Policy.MetadataEntry
+	(CommandStatus)(0),                    // 0: monitor.CommandStatus
+	(Comparator)(0),                       // 1: monitor.Comparator
+	(Severity)(0),                         // 2: monitor.Severity
+	(*StatsRequest)(nil),                  // 3: monitor.StatsRequest
+	(*CollectorError)(nil),                // 4: monitor.CollectorError
+	(*CPUCoreUsage)(nil),                  // 5: monitor.CPUCoreUsage
+	(*ExtendedMetrics)(nil),               // 6: monitor.ExtendedMetrics
+	(*StatsSample)(nil),                   // 7: monitor.StatsSample
+	(*StatsResponse)(nil),                 // 8: monitor.StatsResponse
+	(*GetStatsRequest)(nil),               // 9: monitor.GetStatsRequest
+	(*GetStatsResponse)(nil),              // 10: monitor.GetStatsResponse
+	(*WatchStatsRequest)(nil),             // 11: monitor.WatchStatsRequest
+	(*StatsRangeRequest)(nil),             // 12: monitor.StatsRangeRequest
+	(*StatsRangeResponse)(nil),            // 13: monitor.StatsRangeResponse
+	(*StatsSeries)(nil),                   // 14: monitor.StatsSeries
+	(*StatsPoint)(nil),                    // 15: monitor.StatsPoint
+	(*HostStats)(nil),                     // 16: monitor.HostStats
+	(*RegisterRequest)(nil),               // 17: monitor.RegisterRequest
+	(*RegisterResponse)(nil),              // 18: monitor.RegisterResponse
+	(*RenewTokenRequest)(nil),             // 19: monitor.RenewTokenRequest
+	(*RenewTokenResponse)(nil),            // 20: monitor.RenewTokenResponse
+	(*ControlAgentRequest)(nil),           // 21: monitor.ControlAgentRequest
+	(*ControlAgentResponse)(nil),          // 22: monitor.ControlAgentResponse
+	(*AgentCommand)(nil),                  // 23: monitor.AgentCommand
+	(*CommandAck)(nil),                    // 24: monitor.CommandAck
+	(*CommandStreamRequest)(nil),          // 25: monitor.CommandStreamRequest
+	(*AgentCommandStatus)(nil),            // 26: monitor.AgentCommandStatus
+	(*ListAgentCommandsRequest)(nil),      // 27: monitor.ListAgentCommandsRequest
+	(*ListAgentCommandsResponse)(nil),     // 28: monitor.ListAgentCommandsResponse
+	(*BlockAgentRequest)(nil),             // 29: monitor.BlockAgentRequest
+	(*BlockAgentResponse)(nil),            // 30: monitor.BlockAgentResponse
+	(*MergeAgentsRequest)(nil),            // 31: monitor.MergeAgentsRequest
+	(*MergeAgentsResponse)(nil),           // 32: monitor.MergeAgentsResponse
+	(*Agent)(nil),                         // 33: monitor.Agent
+	(*ListAgentsRequest)(nil),             // 34: monitor.ListAgentsRequest
+	(*ListAgentsResponse)(nil),            // 35: monitor.ListAgentsResponse
+	(*GetAgentRequest)(nil),               // 36: monitor.GetAgentRequest
+	(*GetAgentResponse)(nil),              // 37: monitor.GetAgentResponse
+	(*RevokeAgentRequest)(nil),            // 38: monitor.RevokeAgentRequest
+	(*RevokeAgentResponse)(nil),           // 39: monitor.RevokeAgentResponse
+	(*DeleteAgentRequest)(nil),            // 40: monitor.DeleteAgentRequest
+	(*DeleteAgentResponse)(nil),           // 41: monitor.DeleteAgentResponse
+	(*EnrollmentToken)(nil),               // 42: monitor.EnrollmentToken
+	(*CreateEnrollmentTokenRequest)(nil),  // 43: monitor.CreateEnrollmentTokenRequest
+	(*CreateEnrollmentTokenResponse)(nil), // 44: monitor.CreateEnrollmentTokenResponse
+	(*ListEnrollmentTokensRequest)(nil),   // 45: monitor.ListEnrollmentTokensRequest
+	(*ListEnrollmentTokensResponse)(nil),  // 46: monitor.ListEnrollmentTokensResponse
+	(*RevokeEnrollmentTokenRequest)(nil),  // 47: monitor.RevokeEnrollmentTokenRequest
+	(*RevokeEnrollmentTokenResponse)(nil), // 48: monitor.RevokeEnrollmentTokenResponse
+	(*PolicyRule)(nil),                    // 49: monitor.PolicyRule
+	(*PolicyRequest)(nil),                 // 50: monitor.PolicyRequest
+	(*PolicyResponse)(nil),                // 51: monitor.PolicyResponse
+	(*RemovePolicyRequest)(nil),           // 52: monitor.RemovePolicyRequest
+	(*ListPoliciesRequest)(nil),           // 53: monitor.ListPoliciesRequest
+	(*ListPoliciesResponse)(nil),          // 54: monitor.ListPoliciesResponse
+	(*Policy)(nil),                        // 55: monitor.Policy
+	(*ApplyPolicyRequest)(nil),            // 56: monitor.ApplyPolicyRequest
+	(*UnapplyPolicyRequest)(nil),          // 57: monitor.UnapplyPolicyRequest
+	(*AgentSettings)(nil),                 // 58: monitor.AgentSettings
+	(*CollectorSettings)(nil),             // 59: monitor.CollectorSettings
+	(*ConfigProfile)(nil),                 // 60: monitor.ConfigProfile
+	(*ConfigProfileRequest)(nil),          // 61: monitor.ConfigProfileRequest
+	(*ConfigProfileResponse)(nil),         // 62: monitor.ConfigProfileResponse
+	(*RemoveConfigProfileRequest)(nil),    // 63: monitor.RemoveConfigProfileRequest
+	(*ListConfigProfilesRequest)(nil),     // 64: monitor.ListConfigProfilesRequest
+	(*ListConfigProfilesResponse)(nil),    // 65: monitor.ListConfigProfilesResponse
+	(*AttachConfigProfileRequest)(nil),    // 66: monitor.AttachConfigProfileRequest
+	(*DetachConfigProfileRequest)(nil),    // 67: monitor.DetachConfigProfileRequest
+	(*AgentConfigRequest)(nil),            // 68: monitor.AgentConfigRequest
+	(*AgentConfigResponse)(nil),           // 69: monitor.AgentConfigResponse
+	nil,                                   // 70: monitor.StatsRequest.MetadataEntry
+	nil,                                   // 71: monitor.ExtendedMetrics.CustomEntry
+	nil,                                   // 72: monitor.WatchStatsRequest.LabelsEntry
+	nil,                                   // 73: monitor.HostStats.MetadataEntry
+	nil,                                   // 74: monitor.RegisterRequest.MetadataEntry
+	nil,                                   // 75: monitor.Agent.MetadataEntry
+	nil,                                   // 76: monitor.ListAgentsRequest.LabelsEntry
+	nil,                                   // 77: monitor.EnrollmentToken.LabelsEntry
+	nil,                                   // 78: monitor.CreateEnrollmentTokenRequest.LabelsEntry
+	nil,                                   // 79: monitor.PolicyRequest.ThresholdsEntry
+	nil,                                   // 80: monitor.PolicyRequest.MetadataEntry
+	nil,                                   // 81: monitor.Policy.ThresholdsEntry
+	nil,                                   // 82: monitor.Policy.MetadataEntry
+	nil,                                   // 83: monitor.AgentSettings.MetadataEntry
+	nil,                                   // 84: monitor.AgentSettings.CollectorsEntry
+	nil,                                   // 85: monitor.ConfigProfile.SelectorEntry
+	nil,                                   // 86: monitor.ConfigProfileRequest.SelectorEntry
+	(*disk.DiskPartition)(nil),            // 87: disk.DiskPartition
+	(*network.NetworkInterface)(nil),      // 88: network.NetworkInterface
 }
 var file_monitor_proto_depIdxs = []int32{
-	16, // 0: monitor.StatsRequest.metadata:type_name -> monitor.StatsRequest.MetadataEntry
-	17, // 1: monitor.RegisterRequest.metadata:type_name -> monitor.RegisterRequest.MetadataEntry
-	18, // 2: monitor.PolicyRequest.thresholds:type_name -> monitor.PolicyRequest.ThresholdsEntry
-	19, // 3: monitor.PolicyRequest.metadata:type_name -> monitor.PolicyRequest.MetadataEntry
-	13, // 4: monitor.ListPoliciesResponse.policies:type_name -> monitor.Policy
-	20, // 5: monitor.Policy.thresholds:type_name -> monitor.Policy.ThresholdsEntry
-	21, // 6: monitor.Policy.metadata:type_name -> monitor.Policy.MetadataEntry
-	2,  // 7: monitor.MonitorService.RegisterAgent:input_type -> monitor.RegisterRequest
-	4,  // 8: monitor.MonitorService.ControlAgent:input_type -> monitor.ControlAgentRequest
-	6,  // 9: monitor.MonitorService.BlockAgent:input_type -> monitor.BlockAgentRequest
-	8,  // 10: monitor.MonitorService.AddPolicy:input_type -> monitor.PolicyRequest
-	8,  // 11: monitor.MonitorService.UpdatePolicy:input_type -> monitor.PolicyRequest
-	10, // 12: monitor.MonitorService.RemovePolicy:input_type -> monitor.RemovePolicyRequest
-	11, // 13: monitor.MonitorService.ListPolicies:input_type -> monitor.ListPoliciesRequest
-	14, // 14: monitor.MonitorService.ApplyPolicy:input_type -> monitor.ApplyPolicyRequest
-	15, // 15: monitor.MonitorService.UnapplyPolicy:input_type -> monitor.UnapplyPolicyRequest
-	0,  // 16: monitor.MonitorService.StreamStats:input_type -> monitor.StatsRequest
-	0,  // 17: monitor.MonitorService.GetStats:input_type -> monitor.StatsRequest
-	3,  // 18: monitor.MonitorService.RegisterAgent:output_type -> monitor.RegisterResponse
-	5,  // 19: monitor.MonitorService.ControlAgent:output_type -> monitor.ControlAgentResponse
-	7,  // 20: monitor.MonitorService.BlockAgent:output_type -> monitor.BlockAgentResponse
-	9,  // 21: monitor.MonitorService.AddPolicy:output_type -> monitor.PolicyResponse
-	9,  // 22: monitor.MonitorService.UpdatePolicy:output_type -> monitor.PolicyResponse
-	9,  // 23: monitor.MonitorService.RemovePolicy:output_type -> monitor.PolicyResponse
-	12, // 24: monitor.MonitorService.ListPolicies:output_type -> monitor.ListPoliciesResponse
-	9,  // 25: monitor.MonitorService.ApplyPolicy:output_type -> monitor.PolicyResponse
-	9,  // 26: monitor.MonitorService.UnapplyPolicy:output_type -> monitor.PolicyResponse
-	1,  // 27: monitor.MonitorService.StreamStats:output_type -> monitor.StatsResponse
-	1,  // 28: monitor.MonitorService.GetStats:output_type -> monitor.StatsResponse
-	18, // [18:29] is the sub-list for method output_type
-	7,  // [7:18] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	70, // 0: monitor.StatsRequest.metadata:type_name -> monitor.StatsRequest.MetadataEntry
+	7,  // 1: monitor.StatsRequest.samples:type_name -> monitor.StatsSample
+	6,  // 2: monitor.StatsRequest.extended:type_name -> monitor.ExtendedMetrics
+	4,  // 3: monitor.StatsRequest.collector_errors:type_name -> monitor.CollectorError
+	5,  // 4: monitor.ExtendedMetrics.cores:type_name -> monitor.CPUCoreUsage
+	87, // 5: monitor.ExtendedMetrics.partitions:type_name -> disk.DiskPartition
+	88, // 6: monitor.ExtendedMetrics.interfaces:type_name -> network.NetworkInterface
+	71, // 7: monitor.ExtendedMetrics.custom:type_name -> monitor.ExtendedMetrics.CustomEntry
+	6,  // 8: monitor.StatsSample.extended:type_name -> monitor.ExtendedMetrics
+	4,  // 9: monitor.StatsSample.collector_errors:type_name -> monitor.CollectorError
+	16, // 10: monitor.GetStatsResponse.stats:type_name -> monitor.HostStats
+	72, // 11: monitor.WatchStatsRequest.labels:type_name -> monitor.WatchStatsRequest.LabelsEntry
+	14, // 12: monitor.StatsRangeResponse.series:type_name -> monitor.StatsSeries
+	15, // 13: monitor.StatsSeries.points:type_name -> monitor.StatsPoint
+	73, // 14: monitor.HostStats.metadata:type_name -> monitor.HostStats.MetadataEntry
+	6,  // 15: monitor.HostStats.extended:type_name -> monitor.ExtendedMetrics
+	4,  // 16: monitor.HostStats.collector_errors:type_name -> monitor.CollectorError
+	74, // 17: monitor.RegisterRequest.metadata:type_name -> monitor.RegisterRequest.MetadataEntry
+	0,  // 18: monitor.ControlAgentResponse.status:type_name -> monitor.CommandStatus
+	0,  // 19: monitor.CommandAck.status:type_name -> monitor.CommandStatus
+	24, // 20: monitor.CommandStreamRequest.ack:type_name -> monitor.CommandAck
+	0,  // 21: monitor.AgentCommandStatus.status:type_name -> monitor.CommandStatus
+	26, // 22: monitor.ListAgentCommandsResponse.commands:type_name -> monitor.AgentCommandStatus
+	75, // 23: monitor.Agent.metadata:type_name -> monitor.Agent.MetadataEntry
+	76, // 24: monitor.ListAgentsRequest.labels:type_name -> monitor.ListAgentsRequest.LabelsEntry
+	33, // 25: monitor.ListAgentsResponse.agents:type_name -> monitor.Agent
+	33, // 26: monitor.GetAgentResponse.agent:type_name -> monitor.Agent
+	77, // 27: monitor.EnrollmentToken.labels:type_name -> monitor.EnrollmentToken.LabelsEntry
+	78, // 28: monitor.CreateEnrollmentTokenRequest.labels:type_name -> monitor.CreateEnrollmentTokenRequest.LabelsEntry
+	42, // 29: monitor.CreateEnrollmentTokenResponse.enrollment_token:type_name -> monitor.EnrollmentToken
+	42, // 30: monitor.ListEnrollmentTokensResponse.tokens:type_name -> monitor.EnrollmentToken
+	1,  // 31: monitor.PolicyRule.comparator:type_name -> monitor.Comparator
+	2,  // 32: monitor.PolicyRule.severity:type_name -> monitor.Severity
+	79, // 33: monitor.PolicyRequest.thresholds:type_name -> monitor.PolicyRequest.ThresholdsEntry
+	80, // 34: monitor.PolicyRequest.metadata:type_name -> monitor.PolicyRequest.MetadataEntry
+	49, // 35: monitor.PolicyRequest.rules:type_name -> monitor.PolicyRule
+	55, // 36: monitor.ListPoliciesResponse.policies:type_name -> monitor.Policy
+	81, // 37: monitor.Policy.thresholds:type_name -> monitor.Policy.ThresholdsEntry
+	82, // 38: monitor.Policy.metadata:type_name -> monitor.Policy.MetadataEntry
+	49, // 39: monitor.Policy.rules:type_name -> monitor.PolicyRule
+	83, // 40: monitor.AgentSettings.metadata:type_name -> monitor.AgentSettings.MetadataEntry
+	84, // 41: monitor.AgentSettings.collectors:type_name -> monitor.AgentSettings.CollectorsEntry
+	58, // 42: monitor.ConfigProfile.settings:type_name -> monitor.AgentSettings
+	85, // 43: monitor.ConfigProfile.selector:type_name -> monitor.ConfigProfile.SelectorEntry
+	58, // 44: monitor.ConfigProfileRequest.settings:type_name -> monitor.AgentSettings
+	86, // 45: monitor.ConfigProfileRequest.selector:type_name -> monitor.ConfigProfileRequest.SelectorEntry
+	60, // 46: monitor.ConfigProfileResponse.profile:type_name -> monitor.ConfigProfile
+	60, // 47: monitor.ListConfigProfilesResponse.profiles:type_name -> monitor.ConfigProfile
+	58, // 48: monitor.AgentConfigResponse.settings:type_name -> monitor.AgentSettings
+	59, // 49: monitor.AgentSettings.CollectorsEntry.value:type_name -> monitor.CollectorSettings
+	17, // 50: monitor.MonitorService.RegisterAgent:input_type -> monitor.RegisterRequest
+	19, // 51: monitor.MonitorService.RenewToken:input_type -> monitor.RenewTokenRequest
+	21, // 52: monitor.MonitorService.ControlAgent:input_type -> monitor.ControlAgentRequest
+	25, // 53: monitor.MonitorService.CommandStream:input_type -> monitor.CommandStreamRequest
+	27, // 54: monitor.MonitorService.ListAgentCommands:input_type -> monitor.ListAgentCommandsRequest
+	29, // 55: monitor.MonitorService.BlockAgent:input_type -> monitor.BlockAgentRequest
+	31, // 56: monitor.MonitorService.MergeAgents:input_type -> monitor.MergeAgentsRequest
+	34, // 57: monitor.MonitorService.ListAgents:input_type -> monitor.ListAgentsRequest
+	36, // 58: monitor.MonitorService.GetAgent:input_type -> monitor.GetAgentRequest
+	38, // 59: monitor.MonitorService.RevokeAgent:input_type -> monitor.RevokeAgentRequest
+	40, // 60: monitor.MonitorService.DeleteAgent:input_type -> monitor.DeleteAgentRequest
+	43, // 61: monitor.MonitorService.CreateEnrollmentToken:input_type -> monitor.CreateEnrollmentTokenRequest
+	45, // 62: monitor.MonitorService.ListEnrollmentTokens:input_type -> monitor.ListEnrollmentTokensRequest
+	47, // 63: monitor.MonitorService.RevokeEnrollmentToken:input_type -> monitor.RevokeEnrollmentTokenRequest
+	50, // 64: monitor.MonitorService.AddPolicy:input_type -> monitor.PolicyRequest
+	50, // 65: monitor.MonitorService.UpdatePolicy:input_type -> monitor.PolicyRequest
+	52, // 66: monitor.MonitorService.RemovePolicy:input_type -> monitor.RemovePolicyRequest
+	53, // 67: monitor.MonitorService.ListPolicies:input_type -> monitor.ListPoliciesRequest
+	56, // 68: monitor.MonitorService.ApplyPolicy:input_type -> monitor.ApplyPolicyRequest
+	57, // 69: monitor.MonitorService.UnapplyPolicy:input_type -> monitor.UnapplyPolicyRequest
+	61, // 70: monitor.MonitorService.AddConfigProfile:input_type -> monitor.ConfigProfileRequest
+	61, // 71: monitor.MonitorService.UpdateConfigProfile:input_type -> monitor.ConfigProfileRequest
+	63, // 72: monitor.MonitorService.RemoveConfigProfile:input_type -> monitor.RemoveConfigProfileRequest
+	64, // 73: monitor.MonitorService.ListConfigProfiles:input_type -> monitor.ListConfigProfilesRequest
+	66, // 74: monitor.MonitorService.AttachConfigProfile:input_type -> monitor.AttachConfigProfileRequest
+	67, // 75: monitor.MonitorService.DetachConfigProfile:input_type -> monitor.DetachConfigProfileRequest
+	68, // 76: monitor.MonitorService.GetAgentConfig:input_type -> monitor.AgentConfigRequest
+	3,  // 77: monitor.MonitorService.StreamStats:input_type -> monitor.StatsRequest
+	9,  // 78: monitor.MonitorService.GetStats:input_type -> monitor.GetStatsRequest
+	12, // 79: monitor.MonitorService.QueryStatsRange:input_type -> monitor.StatsRangeRequest
+	11, // 80: monitor.MonitorService.WatchStats:input_type -> monitor.WatchStatsRequest
+	18, // 81: monitor.MonitorService.RegisterAgent:output_type -> monitor.RegisterResponse
+	20, // 82: monitor.MonitorService.RenewToken:output_type -> monitor.RenewTokenResponse
+	22, // 83: monitor.MonitorService.ControlAgent:output_type -> monitor.ControlAgentResponse
+	23, // 84: monitor.MonitorService.CommandStream:output_type -> monitor.AgentCommand
+	28, // 85: monitor.MonitorService.ListAgentCommands:output_type -> monitor.ListAgentCommandsResponse
+	30, // 86: monitor.MonitorService.BlockAgent:output_type -> monitor.BlockAgentResponse
+	32, // 87: monitor.MonitorService.MergeAgents:output_type -> monitor.MergeAgentsResponse
+	35, // 88: monitor.MonitorService.ListAgents:output_type -> monitor.ListAgentsResponse
+	37, // 89: monitor.MonitorService.GetAgent:output_type -> monitor.GetAgentResponse
+	39, // 90: monitor.MonitorService.RevokeAgent:output_type -> monitor.RevokeAgentResponse
+	41, // 91: monitor.MonitorService.DeleteAgent:output_type -> monitor.DeleteAgentResponse
+	44, // 92: monitor.MonitorService.CreateEnrollmentToken:output_type -> monitor.CreateEnrollmentTokenResponse
+	46, // 93: monitor.MonitorService.ListEnrollmentTokens:output_type -> monitor.ListEnrollmentTokensResponse
+	48, // 94: monitor.MonitorService.RevokeEnrollmentToken:output_type -> monitor.RevokeEnrollmentTokenResponse
+	51, // 95: monitor.MonitorService.AddPolicy:output_type -> monitor.PolicyResponse
+	51, // 96: monitor.MonitorService.UpdatePolicy:output_type -> monitor.PolicyResponse
+	51, // 97: monitor.MonitorService.RemovePolicy:output_type -> monitor.PolicyResponse
+	54, // 98: monitor.MonitorService.ListPolicies:output_type -> monitor.ListPoliciesResponse
+	51, // 99: monitor.MonitorService.ApplyPolicy:output_type -> monitor.PolicyResponse
+	51, // 100: monitor.MonitorService.UnapplyPolicy:output_type -> monitor.PolicyResponse
+	62, // 101: monitor.MonitorService.AddConfigProfile:output_type -> monitor.ConfigProfileResponse
+	62, // 102: monitor.MonitorService.UpdateConfigProfile:output_type -> monitor.ConfigProfileResponse
+	62, // 103: monitor.MonitorService.RemoveConfigProfile:output_type -> monitor.ConfigProfileResponse
+	65, // 104: monitor.MonitorService.ListConfigProfiles:output_type -> monitor.ListConfigProfilesResponse
+	62, // 105: monitor.MonitorService.AttachConfigProfile:output_type -> monitor.ConfigProfileResponse
+	62, // 106: monitor.MonitorService.DetachConfigProfile:output_type -> monitor.ConfigProfileResponse
+	69, // 107: monitor.MonitorService.GetAgentConfig:output_type -> monitor.AgentConfigResponse
+	8,  // 108: monitor.MonitorService.StreamStats:output_type -> monitor.StatsResponse
+	10, // 109: monitor.MonitorService.GetStats:output_type -> monitor.GetStatsResponse
+	13, // 110: monitor.MonitorService.QueryStatsRange:output_type -> monitor.StatsRangeResponse
+	16, // 111: monitor.MonitorService.WatchStats:output_type -> monitor.HostStats
+	81, // [81:112] is the sub-list for method output_type
+	50, // [50:81] is the sub-list for method input_type
+	50, // [50:50] is the sub-list for extension type_name
+	50, // [50:50] is the sub-list for extension extendee
+	0,  // [0:50] is the sub-list for field type_name
 }
 
 func init() { file_monitor_proto_init() }
@@ -1363,18 +5697,21 @@ func file_monitor_proto_init() {
 	if File_monitor_proto != nil {
 		return
 	}
+	file_monitor_proto_msgTypes[31].OneofWrappers = []any{}
+	file_monitor_proto_msgTypes[46].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_monitor_proto_rawDesc), len(file_monitor_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   22,
+			NumEnums:      3,
+			NumMessages:   84,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_monitor_proto_goTypes,
 		DependencyIndexes: file_monitor_proto_depIdxs,
+		EnumInfos:         file_monitor_proto_enumTypes,
 		MessageInfos:      file_monitor_proto_msgTypes,
 	}.Build()
 	File_monitor_proto = out.File
@@ -19,17 +19,37 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	MonitorService_RegisterAgent_FullMethodName = "/monitor.MonitorService/RegisterAgent"
-	MonitorService_ControlAgent_FullMethodName  = "/monitor.MonitorService/ControlAgent"
-	MonitorService_BlockAgent_FullMethodName    = "/monitor.MonitorService/BlockAgent"
-	MonitorService_AddPolicy_FullMethodName     = "/monitor.MonitorService/AddPolicy"
-	MonitorService_UpdatePolicy_FullMethodName  = "/monitor.MonitorService/UpdatePolicy"
-	MonitorService_RemovePolicy_FullMethodName  = "/monitor.MonitorService/RemovePolicy"
-	MonitorService_ListPolicies_FullMethodName  = "/monitor.MonitorService/ListPolicies"
-	MonitorService_ApplyPolicy_FullMethodName   = "/monitor.MonitorService/ApplyPolicy"
-	MonitorService_UnapplyPolicy_FullMethodName = "/monitor.MonitorService/UnapplyPolicy"
-	MonitorService_StreamStats_FullMethodName   = "/monitor.MonitorService/StreamStats"
-	MonitorService_GetStats_FullMethodName      = "/monitor.MonitorService/GetStats"
+	MonitorService_RegisterAgent_FullMethodName         = "/monitor.MonitorService/RegisterAgent"
+	MonitorService_RenewToken_FullMethodName            = "/monitor.MonitorService/RenewToken"
+	MonitorService_ControlAgent_FullMethodName          = "/monitor.MonitorService/ControlAgent"
+	MonitorService_CommandStream_FullMethodName         = "/monitor.MonitorService/CommandStream"
+	MonitorService_ListAgentCommands_FullMethodName     = "/monitor.MonitorService/ListAgentCommands"
+	MonitorService_BlockAgent_FullMethodName            = "/monitor.MonitorService/BlockAgent"
+	MonitorService_MergeAgents_FullMethodName           = "/monitor.MonitorService/MergeAgents"
+	MonitorService_ListAgents_FullMethodName            = "/monitor.MonitorService/ListAgents"
+	MonitorService_GetAgent_FullMethodName              = "/monitor.MonitorService/GetAgent"
+	MonitorService_RevokeAgent_FullMethodName           = "/monitor.MonitorService/RevokeAgent"
+	MonitorService_DeleteAgent_FullMethodName           = "/monitor.MonitorService/DeleteAgent"
+	MonitorService_CreateEnrollmentToken_FullMethodName = "/monitor.MonitorService/CreateEnrollmentToken"
+	MonitorService_ListEnrollmentTokens_FullMethodName  = "/monitor.MonitorService/ListEnrollmentTokens"
+	MonitorService_RevokeEnrollmentToken_FullMethodName = "/monitor.MonitorService/RevokeEnrollmentToken"
+	MonitorService_AddPolicy_FullMethodName             = "/monitor.MonitorService/AddPolicy"
+	MonitorService_UpdatePolicy_FullMethodName          = "/monitor.MonitorService/UpdatePolicy"
+	MonitorService_RemovePolicy_FullMethodName          = "/monitor.MonitorService/RemovePolicy"
+	MonitorService_ListPolicies_FullMethodName          = "/monitor.MonitorService/ListPolicies"
+	MonitorService_ApplyPolicy_FullMethodName           = "/monitor.MonitorService/ApplyPolicy"
+	MonitorService_UnapplyPolicy_FullMethodName         = "/monitor.MonitorService/UnapplyPolicy"
+	MonitorService_AddConfigProfile_FullMethodName      = "/monitor.MonitorService/AddConfigProfile"
+	MonitorService_UpdateConfigProfile_FullMethodName   = "/monitor.MonitorService/UpdateConfigProfile"
+	MonitorService_RemoveConfigProfile_FullMethodName   = "/monitor.MonitorService/RemoveConfigProfile"
+	MonitorService_ListConfigProfiles_FullMethodName    = "/monitor.MonitorService/ListConfigProfiles"
+	MonitorService_AttachConfigProfile_FullMethodName   = "/monitor.MonitorService/AttachConfigProfile"
+	MonitorService_DetachConfigProfile_FullMethodName   = "/monitor.MonitorService/DetachConfigProfile"
+	MonitorService_GetAgentConfig_FullMethodName        = "/monitor.MonitorService/GetAgentConfig"
+	MonitorService_StreamStats_FullMethodName           = "/monitor.MonitorService/StreamStats"
+	MonitorService_GetStats_FullMethodName              = "/monitor.MonitorService/GetStats"
+	MonitorService_QueryStatsRange_FullMethodName       = "/monitor.MonitorService/QueryStatsRange"
+	MonitorService_WatchStats_FullMethodName            = "/monitor.MonitorService/WatchStats"
 )
 
 // MonitorServiceClient is the client API for MonitorService service.
@@ -38,10 +58,30 @@ const (
 type MonitorServiceClient interface {
 	// Register Agent - Agent must register first before streaming
 	RegisterAgent(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
+	// Agent exchanges its still valid access token for a new one before it
+	// expires, keeping its agent ID (requires authentication)
+	RenewToken(ctx context.Context, in *RenewTokenRequest, opts ...grpc.CallOption) (*RenewTokenResponse, error)
 	// Control Agent Operations
 	ControlAgent(ctx context.Context, in *ControlAgentRequest, opts ...grpc.CallOption) (*ControlAgentResponse, error)
+	// Command channel - agent keeps it open after registering; the backend pushes
+	// control commands and the agent acknowledges each one on the same stream
+	// (requires authentication)
+	CommandStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CommandStreamRequest, AgentCommand], error)
+	// List commands sent to an agent with their delivery status
+	ListAgentCommands(ctx context.Context, in *ListAgentCommandsRequest, opts ...grpc.CallOption) (*ListAgentCommandsResponse, error)
 	// Block/Unblock Agent
 	BlockAgent(ctx context.Context, in *BlockAgentRequest, opts ...grpc.CallOption) (*BlockAgentResponse, error)
+	// Merge duplicate registrations of the same machine into one agent
+	MergeAgents(ctx context.Context, in *MergeAgentsRequest, opts ...grpc.CallOption) (*MergeAgentsResponse, error)
+	// Agent fleet
+	ListAgents(ctx context.Context, in *ListAgentsRequest, opts ...grpc.CallOption) (*ListAgentsResponse, error)
+	GetAgent(ctx context.Context, in *GetAgentRequest, opts ...grpc.CallOption) (*GetAgentResponse, error)
+	RevokeAgent(ctx context.Context, in *RevokeAgentRequest, opts ...grpc.CallOption) (*RevokeAgentResponse, error)
+	DeleteAgent(ctx context.Context, in *DeleteAgentRequest, opts ...grpc.CallOption) (*DeleteAgentResponse, error)
+	// Enrollment tokens gate RegisterAgent for machines not registered before
+	CreateEnrollmentToken(ctx context.Context, in *CreateEnrollmentTokenRequest, opts ...grpc.CallOption) (*CreateEnrollmentTokenResponse, error)
+	ListEnrollmentTokens(ctx context.Context, in *ListEnrollmentTokensRequest, opts ...grpc.CallOption) (*ListEnrollmentTokensResponse, error)
+	RevokeEnrollmentToken(ctx context.Context, in *RevokeEnrollmentTokenRequest, opts ...grpc.CallOption) (*RevokeEnrollmentTokenResponse, error)
 	// Policy Management
 	AddPolicy(ctx context.Context, in *PolicyRequest, opts ...grpc.CallOption) (*PolicyResponse, error)
 	UpdatePolicy(ctx context.Context, in *PolicyRequest, opts ...grpc.CallOption) (*PolicyResponse, error)
@@ -49,10 +89,33 @@ type MonitorServiceClient interface {
 	ListPolicies(ctx context.Context, in *ListPoliciesRequest, opts ...grpc.CallOption) (*ListPoliciesResponse, error)
 	ApplyPolicy(ctx context.Context, in *ApplyPolicyRequest, opts ...grpc.CallOption) (*PolicyResponse, error)
 	UnapplyPolicy(ctx context.Context, in *UnapplyPolicyRequest, opts ...grpc.CallOption) (*PolicyResponse, error)
+	// Central agent configuration
+	AddConfigProfile(ctx context.Context, in *ConfigProfileRequest, opts ...grpc.CallOption) (*ConfigProfileResponse, error)
+	UpdateConfigProfile(ctx context.Context, in *ConfigProfileRequest, opts ...grpc.CallOption) (*ConfigProfileResponse, error)
+	RemoveConfigProfile(ctx context.Context, in *RemoveConfigProfileRequest, opts ...grpc.CallOption) (*ConfigProfileResponse, error)
+	ListConfigProfiles(ctx context.Context, in *ListConfigProfilesRequest, opts ...grpc.CallOption) (*ListConfigProfilesResponse, error)
+	AttachConfigProfile(ctx context.Context, in *AttachConfigProfileRequest, opts ...grpc.CallOption) (*ConfigProfileResponse, error)
+	DetachConfigProfile(ctx context.Context, in *DetachConfigProfileRequest, opts ...grpc.CallOption) (*ConfigProfileResponse, error)
+	// Agent fetches the config profile that applies to it, after connecting and
+	// whenever it receives a "reconfigure" command (requires authentication)
+	GetAgentConfig(ctx context.Context, in *AgentConfigRequest, opts ...grpc.CallOption) (*AgentConfigResponse, error)
 	// Stream từ Agent gửi về Server (requires authentication)
+	//
+	// Agent RPCs marked "requires authentication" read the agent's credentials
+	// from the "authorization: Bearer <access_token>" and "x-agent-id" metadata,
+	// once per call or stream. Invalid or expired credentials fail with
+	// UNAUTHENTICATED, after which the agent registers again; a revoked or
+	// blocked agent fails with PERMISSION_DENIED.
 	StreamStats(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StatsRequest, StatsResponse], error)
-	// Get stats for a specific hostname
-	GetStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error)
+	// Get the latest stats of a host, by hostname or by the agent reporting it
+	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
+	// Metrics of a host or agent over a time range, downsampled to one point per step
+	QueryStatsRange(ctx context.Context, in *StatsRangeRequest, opts ...grpc.CallOption) (*StatsRangeResponse, error)
+	// Dashboards subscribe to samples as agents report them, optionally only
+	// those of some agents or carrying some metadata labels. A subscriber that
+	// falls behind misses samples. Over HTTP, use the SSE endpoint
+	// GET /v1/stats/watch.
+	WatchStats(ctx context.Context, in *WatchStatsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[HostStats], error)
 }
 
 type monitorServiceClient struct {
@@ -73,6 +136,16 @@ func (c *monitorServiceClient) RegisterAgent(ctx context.Context, in *RegisterRe
 	return out, nil
 }
 
+func (c *monitorServiceClient) RenewToken(ctx context.Context, in *RenewTokenRequest, opts ...grpc.CallOption) (*RenewTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RenewTokenResponse)
+	err := c.cc.Invoke(ctx, MonitorService_RenewToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *monitorServiceClient) ControlAgent(ctx context.Context, in *ControlAgentRequest, opts ...grpc.CallOption) (*ControlAgentResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ControlAgentResponse)
@@ -83,6 +156,29 @@ func (c *monitorServiceClient) ControlAgent(ctx context.Context, in *ControlAgen
 	return out, nil
 }
 
+func (c *monitorServiceClient) CommandStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CommandStreamRequest, AgentCommand], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MonitorService_ServiceDesc.Streams[0], MonitorService_CommandStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CommandStreamRequest, AgentCommand]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MonitorService_CommandStreamClient = grpc.BidiStreamingClient[CommandStreamRequest, AgentCommand]
+
+func (c *monitorServiceClient) ListAgentCommands(ctx context.Context, in *ListAgentCommandsRequest, opts ...grpc.CallOption) (*ListAgentCommandsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAgentCommandsResponse)
+	err := c.cc.Invoke(ctx, MonitorService_ListAgentCommands_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *monitorServiceClient) BlockAgent(ctx context.Context, in *BlockAgentRequest, opts ...grpc.CallOption) (*BlockAgentResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(BlockAgentResponse)
@@ -93,6 +189,86 @@ func (c *monitorServiceClient) BlockAgent(ctx context.Context, in *BlockAgentReq
 	return out, nil
 }
 
+func (c *monitorServiceClient) MergeAgents(ctx context.Context, in *MergeAgentsRequest, opts ...grpc.CallOption) (*MergeAgentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MergeAgentsResponse)
+	err := c.cc.Invoke(ctx, MonitorService_MergeAgents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorServiceClient) ListAgents(ctx context.Context, in *ListAgentsRequest, opts ...grpc.CallOption) (*ListAgentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAgentsResponse)
+	err := c.cc.Invoke(ctx, MonitorService_ListAgents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorServiceClient) GetAgent(ctx context.Context, in *GetAgentRequest, opts ...grpc.CallOption) (*GetAgentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAgentResponse)
+	err := c.cc.Invoke(ctx, MonitorService_GetAgent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorServiceClient) RevokeAgent(ctx context.Context, in *RevokeAgentRequest, opts ...grpc.CallOption) (*RevokeAgentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeAgentResponse)
+	err := c.cc.Invoke(ctx, MonitorService_RevokeAgent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorServiceClient) DeleteAgent(ctx context.Context, in *DeleteAgentRequest, opts ...grpc.CallOption) (*DeleteAgentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteAgentResponse)
+	err := c.cc.Invoke(ctx, MonitorService_DeleteAgent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorServiceClient) CreateEnrollmentToken(ctx context.Context, in *CreateEnrollmentTokenRequest, opts ...grpc.CallOption) (*CreateEnrollmentTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateEnrollmentTokenResponse)
+	err := c.cc.Invoke(ctx, MonitorService_CreateEnrollmentToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorServiceClient) ListEnrollmentTokens(ctx context.Context, in *ListEnrollmentTokensRequest, opts ...grpc.CallOption) (*ListEnrollmentTokensResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEnrollmentTokensResponse)
+	err := c.cc.Invoke(ctx, MonitorService_ListEnrollmentTokens_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorServiceClient) RevokeEnrollmentToken(ctx context.Context, in *RevokeEnrollmentTokenRequest, opts ...grpc.CallOption) (*RevokeEnrollmentTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeEnrollmentTokenResponse)
+	err := c.cc.Invoke(ctx, MonitorService_RevokeEnrollmentToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *monitorServiceClient) AddPolicy(ctx context.Context, in *PolicyRequest, opts ...grpc.CallOption) (*PolicyResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(PolicyResponse)
@@ -153,9 +329,79 @@ func (c *monitorServiceClient) UnapplyPolicy(ctx context.Context, in *UnapplyPol
 	return out, nil
 }
 
+func (c *monitorServiceClient) AddConfigProfile(ctx context.Context, in *ConfigProfileRequest, opts ...grpc.CallOption) (*ConfigProfileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfigProfileResponse)
+	err := c.cc.Invoke(ctx, MonitorService_AddConfigProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorServiceClient) UpdateConfigProfile(ctx context.Context, in *ConfigProfileRequest, opts ...grpc.CallOption) (*ConfigProfileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfigProfileResponse)
+	err := c.cc.Invoke(ctx, MonitorService_UpdateConfigProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorServiceClient) RemoveConfigProfile(ctx context.Context, in *RemoveConfigProfileRequest, opts ...grpc.CallOption) (*ConfigProfileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfigProfileResponse)
+	err := c.cc.Invoke(ctx, MonitorService_RemoveConfigProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorServiceClient) ListConfigProfiles(ctx context.Context, in *ListConfigProfilesRequest, opts ...grpc.CallOption) (*ListConfigProfilesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListConfigProfilesResponse)
+	err := c.cc.Invoke(ctx, MonitorService_ListConfigProfiles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorServiceClient) AttachConfigProfile(ctx context.Context, in *AttachConfigProfileRequest, opts ...grpc.CallOption) (*ConfigProfileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfigProfileResponse)
+	err := c.cc.Invoke(ctx, MonitorService_AttachConfigProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorServiceClient) DetachConfigProfile(ctx context.Context, in *DetachConfigProfileRequest, opts ...grpc.CallOption) (*ConfigProfileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfigProfileResponse)
+	err := c.cc.Invoke(ctx, MonitorService_DetachConfigProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorServiceClient) GetAgentConfig(ctx context.Context, in *AgentConfigRequest, opts ...grpc.CallOption) (*AgentConfigResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AgentConfigResponse)
+	err := c.cc.Invoke(ctx, MonitorService_GetAgentConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *monitorServiceClient) StreamStats(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StatsRequest, StatsResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &MonitorService_ServiceDesc.Streams[0], MonitorService_StreamStats_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &MonitorService_ServiceDesc.Streams[1], MonitorService_StreamStats_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -166,9 +412,9 @@ func (c *monitorServiceClient) StreamStats(ctx context.Context, opts ...grpc.Cal
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type MonitorService_StreamStatsClient = grpc.ClientStreamingClient[StatsRequest, StatsResponse]
 
-func (c *monitorServiceClient) GetStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error) {
+func (c *monitorServiceClient) GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(StatsResponse)
+	out := new(GetStatsResponse)
 	err := c.cc.Invoke(ctx, MonitorService_GetStats_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
@@ -176,16 +422,65 @@ func (c *monitorServiceClient) GetStats(ctx context.Context, in *StatsRequest, o
 	return out, nil
 }
 
+func (c *monitorServiceClient) QueryStatsRange(ctx context.Context, in *StatsRangeRequest, opts ...grpc.CallOption) (*StatsRangeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatsRangeResponse)
+	err := c.cc.Invoke(ctx, MonitorService_QueryStatsRange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorServiceClient) WatchStats(ctx context.Context, in *WatchStatsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[HostStats], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MonitorService_ServiceDesc.Streams[2], MonitorService_WatchStats_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchStatsRequest, HostStats]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MonitorService_WatchStatsClient = grpc.ServerStreamingClient[HostStats]
+
 // MonitorServiceServer is the server API for MonitorService service.
 // All implementations must embed UnimplementedMonitorServiceServer
 // for forward compatibility.
 type MonitorServiceServer interface {
 	// Register Agent - Agent must register first before streaming
 	RegisterAgent(context.Context, *RegisterRequest) (*RegisterResponse, error)
+	// Agent exchanges its still valid access token for a new one before it
+	// expires, keeping its agent ID (requires authentication)
+	RenewToken(context.Context, *RenewTokenRequest) (*RenewTokenResponse, error)
 	// Control Agent Operations
 	ControlAgent(context.Context, *ControlAgentRequest) (*ControlAgentResponse, error)
+	// Command channel - agent keeps it open after registering; the backend pushes
+	// control commands and the agent acknowledges each one on the same stream
+	// (requires authentication)
+	CommandStream(grpc.BidiStreamingServer[CommandStreamRequest, AgentCommand]) error
+	// List commands sent to an agent with their delivery status
+	ListAgentCommands(context.Context, *ListAgentCommandsRequest) (*ListAgentCommandsResponse, error)
 	// Block/Unblock Agent
 	BlockAgent(context.Context, *BlockAgentRequest) (*BlockAgentResponse, error)
+	// Merge duplicate registrations of the same machine into one agent
+	MergeAgents(context.Context, *MergeAgentsRequest) (*MergeAgentsResponse, error)
+	// Agent fleet
+	ListAgents(context.Context, *ListAgentsRequest) (*ListAgentsResponse, error)
+	GetAgent(context.Context, *GetAgentRequest) (*GetAgentResponse, error)
+	RevokeAgent(context.Context, *RevokeAgentRequest) (*RevokeAgentResponse, error)
+	DeleteAgent(context.Context, *DeleteAgentRequest) (*DeleteAgentResponse, error)
+	// Enrollment tokens gate RegisterAgent for machines not registered before
+	CreateEnrollmentToken(context.Context, *CreateEnrollmentTokenRequest) (*CreateEnrollmentTokenResponse, error)
+	ListEnrollmentTokens(context.Context, *ListEnrollmentTokensRequest) (*ListEnrollmentTokensResponse, error)
+	RevokeEnrollmentToken(context.Context, *RevokeEnrollmentTokenRequest) (*RevokeEnrollmentTokenResponse, error)
 	// Policy Management
 	AddPolicy(context.Context, *PolicyRequest) (*PolicyResponse, error)
 	UpdatePolicy(context.Context, *PolicyRequest) (*PolicyResponse, error)
@@ -193,10 +488,33 @@ type MonitorServiceServer interface {
 	ListPolicies(context.Context, *ListPoliciesRequest) (*ListPoliciesResponse, error)
 	ApplyPolicy(context.Context, *ApplyPolicyRequest) (*PolicyResponse, error)
 	UnapplyPolicy(context.Context, *UnapplyPolicyRequest) (*PolicyResponse, error)
+	// Central agent configuration
+	AddConfigProfile(context.Context, *ConfigProfileRequest) (*ConfigProfileResponse, error)
+	UpdateConfigProfile(context.Context, *ConfigProfileRequest) (*ConfigProfileResponse, error)
+	RemoveConfigProfile(context.Context, *RemoveConfigProfileRequest) (*ConfigProfileResponse, error)
+	ListConfigProfiles(context.Context, *ListConfigProfilesRequest) (*ListConfigProfilesResponse, error)
+	AttachConfigProfile(context.Context, *AttachConfigProfileRequest) (*ConfigProfileResponse, error)
+	DetachConfigProfile(context.Context, *DetachConfigProfileRequest) (*ConfigProfileResponse, error)
+	// Agent fetches the config profile that applies to it, after connecting and
+	// whenever it receives a "reconfigure" command (requires authentication)
+	GetAgentConfig(context.Context, *AgentConfigRequest) (*AgentConfigResponse, error)
 	// Stream từ Agent gửi về Server (requires authentication)
+	//
+	// Agent RPCs marked "requires authentication" read the agent's credentials
+	// from the "authorization: Bearer <access_token>" and "x-agent-id" metadata,
+	// once per call or stream. Invalid or expired credentials fail with
+	// UNAUTHENTICATED, after which the agent registers again; a revoked or
+	// blocked agent fails with PERMISSION_DENIED.
 	StreamStats(grpc.ClientStreamingServer[StatsRequest, StatsResponse]) error
-	// Get stats for a specific hostname
-	GetStats(context.Context, *StatsRequest) (*StatsResponse, error)
+	// Get the latest stats of a host, by hostname or by the agent reporting it
+	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
+	// Metrics of a host or agent over a time range, downsampled to one point per step
+	QueryStatsRange(context.Context, *StatsRangeRequest) (*StatsRangeResponse, error)
+	// Dashboards subscribe to samples as agents report them, optionally only
+	// those of some agents or carrying some metadata labels. A subscriber that
+	// falls behind misses samples. Over HTTP, use the SSE endpoint
+	// GET /v1/stats/watch.
+	WatchStats(*WatchStatsRequest, grpc.ServerStreamingServer[HostStats]) error
 	mustEmbedUnimplementedMonitorServiceServer()
 }
 
@@ -210,12 +528,45 @@ type UnimplementedMonitorServiceServer struct{}
 func (UnimplementedMonitorServiceServer) RegisterAgent(context.Context, *RegisterRequest) (*RegisterResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method RegisterAgent not implemented")
 }
+func (UnimplementedMonitorServiceServer) RenewToken(context.Context, *RenewTokenRequest) (*RenewTokenResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RenewToken not implemented")
+}
 func (UnimplementedMonitorServiceServer) ControlAgent(context.Context, *ControlAgentRequest) (*ControlAgentResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ControlAgent not implemented")
 }
+func (UnimplementedMonitorServiceServer) CommandStream(grpc.BidiStreamingServer[CommandStreamRequest, AgentCommand]) error {
+	return status.Error(codes.Unimplemented, "method CommandStream not implemented")
+}
+func (UnimplementedMonitorServiceServer) ListAgentCommands(context.Context, *ListAgentCommandsRequest) (*ListAgentCommandsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListAgentCommands not implemented")
+}
 func (UnimplementedMonitorServiceServer) BlockAgent(context.Context, *BlockAgentRequest) (*BlockAgentResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method BlockAgent not implemented")
 }
+func (UnimplementedMonitorServiceServer) MergeAgents(context.Context, *MergeAgentsRequest) (*MergeAgentsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method MergeAgents not implemented")
+}
+func (UnimplementedMonitorServiceServer) ListAgents(context.Context, *ListAgentsRequest) (*ListAgentsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListAgents not implemented")
+}
+func (UnimplementedMonitorServiceServer) GetAgent(context.Context, *GetAgentRequest) (*GetAgentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetAgent not implemented")
+}
+func (UnimplementedMonitorServiceServer) RevokeAgent(context.Context, *RevokeAgentRequest) (*RevokeAgentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RevokeAgent not implemented")
+}
+func (UnimplementedMonitorServiceServer) DeleteAgent(context.Context, *DeleteAgentRequest) (*DeleteAgentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteAgent not implemented")
+}
+func (UnimplementedMonitorServiceServer) CreateEnrollmentToken(context.Context, *CreateEnrollmentTokenRequest) (*CreateEnrollmentTokenResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateEnrollmentToken not implemented")
+}
+func (UnimplementedMonitorServiceServer) ListEnrollmentTokens(context.Context, *ListEnrollmentTokensRequest) (*ListEnrollmentTokensResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListEnrollmentTokens not implemented")
+}
+func (UnimplementedMonitorServiceServer) RevokeEnrollmentToken(context.Context, *RevokeEnrollmentTokenRequest) (*RevokeEnrollmentTokenResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RevokeEnrollmentToken not implemented")
+}
 func (UnimplementedMonitorServiceServer) AddPolicy(context.Context, *PolicyRequest) (*PolicyResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method AddPolicy not implemented")
 }
@@ -234,12 +585,39 @@ func (UnimplementedMonitorServiceServer) ApplyPolicy(context.Context, *ApplyPoli
 func (UnimplementedMonitorServiceServer) UnapplyPolicy(context.Context, *UnapplyPolicyRequest) (*PolicyResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method UnapplyPolicy not implemented")
 }
+func (UnimplementedMonitorServiceServer) AddConfigProfile(context.Context, *ConfigProfileRequest) (*ConfigProfileResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddConfigProfile not implemented")
+}
+func (UnimplementedMonitorServiceServer) UpdateConfigProfile(context.Context, *ConfigProfileRequest) (*ConfigProfileResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateConfigProfile not implemented")
+}
+func (UnimplementedMonitorServiceServer) RemoveConfigProfile(context.Context, *RemoveConfigProfileRequest) (*ConfigProfileResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveConfigProfile not implemented")
+}
+func (UnimplementedMonitorServiceServer) ListConfigProfiles(context.Context, *ListConfigProfilesRequest) (*ListConfigProfilesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListConfigProfiles not implemented")
+}
+func (UnimplementedMonitorServiceServer) AttachConfigProfile(context.Context, *AttachConfigProfileRequest) (*ConfigProfileResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AttachConfigProfile not implemented")
+}
+func (UnimplementedMonitorServiceServer) DetachConfigProfile(context.Context, *DetachConfigProfileRequest) (*ConfigProfileResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DetachConfigProfile not implemented")
+}
+func (UnimplementedMonitorServiceServer) GetAgentConfig(context.Context, *AgentConfigRequest) (*AgentConfigResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetAgentConfig not implemented")
+}
 func (UnimplementedMonitorServiceServer) StreamStats(grpc.ClientStreamingServer[StatsRequest, StatsResponse]) error {
 	return status.Error(codes.Unimplemented, "method StreamStats not implemented")
 }
-func (UnimplementedMonitorServiceServer) GetStats(context.Context, *StatsRequest) (*StatsResponse, error) {
+func (UnimplementedMonitorServiceServer) GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetStats not implemented")
 }
+func (UnimplementedMonitorServiceServer) QueryStatsRange(context.Context, *StatsRangeRequest) (*StatsRangeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method QueryStatsRange not implemented")
+}
+func (UnimplementedMonitorServiceServer) WatchStats(*WatchStatsRequest, grpc.ServerStreamingServer[HostStats]) error {
+	return status.Error(codes.Unimplemented, "method WatchStats not implemented")
+}
 func (UnimplementedMonitorServiceServer) mustEmbedUnimplementedMonitorServiceServer() {}
 func (UnimplementedMonitorServiceServer) testEmbeddedByValue()                        {}
 
@@ -279,6 +657,24 @@ func _MonitorService_RegisterAgent_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MonitorService_RenewToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenewTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServiceServer).RenewToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorService_RenewToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServiceServer).RenewToken(ctx, req.(*RenewTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MonitorService_ControlAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ControlAgentRequest)
 	if err := dec(in); err != nil {
@@ -297,6 +693,31 @@ func _MonitorService_ControlAgent_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MonitorService_CommandStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MonitorServiceServer).CommandStream(&grpc.GenericServerStream[CommandStreamRequest, AgentCommand]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MonitorService_CommandStreamServer = grpc.BidiStreamingServer[CommandStreamRequest, AgentCommand]
+
+func _MonitorService_ListAgentCommands_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAgentCommandsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServiceServer).ListAgentCommands(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorService_ListAgentCommands_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServiceServer).ListAgentCommands(ctx, req.(*ListAgentCommandsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MonitorService_BlockAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BlockAgentRequest)
 	if err := dec(in); err != nil {
@@ -315,6 +736,150 @@ func _MonitorService_BlockAgent_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MonitorService_MergeAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeAgentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServiceServer).MergeAgents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorService_MergeAgents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServiceServer).MergeAgents(ctx, req.(*MergeAgentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonitorService_ListAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAgentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServiceServer).ListAgents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorService_ListAgents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServiceServer).ListAgents(ctx, req.(*ListAgentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonitorService_GetAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServiceServer).GetAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorService_GetAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServiceServer).GetAgent(ctx, req.(*GetAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonitorService_RevokeAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServiceServer).RevokeAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorService_RevokeAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServiceServer).RevokeAgent(ctx, req.(*RevokeAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonitorService_DeleteAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServiceServer).DeleteAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorService_DeleteAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServiceServer).DeleteAgent(ctx, req.(*DeleteAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonitorService_CreateEnrollmentToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateEnrollmentTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServiceServer).CreateEnrollmentToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorService_CreateEnrollmentToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServiceServer).CreateEnrollmentToken(ctx, req.(*CreateEnrollmentTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonitorService_ListEnrollmentTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEnrollmentTokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServiceServer).ListEnrollmentTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorService_ListEnrollmentTokens_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServiceServer).ListEnrollmentTokens(ctx, req.(*ListEnrollmentTokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonitorService_RevokeEnrollmentToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeEnrollmentTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServiceServer).RevokeEnrollmentToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorService_RevokeEnrollmentToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServiceServer).RevokeEnrollmentToken(ctx, req.(*RevokeEnrollmentTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MonitorService_AddPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PolicyRequest)
 	if err := dec(in); err != nil {
@@ -423,6 +988,132 @@ func _MonitorService_UnapplyPolicy_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MonitorService_AddConfigProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfigProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServiceServer).AddConfigProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorService_AddConfigProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServiceServer).AddConfigProfile(ctx, req.(*ConfigProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonitorService_UpdateConfigProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfigProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServiceServer).UpdateConfigProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorService_UpdateConfigProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServiceServer).UpdateConfigProfile(ctx, req.(*ConfigProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonitorService_RemoveConfigProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveConfigProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServiceServer).RemoveConfigProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorService_RemoveConfigProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServiceServer).RemoveConfigProfile(ctx, req.(*RemoveConfigProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonitorService_ListConfigProfiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListConfigProfilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServiceServer).ListConfigProfiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorService_ListConfigProfiles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServiceServer).ListConfigProfiles(ctx, req.(*ListConfigProfilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonitorService_AttachConfigProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AttachConfigProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServiceServer).AttachConfigProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorService_AttachConfigProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServiceServer).AttachConfigProfile(ctx, req.(*AttachConfigProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonitorService_DetachConfigProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DetachConfigProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServiceServer).DetachConfigProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorService_DetachConfigProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServiceServer).DetachConfigProfile(ctx, req.(*DetachConfigProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonitorService_GetAgentConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServiceServer).GetAgentConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorService_GetAgentConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServiceServer).GetAgentConfig(ctx, req.(*AgentConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MonitorService_StreamStats_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(MonitorServiceServer).StreamStats(&grpc.GenericServerStream[StatsRequest, StatsResponse]{ServerStream: stream})
 }
@@ -431,7 +1122,7 @@ func _MonitorService_StreamStats_Handler(srv interface{}, stream grpc.ServerStre
 type MonitorService_StreamStatsServer = grpc.ClientStreamingServer[StatsRequest, StatsResponse]
 
 func _MonitorService_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StatsRequest)
+	in := new(GetStatsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -443,11 +1134,40 @@ func _MonitorService_GetStats_Handler(srv interface{}, ctx context.Context, dec
 		FullMethod: MonitorService_GetStats_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MonitorServiceServer).GetStats(ctx, req.(*StatsRequest))
+		return srv.(MonitorServiceServer).GetStats(ctx, req.(*GetStatsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MonitorService_QueryStatsRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatsRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServiceServer).QueryStatsRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorService_QueryStatsRange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServiceServer).QueryStatsRange(ctx, req.(*StatsRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonitorService_WatchStats_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchStatsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MonitorServiceServer).WatchStats(m, &grpc.GenericServerStream[WatchStatsRequest, HostStats]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MonitorService_WatchStatsServer = grpc.ServerStreamingServer[HostStats]
+
 // MonitorService_ServiceDesc is the grpc.ServiceDesc for MonitorService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -459,14 +1179,54 @@ var MonitorService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RegisterAgent",
 			Handler:    _MonitorService_RegisterAgent_Handler,
 		},
+		{
+			MethodName: "RenewToken",
+			Handler:    _MonitorService_RenewToken_Handler,
+		},
 		{
 			MethodName: "ControlAgent",
 			Handler:    _MonitorService_ControlAgent_Handler,
 		},
+		{
+			MethodName: "ListAgentCommands",
+			Handler:    _MonitorService_ListAgentCommands_Handler,
+		},
 		{
 			MethodName: "BlockAgent",
 			Handler:    _MonitorService_BlockAgent_Handler,
 		},
+		{
+			MethodName: "MergeAgents",
+			Handler:    _MonitorService_MergeAgents_Handler,
+		},
+		{
+			MethodName: "ListAgents",
+			Handler:    _MonitorService_ListAgents_Handler,
+		},
+		{
+			MethodName: "GetAgent",
+			Handler:    _MonitorService_GetAgent_Handler,
+		},
+		{
+			MethodName: "RevokeAgent",
+			Handler:    _MonitorService_RevokeAgent_Handler,
+		},
+		{
+			MethodName: "DeleteAgent",
+			Handler:    _MonitorService_DeleteAgent_Handler,
+		},
+		{
+			MethodName: "CreateEnrollmentToken",
+			Handler:    _MonitorService_CreateEnrollmentToken_Handler,
+		},
+		{
+			MethodName: "ListEnrollmentTokens",
+			Handler:    _MonitorService_ListEnrollmentTokens_Handler,
+		},
+		{
+			MethodName: "RevokeEnrollmentToken",
+			Handler:    _MonitorService_RevokeEnrollmentToken_Handler,
+		},
 		{
 			MethodName: "AddPolicy",
 			Handler:    _MonitorService_AddPolicy_Handler,
@@ -491,17 +1251,60 @@ var MonitorService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UnapplyPolicy",
 			Handler:    _MonitorService_UnapplyPolicy_Handler,
 		},
+		{
+			MethodName: "AddConfigProfile",
+			Handler:    _MonitorService_AddConfigProfile_Handler,
+		},
+		{
+			MethodName: "UpdateConfigProfile",
+			Handler:    _MonitorService_UpdateConfigProfile_Handler,
+		},
+		{
+			MethodName: "RemoveConfigProfile",
+			Handler:    _MonitorService_RemoveConfigProfile_Handler,
+		},
+		{
+			MethodName: "ListConfigProfiles",
+			Handler:    _MonitorService_ListConfigProfiles_Handler,
+		},
+		{
+			MethodName: "AttachConfigProfile",
+			Handler:    _MonitorService_AttachConfigProfile_Handler,
+		},
+		{
+			MethodName: "DetachConfigProfile",
+			Handler:    _MonitorService_DetachConfigProfile_Handler,
+		},
+		{
+			MethodName: "GetAgentConfig",
+			Handler:    _MonitorService_GetAgentConfig_Handler,
+		},
 		{
 			MethodName: "GetStats",
 			Handler:    _MonitorService_GetStats_Handler,
 		},
+		{
+			MethodName: "QueryStatsRange",
+			Handler:    _MonitorService_QueryStatsRange_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "CommandStream",
+			Handler:       _MonitorService_CommandStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "StreamStats",
 			Handler:       _MonitorService_StreamStats_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "WatchStats",
+			Handler:       _MonitorService_WatchStats_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "monitor.proto",
 }
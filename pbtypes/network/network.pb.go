@@ -119,13 +119,28 @@ func (x *GetNetworkStatsResponse) GetTimestamp() int64 {
 }
 
 type NetworkInterface struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Ip            string                 `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`
-	RxBytes       float64                `protobuf:"fixed64,3,opt,name=rx_bytes,json=rxBytes,proto3" json:"rx_bytes,omitempty"`
-	TxBytes       float64                `protobuf:"fixed64,4,opt,name=tx_bytes,json=txBytes,proto3" json:"tx_bytes,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Name      string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Ip        string                 `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`
+	RxBytes   float64                `protobuf:"fixed64,3,opt,name=rx_bytes,json=rxBytes,proto3" json:"rx_bytes,omitempty"`
+	TxBytes   float64                `protobuf:"fixed64,4,opt,name=tx_bytes,json=txBytes,proto3" json:"tx_bytes,omitempty"`
+	RxPackets uint64                 `protobuf:"varint,5,opt,name=rx_packets,json=rxPackets,proto3" json:"rx_packets,omitempty"`
+	TxPackets uint64                 `protobuf:"varint,6,opt,name=tx_packets,json=txPackets,proto3" json:"tx_packets,omitempty"`
+	RxErrors  uint64                 `protobuf:"varint,7,opt,name=rx_errors,json=rxErrors,proto3" json:"rx_errors,omitempty"`
+	TxErrors  uint64                 `protobuf:"varint,8,opt,name=tx_errors,json=txErrors,proto3" json:"tx_errors,omitempty"`
+	RxDropped uint64                 `protobuf:"varint,9,opt,name=rx_dropped,json=rxDropped,proto3" json:"rx_dropped,omitempty"`
+	TxDropped uint64                 `protobuf:"varint,10,opt,name=tx_dropped,json=txDropped,proto3" json:"tx_dropped,omitempty"`
+	// Per-second rates since the previous sample
+	RxBytesPerSec   float64 `protobuf:"fixed64,11,opt,name=rx_bytes_per_sec,json=rxBytesPerSec,proto3" json:"rx_bytes_per_sec,omitempty"`
+	TxBytesPerSec   float64 `protobuf:"fixed64,12,opt,name=tx_bytes_per_sec,json=txBytesPerSec,proto3" json:"tx_bytes_per_sec,omitempty"`
+	RxPacketsPerSec float64 `protobuf:"fixed64,13,opt,name=rx_packets_per_sec,json=rxPacketsPerSec,proto3" json:"rx_packets_per_sec,omitempty"`
+	TxPacketsPerSec float64 `protobuf:"fixed64,14,opt,name=tx_packets_per_sec,json=txPacketsPerSec,proto3" json:"tx_packets_per_sec,omitempty"`
+	RxErrorsPerSec  float64 `protobuf:"fixed64,15,opt,name=rx_errors_per_sec,json=rxErrorsPerSec,proto3" json:"rx_errors_per_sec,omitempty"`
+	TxErrorsPerSec  float64 `protobuf:"fixed64,16,opt,name=tx_errors_per_sec,json=txErrorsPerSec,proto3" json:"tx_errors_per_sec,omitempty"`
+	RxDroppedPerSec float64 `protobuf:"fixed64,17,opt,name=rx_dropped_per_sec,json=rxDroppedPerSec,proto3" json:"rx_dropped_per_sec,omitempty"`
+	TxDroppedPerSec float64 `protobuf:"fixed64,18,opt,name=tx_dropped_per_sec,json=txDroppedPerSec,proto3" json:"tx_dropped_per_sec,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *NetworkInterface) Reset() {
@@ -186,6 +201,104 @@ func (x *NetworkInterface) GetTxBytes() float64 {
 	return 0
 }
 
+func (x *NetworkInterface) GetRxPackets() uint64 {
+	if x != nil {
+		return x.RxPackets
+	}
+	return 0
+}
+
+func (x *NetworkInterface) GetTxPackets() uint64 {
+	if x != nil {
+		return x.TxPackets
+	}
+	return 0
+}
+
+func (x *NetworkInterface) GetRxErrors() uint64 {
+	if x != nil {
+		return x.RxErrors
+	}
+	return 0
+}
+
+func (x *NetworkInterface) GetTxErrors() uint64 {
+	if x != nil {
+		return x.TxErrors
+	}
+	return 0
+}
+
+func (x *NetworkInterface) GetRxDropped() uint64 {
+	if x != nil {
+		return x.RxDropped
+	}
+	return 0
+}
+
+func (x *NetworkInterface) GetTxDropped() uint64 {
+	if x != nil {
+		return x.TxDropped
+	}
+	return 0
+}
+
+func (x *NetworkInterface) GetRxBytesPerSec() float64 {
+	if x != nil {
+		return x.RxBytesPerSec
+	}
+	return 0
+}
+
+func (x *NetworkInterface) GetTxBytesPerSec() float64 {
+	if x != nil {
+		return x.TxBytesPerSec
+	}
+	return 0
+}
+
+func (x *NetworkInterface) GetRxPacketsPerSec() float64 {
+	if x != nil {
+		return x.RxPacketsPerSec
+	}
+	return 0
+}
+
+func (x *NetworkInterface) GetTxPacketsPerSec() float64 {
+	if x != nil {
+		return x.TxPacketsPerSec
+	}
+	return 0
+}
+
+func (x *NetworkInterface) GetRxErrorsPerSec() float64 {
+	if x != nil {
+		return x.RxErrorsPerSec
+	}
+	return 0
+}
+
+func (x *NetworkInterface) GetTxErrorsPerSec() float64 {
+	if x != nil {
+		return x.TxErrorsPerSec
+	}
+	return 0
+}
+
+func (x *NetworkInterface) GetRxDroppedPerSec() float64 {
+	if x != nil {
+		return x.RxDroppedPerSec
+	}
+	return 0
+}
+
+func (x *NetworkInterface) GetTxDroppedPerSec() float64 {
+	if x != nil {
+		return x.TxDroppedPerSec
+	}
+	return 0
+}
+
 var File_network_network_proto protoreflect.FileDescriptor
 
 const file_network_network_proto_rawDesc = "" +
@@ -197,12 +310,31 @@ const file_network_network_proto_rawDesc = "" +
 	"\n" +
 	"interfaces\x18\x01 \x03(\v2\x19.network.NetworkInterfaceR\n" +
 	"interfaces\x12\x1c\n" +
-	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\"l\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\"\xfe\x04\n" +
 	"\x10NetworkInterface\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x0e\n" +
 	"\x02ip\x18\x02 \x01(\tR\x02ip\x12\x19\n" +
 	"\brx_bytes\x18\x03 \x01(\x01R\arxBytes\x12\x19\n" +
-	"\btx_bytes\x18\x04 \x01(\x01R\atxBytes2\x81\x01\n" +
+	"\btx_bytes\x18\x04 \x01(\x01R\atxBytes\x12\x1d\n" +
+	"\n" +
+	"rx_packets\x18\x05 \x01(\x04R\trxPackets\x12\x1d\n" +
+	"\n" +
+	"tx_packets\x18\x06 \x01(\x04R\ttxPackets\x12\x1b\n" +
+	"\trx_errors\x18\a \x01(\x04R\brxErrors\x12\x1b\n" +
+	"\ttx_errors\x18\b \x01(\x04R\btxErrors\x12\x1d\n" +
+	"\n" +
+	"rx_dropped\x18\t \x01(\x04R\trxDropped\x12\x1d\n" +
+	"\n" +
+	"tx_dropped\x18\n" +
+	" \x01(\x04R\ttxDropped\x12'\n" +
+	"\x10rx_bytes_per_sec\x18\v \x01(\x01R\rrxBytesPerSec\x12'\n" +
+	"\x10tx_bytes_per_sec\x18\f \x01(\x01R\rtxBytesPerSec\x12+\n" +
+	"\x12rx_packets_per_sec\x18\r \x01(\x01R\x0frxPacketsPerSec\x12+\n" +
+	"\x12tx_packets_per_sec\x18\x0e \x01(\x01R\x0ftxPacketsPerSec\x12)\n" +
+	"\x11rx_errors_per_sec\x18\x0f \x01(\x01R\x0erxErrorsPerSec\x12)\n" +
+	"\x11tx_errors_per_sec\x18\x10 \x01(\x01R\x0etxErrorsPerSec\x12+\n" +
+	"\x12rx_dropped_per_sec\x18\x11 \x01(\x01R\x0frxDroppedPerSec\x12+\n" +
+	"\x12tx_dropped_per_sec\x18\x12 \x01(\x01R\x0ftxDroppedPerSec2\x81\x01\n" +
 	"\x0eNetworkService\x12o\n" +
 	"\x0fGetNetworkStats\x12\x1f.network.GetNetworkStatsRequest\x1a .network.GetNetworkStatsResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/network/statsB\x1fZ\x1dsmart-monitor/pbtypes/networkb\x06proto3"
 